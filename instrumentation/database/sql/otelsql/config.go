@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// config is used to configure the instrumented drivers.
+type config struct {
+	TracerProvider     trace.TracerProvider
+	MeterProvider      metric.MeterProvider
+	Attributes         []attribute.KeyValue
+	StatementSanitizer StatementSanitizer
+}
+
+// StatementSanitizer returns the statement recorded as the db.statement span
+// attribute for a query, e.g. without its literal values.
+type StatementSanitizer func(query string) string
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// newConfig creates a new config struct and applies opts to it.
+func newConfig(opts ...Option) *config {
+	c := &config{
+		TracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.TracerProvider = provider
+		}
+	})
+}
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the operations is only recorded if a meter provider is
+// specified, with the attributes specified with WithAttributes and the
+// db.operation of the operations as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithAttributes specifies attributes added to the spans and the metrics of
+// all the operations, e.g. the db.system and db.name of the database:
+//
+//	otelsql.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBNameKey.String("shop"))
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.Attributes = append(cfg.Attributes, attrs...)
+	})
+}
+
+// WithStatementSanitizer specifies a function returning the statement
+// recorded as the db.statement span attribute for a query, e.g.
+// SanitizeStatement to replace its literal values.  An empty statement is
+// not recorded.  If none is specified, the queries are recorded as is.
+func WithStatementSanitizer(sanitizer StatementSanitizer) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.StatementSanitizer = sanitizer
+	})
+}
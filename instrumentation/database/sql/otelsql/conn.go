@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+// conn is an instrumented connection.  It implements the optional
+// interfaces of the connections used by database/sql, falling back to what
+// database/sql does when the wrapped connection does not implement them.
+type conn struct {
+	conn driver.Conn
+	in   *instrumentation
+}
+
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+)
+
+func newConn(c driver.Conn, in *instrumentation) *conn {
+	return &conn{conn: c, in: in}
+}
+
+// Prepare implements driver.Conn.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var s driver.Stmt
+	err := c.in.do(ctx, spanConnPrepare, "PREPARE", query, func(ctx context.Context) error {
+		var err error
+		if cp, ok := c.conn.(driver.ConnPrepareContext); ok {
+			s, err = cp.PrepareContext(ctx, query)
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s, err = c.conn.Prepare(query)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newStmt(s, c.conn, query, c.in), nil
+}
+
+// Close implements driver.Conn.
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+// Begin implements driver.Conn.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var t driver.Tx
+	err := c.in.do(ctx, spanConnBegin, "BEGIN", "", func(ctx context.Context) error {
+		var err error
+		if cb, ok := c.conn.(driver.ConnBeginTx); ok {
+			t, err = cb.BeginTx(ctx, opts)
+			return err
+		}
+		if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+			return errors.New("sql: driver does not support non-default isolation level")
+		}
+		if opts.ReadOnly {
+			return errors.New("sql: driver does not support read-only transactions")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t, err = c.conn.Begin() // nolint: staticcheck  // Fallback of the drivers without BeginTx.
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tx{tx: t, ctx: ctx, in: c.in}, nil
+}
+
+// ExecContext implements driver.ExecerContext.  It returns driver.ErrSkip
+// if the wrapped connection does not execute queries without preparing
+// them.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var run func(context.Context) (driver.Result, error)
+	switch e := c.conn.(type) {
+	case driver.ExecerContext:
+		run = func(ctx context.Context) (driver.Result, error) {
+			return e.ExecContext(ctx, query, args)
+		}
+	case driver.Execer: // nolint: staticcheck  // Fallback of the drivers without ExecerContext.
+		run = func(ctx context.Context) (driver.Result, error) {
+			values, err := namedValueToValue(args)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return e.Exec(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	var res driver.Result
+	err := c.in.do(ctx, spanConnExec, statementOperation(query), query, func(ctx context.Context) error {
+		var err error
+		res, err = run(ctx)
+		return err
+	})
+	return res, err
+}
+
+// QueryContext implements driver.QueryerContext.  It returns driver.ErrSkip
+// if the wrapped connection does not execute queries without preparing
+// them.
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var run func(context.Context) (driver.Rows, error)
+	switch q := c.conn.(type) {
+	case driver.QueryerContext:
+		run = func(ctx context.Context) (driver.Rows, error) {
+			return q.QueryContext(ctx, query, args)
+		}
+	case driver.Queryer: // nolint: staticcheck  // Fallback of the drivers without QueryerContext.
+		run = func(ctx context.Context) (driver.Rows, error) {
+			values, err := namedValueToValue(args)
+			if err != nil {
+				return nil, err
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return q.Query(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	var rows driver.Rows
+	err := c.in.do(ctx, spanConnQuery, statementOperation(query), query, func(ctx context.Context) error {
+		var err error
+		rows, err = run(ctx)
+		return err
+	})
+	return rows, err
+}
+
+// Ping implements driver.Pinger.
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession implements driver.SessionResetter.
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid implements driver.Validator.
+func (c *conn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(c.conn, nv)
+}
+
+// checkNamedValue checks nv with checker if it implements
+// driver.NamedValueChecker, or returns driver.ErrSkip for database/sql to
+// use the default conversion.
+func checkNamedValue(checker interface{}, nv *driver.NamedValue) error {
+	if nvc, ok := checker.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// namedValueToValue returns the values of the arguments of a driver which
+// does not support named arguments, as database/sql does.
+func namedValueToValue(named []driver.NamedValue) ([]driver.Value, error) {
+	args := make([]driver.Value, len(named))
+	for n, param := range named {
+		if len(param.Name) > 0 {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		args[n] = param.Value
+	}
+	return args, nil
+}
+
+// tx is an instrumented transaction.  Its commit and rollback are traced as
+// children of the span of the context it was begun with.
+type tx struct {
+	tx  driver.Tx
+	ctx context.Context
+	in  *instrumentation
+}
+
+var _ driver.Tx = (*tx)(nil)
+
+// Commit implements driver.Tx.
+func (t *tx) Commit() error {
+	return t.in.do(t.ctx, spanTxCommit, "COMMIT", "", func(context.Context) error {
+		return t.tx.Commit()
+	})
+}
+
+// Rollback implements driver.Tx.
+func (t *tx) Rollback() error {
+	return t.in.do(t.ctx, spanTxRollback, "ROLLBACK", "", func(context.Context) error {
+		return t.tx.Rollback()
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelsql instruments the database/sql package.
+//
+// The queries, executions, preparations, and transactions of the
+// connections of a database are traced, and their duration is measured if
+// a meter provider is specified, when the database is opened with Open or
+// OpenDB instead of sql.Open or sql.OpenDB:
+//
+//	db, err := otelsql.Open("postgres", dsn, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
+//
+// or when it uses a driver wrapped with WrapDriver.
+//
+// The spans record the SQL statements as the db.statement attribute, which
+// can be sanitized with WithStatementSanitizer, and the first keyword of the
+// statements, e.g. "SELECT", as the db.operation attribute.  The contexts
+// passed to the methods of sql.DB, sql.Conn, sql.Stmt, and sql.Tx are the
+// parents of the spans; the commits and rollbacks of the transactions are
+// children of the span of the context they were begun with.  The
+// operations the driver skips with driver.ErrSkip, which database/sql then
+// runs another way, are not recorded.
+//
+// The metrics of the connections of a database, observed from its
+// sql.DBStats, are recorded with RecordStats, whether it is opened with Open
+// or sql.Open.
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+)
+
+// Open opens a database specified by the driver name and the data source
+// name, as sql.Open, whose connections are instrumented.
+func Open(driverName, dataSourceName string, opts ...Option) (*sql.DB, error) {
+	// The driver registered with the name is only available through a
+	// sql.DB, which does not connect to the database until it is used.
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	od := newDriver(d, newConfig(opts...))
+	c, err := od.OpenConnector(dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(c), nil
+}
+
+// OpenDB opens a database using the connector, as sql.OpenDB, whose
+// connections are instrumented.
+func OpenDB(c driver.Connector, opts ...Option) *sql.DB {
+	d := newDriver(c.Driver(), newConfig(opts...))
+	return sql.OpenDB(&connector{connector: c, driver: d})
+}
+
+// WrapDriver returns a driver whose connections are instrumented, e.g. to be
+// registered with sql.Register.
+func WrapDriver(d driver.Driver, opts ...Option) driver.Driver {
+	return newDriver(d, newConfig(opts...))
+}
+
+// otelDriver is a driver whose connections are instrumented.
+type otelDriver struct {
+	driver driver.Driver
+	in     *instrumentation
+}
+
+var (
+	_ driver.Driver        = (*otelDriver)(nil)
+	_ driver.DriverContext = (*otelDriver)(nil)
+)
+
+func newDriver(d driver.Driver, cfg *config) *otelDriver {
+	return &otelDriver{driver: d, in: newInstrumentation(cfg)}
+}
+
+// Open implements driver.Driver.
+func (d *otelDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(c, d.in), nil
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *otelDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &connector{connector: c, driver: d}, nil
+	}
+	return &connector{connector: dsnConnector{dsn: name, driver: d.driver}, driver: d}, nil
+}
+
+// connector is a connector whose connections are instrumented.
+type connector struct {
+	connector driver.Connector
+	driver    *otelDriver
+}
+
+var (
+	_ driver.Connector = (*connector)(nil)
+	_ io.Closer        = (*connector)(nil)
+)
+
+// Connect implements driver.Connector.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(conn, c.driver.in), nil
+}
+
+// Driver implements driver.Connector.
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Close closes the wrapped connector if it implements io.Closer, as done
+// by sql.DB.Close.
+func (c *connector) Close() error {
+	if closer, ok := c.connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// dsnConnector is the connector of a driver which does not implement
+// driver.DriverContext, as used by sql.Open.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,14 @@
+module go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql
+
+go 1.17
+
+require (
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
+
+require (
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+)
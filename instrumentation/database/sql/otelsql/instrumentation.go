@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+// Names of the spans of the operations.
+const (
+	spanConnQuery   = "sql.conn.query"
+	spanConnExec    = "sql.conn.exec"
+	spanConnPrepare = "sql.conn.prepare"
+	spanConnBegin   = "sql.conn.begin"
+	spanStmtQuery   = "sql.stmt.query"
+	spanStmtExec    = "sql.stmt.exec"
+	spanTxCommit    = "sql.tx.commit"
+	spanTxRollback  = "sql.tx.rollback"
+)
+
+// instrumentation traces and measures the operations of the connections
+// of an instrumented driver.
+type instrumentation struct {
+	tracer    trace.Tracer
+	metrics   *clientMetrics
+	attrs     []attribute.KeyValue
+	sanitizer StatementSanitizer
+}
+
+func newInstrumentation(cfg *config) *instrumentation {
+	return &instrumentation{
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		metrics:   newClientMetrics(cfg.MeterProvider),
+		attrs:     cfg.Attributes,
+		sanitizer: cfg.StatementSanitizer,
+	}
+}
+
+// do runs the operation f and records it in a span, child of the span of
+// ctx, with the name, and measures its duration.  The operation, e.g.
+// "SELECT", is recorded as the db.operation attribute and the query, if
+// any, as the db.statement attribute.
+//
+// f returning driver.ErrSkip makes database/sql fall back to another
+// operation, which is recorded instead.  The span is therefore only
+// started, with the start time of f, once f returned another result, and
+// f is run with ctx itself.
+func (in *instrumentation) do(ctx context.Context, name, operation, query string, f func(context.Context) error) error {
+	start := time.Now()
+	err := f(ctx)
+	if errors.Is(err, driver.ErrSkip) {
+		return err
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(in.attrs)+1)
+	attrs = append(attrs, in.attrs...)
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationKey.String(operation))
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(attrs...),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+	}
+	if statement := in.statement(query); statement != "" {
+		opts = append(opts, trace.WithAttributes(semconv.DBStatementKey.String(statement)))
+	}
+
+	ctx, span := in.tracer.Start(ctx, name, opts...)
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	in.metrics.record(ctx, start, attrs)
+	return err
+}
+
+// statement returns the statement of the query recorded in the spans.
+func (in *instrumentation) statement(query string) string {
+	if in.sanitizer == nil || query == "" {
+		return query
+	}
+	return in.sanitizer(query)
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"strings"
+)
+
+// SanitizeStatement returns query with its string and numeric literals
+// replaced by '?', e.g. "SELECT * FROM users WHERE name = ? AND age > ?"
+// for "SELECT * FROM users WHERE name = 'bob' AND age > 42".  Quoted
+// identifiers, placeholders, and comments are kept.
+func SanitizeStatement(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'':
+			// A string literal, whose quotes are escaped by doubling them.
+			i = skip(query, i+1, "'")
+			for i < len(query) && query[i] == '\'' {
+				i = skip(query, i+1, "'")
+			}
+			b.WriteByte('?')
+		case c == '"' || c == '`':
+			// A quoted identifier.
+			end := skip(query, i+1, string(c))
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "--"):
+			end := skip(query, i+2, "\n")
+			b.WriteString(query[i:end])
+			i = end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := skip(query, i+2, "*/")
+			b.WriteString(query[i:end])
+			i = end
+		case isDigit(c) && (i == 0 || !isIdentifierByte(query[i-1])):
+			// A numeric literal, not part of an identifier or of a
+			// placeholder such as $1.
+			for i++; i < len(query); i++ {
+				c := query[i]
+				exponentSign := (c == '+' || c == '-') && (query[i-1] == 'e' || query[i-1] == 'E')
+				if !isLetter(c) && !isDigit(c) && c != '.' && !exponentSign {
+					break
+				}
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skip returns the index following the first occurrence of end in query
+// from the index i, or len(query) if end does not occur.
+func skip(query string, i int, end string) int {
+	j := strings.Index(query[i:], end)
+	if j < 0 {
+		return len(query)
+	}
+	return i + j + len(end)
+}
+
+// statementOperation returns the SQL keyword the query starts with,
+// uppercased, e.g. "SELECT", or "" if it does not start with a keyword.
+func statementOperation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := 0
+	for end < len(query) && isLetter(query[end]) {
+		end++
+	}
+	return strings.ToUpper(query[:end])
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentifierByte(c byte) bool {
+	return isLetter(c) || isDigit(c) || c == '_' || c == '$' || c == '@' || c == ':' || c >= 0x80
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Connection pool metrics.
+const (
+	ClientConnectionsUsage             = "db.client.connections.usage"                // Number of the connections per state
+	ClientConnectionsMax               = "db.client.connections.max"                  // Maximum number of the open connections
+	ClientConnectionsWaits             = "db.client.connections.waits"                // Number of the connections waited for
+	ClientConnectionsWaitTime          = "db.client.connections.wait_time"            // Total duration of the waits, milliseconds
+	ClientConnectionsMaxIdleClosed     = "db.client.connections.max_idle_closed"      // Number of the connections closed by SetMaxIdleConns
+	ClientConnectionsMaxIdleTimeClosed = "db.client.connections.max_idle_time_closed" // Number of the connections closed by SetConnMaxIdleTime
+	ClientConnectionsMaxLifetimeClosed = "db.client.connections.max_lifetime_closed"  // Number of the connections closed by SetConnMaxLifetime
+)
+
+// ConnectionStateKey is the state of the connections, "idle" or "used", of
+// the db.client.connections.usage metric.
+const ConnectionStateKey = attribute.Key("state")
+
+// RecordStats records the metrics of the connections of db, observed from
+// its sql.DBStats when the metrics are collected, with the attributes
+// specified with WithAttributes, e.g. the db.system of the database, as
+// attributes.  The global meter provider is used if none is specified.
+//
+// The database does not have to be opened with Open or OpenDB:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	if err != nil {
+//		return err
+//	}
+//	err = otelsql.RecordStats(db, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
+func RecordStats(db *sql.DB, opts ...Option) error {
+	cfg := newConfig(opts...)
+	mp := cfg.MeterProvider
+	if mp == nil {
+		mp = global.MeterProvider()
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	usage, err := meter.AsyncInt64().UpDownCounter(
+		ClientConnectionsUsage,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections per state"),
+	)
+	if err != nil {
+		return err
+	}
+	maxOpen, err := meter.AsyncInt64().UpDownCounter(
+		ClientConnectionsMax,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Maximum number of the open connections, 0 if unlimited"),
+	)
+	if err != nil {
+		return err
+	}
+	waits, err := meter.AsyncInt64().Counter(
+		ClientConnectionsWaits,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections waited for"),
+	)
+	if err != nil {
+		return err
+	}
+	waitTime, err := meter.AsyncInt64().Counter(
+		ClientConnectionsWaitTime,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Total duration of the waits for a connection"),
+	)
+	if err != nil {
+		return err
+	}
+	maxIdleClosed, err := meter.AsyncInt64().Counter(
+		ClientConnectionsMaxIdleClosed,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections closed because of the maximum number of idle connections"),
+	)
+	if err != nil {
+		return err
+	}
+	maxIdleTimeClosed, err := meter.AsyncInt64().Counter(
+		ClientConnectionsMaxIdleTimeClosed,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections closed because of their maximum idle time"),
+	)
+	if err != nil {
+		return err
+	}
+	maxLifetimeClosed, err := meter.AsyncInt64().Counter(
+		ClientConnectionsMaxLifetimeClosed,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections closed because of their maximum lifetime"),
+	)
+	if err != nil {
+		return err
+	}
+
+	attrs := cfg.Attributes
+	idleAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("idle"))
+	usedAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("used"))
+	instruments := []instrument.Asynchronous{usage, maxOpen, waits, waitTime, maxIdleClosed, maxIdleTimeClosed, maxLifetimeClosed}
+	return meter.RegisterCallback(instruments, func(ctx context.Context) {
+		stats := db.Stats()
+		usage.Observe(ctx, int64(stats.Idle), idleAttrs...)
+		usage.Observe(ctx, int64(stats.InUse), usedAttrs...)
+		maxOpen.Observe(ctx, int64(stats.MaxOpenConnections), attrs...)
+		waits.Observe(ctx, stats.WaitCount, attrs...)
+		waitTime.Observe(ctx, stats.WaitDuration.Milliseconds(), attrs...)
+		maxIdleClosed.Observe(ctx, stats.MaxIdleClosed, attrs...)
+		maxIdleTimeClosed.Observe(ctx, stats.MaxIdleTimeClosed, attrs...)
+		maxLifetimeClosed.Observe(ctx, stats.MaxLifetimeClosed, attrs...)
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsql // import "go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// stmt is an instrumented prepared statement.
+type stmt struct {
+	stmt  driver.Stmt
+	conn  driver.Conn
+	query string
+	in    *instrumentation
+}
+
+var (
+	_ driver.Stmt              = (*stmt)(nil)
+	_ driver.StmtExecContext   = (*stmt)(nil)
+	_ driver.StmtQueryContext  = (*stmt)(nil)
+	_ driver.NamedValueChecker = (*stmt)(nil)
+)
+
+// columnConverterStmt is an instrumented prepared statement whose wrapped
+// statement implements driver.ColumnConverter, which changes how
+// database/sql converts the arguments.
+type columnConverterStmt struct {
+	*stmt
+}
+
+var _ driver.ColumnConverter = columnConverterStmt{} // nolint: staticcheck  // Implemented by some drivers.
+
+// newStmt returns the instrumented statement s of the query, prepared by
+// the connection c.
+func newStmt(s driver.Stmt, c driver.Conn, query string, in *instrumentation) driver.Stmt {
+	st := &stmt{stmt: s, conn: c, query: query, in: in}
+	if _, ok := s.(driver.ColumnConverter); ok { // nolint: staticcheck  // Implemented by some drivers.
+		return columnConverterStmt{st}
+	}
+	return st
+}
+
+// Close implements driver.Stmt.
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+// NumInput implements driver.Stmt.
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+// Exec implements driver.Stmt.
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.stmt.Exec(args) // nolint: staticcheck  // Not used by database/sql.
+}
+
+// Query implements driver.Stmt.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.stmt.Query(args) // nolint: staticcheck  // Not used by database/sql.
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	var res driver.Result
+	err := s.in.do(ctx, spanStmtExec, statementOperation(s.query), s.query, func(ctx context.Context) error {
+		var err error
+		if se, ok := s.stmt.(driver.StmtExecContext); ok {
+			res, err = se.ExecContext(ctx, args)
+			return err
+		}
+		values, err := namedValueToValue(args)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res, err = s.stmt.Exec(values) // nolint: staticcheck  // Fallback of the drivers without StmtExecContext.
+		return err
+	})
+	return res, err
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := s.in.do(ctx, spanStmtQuery, statementOperation(s.query), s.query, func(ctx context.Context) error {
+		var err error
+		if sq, ok := s.stmt.(driver.StmtQueryContext); ok {
+			rows, err = sq.QueryContext(ctx, args)
+			return err
+		}
+		values, err := namedValueToValue(args)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err = s.stmt.Query(values) // nolint: staticcheck  // Fallback of the drivers without StmtQueryContext.
+		return err
+	})
+	return rows, err
+}
+
+// CheckNamedValue implements driver.NamedValueChecker.  As database/sql
+// only uses the checker of the connection if the statement does not
+// implement driver.NamedValueChecker, the checker of the wrapped connection
+// is used if the wrapped statement does not implement it.
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checkNamedValue(s.stmt, nv)
+	}
+	return checkNamedValue(s.conn, nv)
+}
+
+// ColumnConverter implements driver.ColumnConverter.
+func (s columnConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.stmt.stmt.(driver.ColumnConverter).ColumnConverter(idx) // nolint: staticcheck  // Implemented by some drivers.
+}
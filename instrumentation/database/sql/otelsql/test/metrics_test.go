@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	db := otelsql.OpenDB(
+		fakeConnector{driver: fakeDriver{}},
+		otelsql.WithMeterProvider(mp),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	defer db.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := db.ExecContext(ctx, "INSERT INTO t VALUES (1)")
+		require.NoError(t, err)
+	}
+	_, err := db.ExecContext(ctx, "FAIL")
+	require.Error(t, err)
+	rows, err := db.QueryContext(ctx, "SELECT n FROM t")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	insert := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String("INSERT"),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelsql.ClientDuration, insert)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(2), duration.Count)
+
+	// The failed operations are measured too.
+	fail := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String("FAIL"),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelsql.ClientDuration, fail)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+
+	selectAttrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String("SELECT"),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelsql.ClientDuration, selectAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestRecordStats(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	db := sql.OpenDB(fakeConnector{driver: fakeDriver{}})
+	defer db.Close()
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(1)
+	require.NoError(t, otelsql.RecordStats(
+		db,
+		otelsql.WithMeterProvider(mp),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+	))
+
+	// One connection stays in use, one is idle, and one is closed as the
+	// maximum number of idle connections is reached.
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+	require.NoError(t, conns[1].Close())
+	require.NoError(t, conns[2].Close())
+	defer conns[0].Close()
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{semconv.DBSystemPostgreSQL}
+	for state, want := range map[string]int64{"idle": 1, "used": 1} {
+		usage, err := exp.GetByNameAndAttributes(
+			otelsql.ClientConnectionsUsage,
+			append(attrs[:len(attrs):len(attrs)], otelsql.ConnectionStateKey.String(state)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, want, usage.Sum.AsInt64(), state)
+	}
+	for name, want := range map[string]int64{
+		otelsql.ClientConnectionsMax:               5,
+		otelsql.ClientConnectionsWaits:             0,
+		otelsql.ClientConnectionsWaitTime:          0,
+		otelsql.ClientConnectionsMaxIdleClosed:     1,
+		otelsql.ClientConnectionsMaxIdleTimeClosed: 0,
+		otelsql.ClientConnectionsMaxLifetimeClosed: 0,
+	} {
+		record, err := exp.GetByNameAndAttributes(name, attrs)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, record.Sum.AsInt64(), name)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/database/sql/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	sql.Register("otelsql-fake", fakeDriver{})
+}
+
+// openDB returns a database of the fake connections, traced by the
+// returned recorder, and the context of a span parent of the spans of the
+// operations.
+func openDB(t *testing.T, minimal bool, opts ...otelsql.Option) (*sql.DB, *tracetest.SpanRecorder, context.Context) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts = append([]otelsql.Option{otelsql.WithTracerProvider(provider)}, opts...)
+
+	db := otelsql.OpenDB(fakeConnector{driver: fakeDriver{minimal: minimal}}, opts...)
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	t.Cleanup(func() { parent.End() })
+	return db, sr, ctx
+}
+
+func names(spans []sdktrace.ReadOnlySpan) []string {
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+func TestQuery(t *testing.T) {
+	db, sr, ctx := openDB(t, false, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
+
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT n FROM t WHERE id = $1", 42).Scan(&n))
+	assert.Equal(t, 1, n)
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.query"}, names(spans))
+	span := spans[0]
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBSystemPostgreSQL)
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("SELECT"))
+	assert.Contains(t, attrs, semconv.DBStatementKey.String("SELECT n FROM t WHERE id = $1"))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestExec(t *testing.T) {
+	db, sr, ctx := openDB(t, false)
+
+	_, err := db.ExecContext(ctx, "INSERT INTO t VALUES (1)")
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.exec"}, names(spans))
+	assert.Contains(t, spans[0].Attributes(), semconv.DBOperationKey.String("INSERT"))
+}
+
+func TestExecSkip(t *testing.T) {
+	db, sr, ctx := openDB(t, false)
+
+	// database/sql falls back to a prepared statement when the connection
+	// skips the execution, only the operations run are recorded.
+	_, err := db.ExecContext(ctx, "SKIP")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec"}, names(sr.Ended()))
+}
+
+func TestPreparedStatements(t *testing.T) {
+	// The minimal connections only execute prepared statements.
+	db, sr, ctx := openDB(t, true)
+
+	_, err := db.ExecContext(ctx, "UPDATE t SET n = ?", customValue{n: 2})
+	require.NoError(t, err)
+	rows, err := db.QueryContext(ctx, "SELECT n FROM t")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec", "sql.conn.prepare", "sql.stmt.query"}, names(spans))
+	assert.Contains(t, spans[0].Attributes(), semconv.DBOperationKey.String("PREPARE"))
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String("UPDATE t SET n = ?"))
+	assert.Contains(t, spans[1].Attributes(), semconv.DBOperationKey.String("UPDATE"))
+	assert.Contains(t, spans[1].Attributes(), semconv.DBStatementKey.String("UPDATE t SET n = ?"))
+	assert.Contains(t, spans[3].Attributes(), semconv.DBOperationKey.String("SELECT"))
+}
+
+func TestStmt(t *testing.T) {
+	db, sr, ctx := openDB(t, false)
+
+	stmt, err := db.PrepareContext(ctx, "DELETE FROM t WHERE id = ?")
+	require.NoError(t, err)
+	_, err = stmt.ExecContext(ctx, customValue{n: 1})
+	require.NoError(t, err)
+	require.NoError(t, stmt.Close())
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.prepare", "sql.stmt.exec"}, names(spans))
+	assert.Contains(t, spans[1].Attributes(), semconv.DBOperationKey.String("DELETE"))
+}
+
+func TestTx(t *testing.T) {
+	for _, minimal := range []bool{false, true} {
+		db, sr, ctx := openDB(t, minimal)
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = tx.ExecContext(ctx, "INSERT INTO t VALUES (1)")
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		tx, err = db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, tx.Rollback())
+
+		spans := sr.Ended()
+		want := []string{"sql.conn.begin", "sql.conn.exec", "sql.tx.commit", "sql.conn.begin", "sql.tx.rollback"}
+		if minimal {
+			want = []string{"sql.conn.begin", "sql.conn.prepare", "sql.stmt.exec", "sql.tx.commit", "sql.conn.begin", "sql.tx.rollback"}
+		}
+		require.Equal(t, want, names(spans))
+		for _, span := range spans {
+			assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+		}
+		assert.Contains(t, spans[0].Attributes(), semconv.DBOperationKey.String("BEGIN"))
+		assert.Contains(t, spans[len(spans)-1].Attributes(), semconv.DBOperationKey.String("ROLLBACK"))
+	}
+}
+
+func TestTxOptions(t *testing.T) {
+	// The minimal connections do not support transaction options.
+	db, sr, ctx := openDB(t, true)
+
+	_, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	require.Error(t, err)
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.begin"}, names(spans))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestError(t *testing.T) {
+	for _, minimal := range []bool{false, true} {
+		db, sr, ctx := openDB(t, minimal)
+
+		_, err := db.ExecContext(ctx, "FAIL")
+		require.ErrorIs(t, err, errFail)
+
+		spans := sr.Ended()
+		require.NotEmpty(t, spans)
+		span := spans[len(spans)-1]
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, errFail.Error(), span.Status().Description)
+		require.Len(t, span.Events(), 1)
+		assert.Equal(t, "exception", span.Events()[0].Name)
+	}
+}
+
+func TestStatementSanitizer(t *testing.T) {
+	db, sr, ctx := openDB(t, false, otelsql.WithStatementSanitizer(otelsql.SanitizeStatement))
+
+	_, err := db.ExecContext(ctx, "UPDATE t SET name = 'bob' WHERE id = 42")
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String("UPDATE t SET name = ? WHERE id = ?"))
+}
+
+func TestSanitizeStatement(t *testing.T) {
+	for _, tc := range []struct {
+		query, want string
+	}{
+		{"SELECT * FROM t", "SELECT * FROM t"},
+		{"SELECT * FROM t WHERE name = 'bob' AND age > 42", "SELECT * FROM t WHERE name = ? AND age > ?"},
+		{"SELECT 'it''s', 1.5e-3, -7", "SELECT ?, ?, -?"},
+		{"SELECT t1.c2 FROM t1 WHERE id = $1 AND n = :2 AND m = @p3", "SELECT t1.c2 FROM t1 WHERE id = $1 AND n = :2 AND m = @p3"},
+		{`SELECT "col 1", ` + "`col2`" + ` FROM "t'1"`, `SELECT "col 1", ` + "`col2`" + ` FROM "t'1"`},
+		{"SELECT 1 -- the 2 first\n, 3 /* 4 */", "SELECT ? -- the 2 first\n, ? /* 4 */"},
+		{"INSERT INTO t VALUES (0x1F, 'unterminated", "INSERT INTO t VALUES (?, ?"},
+	} {
+		assert.Equal(t, tc.want, otelsql.SanitizeStatement(tc.query), tc.query)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	db, err := otelsql.Open("otelsql-fake", "", otelsql.WithTracerProvider(provider))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Ping())
+	_, err = db.Exec("INSERT INTO t VALUES (1)")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sql.conn.exec"}, names(sr.Ended()))
+
+	_, err = otelsql.Open("unknown", "")
+	assert.Error(t, err)
+}
+
+func TestWrapDriver(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	sql.Register("otelsql-wrapped", otelsql.WrapDriver(fakeDriver{}, otelsql.WithTracerProvider(provider)))
+
+	db, err := sql.Open("otelsql-wrapped", "")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO t VALUES (1)")
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Equal(t, []string{"sql.conn.exec"}, names(spans))
+	assert.NotContains(t, spans[0].Attributes(), attribute.Key("db.system"))
+}
@@ -34,7 +34,7 @@ func (pc *partitionConsumer) Messages() <-chan *sarama.ConsumerMessage {
 func WrapPartitionConsumer(pc sarama.PartitionConsumer, opts ...Option) sarama.PartitionConsumer {
 	cfg := newConfig(opts...)
 
-	dispatcher := newConsumerMessagesDispatcherWrapper(pc, cfg)
+	dispatcher := newConsumerMessagesDispatcherWrapper(pc, cfg, newConsumerMetrics(cfg.MeterProvider))
 	go dispatcher.Run()
 	wrapped := &partitionConsumer{
 		PartitionConsumer: pc,
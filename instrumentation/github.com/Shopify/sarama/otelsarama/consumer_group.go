@@ -15,37 +15,108 @@
 package otelsarama // import "go.opentelemetry.io/contrib/instrumentation/github.com/Shopify/sarama/otelsarama"
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/Shopify/sarama"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys of the spans of the claims.
+const (
+	// InitialOffsetKey is the offset the consumption of a claim starts at.
+	InitialOffsetKey = attribute.Key("messaging.kafka.initial_offset")
+	// GenerationIDKey is the generation of the consumer group session.
+	GenerationIDKey = attribute.Key("messaging.kafka.consumer_group.generation_id")
 )
 
 type consumerGroupHandler struct {
 	sarama.ConsumerGroupHandler
 
-	cfg config
+	cfg             config
+	consumerMetrics *consumerMetrics
+	metrics         *consumerGroupMetrics
+	claims          *claims
+}
+
+// Setup records the rebalance of the group and wraps the session to
+// measure its commits.
+// It implements parts of `ConsumerGroupHandler`.
+func (h *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	h.metrics.rebalanced(session.Context())
+	return h.ConsumerGroupHandler.Setup(h.wrapSession(session))
+}
+
+// Cleanup wraps the session to measure its commits.
+// It implements parts of `ConsumerGroupHandler`.
+func (h *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	return h.ConsumerGroupHandler.Cleanup(h.wrapSession(session))
 }
 
 // ConsumeClaim wraps the session and claim to add instruments for messages.
+// The claim is traced in a span lasting as long as it is consumed.
 // It implements parts of `ConsumerGroupHandler`.
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingDestinationKindTopic,
+		semconv.MessagingDestinationKey.String(claim.Topic()),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingKafkaPartitionKey.Int64(int64(claim.Partition())),
+		InitialOffsetKey.Int64(claim.InitialOffset()),
+		GenerationIDKey.Int64(int64(session.GenerationID())),
+	}
+	_, span := h.cfg.Tracer.Start(session.Context(), "kafka.claim",
+		trace.WithAttributes(attrs...),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+	)
+	defer span.End()
+
+	h.claims.add(claim)
+	defer h.claims.remove(claim)
+
 	// Wrap claim
-	dispatcher := newConsumerMessagesDispatcherWrapper(claim, h.cfg)
+	dispatcher := newConsumerMessagesDispatcherWrapper(claim, h.cfg, h.consumerMetrics)
 	go dispatcher.Run()
 	claim = &consumerGroupClaim{
 		ConsumerGroupClaim: claim,
 		dispatcher:         dispatcher,
 	}
 
-	return h.ConsumerGroupHandler.ConsumeClaim(session, claim)
+	err := h.ConsumerGroupHandler.ConsumeClaim(h.wrapSession(session), claim)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
 }
 
 // WrapConsumerGroupHandler wraps a sarama.ConsumerGroupHandler causing each received
-// message to be traced.
+// message and each claim to be traced. If a meter provider is specified, the
+// lag of the claimed partitions, the rebalances of the group, and the
+// duration of the commits of the session are measured.
 func WrapConsumerGroupHandler(handler sarama.ConsumerGroupHandler, opts ...Option) sarama.ConsumerGroupHandler {
 	cfg := newConfig(opts...)
+	claims := &claims{m: make(map[topicPartition]*claimState)}
 
 	return &consumerGroupHandler{
 		ConsumerGroupHandler: handler,
 		cfg:                  cfg,
+		consumerMetrics:      newConsumerMetrics(cfg.MeterProvider),
+		metrics:              newConsumerGroupMetrics(cfg.MeterProvider, claims),
+		claims:               claims,
+	}
+}
+
+func (h *consumerGroupHandler) wrapSession(session sarama.ConsumerGroupSession) sarama.ConsumerGroupSession {
+	return &consumerGroupSession{
+		ConsumerGroupSession: session,
+		handler:              h,
 	}
 }
 
@@ -57,3 +128,100 @@ type consumerGroupClaim struct {
 func (c *consumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
 	return c.dispatcher.Messages()
 }
+
+// consumerGroupSession tracks the offsets marked in a session and measures
+// its commits.
+type consumerGroupSession struct {
+	sarama.ConsumerGroupSession
+	handler *consumerGroupHandler
+}
+
+func (s *consumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	s.ConsumerGroupSession.MarkOffset(topic, partition, offset, metadata)
+	s.handler.claims.mark(topic, partition, offset, false)
+}
+
+func (s *consumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	s.ConsumerGroupSession.ResetOffset(topic, partition, offset, metadata)
+	s.handler.claims.mark(topic, partition, offset, true)
+}
+
+func (s *consumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.ConsumerGroupSession.MarkMessage(msg, metadata)
+	s.handler.claims.mark(msg.Topic, msg.Partition, msg.Offset+1, false)
+}
+
+func (s *consumerGroupSession) Commit() {
+	start := time.Now()
+	s.ConsumerGroupSession.Commit()
+	s.handler.metrics.committed(context.Background(), start)
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// claimState is the state of a claim being consumed.
+type claimState struct {
+	claim sarama.ConsumerGroupClaim
+	// offset is the offset marked in the partition, i.e. the offset of the
+	// next message to consume.  It is negative, sarama.OffsetNewest or
+	// sarama.OffsetOldest, until the first offset is marked if the claim
+	// has no committed offset to start from.
+	offset int64
+}
+
+// claims are the claims being consumed by a handler.
+type claims struct {
+	mu sync.Mutex
+	m  map[topicPartition]*claimState
+}
+
+func (c *claims) add(claim sarama.ConsumerGroupClaim) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[topicPartition{claim.Topic(), claim.Partition()}] = &claimState{
+		claim:  claim,
+		offset: claim.InitialOffset(),
+	}
+}
+
+func (c *claims) remove(claim sarama.ConsumerGroupClaim) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.m, topicPartition{claim.Topic(), claim.Partition()})
+}
+
+// mark marks the offset of the partition if it is claimed. Like sarama, the
+// offset is only decreased if reset.
+func (c *claims) mark(topic string, partition int32, offset int64, reset bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state, ok := c.m[topicPartition{topic, partition}]
+	if !ok {
+		return
+	}
+	if reset || offset > state.offset {
+		state.offset = offset
+	}
+}
+
+// each calls f with the lag of each claimed partition: the high watermark
+// of the partition minus the offset marked, which is the offset committed
+// by the session.  The partitions with no offset marked nor committed yet
+// are skipped.
+func (c *claims) each(f func(topic string, partition int32, lag int64)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for tp, state := range c.m {
+		if state.offset < 0 {
+			continue
+		}
+		lag := state.claim.HighWaterMarkOffset() - state.offset
+		if lag < 0 {
+			lag = 0
+		}
+		f(tp.topic, tp.partition, lag)
+	}
+}
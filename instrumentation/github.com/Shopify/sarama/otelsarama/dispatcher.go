@@ -33,14 +33,16 @@ type consumerMessagesDispatcherWrapper struct {
 	d        consumerMessagesDispatcher
 	messages chan *sarama.ConsumerMessage
 
-	cfg config
+	cfg     config
+	metrics *consumerMetrics
 }
 
-func newConsumerMessagesDispatcherWrapper(d consumerMessagesDispatcher, cfg config) *consumerMessagesDispatcherWrapper {
+func newConsumerMessagesDispatcherWrapper(d consumerMessagesDispatcher, cfg config, metrics *consumerMetrics) *consumerMessagesDispatcherWrapper {
 	return &consumerMessagesDispatcherWrapper{
 		d:        d,
 		messages: make(chan *sarama.ConsumerMessage),
 		cfg:      cfg,
+		metrics:  metrics,
 	}
 }
 
@@ -72,6 +74,7 @@ func (w *consumerMessagesDispatcherWrapper) Run() {
 			trace.WithSpanKind(trace.SpanKindConsumer),
 		}
 		newCtx, span := w.cfg.Tracer.Start(parentSpanContext, "kafka.consume", opts...)
+		w.metrics.received(newCtx, msg)
 
 		// Inject current span context, so consumers can use it to propagate span.
 		w.cfg.Propagators.Inject(newCtx, carrier)
@@ -16,6 +16,24 @@
 //
 // The consumer's span will be created as a child of the producer's span.
 //
+// The batches of messages sent with SyncProducer.SendMessages are traced in
+// "kafka.produce_batch" spans linked to the spans of their messages.
+//
+// The partitions claimed by a consumer group handler are traced in "kafka.claim"
+// spans lasting as long as they are consumed.
+//
+// With WithMeterProvider, the wrapped producers and consumers count the
+// messages they produce and consume by topic, and record the size of their
+// keys and values and the production latency. The number of the batches the
+// producers flush and their mean number of messages are observed from the
+// metrics sarama records in the MetricRegistry of the producer Config.
+//
+// The consumer group handlers also report the lag of the claimed
+// partitions, i.e. their high watermark minus the offset marked by the
+// session, the rebalances of the group, and the duration of the commits
+// made with the session. The lag of a partition without committed offset is
+// only reported once an offset is marked.
+//
 // Context propagation only works on Kafka versions higher than 0.11.0.0 which supports record headers.
 // (https://archive.apache.org/dist/kafka/0.11.0.0/RELEASE_NOTES.html)
 //
@@ -0,0 +1,336 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelsarama // import "go.opentelemetry.io/contrib/instrumentation/github.com/Shopify/sarama/otelsarama"
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Producer metrics.
+const (
+	ProducerMessages    = "messaging.kafka.producer.messages"     // Number of the messages sent to the producers
+	ProducerMessageSize = "messaging.kafka.producer.message.size" // Size of the keys and values of the messages sent, bytes
+	ProducerDuration    = "messaging.kafka.producer.duration"     // Duration of the production of the messages, milliseconds
+)
+
+// Producer batch metrics, observed from the metrics sarama records in the
+// MetricRegistry of the producer Config.
+const (
+	ProducerBatches           = "messaging.kafka.producer.batches"             // Number of the batches of messages flushed by the producers
+	ProducerBatchMessageCount = "messaging.kafka.producer.batch.message_count" // Mean number of the messages of the recent batches flushed by the producers
+)
+
+// recordsPerRequest is the histogram sarama updates in its MetricRegistry
+// with the number of the messages of each produce request it sends, i.e.
+// of each batch it flushes.
+const recordsPerRequest = "records-per-request"
+
+// Consumer metrics.
+const (
+	ConsumerMessages    = "messaging.kafka.consumer.messages"     // Number of the messages received by the consumers
+	ConsumerMessageSize = "messaging.kafka.consumer.message.size" // Size of the keys and values of the messages received, bytes
+)
+
+// Consumer group metrics.
+const (
+	ConsumerLag            = "messaging.kafka.consumer.lag"             // Number of the messages of a claimed partition not consumed yet
+	ConsumerRebalances     = "messaging.kafka.consumer.rebalances"      // Number of the rebalances of the consumer group
+	ConsumerCommitDuration = "messaging.kafka.consumer.commit.duration" // Duration of the commits of the offsets, milliseconds
+)
+
+// kafka is the messaging.system of the metrics.
+var kafka = semconv.MessagingSystemKey.String("kafka")
+
+// topicAttrs returns the attributes of the metrics of the messages of the
+// topic.
+func topicAttrs(topic string) []attribute.KeyValue {
+	return []attribute.KeyValue{kafka, semconv.MessagingDestinationKey.String(topic)}
+}
+
+// producerMetrics holds the instruments of a producer. A nil
+// *producerMetrics records nothing.
+type producerMetrics struct {
+	messages syncint64.Counter
+	size     syncint64.Histogram
+	duration syncfloat64.Histogram
+}
+
+// newProducerMetrics returns the instruments of a producer, or nil if the
+// metrics are not enabled.
+func newProducerMetrics(mp metric.MeterProvider) *producerMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   producerMetrics
+		err error
+	)
+	m.messages, err = meter.SyncInt64().Counter(
+		ProducerMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the messages sent to the producers"),
+	)
+	handleErr(err)
+
+	m.size, err = meter.SyncInt64().Histogram(
+		ProducerMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the keys and values of the messages sent"),
+	)
+	handleErr(err)
+
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ProducerDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the production of the messages"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+// sent records the message sent to the producer.
+func (m *producerMetrics) sent(ctx context.Context, msg *sarama.ProducerMessage) {
+	if m == nil {
+		return
+	}
+	attrs := topicAttrs(msg.Topic)
+	m.messages.Add(ctx, 1, attrs...)
+	m.size.Record(ctx, int64(encodedLength(msg.Key)+encodedLength(msg.Value)), attrs...)
+}
+
+// produced records the duration since start of the production of a message
+// of the topic.
+func (m *producerMetrics) produced(ctx context.Context, topic string, start time.Time) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	m.duration.Record(ctx, elapsed, topicAttrs(topic)...)
+}
+
+// registerBatchMetrics observes the batches flushed by the producers
+// created with the saramaConfig from the histogram of their sizes sarama
+// records in its MetricRegistry. The batches are attributed by the client
+// ID of the Config. It does nothing if the metrics are not enabled.
+func registerBatchMetrics(mp metric.MeterProvider, saramaConfig *sarama.Config) {
+	if mp == nil || saramaConfig.MetricRegistry == nil {
+		return
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		batches      asyncint64.Counter
+		messageCount asyncfloat64.Gauge
+		err          error
+	)
+	batches, err = meter.AsyncInt64().Counter(
+		ProducerBatches,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the batches of messages flushed by the producers"),
+	)
+	handleErr(err)
+
+	messageCount, err = meter.AsyncFloat64().Gauge(
+		ProducerBatchMessageCount,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Mean number of the messages of the recent batches flushed by the producers"),
+	)
+	handleErr(err)
+
+	if batches == nil || messageCount == nil {
+		return
+	}
+	registry := saramaConfig.MetricRegistry
+	attrs := []attribute.KeyValue{kafka, semconv.MessagingKafkaClientIDKey.String(saramaConfig.ClientID)}
+	err = meter.RegisterCallback([]instrument.Asynchronous{batches, messageCount}, func(ctx context.Context) {
+		// The histogram is registered once the first batch is flushed.
+		h, ok := registry.Get(recordsPerRequest).(interface {
+			Count() int64
+			Mean() float64
+		})
+		if !ok {
+			return
+		}
+		batches.Observe(ctx, h.Count(), attrs...)
+		if h.Count() > 0 {
+			messageCount.Observe(ctx, h.Mean(), attrs...)
+		}
+	})
+	handleErr(err)
+}
+
+// encodedLength returns the length of the encoded e, 0 if e is nil.
+func encodedLength(e sarama.Encoder) int {
+	if e == nil {
+		return 0
+	}
+	return e.Length()
+}
+
+// consumerMetrics holds the instruments of a consumer. A nil
+// *consumerMetrics records nothing.
+type consumerMetrics struct {
+	messages syncint64.Counter
+	size     syncint64.Histogram
+}
+
+// newConsumerMetrics returns the instruments of a consumer, or nil if the
+// metrics are not enabled.
+func newConsumerMetrics(mp metric.MeterProvider) *consumerMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   consumerMetrics
+		err error
+	)
+	m.messages, err = meter.SyncInt64().Counter(
+		ConsumerMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the messages received by the consumers"),
+	)
+	handleErr(err)
+
+	m.size, err = meter.SyncInt64().Histogram(
+		ConsumerMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the keys and values of the messages received"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+// received records the message received by the consumer.
+func (m *consumerMetrics) received(ctx context.Context, msg *sarama.ConsumerMessage) {
+	if m == nil {
+		return
+	}
+	attrs := topicAttrs(msg.Topic)
+	m.messages.Add(ctx, 1, attrs...)
+	m.size.Record(ctx, int64(len(msg.Key)+len(msg.Value)), attrs...)
+}
+
+// consumerGroupMetrics holds the instruments of a consumer group handler.
+// A nil *consumerGroupMetrics records nothing.
+type consumerGroupMetrics struct {
+	lag            asyncint64.Gauge
+	rebalances     syncint64.Counter
+	commitDuration syncfloat64.Histogram
+}
+
+// newConsumerGroupMetrics returns the instruments of a consumer group
+// handler whose claims are observed to report their lag, or nil if the
+// metrics are not enabled.
+func newConsumerGroupMetrics(mp metric.MeterProvider, claims *claims) *consumerGroupMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   consumerGroupMetrics
+		err error
+	)
+	m.lag, err = meter.AsyncInt64().Gauge(
+		ConsumerLag,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the messages of a claimed partition not consumed yet"),
+	)
+	handleErr(err)
+
+	m.rebalances, err = meter.SyncInt64().Counter(
+		ConsumerRebalances,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the rebalances of the consumer group"),
+	)
+	handleErr(err)
+
+	m.commitDuration, err = meter.SyncFloat64().Histogram(
+		ConsumerCommitDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the commits of the offsets"),
+	)
+	handleErr(err)
+
+	if m.lag != nil {
+		err = meter.RegisterCallback([]instrument.Asynchronous{m.lag}, func(ctx context.Context) {
+			claims.each(func(topic string, partition int32, lag int64) {
+				m.lag.Observe(ctx, lag,
+					kafka,
+					semconv.MessagingDestinationKey.String(topic),
+					semconv.MessagingKafkaPartitionKey.Int64(int64(partition)),
+				)
+			})
+		})
+		handleErr(err)
+	}
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// rebalanced records a rebalance of the consumer group.
+func (m *consumerGroupMetrics) rebalanced(ctx context.Context) {
+	if m == nil {
+		return
+	}
+	m.rebalances.Add(ctx, 1, kafka)
+}
+
+// committed records the duration since start of a commit.
+func (m *consumerGroupMetrics) committed(ctx context.Context, start time.Time) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	m.commitDuration.Record(ctx, elapsed, kafka)
+}
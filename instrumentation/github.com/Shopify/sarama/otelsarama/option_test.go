@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -45,6 +46,7 @@ func (fakeTracer) Start(ctx context.Context, spanName string, opts ...trace.Span
 
 func TestNewConfig(t *testing.T) {
 	tp := fakeTracerProvider{}
+	mp := metric.NewNoopMeterProvider()
 	prop := propagation.NewCompositeTextMapPropagator()
 
 	testCases := []struct {
@@ -74,6 +76,18 @@ func TestNewConfig(t *testing.T) {
 				Propagators:    otel.GetTextMapPropagator(),
 			},
 		},
+		{
+			name: "with meter provider",
+			opts: []Option{
+				WithMeterProvider(mp),
+			},
+			expected: config{
+				TracerProvider: otel.GetTracerProvider(),
+				MeterProvider:  mp,
+				Tracer:         otel.GetTracerProvider().Tracer(defaultTracerName, trace.WithInstrumentationVersion(SemVersion())),
+				Propagators:    otel.GetTextMapPropagator(),
+			},
+		},
 		{
 			name: "with propagators",
 			opts: []Option{
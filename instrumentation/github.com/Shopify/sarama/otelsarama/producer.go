@@ -18,6 +18,7 @@ import (
 	"context"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 
@@ -28,47 +29,69 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// BatchMessageCountKey is the number of the messages of the batch of a
+// "kafka.produce_batch" span.
+const BatchMessageCountKey = attribute.Key("messaging.batch.message_count")
+
 type syncProducer struct {
 	sarama.SyncProducer
 	cfg          config
 	saramaConfig *sarama.Config
+	metrics      *producerMetrics
 }
 
 // SendMessage calls sarama.SyncProducer.SendMessage and traces the request.
 func (p *syncProducer) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
 	span := startProducerSpan(p.cfg, p.saramaConfig.Version, msg)
+	p.metrics.sent(context.Background(), msg)
+	start := time.Now()
 	partition, offset, err = p.SyncProducer.SendMessage(msg)
+	p.metrics.produced(context.Background(), msg.Topic, start)
 	finishProducerSpan(span, partition, offset, err)
 	return partition, offset, err
 }
 
 // SendMessages calls sarama.SyncProducer.SendMessages and traces the requests.
+// The flush of the batch is traced in a span linked to the spans of the
+// messages.
 func (p *syncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
 	// Although there's only one call made to the SyncProducer, the messages are
 	// treated individually, so we create a span for each one
 	spans := make([]trace.Span, len(msgs))
 	for i, msg := range msgs {
 		spans[i] = startProducerSpan(p.cfg, p.saramaConfig.Version, msg)
+		p.metrics.sent(context.Background(), msg)
 	}
+	batchSpan := startBatchSpan(p.cfg, msgs, spans)
+	start := time.Now()
 	err := p.SyncProducer.SendMessages(msgs)
 	for i, span := range spans {
+		p.metrics.produced(context.Background(), msgs[i].Topic, start)
 		finishProducerSpan(span, msgs[i].Partition, msgs[i].Offset, err)
 	}
+	if err != nil {
+		batchSpan.SetStatus(codes.Error, err.Error())
+	}
+	batchSpan.End()
 	return err
 }
 
 // WrapSyncProducer wraps a sarama.SyncProducer so that all produced messages
-// are traced.
+// are traced. If a meter provider is specified, the batches flushed by the
+// producer are measured like the ones of a wrapped AsyncProducer.
 func WrapSyncProducer(saramaConfig *sarama.Config, producer sarama.SyncProducer, opts ...Option) sarama.SyncProducer {
 	cfg := newConfig(opts...)
 	if saramaConfig == nil {
 		saramaConfig = sarama.NewConfig()
+	} else {
+		registerBatchMetrics(cfg.MeterProvider, saramaConfig)
 	}
 
 	return &syncProducer{
 		SyncProducer: producer,
 		cfg:          cfg,
 		saramaConfig: saramaConfig,
+		metrics:      newProducerMetrics(cfg.MeterProvider),
 	}
 }
 
@@ -116,6 +139,7 @@ func (p *asyncProducer) Close() error {
 
 type producerMessageContext struct {
 	span           trace.Span
+	start          time.Time
 	metadataBackup interface{}
 }
 
@@ -125,11 +149,19 @@ type producerMessageContext struct {
 //
 // If `Return.Successes` is false, there is no way to know partition and offset of
 // the message.
+//
+// The batches the messages are flushed in are internal to sarama, so they
+// are not traced. If a meter provider is specified, the number of the
+// batches flushed and their mean number of messages are instead observed
+// from the metrics sarama records in the MetricRegistry of saramaConfig.
 func WrapAsyncProducer(saramaConfig *sarama.Config, p sarama.AsyncProducer, opts ...Option) sarama.AsyncProducer {
 	cfg := newConfig(opts...)
 	if saramaConfig == nil {
 		saramaConfig = sarama.NewConfig()
+	} else {
+		registerBatchMetrics(cfg.MeterProvider, saramaConfig)
 	}
+	metrics := newProducerMetrics(cfg.MeterProvider)
 
 	wrapped := &asyncProducer{
 		AsyncProducer: p,
@@ -161,11 +193,13 @@ func WrapAsyncProducer(saramaConfig *sarama.Config, p sarama.AsyncProducer, opts
 					continue // wait for closeAsyncSig
 				}
 				span := startProducerSpan(cfg, saramaConfig.Version, msg)
+				metrics.sent(context.Background(), msg)
 
 				// Create message context, backend message metadata
 				mc := producerMessageContext{
 					metadataBackup: msg.Metadata,
 					span:           span,
+					start:          time.Now(),
 				}
 
 				// Remember metadata using span ID as a cache key
@@ -202,6 +236,7 @@ func WrapAsyncProducer(saramaConfig *sarama.Config, p sarama.AsyncProducer, opts
 			mtx.Lock()
 			if mc, ok := producerMessageContexts[key]; ok {
 				delete(producerMessageContexts, key)
+				metrics.produced(context.Background(), msg.Topic, mc.start)
 				finishProducerSpan(mc.span, msg.Partition, msg.Offset, nil)
 				msg.Metadata = mc.metadataBackup // Restore message metadata
 			}
@@ -222,6 +257,7 @@ func WrapAsyncProducer(saramaConfig *sarama.Config, p sarama.AsyncProducer, opts
 			mtx.Lock()
 			if mc, ok := producerMessageContexts[key]; ok {
 				delete(producerMessageContexts, key)
+				metrics.produced(context.Background(), errMsg.Msg.Topic, mc.start)
 				finishProducerSpan(mc.span, errMsg.Msg.Partition, errMsg.Msg.Offset, errMsg.Err)
 				errMsg.Msg.Metadata = mc.metadataBackup // Restore message metadata
 			}
@@ -270,6 +306,42 @@ func startProducerSpan(cfg config, version sarama.KafkaVersion, msg *sarama.Prod
 	return span
 }
 
+// startBatchSpan starts the span of the batch of the messages, linked to
+// their spans.
+func startBatchSpan(cfg config, msgs []*sarama.ProducerMessage, spans []trace.Span) trace.Span {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		BatchMessageCountKey.Int(len(msgs)),
+	}
+	// The batch has a destination if all its messages have the same topic.
+	if len(msgs) > 0 {
+		topic := msgs[0].Topic
+		for _, msg := range msgs[1:] {
+			if msg.Topic != topic {
+				topic = ""
+				break
+			}
+		}
+		if topic != "" {
+			attrs = append(attrs,
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingDestinationKey.String(topic),
+			)
+		}
+	}
+
+	links := make([]trace.Link, len(spans))
+	for i, span := range spans {
+		links[i] = trace.Link{SpanContext: span.SpanContext()}
+	}
+	_, span := cfg.Tracer.Start(context.Background(), "kafka.produce_batch",
+		trace.WithAttributes(attrs...),
+		trace.WithLinks(links...),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+	return span
+}
+
 func finishProducerSpan(span trace.Span, partition int32, offset int64, err error) {
 	span.SetAttributes(
 		semconv.MessagingMessageIDKey.String(strconv.FormatInt(offset, 10)),
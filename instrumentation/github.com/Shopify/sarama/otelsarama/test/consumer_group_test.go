@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/Shopify/sarama/otelsarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeSession is a sarama.ConsumerGroupSession of the generation 3 counting
+// its commits.
+type fakeSession struct {
+	commits int
+}
+
+func (s *fakeSession) Claims() map[string][]int32 { return map[string][]int32{topic: {1}} }
+
+func (s *fakeSession) MemberID() string { return "member" }
+
+func (s *fakeSession) GenerationID() int32 { return 3 }
+
+func (s *fakeSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {}
+
+func (s *fakeSession) Commit() { s.commits++ }
+
+func (s *fakeSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {}
+
+func (s *fakeSession) Context() context.Context { return context.Background() }
+
+// fakeClaim is a sarama.ConsumerGroupClaim of the partition 1 of the topic,
+// starting at the initial offset and whose high watermark is 20.
+type fakeClaim struct {
+	initialOffset int64
+	messages      chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string { return topic }
+
+func (c *fakeClaim) Partition() int32 { return 1 }
+
+func (c *fakeClaim) InitialOffset() int64 { return c.initialOffset }
+
+func (c *fakeClaim) HighWaterMarkOffset() int64 { return 20 }
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// markingHandler is a sarama.ConsumerGroupHandler marking the messages it
+// consumes and committing them, then calling claimed before returning err.
+type markingHandler struct {
+	claimed func()
+	err     error
+}
+
+func (h *markingHandler) Setup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *markingHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h *markingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		session.MarkMessage(msg, "")
+	}
+	session.Commit()
+	if h.claimed != nil {
+		h.claimed()
+	}
+	return h.err
+}
+
+// consume consumes two messages of a claim with the handler.
+func consume(t *testing.T, handler sarama.ConsumerGroupHandler, session sarama.ConsumerGroupSession) error {
+	claim := &fakeClaim{initialOffset: 10, messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: topic, Partition: 1, Offset: 10}
+	claim.messages <- &sarama.ConsumerMessage{Topic: topic, Partition: 1, Offset: 11}
+	close(claim.messages)
+
+	require.NoError(t, handler.Setup(session))
+	err := handler.ConsumeClaim(session, claim)
+	require.NoError(t, handler.Cleanup(session))
+	return err
+}
+
+func TestWrapConsumerGroupHandler(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	handler := otelsarama.WrapConsumerGroupHandler(
+		&markingHandler{},
+		otelsarama.WithTracerProvider(provider),
+		otelsarama.WithPropagators(propagation.TraceContext{}),
+	)
+
+	session := &fakeSession{}
+	require.NoError(t, consume(t, handler, session))
+	assert.Equal(t, 1, session.commits)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "kafka.consume", spans[0].Name())
+	assert.Equal(t, "kafka.consume", spans[1].Name())
+
+	claim := spans[2]
+	assert.Equal(t, "kafka.claim", claim.Name())
+	assert.Equal(t, trace.SpanKindConsumer, claim.SpanKind())
+	expected := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingDestinationKindTopic,
+		semconv.MessagingDestinationKey.String(topic),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingKafkaPartitionKey.Int64(1),
+		otelsarama.InitialOffsetKey.Int64(10),
+		otelsarama.GenerationIDKey.Int64(3),
+	}
+	assert.ElementsMatch(t, expected, claim.Attributes())
+	assert.Equal(t, codes.Unset, claim.Status().Code)
+}
+
+func TestWrapConsumerGroupHandlerError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	handler := otelsarama.WrapConsumerGroupHandler(
+		&markingHandler{err: errors.New("fail")},
+		otelsarama.WithTracerProvider(provider),
+	)
+
+	require.Error(t, consume(t, handler, &fakeSession{}))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "kafka.claim", spans[2].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, "fail", spans[2].Status().Description)
+}
+
+func TestConsumerGroupMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	kafka := []attribute.KeyValue{semconv.MessagingSystemKey.String("kafka")}
+	partition := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingDestinationKey.String(topic),
+		semconv.MessagingKafkaPartitionKey.Int64(1),
+	}
+
+	var claimed bool
+	handler := otelsarama.WrapConsumerGroupHandler(&markingHandler{
+		claimed: func() {
+			claimed = true
+			require.NoError(t, exp.Collect(context.Background()))
+
+			// The offsets 10 and 11 of the 20 ones are consumed.
+			lag, err := exp.GetByNameAndAttributes(otelsarama.ConsumerLag, partition)
+			require.NoError(t, err)
+			assert.Equal(t, aggregation.LastValueKind, lag.AggregationKind)
+			assert.Equal(t, int64(8), lag.LastValue.AsInt64())
+
+			rebalances, err := exp.GetByNameAndAttributes(otelsarama.ConsumerRebalances, kafka)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), rebalances.Sum.AsInt64())
+
+			commits, err := exp.GetByNameAndAttributes(otelsarama.ConsumerCommitDuration, kafka)
+			require.NoError(t, err)
+			assert.Equal(t, aggregation.HistogramKind, commits.AggregationKind)
+			assert.Equal(t, uint64(1), commits.Count)
+		},
+	}, otelsarama.WithMeterProvider(mp))
+
+	require.NoError(t, consume(t, handler, &fakeSession{}))
+	require.True(t, claimed)
+
+	// The lag of the partition is not reported once it is not claimed.
+	require.NoError(t, exp.Collect(context.Background()))
+	_, err := exp.GetByNameAndAttributes(otelsarama.ConsumerLag, partition)
+	assert.Error(t, err)
+}
+
+// claimHandler is a sarama.ConsumerGroupHandler consuming the claims with
+// the function.
+type claimHandler func(sarama.ConsumerGroupSession, sarama.ConsumerGroupClaim) error
+
+func (h claimHandler) Setup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h claimHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (h claimHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	return h(session, claim)
+}
+
+func TestConsumerGroupLagWithoutCommittedOffset(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	partition := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingDestinationKey.String(topic),
+		semconv.MessagingKafkaPartitionKey.Int64(1),
+	}
+
+	var claimed bool
+	handler := otelsarama.WrapConsumerGroupHandler(claimHandler(func(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+		claimed = true
+
+		// The lag is not known until an offset is marked.
+		require.NoError(t, exp.Collect(context.Background()))
+		_, err := exp.GetByNameAndAttributes(otelsarama.ConsumerLag, partition)
+		assert.Error(t, err)
+
+		session.MarkOffset(topic, 1, 15, "")
+		require.NoError(t, exp.Collect(context.Background()))
+		lag, err := exp.GetByNameAndAttributes(otelsarama.ConsumerLag, partition)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), lag.LastValue.AsInt64())
+		return nil
+	}), otelsarama.WithMeterProvider(mp))
+
+	claim := &fakeClaim{initialOffset: sarama.OffsetNewest, messages: make(chan *sarama.ConsumerMessage)}
+	close(claim.messages)
+	require.NoError(t, handler.ConsumeClaim(&fakeSession{}, claim))
+	require.True(t, claimed)
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/Shopify/sarama/otelsarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+var topicAttrs = []attribute.KeyValue{
+	semconv.MessagingSystemKey.String("kafka"),
+	semconv.MessagingDestinationKey.String(topic),
+}
+
+func TestSyncProducerMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	cfg := newSaramaConfig()
+	mockSyncProducer := mocks.NewSyncProducer(t, cfg)
+	producer := otelsarama.WrapSyncProducer(cfg, mockSyncProducer, otelsarama.WithMeterProvider(mp))
+
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	_, _, err := producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder("foo"),
+		Value: sarama.StringEncoder("hello"),
+	})
+	require.NoError(t, err)
+
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	require.NoError(t, producer.SendMessages([]*sarama.ProducerMessage{
+		{Topic: topic, Value: sarama.ByteEncoder("world")},
+		{Topic: topic, Key: sarama.StringEncoder("bar")},
+	}))
+	require.NoError(t, producer.Close())
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	messages, err := exp.GetByNameAndAttributes(otelsarama.ProducerMessages, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), messages.Sum.AsInt64())
+
+	size, err := exp.GetByNameAndAttributes(otelsarama.ProducerMessageSize, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, size.AggregationKind)
+	assert.Equal(t, uint64(3), size.Count)
+	assert.Equal(t, int64(16), size.Sum.AsInt64())
+
+	duration, err := exp.GetByNameAndAttributes(otelsarama.ProducerDuration, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(3), duration.Count)
+}
+
+func TestAsyncProducerMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	cfg := newSaramaConfig()
+	cfg.Producer.Return.Successes = true
+	mockAsyncProducer := mocks.NewAsyncProducer(t, cfg)
+	producer := otelsarama.WrapAsyncProducer(cfg, mockAsyncProducer, otelsarama.WithMeterProvider(mp))
+
+	mockAsyncProducer.ExpectInputAndSucceed()
+	producer.Input() <- &sarama.ProducerMessage{Topic: topic, Value: sarama.StringEncoder("hello")}
+	<-producer.Successes()
+	require.NoError(t, producer.Close())
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	messages, err := exp.GetByNameAndAttributes(otelsarama.ProducerMessages, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), messages.Sum.AsInt64())
+
+	duration, err := exp.GetByNameAndAttributes(otelsarama.ProducerDuration, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestAsyncProducerBatchMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	cfg := newSaramaConfig()
+	cfg.ClientID = "client"
+	producer := otelsarama.WrapAsyncProducer(cfg, mocks.NewAsyncProducer(t, cfg), otelsarama.WithMeterProvider(mp))
+	defer func() { require.NoError(t, producer.Close()) }()
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingKafkaClientIDKey.String("client"),
+	}
+
+	// No batch is reported until sarama flushes one.
+	require.NoError(t, exp.Collect(context.Background()))
+	_, err := exp.GetByNameAndAttributes(otelsarama.ProducerBatches, attrs)
+	assert.Error(t, err)
+
+	// The mock producer does not flush batches, the sizes sarama records
+	// for the batches it flushes are recorded in its stead.
+	sizes := metrics.GetOrRegisterHistogram("records-per-request", cfg.MetricRegistry, metrics.NewUniformSample(10))
+	sizes.Update(2)
+	sizes.Update(4)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	batches, err := exp.GetByNameAndAttributes(otelsarama.ProducerBatches, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), batches.Sum.AsInt64())
+
+	count, err := exp.GetByNameAndAttributes(otelsarama.ProducerBatchMessageCount, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.LastValueKind, count.AggregationKind)
+	assert.Equal(t, 3.0, count.LastValue.AsFloat64())
+}
+
+func TestConsumerMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	mockConsumer := mocks.NewConsumer(t, sarama.NewConfig())
+	mockPartitionConsumer := mockConsumer.ExpectConsumePartition(topic, 0, 0)
+	consumer := otelsarama.WrapConsumer(mockConsumer, otelsarama.WithMeterProvider(mp))
+
+	partitionConsumer, err := consumer.ConsumePartition(topic, 0, 0)
+	require.NoError(t, err)
+
+	mockPartitionConsumer.YieldMessage(&sarama.ConsumerMessage{Key: []byte("foo"), Value: []byte("hello")})
+	mockPartitionConsumer.YieldMessage(&sarama.ConsumerMessage{Value: []byte("world")})
+	<-partitionConsumer.Messages()
+	<-partitionConsumer.Messages()
+	require.NoError(t, partitionConsumer.Close())
+	// Wait for the channel to be closed
+	<-partitionConsumer.Messages()
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	messages, err := exp.GetByNameAndAttributes(otelsarama.ConsumerMessages, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), messages.Sum.AsInt64())
+
+	size, err := exp.GetByNameAndAttributes(otelsarama.ConsumerMessageSize, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, size.AggregationKind)
+	assert.Equal(t, uint64(2), size.Count)
+	assert.Equal(t, int64(13), size.Sum.AsInt64())
+}
@@ -143,6 +143,68 @@ func TestWrapSyncProducer(t *testing.T) {
 	}
 }
 
+func TestWrapSyncProducerBatch(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	cfg := newSaramaConfig()
+	mockSyncProducer := mocks.NewSyncProducer(t, cfg)
+	syncProducer := otelsarama.WrapSyncProducer(cfg, mockSyncProducer, otelsarama.WithTracerProvider(provider))
+
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	require.NoError(t, syncProducer.SendMessages([]*sarama.ProducerMessage{
+		{Topic: topic, Key: sarama.StringEncoder("foo")},
+		{Topic: topic, Key: sarama.StringEncoder("foo2")},
+	}))
+
+	spanList := sr.Ended()
+	require.Len(t, spanList, 3)
+	batch := spanList[2]
+	assert.Equal(t, "kafka.produce_batch", batch.Name())
+	assert.Equal(t, oteltrace.SpanKindProducer, batch.SpanKind())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		otelsarama.BatchMessageCountKey.Int(2),
+		semconv.MessagingDestinationKindTopic,
+		semconv.MessagingDestinationKey.String(topic),
+	}, batch.Attributes())
+	assert.Equal(t, codes.Unset, batch.Status().Code)
+
+	// The batch is linked to the spans of its messages.
+	require.Len(t, batch.Links(), 2)
+	for i, link := range batch.Links() {
+		assert.Equal(t, spanList[i].SpanContext(), link.SpanContext)
+	}
+}
+
+func TestWrapSyncProducerBatchError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	cfg := newSaramaConfig()
+	mockSyncProducer := mocks.NewSyncProducer(t, cfg)
+	syncProducer := otelsarama.WrapSyncProducer(cfg, mockSyncProducer, otelsarama.WithTracerProvider(provider))
+
+	mockSyncProducer.ExpectSendMessageAndSucceed()
+	mockSyncProducer.ExpectSendMessageAndFail(errors.New("test"))
+	require.Error(t, syncProducer.SendMessages([]*sarama.ProducerMessage{
+		{Topic: topic, Key: sarama.StringEncoder("foo")},
+		{Topic: "other", Key: sarama.StringEncoder("foo2")},
+	}))
+
+	spanList := sr.Ended()
+	require.Len(t, spanList, 3)
+	batch := spanList[2]
+	assert.Equal(t, "kafka.produce_batch", batch.Name())
+	// The messages have different topics: the batch has no destination.
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		otelsarama.BatchMessageCountKey.Int(2),
+	}, batch.Attributes())
+	assert.Equal(t, codes.Error, batch.Status().Code)
+}
+
 func TestWrapAsyncProducer(t *testing.T) {
 	propagators := propagation.TraceContext{}
 	// Create message with span context
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbeego // import "go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// config provides configuration for the beego OpenTelemetry filter chains.
+// Configuration is modified using the provided Options.
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+	filters        []Filter
+	formatter      SpanNameFormatter
+}
+
+// Filter returns true if the request should be traced.
+type Filter func(*http.Request) bool
+
+// SpanNameFormatter creates a custom span name from the route pattern of the
+// request, e.g. "/users/:id", and the request. The route pattern is empty
+// for the requests matching no route.
+type SpanNameFormatter func(route string, req *http.Request) string
+
+// Option applies a configuration to the given config.
+type Option interface {
+	apply(*config)
+}
+
+// optionFunc is a function type that applies a particular
+// configuration to the beego filter chains in question.
+type optionFunc func(c *config)
+
+// Apply will apply the option to the config, c.
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.tracerProvider = provider
+		}
+	})
+}
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the requests is only recorded if a meter provider is
+// specified, with the HTTP method, route pattern, and status code of the
+// requests as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.meterProvider = provider
+		}
+	})
+}
+
+// WithPropagators sets the propagators used in the web filter chain.
+// Defaults to global.Propagators().
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *config) {
+		if propagators != nil {
+			c.propagators = propagators
+		}
+	})
+}
+
+// WithFilter adds the given filter for use in the web filter chain.
+// Defaults to no filters.
+func WithFilter(f Filter) Option {
+	return optionFunc(func(c *config) {
+		if f != nil {
+			c.filters = append(c.filters, f)
+		}
+	})
+}
+
+// WithSpanNameFormatter sets the formatter to be used to format the names
+// of the spans of the requests. Defaults to the route pattern, or
+// "HTTP <method> route not found" for the requests matching no route.
+func WithSpanNameFormatter(f SpanNameFormatter) Option {
+	return optionFunc(func(c *config) {
+		if f != nil {
+			c.formatter = f
+		}
+	})
+}
+
+func newConfig(options ...Option) *config {
+	config := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+		formatter:      defaultSpanNameFormatter,
+	}
+	for _, option := range options {
+		option.apply(config)
+	}
+	return config
+}
@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelbeego instruments the github.com/beego/beego/v2 package.
+//
+// The requests served by a beego web app are traced, and measured if a
+// meter provider is specified, by the filter chain of FilterChain:
+//
+//	web.InsertFilterChain("*", otelbeego.FilterChain("my-service"))
+//
+// The queries of the beego ORM are traced by the filter chain of
+// ORMFilterChain:
+//
+//	orm.AddGlobalFilterChain(otelbeego.ORMFilterChain())
+//
+// The github.com/astaxie/beego package, beego v1, is instrumented by the
+// go.opentelemetry.io/contrib/instrumentation/github.com/astaxie/beego/otelbeego
+// package.
+package otelbeego // import "go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
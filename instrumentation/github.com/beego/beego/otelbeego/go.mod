@@ -0,0 +1,10 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego
+
+go 1.17
+
+require (
+	github.com/beego/beego/v2 v2.0.4
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
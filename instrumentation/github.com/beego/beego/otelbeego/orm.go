@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbeego // import "go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
+
+import (
+	"context"
+
+	"github.com/beego/beego/v2/client/orm"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the spans of the ORM operations.
+const (
+	// ORMInsideTxKey is set to true for the operations made in a
+	// transaction.
+	ORMInsideTxKey = attribute.Key("beego.orm.inside_tx")
+	// ORMTxNameKey is the name of the transaction of the operation, if any.
+	ORMTxNameKey = attribute.Key("beego.orm.tx_name")
+)
+
+// ORMFilterChain returns an orm.FilterChain that traces the operations of
+// the beego ORM, to be added with orm.AddGlobalFilterChain. The spans are
+// children of the spans of the contexts passed to the context-aware methods
+// of the ORM, e.g. ReadWithCtx, and are named after the operation and the
+// table of its model, e.g. "Read user".
+//
+// Only the tracer provider option applies to the ORM.
+func ORMFilterChain(options ...Option) orm.FilterChain {
+	cfg := newConfig(options...)
+	tracer := cfg.tracerProvider.Tracer(
+		instrumentationName,
+		trace.WithInstrumentationVersion(SemVersion()),
+	)
+
+	return func(next orm.Filter) orm.Filter {
+		return func(ctx context.Context, inv *orm.Invocation) []interface{} {
+			table := inv.GetTableName()
+			name := inv.Method
+			attrs := []attribute.KeyValue{
+				semconv.DBOperationKey.String(inv.Method),
+				ORMInsideTxKey.Bool(inv.InsideTx),
+			}
+			if table != "" {
+				name += " " + table
+				attrs = append(attrs, semconv.DBSQLTableKey.String(table))
+			}
+			if inv.TxName != "" {
+				attrs = append(attrs, ORMTxNameKey.String(inv.TxName))
+			}
+
+			ctx, span := tracer.Start(
+				ctx,
+				name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			res := next(ctx, inv)
+			// The error, if any, is the last result of the operations.
+			if n := len(res); n > 0 {
+				if err, ok := res[n-1].(error); ok && err != nil && err != orm.ErrNoRows {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+			}
+			return res
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/beego/beego/v2/server/web"
+	beecontext "github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serve serves the request with the filter chain, the route pattern being
+// set as by the beego router if not empty.
+func serve(chain web.FilterChain, route string, status int, r *http.Request) *beecontext.Context {
+	ctx := beecontext.NewContext()
+	ctx.Reset(httptest.NewRecorder(), r)
+	chain(func(ctx *beecontext.Context) {
+		if route != "" {
+			ctx.Input.SetData("RouterPattern", route)
+		}
+		ctx.ResponseWriter.WriteHeader(status)
+	})(ctx)
+	return ctx
+}
+
+func TestFilterChain(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	mp, exp := metrictest.NewTestMeterProvider()
+	chain := otelbeego.FilterChain(
+		"foobar",
+		otelbeego.WithTracerProvider(tp),
+		otelbeego.WithMeterProvider(mp),
+		otelbeego.WithPropagators(propagation.TraceContext{}),
+		otelbeego.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	)
+
+	parent, parentSpan := tp.Tracer("test").Start(context.Background(), "parent")
+	parentSpan.End()
+	r := httptest.NewRequest("GET", "/users/123", nil)
+	propagation.TraceContext{}.Inject(parent, propagation.HeaderCarrier(r.Header))
+	serve(chain, "/users/:id", http.StatusCreated, r)
+	serve(chain, "", http.StatusNotFound, httptest.NewRequest("GET", "/unknown", nil))
+	serve(chain, "/healthz", http.StatusOK, httptest.NewRequest("GET", "/healthz", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	span := spans[1]
+	assert.Equal(t, "/users/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, parentSpan.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Contains(t, span.Attributes(), semconv.HTTPRouteKey.String("/users/:id"))
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusCreated))
+	assert.Contains(t, span.Attributes(), attribute.String("http.server_name", "foobar"))
+	assert.Equal(t, "HTTP GET route not found", spans[2].Name())
+
+	require.NoError(t, exp.Collect(context.Background()))
+	duration, err := exp.GetByNameAndAttributes(otelbeego.ServerDuration, []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("GET"),
+		semconv.HTTPRouteKey.String("/users/:id"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestORMFilterChain(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	chain := otelbeego.ORMFilterChain(otelbeego.WithTracerProvider(tp))
+
+	errRead := errors.New("read failure")
+	filter := chain(func(ctx context.Context, inv *orm.Invocation) []interface{} {
+		if inv.Method == "Read" {
+			return []interface{}{errRead}
+		}
+		return []interface{}{int64(1), nil}
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	filter(ctx, &orm.Invocation{Method: "Insert", InsideTx: true, TxName: "tx"})
+	filter(ctx, &orm.Invocation{Method: "Read"})
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	insert, read := spans[0], spans[1]
+
+	assert.Equal(t, "Insert", insert.Name())
+	assert.Equal(t, trace.SpanKindClient, insert.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), insert.Parent().SpanID())
+	assert.Contains(t, insert.Attributes(), semconv.DBOperationKey.String("Insert"))
+	assert.Contains(t, insert.Attributes(), otelbeego.ORMInsideTxKey.Bool(true))
+	assert.Contains(t, insert.Attributes(), otelbeego.ORMTxNameKey.String("tx"))
+	assert.Equal(t, codes.Unset, insert.Status().Code)
+
+	assert.Equal(t, "Read", read.Name())
+	assert.Equal(t, codes.Error, read.Status().Code)
+	assert.Equal(t, "read failure", read.Status().Description)
+}
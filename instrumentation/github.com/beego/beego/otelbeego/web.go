@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbeego // import "go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+	beecontext "github.com/beego/beego/v2/server/web/context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/beego/beego/otelbeego"
+
+	// routerPatternKey is the key of the input data of the route pattern
+	// matched by the request, set by the beego router.
+	routerPatternKey = "RouterPattern"
+)
+
+// FilterChain returns a web.FilterChain that traces the requests served by
+// a beego web app, to be inserted with web.InsertFilterChain. The service
+// parameter should describe the name of the (virtual) server handling the
+// request.
+//
+// The spans are named after the route patterns of the requests, which are
+// only known once the requests are routed: the default span names and the
+// SpanNameFormatter are applied when the requests are served.
+func FilterChain(service string, options ...Option) web.FilterChain {
+	cfg := newConfig(options...)
+	tracer := cfg.tracerProvider.Tracer(
+		instrumentationName,
+		trace.WithInstrumentationVersion(SemVersion()),
+	)
+	metrics := newServerMetrics(cfg.meterProvider)
+
+	return func(next web.FilterFunc) web.FilterFunc {
+		return func(ctx *beecontext.Context) {
+			req := ctx.Request
+			if !cfg.traces(req) {
+				next(ctx)
+				return
+			}
+			start := time.Now()
+
+			reqCtx := cfg.propagators.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			reqCtx, span := tracer.Start(
+				reqCtx,
+				req.Method,
+				trace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", req)...),
+				trace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(req)...),
+				trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(service, "", req)...),
+				trace.WithSpanKind(trace.SpanKindServer),
+			)
+			defer span.End()
+
+			// pass the span through the request context
+			ctx.Request = req.WithContext(reqCtx)
+			defer func() {
+				ctx.Request = req
+			}()
+
+			next(ctx)
+
+			route, _ := ctx.Input.GetData(routerPatternKey).(string)
+			span.SetName(cfg.formatter(route, req))
+			span.SetAttributes(routeAttr(route)...)
+
+			status := responseStatus(ctx)
+			spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer)
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			span.SetStatus(spanStatus, spanMessage)
+			metrics.record(reqCtx, req.Method, route, status, start)
+		}
+	}
+}
+
+// traces reports whether the request is not excluded by a filter.
+func (c *config) traces(req *http.Request) bool {
+	for _, f := range c.filters {
+		if !f(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// responseStatus returns the status code of the response, 200 if the
+// response was written without an explicit status code.
+func responseStatus(ctx *beecontext.Context) int {
+	if ctx.ResponseWriter == nil || ctx.ResponseWriter.Status == 0 {
+		return http.StatusOK
+	}
+	return ctx.ResponseWriter.Status
+}
+
+// defaultSpanNameFormatter names the span after the route pattern, so that
+// the span names of the requests matching no route are not the raw paths.
+func defaultSpanNameFormatter(route string, req *http.Request) string {
+	if route == "" {
+		return fmt.Sprintf("HTTP %s route not found", req.Method)
+	}
+	return route
+}
+
+// routeAttr returns the http.route attribute of the route, if any.
+func routeAttr(route string) []attribute.KeyValue {
+	if route == "" {
+		return nil
+	}
+	return []attribute.KeyValue{semconv.HTTPRouteKey.String(route)}
+}
@@ -15,11 +15,14 @@
 package otelmemcache // import "go.opentelemetry.io/contrib/instrumentation/github.com/bradfitz/gomemcache/memcache/otelmemcache"
 
 import (
+	"go.opentelemetry.io/otel/metric"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type config struct {
 	tracerProvider oteltrace.TracerProvider
+	meterProvider  metric.MeterProvider
+	itemSizes      bool
 }
 
 // Option is used to configure the client.
@@ -42,3 +45,24 @@ func WithTracerProvider(provider oteltrace.TracerProvider) Option {
 		}
 	})
 }
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the operations and the hits and misses of the get
+// operations are only recorded if a meter provider is specified.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.meterProvider = provider
+		}
+	})
+}
+
+// WithItemSizes specifies whether the sizes of the values of the items found
+// by the get operations are recorded, as the db.memcached.items.size span
+// attribute and, if a meter provider is specified, the
+// db.client.memcached.item.size metric.  They are not recorded by default.
+func WithItemSizes(enabled bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.itemSizes = enabled
+	})
+}
@@ -16,6 +16,8 @@ package otelmemcache // import "go.opentelemetry.io/contrib/instrumentation/gith
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 
@@ -33,8 +35,17 @@ const (
 // Client is a wrapper around *memcache.Client.
 type Client struct {
 	*memcache.Client
-	tracer oteltrace.Tracer
-	ctx    context.Context
+	tracer    oteltrace.Tracer
+	metrics   *clientMetrics
+	itemSizes bool
+	ctx       context.Context
+}
+
+// operation is a traced operation of the client.
+type operation struct {
+	span  oteltrace.Span
+	name  internal.Operation
+	start time.Time
 }
 
 // NewClientWithTracing wraps the provided memcache client to allow
@@ -57,12 +68,14 @@ func NewClientWithTracing(client *memcache.Client, opts ...Option) *Client {
 	}
 
 	return &Client{
-		client,
-		cfg.tracerProvider.Tracer(
+		Client: client,
+		tracer: cfg.tracerProvider.Tracer(
 			tracerName,
 			oteltrace.WithInstrumentationVersion(SemVersion()),
 		),
-		context.Background(),
+		metrics:   newClientMetrics(cfg.meterProvider),
+		itemSizes: cfg.itemSizes,
+		ctx:       context.Background(),
 	}
 }
 
@@ -82,7 +95,7 @@ func (c *Client) attrsByOperationAndItemKey(operation internal.Operation, key ..
 }
 
 // Starts span with appropriate span kind and attributes.
-func (c *Client) startSpan(operationName internal.Operation, itemKey ...string) oteltrace.Span {
+func (c *Client) startSpan(operationName internal.Operation, itemKey ...string) operation {
 	opts := []oteltrace.SpanStartOption{
 		// for database client calls, always use CLIENT span kind
 		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
@@ -91,30 +104,35 @@ func (c *Client) startSpan(operationName internal.Operation, itemKey ...string)
 		),
 	}
 
+	start := time.Now()
 	_, span := c.tracer.Start(
 		c.ctx,
 		string(operationName),
 		opts...,
 	)
 
-	return span
+	return operation{span: span, name: operationName, start: start}
 }
 
-// Ends span and, if applicable, sets error status.
-func endSpan(s oteltrace.Span, err error) {
+// Ends span and, if applicable, sets error status, and records the duration
+// of the operation.
+func (c *Client) endSpan(op operation, err error) {
 	if err != nil {
-		s.SetStatus(codes.Error, err.Error())
+		op.span.SetStatus(codes.Error, err.Error())
 	}
-	s.End()
+	op.span.End()
+	c.metrics.record(c.ctx, op.name, op.start, err)
 }
 
 // WithContext retruns a copy of the client with provided context.
 func (c *Client) WithContext(ctx context.Context) *Client {
 	cc := c.Client
 	return &Client{
-		Client: cc,
-		tracer: c.tracer,
-		ctx:    ctx,
+		Client:    cc,
+		tracer:    c.tracer,
+		metrics:   c.metrics,
+		itemSizes: c.itemSizes,
+		ctx:       ctx,
 	}
 }
 
@@ -122,7 +140,7 @@ func (c *Client) WithContext(ctx context.Context) *Client {
 func (c *Client) Add(item *memcache.Item) error {
 	s := c.startSpan(internal.OperationAdd, item.Key)
 	err := c.Client.Add(item)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -130,7 +148,7 @@ func (c *Client) Add(item *memcache.Item) error {
 func (c *Client) CompareAndSwap(item *memcache.Item) error {
 	s := c.startSpan(internal.OperationCompareAndSwap, item.Key)
 	err := c.Client.CompareAndSwap(item)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -138,7 +156,7 @@ func (c *Client) CompareAndSwap(item *memcache.Item) error {
 func (c *Client) Decrement(key string, delta uint64) (uint64, error) {
 	s := c.startSpan(internal.OperationDecrement, key)
 	newValue, err := c.Client.Decrement(key, delta)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return newValue, err
 }
 
@@ -146,7 +164,7 @@ func (c *Client) Decrement(key string, delta uint64) (uint64, error) {
 func (c *Client) Delete(key string) error {
 	s := c.startSpan(internal.OperationDelete, key)
 	err := c.Client.Delete(key)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -154,7 +172,7 @@ func (c *Client) Delete(key string) error {
 func (c *Client) DeleteAll() error {
 	s := c.startSpan(internal.OperationDeleteAll)
 	err := c.Client.DeleteAll()
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -162,7 +180,7 @@ func (c *Client) DeleteAll() error {
 func (c *Client) FlushAll() error {
 	s := c.startSpan(internal.OperationFlushAll)
 	err := c.Client.FlushAll()
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -170,7 +188,18 @@ func (c *Client) FlushAll() error {
 func (c *Client) Get(key string) (*memcache.Item, error) {
 	s := c.startSpan(internal.OperationGet, key)
 	item, err := c.Client.Get(key)
-	endSpan(s, err)
+	var found []*memcache.Item
+	if err == nil && c.itemSizes {
+		found = []*memcache.Item{item}
+		s.span.SetAttributes(internal.MemcacheDBItemsSize(len(item.Value)))
+	}
+	c.endSpan(s, err)
+	switch {
+	case err == nil:
+		c.metrics.lookups(c.ctx, 1, 0, found)
+	case errors.Is(err, memcache.ErrCacheMiss):
+		c.metrics.lookups(c.ctx, 0, 1, nil)
+	}
 	return item, err
 }
 
@@ -178,7 +207,23 @@ func (c *Client) Get(key string) (*memcache.Item, error) {
 func (c *Client) GetMulti(keys []string) (map[string]*memcache.Item, error) {
 	s := c.startSpan(internal.OperationGet, keys...)
 	items, err := c.Client.GetMulti(keys)
-	endSpan(s, err)
+	s.span.SetAttributes(
+		internal.MemcacheDBItemsRequested(len(keys)),
+		internal.MemcacheDBItemsFound(len(items)),
+	)
+	var found []*memcache.Item
+	if c.itemSizes {
+		size := 0
+		for _, item := range items {
+			found = append(found, item)
+			size += len(item.Value)
+		}
+		s.span.SetAttributes(internal.MemcacheDBItemsSize(size))
+	}
+	c.endSpan(s, err)
+	if err == nil {
+		c.metrics.lookups(c.ctx, len(items), len(keys)-len(items), found)
+	}
 	return items, err
 }
 
@@ -186,7 +231,7 @@ func (c *Client) GetMulti(keys []string) (map[string]*memcache.Item, error) {
 func (c *Client) Increment(key string, delta uint64) (uint64, error) {
 	s := c.startSpan(internal.OperationIncrement, key)
 	newValue, err := c.Client.Increment(key, delta)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return newValue, err
 }
 
@@ -194,7 +239,7 @@ func (c *Client) Increment(key string, delta uint64) (uint64, error) {
 func (c *Client) Ping() error {
 	s := c.startSpan(internal.OperationPing)
 	err := c.Client.Ping()
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -202,7 +247,7 @@ func (c *Client) Ping() error {
 func (c *Client) Replace(item *memcache.Item) error {
 	s := c.startSpan(internal.OperationReplace, item.Key)
 	err := c.Client.Replace(item)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -210,7 +255,7 @@ func (c *Client) Replace(item *memcache.Item) error {
 func (c *Client) Set(item *memcache.Item) error {
 	s := c.startSpan(internal.OperationSet, item.Key)
 	err := c.Client.Set(item)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
 
@@ -218,6 +263,6 @@ func (c *Client) Set(item *memcache.Item) error {
 func (c *Client) Touch(key string, seconds int32) error {
 	s := c.startSpan(internal.OperationTouch, key)
 	err := c.Client.Touch(key, seconds)
-	endSpan(s, err)
+	c.endSpan(s, err)
 	return err
 }
@@ -39,6 +39,23 @@ const (
 	MamcacheDBSystemValue = "memcached"
 
 	MemcacheDBItemKeyName attribute.Key = "db.memcached.item"
+
+	MemcacheDBItemsRequestedName attribute.Key = "db.memcached.items.requested"
+	MemcacheDBItemsFoundName     attribute.Key = "db.memcached.items.found"
+	MemcacheDBItemsSizeName      attribute.Key = "db.memcached.items.size"
+)
+
+type Result string
+
+// Instrumentation specific metrics information.
+const (
+	ResultOK          Result = "ok"
+	ResultMiss        Result = "miss"
+	ResultNotStored   Result = "not_stored"
+	ResultCASConflict Result = "cas_conflict"
+	ResultError       Result = "error"
+
+	MemcacheDBResultName attribute.Key = "db.memcached.result"
 )
 
 func MemcacheDBSystem() attribute.KeyValue {
@@ -56,3 +73,19 @@ func MemcacheDBItemKeys(itemKeys ...string) attribute.KeyValue {
 
 	return MemcacheDBItemKeyName.String(itemKeys[0])
 }
+
+func MemcacheDBResult(result Result) attribute.KeyValue {
+	return MemcacheDBResultName.String(string(result))
+}
+
+func MemcacheDBItemsRequested(n int) attribute.KeyValue {
+	return MemcacheDBItemsRequestedName.Int(n)
+}
+
+func MemcacheDBItemsFound(n int) attribute.KeyValue {
+	return MemcacheDBItemsFoundName.Int(n)
+}
+
+func MemcacheDBItemsSize(size int) attribute.KeyValue {
+	return MemcacheDBItemsSizeName.Int(size)
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelmemcache // import "go.opentelemetry.io/contrib/instrumentation/github.com/bradfitz/gomemcache/memcache/otelmemcache"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/bradfitz/gomemcache/memcache/otelmemcache/internal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Client operation metrics.
+const (
+	ClientOperationDuration = "db.client.operation.duration"  // Duration of the operations, milliseconds
+	ClientHits              = "db.client.memcached.hits"      // Number of the items found by the get operations
+	ClientMisses            = "db.client.memcached.misses"    // Number of the items not found by the get operations
+	ClientItemSize          = "db.client.memcached.item.size" // Size of the values of the items found, bytes
+)
+
+// clientMetrics holds the instruments of the operations of the client.  A
+// nil *clientMetrics records nothing.
+type clientMetrics struct {
+	duration syncfloat64.Histogram
+	hits     syncint64.Counter
+	misses   syncint64.Counter
+	itemSize syncint64.Histogram
+}
+
+// newClientMetrics returns the instruments of the operations, or nil if the
+// metrics are not enabled.
+func newClientMetrics(mp metric.MeterProvider) *clientMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		tracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   clientMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ClientOperationDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the operations"),
+	)
+	handleErr(err)
+
+	m.hits, err = meter.SyncInt64().Counter(
+		ClientHits,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the items found by the get operations"),
+	)
+	handleErr(err)
+
+	m.misses, err = meter.SyncInt64().Counter(
+		ClientMisses,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the items not found by the get operations"),
+	)
+	handleErr(err)
+
+	m.itemSize, err = meter.SyncInt64().Histogram(
+		ClientItemSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the values of the items found"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// record records the duration since start of the operation, with the
+// operation and its result as attributes.
+func (m *clientMetrics) record(ctx context.Context, operation internal.Operation, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	m.duration.Record(ctx, elapsed,
+		internal.MemcacheDBSystem(),
+		internal.MemcacheDBOperation(operation),
+		internal.MemcacheDBResult(result(err)),
+	)
+}
+
+// lookups records the hits and misses of a get operation, and the sizes of
+// the values of the items.
+func (m *clientMetrics) lookups(ctx context.Context, hits, misses int, items []*memcache.Item) {
+	if m == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		internal.MemcacheDBSystem(),
+		internal.MemcacheDBOperation(internal.OperationGet),
+	}
+	if hits > 0 {
+		m.hits.Add(ctx, int64(hits), attrs...)
+	}
+	if misses > 0 {
+		m.misses.Add(ctx, int64(misses), attrs...)
+	}
+	for _, item := range items {
+		m.itemSize.Record(ctx, int64(len(item.Value)), attrs...)
+	}
+}
+
+// result returns the result of an operation returning err.
+func result(err error) internal.Result {
+	switch {
+	case err == nil:
+		return internal.ResultOK
+	case errors.Is(err, memcache.ErrCacheMiss):
+		return internal.ResultMiss
+	case errors.Is(err, memcache.ErrNotStored):
+		return internal.ResultNotStored
+	case errors.Is(err, memcache.ErrCASConflict):
+		return internal.ResultCASConflict
+	default:
+		return internal.ResultError
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/bradfitz/gomemcache/memcache/otelmemcache"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/bradfitz/gomemcache/memcache/otelmemcache/internal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tests require running memcached instance.
+func TestMetrics(t *testing.T) {
+	mc := memcache.New("localhost:11211")
+	require.NoError(t, clearDB(mc))
+
+	mp, exp := metrictest.NewTestMeterProvider()
+	c := otelmemcache.NewClientWithTracing(mc, otelmemcache.WithMeterProvider(mp))
+
+	require.NoError(t, c.Set(&memcache.Item{Key: "foo", Value: []byte("bar")}))
+	assert.ErrorIs(t, c.Add(&memcache.Item{Key: "foo", Value: []byte("baz")}), memcache.ErrNotStored)
+	_, err := c.Get("foo")
+	require.NoError(t, err)
+	_, err = c.Get("bar")
+	assert.ErrorIs(t, err, memcache.ErrCacheMiss)
+	_, err = c.GetMulti([]string{"foo", "bar", "baz"})
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := func(op internal.Operation, result ...internal.Result) []attribute.KeyValue {
+		a := []attribute.KeyValue{
+			internal.MemcacheDBSystem(),
+			internal.MemcacheDBOperation(op),
+		}
+		for _, r := range result {
+			a = append(a, internal.MemcacheDBResult(r))
+		}
+		return a
+	}
+
+	for _, tc := range []struct {
+		attrs []attribute.KeyValue
+		count uint64
+	}{
+		{attrs(internal.OperationSet, internal.ResultOK), 1},
+		{attrs(internal.OperationAdd, internal.ResultNotStored), 1},
+		{attrs(internal.OperationGet, internal.ResultOK), 2},
+		{attrs(internal.OperationGet, internal.ResultMiss), 1},
+	} {
+		duration, err := exp.GetByNameAndAttributes(otelmemcache.ClientOperationDuration, tc.attrs)
+		require.NoError(t, err)
+		assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+		assert.Equal(t, tc.count, duration.Count, tc.attrs)
+	}
+
+	hits, err := exp.GetByNameAndAttributes(otelmemcache.ClientHits, attrs(internal.OperationGet))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), hits.Sum.AsInt64())
+
+	misses, err := exp.GetByNameAndAttributes(otelmemcache.ClientMisses, attrs(internal.OperationGet))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), misses.Sum.AsInt64())
+}
+
+func TestGetMultiItems(t *testing.T) {
+	mc := memcache.New("localhost:11211")
+	require.NoError(t, clearDB(mc))
+
+	sr := tracetest.NewSpanRecorder()
+	mp, exp := metrictest.NewTestMeterProvider()
+	c := otelmemcache.NewClientWithTracing(
+		mc,
+		otelmemcache.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(sr))),
+		otelmemcache.WithMeterProvider(mp),
+		otelmemcache.WithItemSizes(true),
+	)
+
+	require.NoError(t, c.Set(&memcache.Item{Key: "foo", Value: []byte("bar")}))
+	require.NoError(t, c.Set(&memcache.Item{Key: "baz", Value: []byte("quux")}))
+	items, err := c.GetMulti([]string{"foo", "bar", "baz"})
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	attrs := spans[2].Attributes()
+	assert.Contains(t, attrs, internal.MemcacheDBItemsRequested(3))
+	assert.Contains(t, attrs, internal.MemcacheDBItemsFound(2))
+	assert.Contains(t, attrs, internal.MemcacheDBItemsSize(len("bar")+len("quux")))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	get := []attribute.KeyValue{
+		internal.MemcacheDBSystem(),
+		internal.MemcacheDBOperation(internal.OperationGet),
+	}
+	size, err := exp.GetByNameAndAttributes(otelmemcache.ClientItemSize, get)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, size.AggregationKind)
+	assert.Equal(t, uint64(2), size.Count)
+	assert.Equal(t, int64(len("bar")+len("quux")), size.Sum.AsInt64())
+}
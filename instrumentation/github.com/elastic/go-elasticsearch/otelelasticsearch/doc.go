@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelelasticsearch instruments the github.com/elastic/go-elasticsearch
+// package.
+//
+// The requests of a client are traced when its transport is wrapped with
+// NewTransport:
+//
+//	es, err := elasticsearch.NewClient(elasticsearch.Config{
+//		Transport: otelelasticsearch.NewTransport(http.DefaultTransport),
+//	})
+//
+// The spans are named by the API called, e.g. "search", "bulk", or
+// "indices.create", which is also recorded as the db.operation attribute,
+// instead of the URL of the request, and record the index targeted by the
+// request, if any, as the db.elasticsearch.index attribute and the status code
+// of the response.  The request bodies, e.g. the queries, are only recorded
+// as the db.statement attribute, up to a size, with WithRequestBody.
+package otelelasticsearch // import "go.opentelemetry.io/contrib/instrumentation/github.com/elastic/go-elasticsearch/otelelasticsearch"
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelelasticsearch // import "go.opentelemetry.io/contrib/instrumentation/github.com/elastic/go-elasticsearch/otelelasticsearch"
+
+import (
+	"net/http"
+	"strings"
+)
+
+// namespaces are the APIs whose path segment following theirs names the
+// API called, e.g. /_cluster/health for "cluster.health".
+var namespaces = map[string]bool{
+	"cat":      true,
+	"cluster":  true,
+	"ingest":   true,
+	"security": true,
+}
+
+// nodesAPIs are the APIs of /_nodes, which can follow the node ids in the
+// path, e.g. /_nodes/node-1/stats for "nodes.stats".
+var nodesAPIs = map[string]bool{
+	"hot_threads": true,
+	"info":        true,
+	"stats":       true,
+	"usage":       true,
+}
+
+// indicesAPIs are the APIs of the indices, whose names are prefixed with
+// "indices." as in the documentation of the API.
+var indicesAPIs = map[string]bool{
+	"alias":      true,
+	"aliases":    true,
+	"analyze":    true,
+	"cache":      true,
+	"close":      true,
+	"flush":      true,
+	"forcemerge": true,
+	"mapping":    true,
+	"open":       true,
+	"refresh":    true,
+	"rollover":   true,
+	"settings":   true,
+	"stats":      true,
+}
+
+// endpoint returns the name of the API called by a request with the method
+// and path, e.g. "search" for POST /books/_search, and the index, indices or
+// pattern targeted by the request, e.g. "books", if any.
+//
+// The segments of the path starting with an underscore are the APIs and the
+// others are their arguments, which are never part of the name so the
+// number of names stays bounded.
+func endpoint(method, path string) (name, index string) {
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	if len(segments) > 0 && !isAPI(segments[0]) {
+		index, segments = segments[0], segments[1:]
+	}
+
+	if len(segments) == 0 {
+		if index == "" {
+			if method == http.MethodHead {
+				return "ping", index
+			}
+			return "info", index
+		}
+		switch method {
+		case http.MethodPut:
+			return "indices.create", index
+		case http.MethodDelete:
+			return "indices.delete", index
+		case http.MethodHead:
+			return "indices.exists", index
+		}
+		return "indices.get", index
+	}
+
+	api := strings.TrimPrefix(segments[0], "_")
+	switch {
+	case api == "doc":
+		switch method {
+		case http.MethodGet:
+			return "get", index
+		case http.MethodHead:
+			return "exists", index
+		case http.MethodDelete:
+			return "delete", index
+		}
+		return "index", index
+	case api == "source":
+		if method == http.MethodHead {
+			return "exists_source", index
+		}
+		return "get_source", index
+	case api == "search" && len(segments) > 1:
+		if segments[1] == "scroll" {
+			if method == http.MethodDelete {
+				return "clear_scroll", index
+			}
+			return "scroll", index
+		}
+		return "search_" + segments[1], index
+	case api == "nodes":
+		for _, s := range segments[1:] {
+			if nodesAPIs[s] {
+				return "nodes." + s, index
+			}
+		}
+		return "nodes.info", index
+	case namespaces[api]:
+		if len(segments) > 1 {
+			return api + "." + segments[1], index
+		}
+		return api, index
+	case indicesAPIs[api]:
+		switch api {
+		case "alias", "mapping", "settings":
+			return "indices." + methodPrefix(method) + api, index
+		case "aliases":
+			if method == http.MethodGet {
+				return "indices.get_alias", index
+			}
+			return "indices.update_aliases", index
+		case "cache":
+			return "indices.clear_cache", index
+		}
+		return "indices." + api, index
+	}
+	return api, index
+}
+
+// isAPI returns whether the path segment s is an API rather than an index.
+// The _all pseudo index targets all the indices.
+func isAPI(s string) bool {
+	return strings.HasPrefix(s, "_") && s != "_all"
+}
+
+// methodPrefix returns the prefix of the names of the APIs getting,
+// checking the existence of, updating, or deleting a property of the
+// indices with the method, e.g. "get_" for "indices.get_mapping".
+func methodPrefix(method string) string {
+	switch method {
+	case http.MethodHead:
+		return "exists_"
+	case http.MethodPut, http.MethodPost:
+		return "put_"
+	case http.MethodDelete:
+		return "delete_"
+	}
+	return "get_"
+}
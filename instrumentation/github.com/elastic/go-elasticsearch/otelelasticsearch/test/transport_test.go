@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/elastic/go-elasticsearch/otelelasticsearch"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// do sends a request with the method, path, and body to a server responding
+// with the status through a transport with the options, and returns the
+// ended spans and the body received by the server.
+func do(t *testing.T, method, path, body string, status int, opts ...otelelasticsearch.Option) ([]sdktrace.ReadOnlySpan, string) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = string(b)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts = append([]otelelasticsearch.Option{otelelasticsearch.WithTracerProvider(provider)}, opts...)
+	client := &http.Client{Transport: otelelasticsearch.NewTransport(http.DefaultTransport, opts...)}
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, srv.URL+path, reader)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return sr.Ended(), received
+}
+
+func TestEndpoints(t *testing.T) {
+	testCases := []struct {
+		method string
+		path   string
+		name   string
+		index  string
+	}{
+		{method: http.MethodGet, path: "/", name: "info"},
+		{method: http.MethodHead, path: "/", name: "ping"},
+		{method: http.MethodPost, path: "/_search", name: "search"},
+		{method: http.MethodPost, path: "/books/_search", name: "search", index: "books"},
+		{method: http.MethodPost, path: "/books,movies/_search", name: "search", index: "books,movies"},
+		{method: http.MethodPost, path: "/_all/_search", name: "search", index: "_all"},
+		{method: http.MethodPost, path: "/_search/scroll", name: "scroll"},
+		{method: http.MethodDelete, path: "/_search/scroll", name: "clear_scroll"},
+		{method: http.MethodPost, path: "/books/_search/template", name: "search_template", index: "books"},
+		{method: http.MethodPost, path: "/_bulk", name: "bulk"},
+		{method: http.MethodPost, path: "/books/_bulk", name: "bulk", index: "books"},
+		{method: http.MethodGet, path: "/books/_count", name: "count", index: "books"},
+		{method: http.MethodGet, path: "/books/_doc/1", name: "get", index: "books"},
+		{method: http.MethodHead, path: "/books/_doc/1", name: "exists", index: "books"},
+		{method: http.MethodPut, path: "/books/_doc/1", name: "index", index: "books"},
+		{method: http.MethodPost, path: "/books/_doc", name: "index", index: "books"},
+		{method: http.MethodDelete, path: "/books/_doc/1", name: "delete", index: "books"},
+		{method: http.MethodPut, path: "/books/_create/1", name: "create", index: "books"},
+		{method: http.MethodPost, path: "/books/_update/1", name: "update", index: "books"},
+		{method: http.MethodGet, path: "/books/_source/1", name: "get_source", index: "books"},
+		{method: http.MethodPost, path: "/books/_delete_by_query", name: "delete_by_query", index: "books"},
+		{method: http.MethodGet, path: "/_mget", name: "mget"},
+		{method: http.MethodPut, path: "/books", name: "indices.create", index: "books"},
+		{method: http.MethodDelete, path: "/books", name: "indices.delete", index: "books"},
+		{method: http.MethodHead, path: "/books", name: "indices.exists", index: "books"},
+		{method: http.MethodGet, path: "/books", name: "indices.get", index: "books"},
+		{method: http.MethodGet, path: "/books/_mapping", name: "indices.get_mapping", index: "books"},
+		{method: http.MethodPut, path: "/books/_mapping", name: "indices.put_mapping", index: "books"},
+		{method: http.MethodPut, path: "/books/_settings", name: "indices.put_settings", index: "books"},
+		{method: http.MethodDelete, path: "/books/_alias/old", name: "indices.delete_alias", index: "books"},
+		{method: http.MethodPost, path: "/_aliases", name: "indices.update_aliases"},
+		{method: http.MethodPost, path: "/books/_refresh", name: "indices.refresh", index: "books"},
+		{method: http.MethodPost, path: "/books/_cache/clear", name: "indices.clear_cache", index: "books"},
+		{method: http.MethodGet, path: "/_stats", name: "indices.stats"},
+		{method: http.MethodGet, path: "/_cat/indices/books", name: "cat.indices"},
+		{method: http.MethodGet, path: "/_cluster/health", name: "cluster.health"},
+		{method: http.MethodPut, path: "/_ingest/pipeline/ingest-books", name: "ingest.pipeline"},
+		{method: http.MethodGet, path: "/_nodes", name: "nodes.info"},
+		{method: http.MethodGet, path: "/_nodes/node-1/stats", name: "nodes.stats"},
+		{method: http.MethodGet, path: "/_tasks/node-1:42", name: "tasks"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			spans, _ := do(t, tc.method, tc.path, "", http.StatusOK)
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.name, spans[0].Name())
+			attrs := spans[0].Attributes()
+			assert.Contains(t, attrs, semconv.DBOperationKey.String(tc.name))
+			if tc.index != "" {
+				assert.Contains(t, attrs, otelelasticsearch.IndexKey.String(tc.index))
+			} else {
+				for _, kv := range attrs {
+					assert.NotEqual(t, otelelasticsearch.IndexKey, kv.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	transport := otelelasticsearch.NewTransport(
+		nil,
+		otelelasticsearch.WithTracerProvider(provider),
+		otelelasticsearch.WithPropagators(propagation.TraceContext{}),
+	)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/books/_doc/1", nil)
+	require.NoError(t, err)
+	res, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	parent.End()
+
+	// The request sent is not modified.
+	assert.Empty(t, req.Header.Get("traceparent"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "get", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBSystemElasticsearch)
+	assert.Contains(t, attrs, otelelasticsearch.IndexKey.String("books"))
+	assert.Contains(t, attrs, semconv.HTTPMethodKey.String(http.MethodGet))
+	assert.Contains(t, attrs, semconv.HTTPStatusCodeKey.Int(http.StatusOK))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestStatus(t *testing.T) {
+	// A missing document or index is not an error for the exists APIs.
+	spans, _ := do(t, http.MethodHead, "/books/_doc/1", "", http.StatusNotFound)
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusNotFound))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	spans, _ = do(t, http.MethodGet, "/books/_doc/1", "", http.StatusNotFound)
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	spans, _ = do(t, http.MethodPost, "/books/_search", "", http.StatusInternalServerError)
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+type errTransport struct{}
+
+func (errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	transport := otelelasticsearch.NewTransport(errTransport{}, otelelasticsearch.WithTracerProvider(provider))
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:9200/_bulk", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	assert.EqualError(t, err, "connection refused")
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "bulk", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "connection refused", spans[0].Status().Description)
+}
+
+func TestRequestBody(t *testing.T) {
+	const body = `{"query":{"match":{"title":"café"}}}`
+
+	// The bodies are not recorded by default.
+	spans, received := do(t, http.MethodPost, "/books/_search", body, http.StatusOK)
+	require.Len(t, spans, 1)
+	assert.Equal(t, body, received)
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, semconv.DBStatementKey, kv.Key)
+	}
+
+	spans, received = do(t, http.MethodPost, "/books/_search", body, http.StatusOK, otelelasticsearch.WithRequestBody(1024))
+	require.Len(t, spans, 1)
+	assert.Equal(t, body, received)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String(body))
+
+	// The body is truncated before the incomplete "é", but sent in full.
+	limit := strings.Index(body, "é") + 1
+	spans, received = do(t, http.MethodPost, "/books/_search", body, http.StatusOK, otelelasticsearch.WithRequestBody(limit))
+	require.Len(t, spans, 1)
+	assert.Equal(t, body, received)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String(body[:limit-1]))
+}
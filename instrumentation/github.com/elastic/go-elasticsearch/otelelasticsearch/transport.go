@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelelasticsearch // import "go.opentelemetry.io/contrib/instrumentation/github.com/elastic/go-elasticsearch/otelelasticsearch"
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/elastic/go-elasticsearch/otelelasticsearch"
+
+// IndexKey is the attribute key of the index, indices, or index pattern
+// targeted by a request.
+const IndexKey = attribute.Key("db.elasticsearch.index")
+
+// Transport implements the http.RoundTripper interface and traces the
+// requests of an Elasticsearch client.
+type Transport struct {
+	rt http.RoundTripper
+
+	tracer           trace.Tracer
+	propagators      propagation.TextMapPropagator
+	requestBodyLimit int
+}
+
+var _ http.RoundTripper = &Transport{}
+
+// NewTransport wraps the provided http.RoundTripper with one that traces the
+// requests of an Elasticsearch client and injects the span context into
+// their headers.
+//
+// If the provided http.RoundTripper is nil, http.DefaultTransport will be used
+// as the base http.RoundTripper.
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	cfg := newConfig(opts...)
+	return &Transport{
+		rt: base,
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		propagators:      cfg.Propagators,
+		requestBodyLimit: cfg.RequestBodyLimit,
+	}
+}
+
+// RoundTrip traces the request in a span named by the API it calls, child of
+// the span of the context of the request, and hands the request to the base
+// http.RoundTripper.  The span ends when the response is received.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	name, index := endpoint(r.Method, r.URL.Path)
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemElasticsearch,
+		semconv.DBOperationKey.String(name),
+	}
+	if index != "" {
+		attrs = append(attrs, IndexKey.String(index))
+	}
+	attrs = append(attrs, semconv.HTTPClientAttributesFromHTTPRequest(r)...)
+
+	ctx, span := t.tracer.Start(r.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	r = r.WithContext(ctx)
+	r.Header = r.Header.Clone()
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	t.propagators.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	if t.requestBodyLimit > 0 && r.Body != nil && r.Body != http.NoBody {
+		var statement string
+		statement, r.Body = captureBody(r.Body, t.requestBodyLimit)
+		if statement != "" {
+			span.SetAttributes(semconv.DBStatementKey.String(statement))
+		}
+	}
+
+	res, err := t.rt.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(res.StatusCode)...)
+	// The exists APIs respond to the HEAD requests with 404 Not Found when
+	// the document or index does not exist, which is not an error.
+	if r.Method != http.MethodHead || res.StatusCode != http.StatusNotFound {
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(res.StatusCode))
+	}
+	return res, nil
+}
+
+// readCloser combines the reader of the captured body with the closer of
+// the original one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureBody reads the first limit bytes of body, cut back to the last
+// complete UTF-8 character, and returns them with a body reading them again
+// followed by the rest of body.
+func captureBody(body io.ReadCloser, limit int) (string, io.ReadCloser) {
+	buf, _ := io.ReadAll(io.LimitReader(body, int64(limit)))
+	restored := &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		Closer: body,
+	}
+
+	captured := buf
+	if len(captured) == limit {
+		for i := len(captured) - 1; i >= 0 && i >= len(captured)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(captured[i]) {
+				if !utf8.FullRune(captured[i:]) {
+					captured = captured[:i]
+				}
+				break
+			}
+		}
+	}
+	return string(captured), restored
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelrestful // import "go.opentelemetry.io/contrib/instrumentation/github.com/emicklei/go-restful/otelrestful"
+
+import (
+	"context"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Metrics of the requests dispatched by a restful.Container to the
+// WebServices the filter is added to.
+const (
+	ServerDuration     = "http.server.duration"      // Duration of the requests, milliseconds
+	ServerRequestSize  = "http.server.request.size"  // Size of the request bodies, bytes
+	ServerResponseSize = "http.server.response.size" // Size of the bodies written with restful.Response, bytes
+)
+
+// serverMetrics holds the instruments of the restful.FilterFunction
+// returned by OTelFilter.  A nil *serverMetrics records nothing.
+type serverMetrics struct {
+	duration     syncfloat64.Histogram
+	requestSize  syncint64.Histogram
+	responseSize syncint64.Histogram
+}
+
+// newServerMetrics returns the instruments of the filter, or nil if it has
+// no meter provider.
+func newServerMetrics(mp metric.MeterProvider) *serverMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		tracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   serverMetrics
+		err error
+	)
+	if m.duration, err = meter.SyncFloat64().Histogram(
+		ServerDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the requests"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.requestSize, err = meter.SyncInt64().Histogram(
+		ServerRequestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the request bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.responseSize, err = meter.SyncInt64().Histogram(
+		ServerResponseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the response bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	return &m
+}
+
+// record records the duration since start and the sizes of the request req
+// and its response resp, with the method, route path, and status code of the
+// request as attributes.  The size of the requests of unknown length is not
+// recorded.
+func (m *serverMetrics) record(ctx context.Context, req *restful.Request, resp *restful.Response, start time.Time) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, semconv.HTTPMethodKey.String(req.Request.Method))
+	if route := req.SelectedRoutePath(); route != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(route))
+	}
+	attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(resp.StatusCode()))
+
+	m.duration.Record(ctx, elapsed, attrs...)
+	if req.Request.ContentLength >= 0 {
+		m.requestSize.Record(ctx, req.Request.ContentLength, attrs...)
+	}
+	m.responseSize.Record(ctx, int64(resp.ContentLength()), attrs...)
+}
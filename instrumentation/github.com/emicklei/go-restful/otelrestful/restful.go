@@ -15,6 +15,8 @@
 package otelrestful // import "go.opentelemetry.io/contrib/instrumentation/github.com/emicklei/go-restful/otelrestful"
 
 import (
+	"time"
+
 	"github.com/emicklei/go-restful/v3"
 
 	"go.opentelemetry.io/otel"
@@ -25,11 +27,13 @@ import (
 
 const tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/emicklei/go-restful/otelrestful"
 
-// OTelFilter returns a restful.FilterFunction which will trace an incoming request.
+// OTelFilter returns a restful.FilterFunction which will trace an incoming
+// request, and measure it if a meter provider is specified with
+// WithMeterProvider.
 //
 // The service parameter should describe the name of the (virtual) server handling
-// the request.  Options can be applied to configure the tracer and propagators
-// used for this filter.
+// the request.  Options can be applied to configure the tracer, meter, and
+// propagators used for this filter.
 func OTelFilter(service string, opts ...Option) restful.FilterFunction {
 	cfg := config{}
 	for _, opt := range opts {
@@ -45,7 +49,9 @@ func OTelFilter(service string, opts ...Option) restful.FilterFunction {
 	if cfg.Propagators == nil {
 		cfg.Propagators = otel.GetTextMapPropagator()
 	}
+	metrics := newServerMetrics(cfg.MeterProvider)
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		start := time.Now()
 		r := req.Request
 		ctx := cfg.Propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 		route := req.SelectedRoutePath()
@@ -68,5 +74,6 @@ func OTelFilter(service string, opts ...Option) restful.FilterFunction {
 		spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(resp.StatusCode(), oteltrace.SpanKindServer)
 		span.SetAttributes(attrs...)
 		span.SetStatus(spanStatus, spanMessage)
+		metrics.record(ctx, req, resp, start)
 	}
 }
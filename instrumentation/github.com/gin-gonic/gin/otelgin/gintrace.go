@@ -17,7 +17,9 @@
 package otelgin // import "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -25,6 +27,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	oteltrace "go.opentelemetry.io/otel/trace"
@@ -35,7 +38,8 @@ const (
 	tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-// Middleware returns middleware that will trace incoming requests.
+// Middleware returns middleware that will trace incoming requests, and
+// measure them if a meter provider is specified with WithMeterProvider.
 // The service parameter should describe the name of the (virtual)
 // server handling the request.
 func Middleware(service string, opts ...Option) gin.HandlerFunc {
@@ -53,8 +57,19 @@ func Middleware(service string, opts ...Option) gin.HandlerFunc {
 	if cfg.Propagators == nil {
 		cfg.Propagators = otel.GetTextMapPropagator()
 	}
+	if cfg.SpanNameFormatter == nil {
+		cfg.SpanNameFormatter = defaultSpanNameFormatter
+	}
+	metrics := newServerMetrics(cfg.MeterProvider)
+	requestHeaders := newCapturedHeaders("http.request.header.", cfg.RequestHeaders)
+	responseHeaders := newCapturedHeaders("http.response.header.", cfg.ResponseHeaders)
 	return func(c *gin.Context) {
+		start := time.Now()
 		c.Set(tracerKey, tracer)
+		if !cfg.traces(c) {
+			c.Next()
+			return
+		}
 		savedCtx := c.Request.Context()
 		defer func() {
 			c.Request = c.Request.WithContext(savedCtx)
@@ -64,13 +79,11 @@ func Middleware(service string, opts ...Option) gin.HandlerFunc {
 			oteltrace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", c.Request)...),
 			oteltrace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(c.Request)...),
 			oteltrace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(service, c.FullPath(), c.Request)...),
+			oteltrace.WithAttributes(headerAttributes(requestHeaders, c.Request.Header)...),
+			oteltrace.WithAttributes(baggageAttributes(ctx, cfg.BaggageKeys)...),
 			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
 		}
-		spanName := c.FullPath()
-		if spanName == "" {
-			spanName = fmt.Sprintf("HTTP %s route not found", c.Request.Method)
-		}
-		ctx, span := tracer.Start(ctx, spanName, opts...)
+		ctx, span := tracer.Start(ctx, cfg.SpanNameFormatter(c), opts...)
 		defer span.End()
 
 		// pass the span through the request context
@@ -83,11 +96,53 @@ func Middleware(service string, opts ...Option) gin.HandlerFunc {
 		attrs := semconv.HTTPAttributesFromHTTPStatusCode(status)
 		spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, oteltrace.SpanKindServer)
 		span.SetAttributes(attrs...)
+		span.SetAttributes(headerAttributes(responseHeaders, c.Writer.Header())...)
 		span.SetStatus(spanStatus, spanMessage)
 		if len(c.Errors) > 0 {
 			span.SetAttributes(attribute.String("gin.errors", c.Errors.String()))
 		}
+		metrics.record(ctx, c, start)
+	}
+}
+
+// traces reports whether the request served by c is neither served by a
+// skipped route nor excluded by a filter.
+func (cfg *config) traces(c *gin.Context) bool {
+	if _, ok := cfg.SkippedRoutes[c.FullPath()]; ok {
+		return false
+	}
+	for _, f := range cfg.Filters {
+		if !f(c.Request) {
+			return false
+		}
+	}
+	return true
+}
+
+// baggageAttributes returns the attributes of the members of the baggage of
+// ctx with the keys, named after the keys.
+func baggageAttributes(ctx context.Context, keys []string) []attribute.KeyValue {
+	if len(keys) == 0 {
+		return nil
+	}
+	bag := baggage.FromContext(ctx)
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		m := bag.Member(key)
+		if m.Key() == "" {
+			continue
+		}
+		attrs = append(attrs, attribute.String(key, m.Value()))
+	}
+	return attrs
+}
+
+// defaultSpanNameFormatter names the span after the route of the request.
+func defaultSpanNameFormatter(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
 	}
+	return fmt.Sprintf("HTTP %s route not found", c.Request.Method)
 }
 
 // HTML will trace the rendering of the template as a child of the
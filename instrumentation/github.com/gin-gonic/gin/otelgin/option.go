@@ -17,15 +17,35 @@
 package otelgin // import "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type config struct {
-	TracerProvider oteltrace.TracerProvider
-	Propagators    propagation.TextMapPropagator
+	TracerProvider    oteltrace.TracerProvider
+	MeterProvider     metric.MeterProvider
+	Propagators       propagation.TextMapPropagator
+	Filters           []Filter
+	SkippedRoutes     map[string]struct{}
+	SpanNameFormatter SpanNameFormatter
+	RequestHeaders    []string
+	ResponseHeaders   []string
+	BaggageKeys       []string
 }
 
+// Filter is a predicate used to determine whether a given http.Request should
+// be traced. A Filter must return true if the request should be traced.
+type Filter func(*http.Request) bool
+
+// SpanNameFormatter is used to set the span name of the request served by
+// the gin.Context, e.g. from its route, c.FullPath(), and its method.
+type SpanNameFormatter func(c *gin.Context) string
+
 // Option specifies instrumentation configuration options.
 type Option interface {
 	apply(*config)
@@ -57,3 +77,85 @@ func WithTracerProvider(provider oteltrace.TracerProvider) Option {
 		}
 	})
 }
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration and the sizes of the requests are only recorded if a meter
+// provider is specified, with the HTTP method, route, and status code of the
+// requests as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithFilter adds a filter to the list of filters used by the middleware.
+// If any filter indicates to exclude a request then the request is neither
+// traced nor measured. If no filters are provided then all requests are
+// traced. Filters are invoked for each processed request, it is advised to
+// make them simple and fast.
+func WithFilter(f Filter) Option {
+	return optionFunc(func(cfg *config) {
+		if f != nil {
+			cfg.Filters = append(cfg.Filters, f)
+		}
+	})
+}
+
+// WithSkippedRoutes specifies routes, as registered with gin (e.g.
+// "/healthz" or "/users/:id"), whose requests are neither traced nor
+// measured, such as the health and metrics endpoints.
+func WithSkippedRoutes(routes ...string) Option {
+	return optionFunc(func(cfg *config) {
+		if cfg.SkippedRoutes == nil {
+			cfg.SkippedRoutes = make(map[string]struct{}, len(routes))
+		}
+		for _, r := range routes {
+			cfg.SkippedRoutes[r] = struct{}{}
+		}
+	})
+}
+
+// WithSpanNameFormatter specifies a function to use for generating a custom
+// span name. By default, the route of the request is used, or
+// "HTTP <method> route not found" for the requests matching no route.
+func WithSpanNameFormatter(f SpanNameFormatter) Option {
+	return optionFunc(func(cfg *config) {
+		if f != nil {
+			cfg.SpanNameFormatter = f
+		}
+	})
+}
+
+// WithCapturedRequestHeaders specifies request headers whose values are
+// recorded as the http.request.header.<name> span attributes, <name> being
+// the header name lowercased with '-' replaced by '_'.  Headers absent from
+// a request are not recorded.
+func WithCapturedRequestHeaders(headers []string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.RequestHeaders = headers
+	})
+}
+
+// WithCapturedResponseHeaders specifies response headers whose values are
+// recorded as the http.response.header.<name> span attributes, <name> being
+// the header name lowercased with '-' replaced by '_'.  Headers absent from
+// a response are not recorded.
+func WithCapturedResponseHeaders(headers []string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.ResponseHeaders = headers
+	})
+}
+
+// WithBaggageAttributes specifies baggage keys whose values in the baggage
+// extracted from the requests are recorded as span attributes, named after
+// the keys, e.g. to query the spans by the tenant or experiment set
+// upstream.  The baggage is only extracted if the propagators include the
+// baggage propagator.  Keys absent from the baggage of a request are not
+// recorded.
+func WithBaggageAttributes(keys []string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.BaggageKeys = keys
+	})
+}
@@ -31,6 +31,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
@@ -203,3 +204,128 @@ func TestHTML(t *testing.T) {
 	require.NotNil(t, tspan)
 	assert.Contains(t, tspan.Attributes(), attribute.String("go.template", "hello"))
 }
+
+func TestFilter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(
+		"foobar",
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return r.Header.Get("X-Internal") == ""
+		}),
+		otelgin.WithSkippedRoutes("/healthz", "/metrics"),
+	))
+	router.GET("/healthz", func(c *gin.Context) {})
+	router.GET("/user/:id", func(c *gin.Context) {
+		// The context of the filtered requests has no span.
+		assert.Equal(t, c.GetHeader("X-Internal") == "", oteltrace.SpanFromContext(c.Request.Context()).SpanContext().IsValid())
+	})
+
+	for _, target := range []string{"/healthz", "/user/123", "/user/456"} {
+		r := httptest.NewRequest("GET", target, nil)
+		if target == "/user/456" {
+			r.Header.Set("X-Internal", "true")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/user/:id", spans[0].Name())
+}
+
+func TestSpanNameFormatter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(
+		"foobar",
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithSpanNameFormatter(func(c *gin.Context) string {
+			return c.Request.Method + " " + c.FullPath()
+		}),
+	))
+	router.GET("/user/:id", func(c *gin.Context) {})
+
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /user/:id", spans[0].Name())
+}
+
+func TestCapturedHeaders(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(
+		"foobar",
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithCapturedRequestHeaders([]string{"X-Tenant-ID", "x-correlation-id", "X-Missing"}),
+		otelgin.WithCapturedResponseHeaders([]string{"Content-Encoding"}),
+	))
+	router.GET("/user/:id", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Header("X-Other", "other")
+		c.Status(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("X-Tenant-Id", "tenant")
+	r.Header.Add("X-Correlation-Id", "a")
+	r.Header.Add("X-Correlation-Id", "b")
+	r.Header.Set("X-Other", "other")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, attribute.StringSlice("http.request.header.x_tenant_id", []string{"tenant"}))
+	assert.Contains(t, attrs, attribute.StringSlice("http.request.header.x_correlation_id", []string{"a", "b"}))
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.header.content_encoding", []string{"gzip"}))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("http.request.header.x_missing"), kv.Key)
+		assert.NotEqual(t, attribute.Key("http.request.header.x_other"), kv.Key)
+		assert.NotEqual(t, attribute.Key("http.response.header.x_other"), kv.Key)
+	}
+}
+
+func TestBaggageAttributes(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	router := gin.New()
+	router.Use(otelgin.Middleware(
+		"foobar",
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})),
+		otelgin.WithBaggageAttributes([]string{"tenant", "missing"}),
+	))
+	router.GET("/user/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("Baggage", "tenant=acme,experiment=blue")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, attribute.String("tenant", "acme"))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("missing"), kv.Key)
+		assert.NotEqual(t, attribute.Key("experiment"), kv.Key)
+	}
+}
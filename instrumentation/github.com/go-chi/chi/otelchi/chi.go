@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelchi // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi"
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/go-chi/chi/v5"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi"
+)
+
+// Middleware sets up a handler to start tracing the incoming
+// requests, and measuring them if a meter provider is specified with
+// WithMeterProvider.  The service parameter should describe the name of the
+// (virtual) server handling the request.
+//
+// The middleware is meant to be used by a chi router, with its Use method.
+func Middleware(service string, opts ...Option) func(http.Handler) http.Handler {
+	cfg := config{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+	tracer := cfg.TracerProvider.Tracer(
+		tracerName,
+		oteltrace.WithInstrumentationVersion(SemVersion()),
+	)
+	if cfg.Propagators == nil {
+		cfg.Propagators = otel.GetTextMapPropagator()
+	}
+	if cfg.SpanNameFormatter == nil {
+		cfg.SpanNameFormatter = defaultSpanNameFormatter
+	}
+	metrics := newServerMetrics(cfg.MeterProvider)
+	return func(handler http.Handler) http.Handler {
+		return traceware{
+			service:           service,
+			tracer:            tracer,
+			metrics:           metrics,
+			propagators:       cfg.Propagators,
+			filters:           cfg.Filters,
+			handler:           handler,
+			spanNameFormatter: cfg.SpanNameFormatter,
+		}
+	}
+}
+
+type traceware struct {
+	service     string
+	tracer      oteltrace.Tracer
+	metrics     *serverMetrics
+	propagators propagation.TextMapPropagator
+	filters     []Filter
+	handler     http.Handler
+
+	spanNameFormatter func(string, *http.Request) string
+}
+
+// defaultSpanNameFormatter names the span after the route pattern, so that
+// the span names of the requests matching no route are not the raw paths.
+func defaultSpanNameFormatter(routePattern string, r *http.Request) string {
+	if routePattern == "" {
+		return fmt.Sprintf("HTTP %s route not found", r.Method)
+	}
+	return routePattern
+}
+
+type recordingResponseWriter struct {
+	writer  http.ResponseWriter
+	written bool
+	status  int
+	size    int64
+}
+
+var rrwPool = &sync.Pool{
+	New: func() interface{} {
+		return &recordingResponseWriter{}
+	},
+}
+
+func getRRW(writer http.ResponseWriter) *recordingResponseWriter {
+	rrw := rrwPool.Get().(*recordingResponseWriter)
+	rrw.written = false
+	rrw.status = http.StatusOK
+	rrw.size = 0
+	rrw.writer = httpsnoop.Wrap(writer, httpsnoop.Hooks{
+		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return func(b []byte) (int, error) {
+				if !rrw.written {
+					rrw.written = true
+				}
+				n, err := next(b)
+				rrw.size += int64(n)
+				return n, err
+			}
+		},
+		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return func(statusCode int) {
+				if !rrw.written {
+					rrw.written = true
+					rrw.status = statusCode
+				}
+				next(statusCode)
+			}
+		},
+	})
+	return rrw
+}
+
+func putRRW(rrw *recordingResponseWriter) {
+	rrw.writer = nil
+	rrwPool.Put(rrw)
+}
+
+// routePattern returns the pattern of the route matched by the request, or
+// "" if no route was matched, e.g. if the middleware wraps a handler instead
+// of being used by a chi router.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
+// traces returns whether the request passes all the filters and should be
+// traced.
+func (tw traceware) traces(r *http.Request) bool {
+	for _, f := range tw.filters {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeHTTP implements the http.Handler interface. It does the actual
+// tracing of the request.
+func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !tw.traces(r) {
+		tw.handler.ServeHTTP(w, r)
+		return
+	}
+	start := time.Now()
+	ctx := tw.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	opts := []oteltrace.SpanStartOption{
+		oteltrace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", r)...),
+		oteltrace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(r)...),
+		oteltrace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(tw.service, "", r)...),
+		oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+	}
+	// The route of the request is only known once chi has routed it, the
+	// span is renamed after it then.
+	ctx, span := tw.tracer.Start(ctx, "HTTP "+r.Method, opts...)
+	defer span.End()
+	r2 := r.WithContext(ctx)
+	rrw := getRRW(w)
+	defer putRRW(rrw)
+	tw.handler.ServeHTTP(rrw.writer, r2)
+
+	route := routePattern(r)
+	span.SetName(tw.spanNameFormatter(route, r))
+	if route != "" {
+		span.SetAttributes(semconv.HTTPRouteKey.String(route))
+	}
+	attrs := semconv.HTTPAttributesFromHTTPStatusCode(rrw.status)
+	spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rrw.status, oteltrace.SpanKindServer)
+	span.SetAttributes(attrs...)
+	span.SetStatus(spanStatus, spanMessage)
+	tw.metrics.record(ctx, r, route, rrw.status, rrw.size, start)
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelchi // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// config is used to configure the chi middleware.
+type config struct {
+	TracerProvider    oteltrace.TracerProvider
+	MeterProvider     metric.MeterProvider
+	Propagators       propagation.TextMapPropagator
+	Filters           []Filter
+	SpanNameFormatter func(string, *http.Request) string
+}
+
+// Filter is a predicate used to determine whether a given http.Request should
+// be traced. A Filter must return true if the request should be traced.
+type Filter func(*http.Request) bool
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// WithPropagators specifies propagators to use for extracting
+// information from the HTTP requests. If none are specified, global
+// ones will be used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		if propagators != nil {
+			cfg.Propagators = propagators
+		}
+	})
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider oteltrace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.TracerProvider = provider
+		}
+	})
+}
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration and the sizes of the requests are only recorded if a meter
+// provider is specified, with the HTTP method, route pattern, and status
+// code of the requests as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithFilter adds a filter to the list of filters used by the middleware.
+// If any filter indicates to exclude a request then the request is neither
+// traced nor measured. If no filters are provided then all requests are
+// traced. Filters are invoked for each processed request, it is advised to
+// make them simple and fast.
+func WithFilter(f Filter) Option {
+	return optionFunc(func(cfg *config) {
+		if f != nil {
+			cfg.Filters = append(cfg.Filters, f)
+		}
+	})
+}
+
+// WithSpanNameFormatter specifies a function to use for generating a custom
+// span name from the route pattern of the request, e.g. "/users/{id}", and
+// the request. The route pattern is empty for the requests matching no
+// route. By default, the span is named after the route pattern, or
+// "HTTP <method> route not found" for the requests matching no route.
+func WithSpanNameFormatter(fn func(routePattern string, r *http.Request) string) Option {
+	return optionFunc(func(cfg *config) {
+		if fn != nil {
+			cfg.SpanNameFormatter = fn
+		}
+	})
+}
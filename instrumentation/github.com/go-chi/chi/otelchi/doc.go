@@ -0,0 +1,25 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelchi instruments the github.com/go-chi/chi/v5 package.
+//
+// The requests served by a chi router are traced, and measured if a meter
+// provider is specified, by the Middleware:
+//
+//	r := chi.NewRouter()
+//	r.Use(otelchi.Middleware("my-service"))
+//
+// The spans are named after the route patterns matched by the requests, eg
+// "/users/{id}".
+package otelchi // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi"
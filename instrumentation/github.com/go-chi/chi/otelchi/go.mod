@@ -0,0 +1,16 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi
+
+go 1.17
+
+require (
+	github.com/felixge/httpsnoop v1.0.3
+	github.com/go-chi/chi/v5 v5.0.7
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
+
+require (
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+)
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ok(w http.ResponseWriter, _ *http.Request) {}
+func internalError(w http.ResponseWriter, _ *http.Request) {
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func TestSDKIntegration(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar", otelchi.WithTracerProvider(provider)))
+	router.Get("/user/{id:[0-9]+}", ok)
+	router.Route("/book", func(r chi.Router) {
+		r.Get("/{title}", ok)
+	})
+
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r1 := httptest.NewRequest("GET", "/book/foo", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r0)
+	router.ServeHTTP(w, r1)
+
+	require.Len(t, sr.Ended(), 2)
+	assertSpan(t, sr.Ended()[0],
+		"/user/{id:[0-9]+}",
+		trace.SpanKindServer,
+		attribute.String("http.server_name", "foobar"),
+		attribute.Int("http.status_code", http.StatusOK),
+		attribute.String("http.method", "GET"),
+		attribute.String("http.target", "/user/123"),
+		attribute.String("http.route", "/user/{id:[0-9]+}"),
+	)
+	assertSpan(t, sr.Ended()[1],
+		"/book/{title}",
+		trace.SpanKindServer,
+		attribute.String("http.server_name", "foobar"),
+		attribute.Int("http.status_code", http.StatusOK),
+		attribute.String("http.method", "GET"),
+		attribute.String("http.target", "/book/foo"),
+		attribute.String("http.route", "/book/{title}"),
+	)
+}
+
+func TestSpanStatus(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar", otelchi.WithTracerProvider(provider)))
+	router.Get("/error", internalError)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/error", nil))
+
+	require.Len(t, sr.Ended(), 1)
+	assertSpan(t, sr.Ended()[0],
+		"/error",
+		trace.SpanKindServer,
+		attribute.Int("http.status_code", http.StatusInternalServerError),
+	)
+	assert.Equal(t, codes.Error, sr.Ended()[0].Status().Code)
+}
+
+func TestRouteNotFound(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar", otelchi.WithTracerProvider(provider)))
+	router.Get("/user/{id}", ok)
+
+	r0 := httptest.NewRequest("GET", "/unknown", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r0)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assertSpan(t, span,
+		"HTTP GET route not found",
+		trace.SpanKindServer,
+		attribute.Int("http.status_code", http.StatusNotFound),
+		attribute.String("http.target", "/unknown"),
+	)
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	for _, a := range span.Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route"), a.Key)
+	}
+}
+
+func TestSpanNameFormatter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware(
+		"foobar",
+		otelchi.WithTracerProvider(provider),
+		otelchi.WithSpanNameFormatter(func(routePattern string, r *http.Request) string {
+			return r.Method + " " + routePattern
+		}),
+	))
+	router.Get("/user/{id}", ok)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	require.Len(t, sr.Ended(), 1)
+	assertSpan(t, sr.Ended()[0],
+		"GET /user/{id}",
+		trace.SpanKindServer,
+		attribute.String("http.route", "/user/{id}"),
+	)
+}
+
+func TestFilter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware(
+		"foobar",
+		otelchi.WithTracerProvider(provider),
+		otelchi.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	))
+	var served int
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		served++
+	}
+	router.Get("/healthz", handler)
+	router.Get("/user/{id}", handler)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	assert.Equal(t, 2, served, "filtered requests should still be served")
+	require.Len(t, sr.Ended(), 1)
+	assert.Equal(t, "/user/{id}", sr.Ended()[0].Name())
+}
+
+func TestPropagation(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+	propagator := propagation.TraceContext{}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	parent.End()
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware(
+		"foobar",
+		otelchi.WithTracerProvider(provider),
+		otelchi.WithPropagators(propagator),
+	))
+	var got trace.SpanContext
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		got = trace.SpanContextFromContext(r.Context())
+	})
+
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r0.Header))
+	router.ServeHTTP(httptest.NewRecorder(), r0)
+
+	require.Len(t, sr.Ended(), 2)
+	span := sr.Ended()[1]
+	assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, span.SpanContext(), got)
+}
+
+func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) {
+	assert.Equal(t, name, span.Name())
+	assert.Equal(t, kind, span.SpanKind())
+
+	got := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
+	for _, a := range span.Attributes() {
+		got[a.Key] = a.Value
+	}
+	for _, want := range attrs {
+		if !assert.Contains(t, got, want.Key) {
+			continue
+		}
+		assert.Equal(t, got[want.Key], want.Value)
+	}
+}
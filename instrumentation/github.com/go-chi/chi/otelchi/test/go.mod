@@ -0,0 +1,26 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi/test
+
+go 1.17
+
+require (
+	github.com/go-chi/chi/v5 v5.0.7
+	github.com/stretchr/testify v1.8.0
+	go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi v0.34.0
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/sdk v1.9.0
+	go.opentelemetry.io/otel/sdk/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/felixge/httpsnoop v1.0.3 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel/metric v0.31.0 // indirect
+	golang.org/x/sys v0.0.0-20210423185535-09eb48e85fd7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace go.opentelemetry.io/contrib/instrumentation/github.com/go-chi/chi/otelchi => ../
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelredis // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// config is used to configure the instrumented clients.
+type config struct {
+	TracerProvider     trace.TracerProvider
+	MeterProvider      metric.MeterProvider
+	Attributes         []attribute.KeyValue
+	StatementMaxLength int
+}
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// newConfig creates a new config struct and applies opts to it.
+func newConfig(opts ...Option) *config {
+	c := &config{
+		TracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.TracerProvider = provider
+		}
+	})
+}
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the commands and the metrics of the connection pools are
+// only recorded if a meter provider is specified.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithAttributes specifies attributes added to the spans and the metrics of
+// all the commands, e.g. the db.redis.database_index of the clients
+// instrumented with NewHook.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.Attributes = append(cfg.Attributes, attrs...)
+	})
+}
+
+// WithStatementMaxLength specifies the maximum length, in bytes, of the
+// db.statement span attribute, longer statements being truncated.  The
+// statements are not truncated by default, or if maxLength is not positive.
+func WithStatementMaxLength(maxLength int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.StatementMaxLength = maxLength
+	})
+}
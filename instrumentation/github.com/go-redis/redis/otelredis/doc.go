@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelredis instruments the github.com/go-redis/redis/v8 package.
+//
+// The commands and pipelines of a client are traced, and their duration is
+// measured if a meter provider is specified, once the client is instrumented
+// with Instrument:
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	if err := otelredis.Instrument(rdb); err != nil {
+//		panic(err)
+//	}
+//
+// Instrument also records the metrics of the connection pool of the client
+// if a meter provider is specified.  NewHook returns the hook tracing the
+// commands, to be added to the clients with their AddHook method.
+//
+// The spans record the commands and their arguments as the db.statement
+// attribute, which can be truncated with WithStatementMaxLength, and the
+// name of the commands, e.g. "get", as the db.operation attribute.  The
+// contexts the commands are run with are the parents of the spans.
+package otelredis // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
@@ -0,0 +1,17 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis
+
+go 1.17
+
+require (
+	github.com/go-redis/redis/v8 v8.11.5
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+)
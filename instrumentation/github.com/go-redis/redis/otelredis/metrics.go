@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelredis // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Client command metrics.
+const (
+	ClientOperationDuration = "db.client.operation.duration" // Duration of the commands and pipelines, milliseconds
+)
+
+// Client connection pool metrics.
+const (
+	ClientConnectionsUsage    = "db.client.connections.usage"    // Number of the connections per state
+	ClientConnectionsHits     = "db.client.connections.hits"     // Number of the free connections found in the pool
+	ClientConnectionsMisses   = "db.client.connections.misses"   // Number of the free connections not found in the pool
+	ClientConnectionsTimeouts = "db.client.connections.timeouts" // Number of the waits for a connection timed out
+)
+
+// ConnectionStateKey is the state of the connections, "idle" or "used", of
+// the db.client.connections.usage metric.
+const ConnectionStateKey = attribute.Key("state")
+
+// commandMetrics holds the instruments of the commands of the clients.  A
+// nil *commandMetrics records nothing.
+type commandMetrics struct {
+	duration syncfloat64.Histogram
+}
+
+// newCommandMetrics returns the instruments of the commands, or nil if the
+// metrics are not enabled.
+func newCommandMetrics(mp metric.MeterProvider) *commandMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   commandMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ClientOperationDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the commands and pipelines"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// record records the duration since start of the operation with the
+// attributes.
+func (m *commandMetrics) record(ctx context.Context, operation string, start time.Time, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	attrs = append(attrs[:len(attrs):len(attrs)], semconv.DBOperationKey.String(operation))
+	m.duration.Record(ctx, elapsed, attrs...)
+}
+
+// registerPoolMetrics registers the instruments observing the statistics of
+// the connection pool of the client, if the metrics are enabled.
+func registerPoolMetrics(cfg *config, client redis.UniversalClient) error {
+	if cfg.MeterProvider == nil {
+		return nil
+	}
+	meter := cfg.MeterProvider.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	usage, err := meter.AsyncInt64().UpDownCounter(
+		ClientConnectionsUsage,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections per state"),
+	)
+	if err != nil {
+		return err
+	}
+	hits, err := meter.AsyncInt64().Counter(
+		ClientConnectionsHits,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the free connections found in the pool"),
+	)
+	if err != nil {
+		return err
+	}
+	misses, err := meter.AsyncInt64().Counter(
+		ClientConnectionsMisses,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the free connections not found in the pool"),
+	)
+	if err != nil {
+		return err
+	}
+	timeouts, err := meter.AsyncInt64().Counter(
+		ClientConnectionsTimeouts,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the waits for a connection timed out"),
+	)
+	if err != nil {
+		return err
+	}
+
+	attrs := append([]attribute.KeyValue{semconv.DBSystemRedis}, cfg.Attributes...)
+	idleAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("idle"))
+	usedAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("used"))
+	instruments := []instrument.Asynchronous{usage, hits, misses, timeouts}
+	return meter.RegisterCallback(instruments, func(ctx context.Context) {
+		stats := client.PoolStats()
+		usage.Observe(ctx, int64(stats.IdleConns), idleAttrs...)
+		usage.Observe(ctx, int64(stats.TotalConns)-int64(stats.IdleConns), usedAttrs...)
+		hits.Observe(ctx, int64(stats.Hits), attrs...)
+		misses.Observe(ctx, int64(stats.Misses), attrs...)
+		timeouts.Observe(ctx, int64(stats.Timeouts), attrs...)
+	})
+}
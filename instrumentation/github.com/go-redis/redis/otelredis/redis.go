@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelredis // import "go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+
+// Operation of the pipelines, recorded as their span name and db.operation
+// attribute.
+const pipelineOperation = "pipeline"
+
+// NumCommandsKey is the number of the commands of a pipeline.
+const NumCommandsKey = attribute.Key("db.redis.num_cmd")
+
+// Instrument adds the hook tracing the commands returned by NewHook to the
+// client, and records the metrics of its connection pool if a meter provider
+// is specified.
+//
+// The database index and the address of the server of a *redis.Client are
+// recorded as the db.redis.database_index, net.peer.name, and net.peer.port
+// attributes.
+func Instrument(client redis.UniversalClient, opts ...Option) error {
+	cfg := newConfig(opts...)
+	if c, ok := client.(*redis.Client); ok {
+		cfg.Attributes = append(clientAttributes(c.Options()), cfg.Attributes...)
+	}
+	client.AddHook(newHook(cfg))
+	return registerPoolMetrics(cfg, client)
+}
+
+// NewHook returns a hook tracing the commands and the pipelines of the
+// clients it is added to, and measuring their duration if a meter provider is
+// specified.
+func NewHook(opts ...Option) redis.Hook {
+	return newHook(newConfig(opts...))
+}
+
+// clientAttributes returns the attributes of the commands of a client.
+func clientAttributes(opt *redis.Options) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.DBRedisDBIndexKey.Int(opt.DB)}
+	if opt.Network != "" && opt.Network != "tcp" {
+		return attrs
+	}
+	host, port, err := net.SplitHostPort(opt.Addr)
+	if err != nil {
+		return attrs
+	}
+	attrs = append(attrs, semconv.NetPeerNameKey.String(host))
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
+
+type hook struct {
+	tracer             trace.Tracer
+	metrics            *commandMetrics
+	attrs              []attribute.KeyValue
+	statementMaxLength int
+}
+
+func newHook(cfg *config) *hook {
+	return &hook{
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		metrics:            newCommandMetrics(cfg.MeterProvider),
+		attrs:              append([]attribute.KeyValue{semconv.DBSystemRedis}, cfg.Attributes...),
+		statementMaxLength: cfg.StatementMaxLength,
+	}
+}
+
+// startedKey is the key of the started command in the contexts returned by
+// the hook.
+type startedKey struct{}
+
+// started is a command, or pipeline, started by the hook.
+type started struct {
+	span      trace.Span
+	operation string
+	start     time.Time
+}
+
+func (h *hook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return h.start(ctx, cmd.FullName(), appendCommand(nil, cmd)), nil
+}
+
+func (h *hook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.end(ctx, cmd.Err())
+	return nil
+}
+
+func (h *hook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	var statement []byte
+	for i, cmd := range cmds {
+		if i > 0 {
+			statement = append(statement, '\n')
+		}
+		statement = appendCommand(statement, cmd)
+	}
+	return h.start(ctx, pipelineOperation, statement, NumCommandsKey.Int(len(cmds))), nil
+}
+
+func (h *hook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if err = cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+			break
+		}
+	}
+	h.end(ctx, err)
+	return nil
+}
+
+// start starts the span of the operation, with the statement and the
+// attributes, and returns the context to end it with.
+func (h *hook) start(ctx context.Context, operation string, statement []byte, attrs ...attribute.KeyValue) context.Context {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(h.attrs...),
+		trace.WithAttributes(
+			semconv.DBOperationKey.String(operation),
+			semconv.DBStatementKey.String(h.truncate(statement)),
+		),
+		trace.WithAttributes(attrs...),
+	}
+	ctx, span := h.tracer.Start(ctx, operation, opts...)
+	return context.WithValue(ctx, startedKey{}, started{
+		span:      span,
+		operation: operation,
+		start:     time.Now(),
+	})
+}
+
+// end ends the span of the operation started with ctx, recording err unless
+// it is redis.Nil, the reply to the lookups of missing keys, and measures its
+// duration.
+func (h *hook) end(ctx context.Context, err error) {
+	s, ok := ctx.Value(startedKey{}).(started)
+	if !ok {
+		return
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+	h.metrics.record(ctx, s.operation, s.start, h.attrs)
+}
+
+// truncate returns the statement truncated to the maximum length, if any,
+// without splitting its UTF-8 characters.
+func (h *hook) truncate(statement []byte) string {
+	if h.statementMaxLength <= 0 || len(statement) <= h.statementMaxLength {
+		return string(statement)
+	}
+	n := h.statementMaxLength
+	for n > 0 && !utf8.RuneStart(statement[n]) {
+		n--
+	}
+	return string(statement[:n])
+}
+
+// appendCommand appends the arguments of the command, separated by spaces.
+func appendCommand(b []byte, cmd redis.Cmder) []byte {
+	for i, arg := range cmd.Args() {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		switch arg := arg.(type) {
+		case string:
+			b = append(b, arg...)
+		case []byte:
+			b = append(b, arg...)
+		case int:
+			b = strconv.AppendInt(b, int64(arg), 10)
+		case int64:
+			b = strconv.AppendInt(b, arg, 10)
+		default:
+			b = append(b, fmt.Sprint(arg)...)
+		}
+	}
+	return b
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	hook := otelredis.NewHook(otelredis.WithMeterProvider(mp))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		process(t, hook, ctx, redis.NewStringCmd(ctx, "get", "key"), nil)
+	}
+	processPipeline(t, hook, ctx, redis.NewStringCmd(ctx, "get", "key"), redis.NewStatusCmd(ctx, "set", "key", 1))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	get := []attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBOperationKey.String("get"),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelredis.ClientOperationDuration, get)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(2), duration.Count)
+
+	pipeline := []attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBOperationKey.String("pipeline"),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelredis.ClientOperationDuration, pipeline)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestPoolMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	// No server is listening, no connection is established.
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+	require.NoError(t, otelredis.Instrument(client, otelredis.WithMeterProvider(mp)))
+	assert.Error(t, client.Get(context.Background(), "key").Err())
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemRedis,
+		semconv.DBRedisDBIndexKey.Int(0),
+		semconv.NetPeerNameKey.String("127.0.0.1"),
+		semconv.NetPeerPortKey.Int(1),
+	}
+	for _, state := range []string{"idle", "used"} {
+		usage, err := exp.GetByNameAndAttributes(
+			otelredis.ClientConnectionsUsage,
+			append(attrs[:len(attrs):len(attrs)], otelredis.ConnectionStateKey.String(state)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), usage.Sum.AsInt64(), state)
+	}
+	for _, name := range []string{
+		otelredis.ClientConnectionsHits,
+		otelredis.ClientConnectionsMisses,
+		otelredis.ClientConnectionsTimeouts,
+	} {
+		_, err := exp.GetByNameAndAttributes(name, attrs)
+		assert.NoError(t, err, name)
+	}
+}
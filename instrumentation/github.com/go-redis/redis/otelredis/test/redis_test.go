@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/go-redis/redis/otelredis"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newHook returns a hook traced by the returned recorder, and the context of
+// a span parent of the spans of the commands.
+func newHook(t *testing.T, opts ...otelredis.Option) (redis.Hook, *tracetest.SpanRecorder, context.Context) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts = append([]otelredis.Option{otelredis.WithTracerProvider(provider)}, opts...)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	t.Cleanup(func() { parent.End() })
+	return otelredis.NewHook(opts...), sr, ctx
+}
+
+// process runs the hook around a command returning err.
+func process(t *testing.T, hook redis.Hook, ctx context.Context, cmd redis.Cmder, err error) {
+	ctx, e := hook.BeforeProcess(ctx, cmd)
+	require.NoError(t, e)
+	cmd.SetErr(err)
+	require.NoError(t, hook.AfterProcess(ctx, cmd))
+}
+
+// processPipeline runs the hook around a pipeline of commands.
+func processPipeline(t *testing.T, hook redis.Hook, ctx context.Context, cmds ...redis.Cmder) {
+	ctx, err := hook.BeforeProcessPipeline(ctx, cmds)
+	require.NoError(t, err)
+	require.NoError(t, hook.AfterProcessPipeline(ctx, cmds))
+}
+
+func TestCommand(t *testing.T) {
+	hook, sr, ctx := newHook(t, otelredis.WithAttributes(semconv.DBRedisDBIndexKey.Int(1)))
+
+	process(t, hook, ctx, redis.NewStatusCmd(ctx, "set", "key", []byte("value"), "ex", int64(10)), nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "set", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBSystemRedis)
+	assert.Contains(t, attrs, semconv.DBRedisDBIndexKey.Int(1))
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("set"))
+	assert.Contains(t, attrs, semconv.DBStatementKey.String("set key value ex 10"))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestCommandError(t *testing.T) {
+	hook, sr, ctx := newHook(t)
+
+	process(t, hook, ctx, redis.NewStringCmd(ctx, "get", "missing"), redis.Nil)
+	process(t, hook, ctx, redis.NewStringCmd(ctx, "get", "key"), errors.New("ERR fail"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	// The lookups of missing keys are not errors.
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "ERR fail", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
+
+func TestPipeline(t *testing.T) {
+	hook, sr, ctx := newHook(t)
+
+	get := redis.NewStringCmd(ctx, "get", "missing")
+	get.SetErr(redis.Nil)
+	set := redis.NewStatusCmd(ctx, "set", "key", 42)
+	processPipeline(t, hook, ctx, get, set)
+
+	set.SetErr(errors.New("ERR fail"))
+	processPipeline(t, hook, ctx, get, set)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "pipeline", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBSystemRedis)
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("pipeline"))
+	assert.Contains(t, attrs, semconv.DBStatementKey.String("get missing\nset key 42"))
+	assert.Contains(t, attrs, otelredis.NumCommandsKey.Int(2))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "ERR fail", spans[1].Status().Description)
+}
+
+func TestStatementMaxLength(t *testing.T) {
+	hook, sr, ctx := newHook(t, otelredis.WithStatementMaxLength(10))
+
+	process(t, hook, ctx, redis.NewStatusCmd(ctx, "set", "key", "value"), nil)
+	process(t, hook, ctx, redis.NewStatusCmd(ctx, "set", "key", "éé"), nil)
+	process(t, hook, ctx, redis.NewStringCmd(ctx, "get", "key"), nil)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String("set key va"))
+	// The UTF-8 characters are not split.
+	assert.Contains(t, spans[1].Attributes(), semconv.DBStatementKey.String("set key é"))
+	assert.Contains(t, spans[2].Attributes(), semconv.DBStatementKey.String("get key"))
+}
+
+func TestInstrument(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// No server is listening, the commands fail.
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DB: 2, MaxRetries: -1})
+	defer client.Close()
+	require.NoError(t, otelredis.Instrument(client, otelredis.WithTracerProvider(provider)))
+
+	assert.Error(t, client.Get(context.Background(), "key").Err())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, semconv.DBRedisDBIndexKey.Int(2))
+	assert.Contains(t, attrs, semconv.NetPeerNameKey.String("127.0.0.1"))
+	assert.Contains(t, attrs, semconv.NetPeerPortKey.Int(1))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -39,3 +39,20 @@ func TestHostOrIP(t *testing.T) {
 	attribute = internal.HostOrIP(hostAndPort)
 	require.Empty(t, attribute.Value.AsString())
 }
+
+func TestCassQueryOperation(t *testing.T) {
+	testCases := []struct {
+		stmt string
+		want string
+	}{
+		{stmt: "insert into t (id) values (?)", want: "INSERT"},
+		{stmt: "  SELECT * FROM t", want: "SELECT"},
+		{stmt: "\nupdate t set a = ?", want: "UPDATE"},
+		{stmt: "", want: "db.cassandra.query"},
+	}
+	for _, tc := range testCases {
+		attr := internal.CassQueryOperation(tc.stmt)
+		assert.Equal(t, semconv.DBOperationKey, attr.Key)
+		assert.Equal(t, tc.want, attr.Value.AsString(), tc.stmt)
+	}
+}
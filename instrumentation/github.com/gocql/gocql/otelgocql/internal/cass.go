@@ -17,6 +17,7 @@ package internal // import "go.opentelemetry.io/contrib/instrumentation/github.c
 import (
 	"log"
 	"net"
+	"strings"
 
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
@@ -35,6 +36,14 @@ const (
 	// the state of the casssandra server hosting the node being queried.
 	CassHostStateKey = attribute.Key("db.cassandra.host.state")
 
+	// CassHostDCKey is the key for the attribute/label describing
+	// the datacenter of the host being queried.
+	CassHostDCKey = attribute.Key("db.cassandra.host.dc")
+
+	// CassHostRackKey is the key for the attribute/label describing
+	// the rack of the host being queried.
+	CassHostRackKey = attribute.Key("db.cassandra.host.rack")
+
 	// CassBatchQueriesKey is the key for the attribute describing
 	// the number of queries contained within the batch statement.
 	CassBatchQueriesKey = attribute.Key("db.cassandra.batch.queries")
@@ -102,6 +111,16 @@ func CassHostState(state string) attribute.KeyValue {
 	return CassHostStateKey.String(state)
 }
 
+// CassHostDC returns the datacenter of the cassandra host as a KeyValue pair.
+func CassHostDC(dc string) attribute.KeyValue {
+	return CassHostDCKey.String(dc)
+}
+
+// CassHostRack returns the rack of the cassandra host as a KeyValue pair.
+func CassHostRack(rack string) attribute.KeyValue {
+	return CassHostRackKey.String(rack)
+}
+
 // ------------------------------------------ Call-level attributes
 
 // CassStatement returns the statement made to the cassandra database as a
@@ -110,6 +129,18 @@ func CassStatement(stmt string) attribute.KeyValue {
 	return semconv.DBStatementKey.String(stmt)
 }
 
+// CassQueryOperation returns the operation of the statement, its first
+// keyword in upper case (e.g. SELECT), as a semconv KeyValue pair
+// (db.operation). Unlike the db.statement, the operation is of a low enough
+// cardinality to be used as a label of the instruments.
+func CassQueryOperation(stmt string) attribute.KeyValue {
+	cassQueryOperation := "db.cassandra.query"
+	if fields := strings.Fields(stmt); len(fields) > 0 {
+		cassQueryOperation = strings.ToUpper(fields[0])
+	}
+	return semconv.DBOperationKey.String(cassQueryOperation)
+}
+
 // CassBatchQueryOperation returns the batch query operation
 // as a semconv KeyValue pair (db.operation). This is used in lieu of a
 // db.statement, which is not feasible to include in a span for a batch query
@@ -60,10 +60,12 @@ func (o *OTelQueryObserver) ObserveQuery(ctx context.Context, observedQuery gocq
 	if o.enabled {
 		host := observedQuery.Host
 		keyspace := observedQuery.Keyspace
+		operation := internal.CassQueryOperation(observedQuery.Statement)
 		inst := o.inst
 
-		attributes := includeKeyValues(host,
+		attributes := includeHostKeyValues(host,
 			internal.CassKeyspace(keyspace),
+			operation,
 			internal.CassStatement(observedQuery.Statement),
 			internal.CassRowsReturned(observedQuery.Rows),
 			internal.CassQueryAttempts(observedQuery.Metrics.Attempts),
@@ -82,7 +84,7 @@ func (o *OTelQueryObserver) ObserveQuery(ctx context.Context, observedQuery gocq
 			inst.queryCount.Add(
 				ctx,
 				1,
-				includeKeyValues(host,
+				includeHostKeyValues(host,
 					internal.CassKeyspace(keyspace),
 					internal.CassStatement(observedQuery.Statement),
 					internal.CassErrMsg(observedQuery.Err.Error()),
@@ -92,7 +94,7 @@ func (o *OTelQueryObserver) ObserveQuery(ctx context.Context, observedQuery gocq
 			inst.queryCount.Add(
 				ctx,
 				1,
-				includeKeyValues(host,
+				includeHostKeyValues(host,
 					internal.CassKeyspace(keyspace),
 					internal.CassStatement(observedQuery.Statement),
 				)...,
@@ -104,12 +106,12 @@ func (o *OTelQueryObserver) ObserveQuery(ctx context.Context, observedQuery gocq
 		inst.queryRows.Record(
 			ctx,
 			int64(observedQuery.Rows),
-			includeKeyValues(host, internal.CassKeyspace(keyspace))...,
+			includeHostKeyValues(host, internal.CassKeyspace(keyspace), operation)...,
 		)
 		inst.latency.Record(
 			ctx,
 			nanoToMilliseconds(observedQuery.Metrics.TotalLatency),
-			includeKeyValues(host, internal.CassKeyspace(keyspace))...,
+			includeHostKeyValues(host, internal.CassKeyspace(keyspace), operation)...,
 		)
 	}
 
@@ -123,12 +125,13 @@ func (o *OTelBatchObserver) ObserveBatch(ctx context.Context, observedBatch gocq
 	if o.enabled {
 		host := observedBatch.Host
 		keyspace := observedBatch.Keyspace
+		size := internal.CassBatchQueries(len(observedBatch.Statements))
 		inst := o.inst
 
-		attributes := includeKeyValues(host,
+		attributes := includeHostKeyValues(host,
 			internal.CassKeyspace(keyspace),
 			internal.CassBatchQueryOperation(),
-			internal.CassBatchQueries(len(observedBatch.Statements)),
+			size,
 		)
 
 		ctx, span := o.tracer.Start(
@@ -144,8 +147,9 @@ func (o *OTelBatchObserver) ObserveBatch(ctx context.Context, observedBatch gocq
 			inst.batchCount.Add(
 				ctx,
 				1,
-				includeKeyValues(host,
+				includeHostKeyValues(host,
 					internal.CassKeyspace(keyspace),
+					size,
 					internal.CassErrMsg(observedBatch.Err.Error()),
 				)...,
 			)
@@ -153,7 +157,7 @@ func (o *OTelBatchObserver) ObserveBatch(ctx context.Context, observedBatch gocq
 			inst.batchCount.Add(
 				ctx,
 				1,
-				includeKeyValues(host, internal.CassKeyspace(keyspace))...,
+				includeHostKeyValues(host, internal.CassKeyspace(keyspace), size)...,
 			)
 		}
 
@@ -162,7 +166,11 @@ func (o *OTelBatchObserver) ObserveBatch(ctx context.Context, observedBatch gocq
 		inst.latency.Record(
 			ctx,
 			nanoToMilliseconds(observedBatch.Metrics.TotalLatency),
-			includeKeyValues(host, internal.CassKeyspace(keyspace))...,
+			includeHostKeyValues(host,
+				internal.CassKeyspace(keyspace),
+				internal.CassBatchQueryOperation(),
+				size,
+			)...,
 		)
 	}
 
@@ -228,6 +236,20 @@ func includeKeyValues(host *gocql.HostInfo, values ...attribute.KeyValue) []attr
 	return append(connectionLevelAttributes, values...)
 }
 
+// includeHostKeyValues is like includeKeyValues, but also includes the
+// datacenter and rack of the host, when known, so the queries and batch
+// queries of each part of the cluster can be told apart.
+func includeHostKeyValues(host *gocql.HostInfo, values ...attribute.KeyValue) []attribute.KeyValue {
+	var hostAttributes []attribute.KeyValue
+	if dc := host.DataCenter(); dc != "" {
+		hostAttributes = append(hostAttributes, internal.CassHostDC(dc))
+	}
+	if rack := host.Rack(); rack != "" {
+		hostAttributes = append(hostAttributes, internal.CassHostRack(rack))
+	}
+	return includeKeyValues(host, append(hostAttributes, values...)...)
+}
+
 // nanoToMilliseconds converts nanoseconds to milliseconds.
 func nanoToMilliseconds(ns int64) int64 {
 	return ns / int64(time.Millisecond)
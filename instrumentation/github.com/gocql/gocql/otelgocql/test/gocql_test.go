@@ -103,6 +103,7 @@ func TestQuery(t *testing.T) {
 		switch span.Name() {
 		case insertStmt:
 			assert.Contains(t, span.Attributes(), semconv.DBStatementKey.String(insertStmt))
+			assert.Contains(t, span.Attributes(), semconv.DBOperationKey.String("INSERT"))
 			assert.Equal(t, parentSpan.SpanContext().SpanID().String(), span.Parent().SpanID().String())
 		default:
 			t.Fatalf("unexpected span name %s", span.Name())
@@ -125,6 +126,8 @@ func TestQuery(t *testing.T) {
 				internal.CassVersion("3"),
 				internal.CassHostID("test-id"),
 				internal.CassHostState("UP"),
+				internal.CassHostDC("datacenter1"),
+				internal.CassHostRack("rack1"),
 				internal.CassKeyspace(keyspace),
 				internal.CassStatement(insertStmt),
 			},
@@ -140,7 +143,10 @@ func TestQuery(t *testing.T) {
 				internal.CassVersion("3"),
 				internal.CassHostID("test-id"),
 				internal.CassHostState("UP"),
+				internal.CassHostDC("datacenter1"),
+				internal.CassHostRack("rack1"),
 				internal.CassKeyspace(keyspace),
+				internal.CassQueryOperation(insertStmt),
 			},
 			number: 0,
 		},
@@ -154,7 +160,10 @@ func TestQuery(t *testing.T) {
 				internal.CassVersion("3"),
 				internal.CassHostID("test-id"),
 				internal.CassHostState("UP"),
+				internal.CassHostDC("datacenter1"),
+				internal.CassHostRack("rack1"),
 				internal.CassKeyspace(keyspace),
+				internal.CassQueryOperation(insertStmt),
 			},
 		},
 	}
@@ -218,6 +227,7 @@ func TestBatch(t *testing.T) {
 		assert.Equal(t, internal.CassBatchQueryName, span.Name())
 		assert.Equal(t, parentSpan.SpanContext().SpanID(), span.Parent().SpanID())
 		assert.Contains(t, span.Attributes(), semconv.DBOperationKey.String("db.cassandra.batch.query"))
+		assert.Contains(t, span.Attributes(), internal.CassBatchQueries(10))
 		assertConnectionLevelAttributes(t, span)
 	}
 
@@ -236,7 +246,10 @@ func TestBatch(t *testing.T) {
 				internal.CassVersion("3"),
 				internal.CassHostID("test-id"),
 				internal.CassHostState("UP"),
+				internal.CassHostDC("datacenter1"),
+				internal.CassHostRack("rack1"),
 				internal.CassKeyspace(keyspace),
+				internal.CassBatchQueries(10),
 			},
 			number: 1,
 		},
@@ -250,7 +263,11 @@ func TestBatch(t *testing.T) {
 				internal.CassVersion("3"),
 				internal.CassHostID("test-id"),
 				internal.CassHostState("UP"),
+				internal.CassHostDC("datacenter1"),
+				internal.CassHostRack("rack1"),
 				internal.CassKeyspace(keyspace),
+				internal.CassBatchQueryOperation(),
+				internal.CassBatchQueries(10),
 			},
 		},
 	}
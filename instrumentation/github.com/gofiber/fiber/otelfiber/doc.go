@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelfiber instruments the github.com/gofiber/fiber/v2 package.
+//
+// The requests served by a Fiber app are traced, and measured if a meter
+// provider is specified, by the Middleware:
+//
+//	app := fiber.New()
+//	app.Use(otelfiber.Middleware("my-service"))
+//
+// The trace context of the requests is extracted from their fasthttp
+// headers, and made available to the handlers by the UserContext of the
+// fiber.Ctx.
+package otelfiber // import "go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
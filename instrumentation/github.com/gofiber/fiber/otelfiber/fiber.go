@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfiber // import "go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
+)
+
+// Middleware returns a Fiber handler to trace the incoming requests, and
+// measure them if a meter provider is specified with WithMeterProvider.  The
+// service parameter should describe the name of the (virtual) server
+// handling the request.
+//
+// The spans are named after the path of the routes matched by the requests,
+// eg "/users/:id", once the requests are handled.
+func Middleware(service string, opts ...Option) fiber.Handler {
+	cfg := config{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+	tracer := cfg.TracerProvider.Tracer(
+		tracerName,
+		oteltrace.WithInstrumentationVersion(SemVersion()),
+	)
+	if cfg.Propagators == nil {
+		cfg.Propagators = otel.GetTextMapPropagator()
+	}
+	metrics := newServerMetrics(cfg.MeterProvider)
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		savedCtx := c.UserContext()
+		defer c.SetUserContext(savedCtx)
+
+		ctx := cfg.Propagators.Extract(savedCtx, headerCarrier{c})
+		opts := []oteltrace.SpanStartOption{
+			oteltrace.WithAttributes(requestAttributes(service, c)...),
+			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+		}
+		// The route of the request is only known once Fiber has routed it,
+		// the span is renamed after it then.
+		ctx, span := tracer.Start(ctx, "HTTP "+c.Method(), opts...)
+		defer span.End()
+
+		// pass the span through the user context of the request
+		c.SetUserContext(ctx)
+
+		// The route of the middleware is left unchanged by the requests
+		// matching no other route.
+		self := c.Route()
+		err := c.Next()
+
+		var route string
+		if r := c.Route(); r != self {
+			route = r.Path
+		}
+		status := c.Response().StatusCode()
+		if err != nil {
+			// The error is only turned into a response by the error
+			// handler of the app once all the handlers returned.
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+			span.RecordError(err)
+		}
+
+		if route == "" {
+			span.SetName(fmt.Sprintf("HTTP %s route not found", c.Method()))
+		} else {
+			span.SetName(route)
+			span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		}
+		attrs := semconv.HTTPAttributesFromHTTPStatusCode(status)
+		spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, oteltrace.SpanKindServer)
+		span.SetAttributes(attrs...)
+		span.SetStatus(spanStatus, spanMessage)
+		metrics.record(ctx, c, route, status, start)
+		return err
+	}
+}
+
+// requestAttributes returns the attributes of the request served by c.
+func requestAttributes(service string, c *fiber.Ctx) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(c.Method()),
+		semconv.HTTPTargetKey.String(c.OriginalURL()),
+		semconv.HTTPSchemeKey.String(c.Protocol()),
+		semconv.NetHostNameKey.String(c.Hostname()),
+		semconv.NetPeerIPKey.String(c.IP()),
+	}
+	if service != "" {
+		attrs = append(attrs, semconv.HTTPServerNameKey.String(service))
+	}
+	if ua := c.Get(fiber.HeaderUserAgent); ua != "" {
+		attrs = append(attrs, semconv.HTTPUserAgentKey.String(ua))
+	}
+	if n := len(c.Request().Body()); n > 0 {
+		attrs = append(attrs, semconv.HTTPRequestContentLengthKey.Int(n))
+	}
+	return attrs
+}
+
+// headerCarrier adapts the fasthttp headers of the request served by a
+// fiber.Ctx to a propagation.TextMapCarrier.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+// Get returns the value of the header key.
+func (hc headerCarrier) Get(key string) string {
+	return hc.c.Get(key)
+}
+
+// Set sets the value of the header key.
+func (hc headerCarrier) Set(key string, value string) {
+	hc.c.Request().Header.Set(key, value)
+}
+
+// Keys lists the keys of the headers.
+func (hc headerCarrier) Keys() []string {
+	var keys []string
+	hc.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
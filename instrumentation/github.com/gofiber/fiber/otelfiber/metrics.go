@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfiber // import "go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Metrics of the requests served by a fiber.App, recorded by Middleware
+// once the next handlers returned, with the code of the *fiber.Error they
+// returned, if any, as status.
+const (
+	ServerDuration     = "http.server.duration"      // Duration of the requests, milliseconds
+	ServerRequestSize  = "http.server.request.size"  // Size of the request bodies read by fasthttp, bytes
+	ServerResponseSize = "http.server.response.size" // Size of the response bodies set on fiber.Ctx, bytes
+)
+
+// serverMetrics holds the instruments of the fiber.Handler returned by
+// Middleware.  A nil *serverMetrics records nothing.
+type serverMetrics struct {
+	duration     syncfloat64.Histogram
+	requestSize  syncint64.Histogram
+	responseSize syncint64.Histogram
+}
+
+// newServerMetrics returns the instruments of the fiber handler, or nil if
+// it has no meter provider.
+func newServerMetrics(mp metric.MeterProvider) *serverMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		tracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   serverMetrics
+		err error
+	)
+	if m.duration, err = meter.SyncFloat64().Histogram(
+		ServerDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the requests"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.requestSize, err = meter.SyncInt64().Histogram(
+		ServerRequestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the request bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.responseSize, err = meter.SyncInt64().Histogram(
+		ServerResponseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the response bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	return &m
+}
+
+// record records the duration since start and the sizes of the request
+// served by c, with the method, route path, and status code of the request
+// as attributes.  The route is omitted if empty.
+func (m *serverMetrics) record(ctx context.Context, c *fiber.Ctx, route string, status int, start time.Time) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, semconv.HTTPMethodKey.String(c.Method()))
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(route))
+	}
+	attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(status))
+
+	m.duration.Record(ctx, elapsed, attrs...)
+	m.requestSize.Record(ctx, int64(len(c.Request().Body())), attrs...)
+	m.responseSize.Record(ctx, int64(len(c.Response().Body())), attrs...)
+}
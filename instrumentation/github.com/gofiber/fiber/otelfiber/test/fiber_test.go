@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegration(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	app := fiber.New()
+	app.Use(otelfiber.Middleware("foobar", otelfiber.WithTracerProvider(provider)))
+	app.Get("/user/:id", func(c *fiber.Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/user/123", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, sr.Ended(), 1)
+	assertSpan(t, sr.Ended()[0],
+		"/user/:id",
+		trace.SpanKindServer,
+		attribute.String("http.server_name", "foobar"),
+		attribute.Int("http.status_code", http.StatusOK),
+		attribute.String("http.method", "GET"),
+		attribute.String("http.target", "/user/123"),
+		attribute.String("http.route", "/user/:id"),
+	)
+}
+
+func TestRouteNotFound(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	app := fiber.New()
+	app.Use(otelfiber.Middleware("foobar", otelfiber.WithTracerProvider(provider)))
+	app.Get("/user/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/unknown", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assertSpan(t, span,
+		"HTTP GET route not found",
+		trace.SpanKindServer,
+		attribute.Int("http.status_code", http.StatusNotFound),
+		attribute.String("http.target", "/unknown"),
+	)
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	for _, a := range span.Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route"), a.Key)
+	}
+}
+
+func TestHandlerError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	app := fiber.New()
+	app.Use(otelfiber.Middleware("foobar", otelfiber.WithTracerProvider(provider)))
+	app.Get("/error", func(c *fiber.Ctx) error {
+		return fiber.NewError(http.StatusServiceUnavailable, "unavailable")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/error", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assertSpan(t, span,
+		"/error",
+		trace.SpanKindServer,
+		attribute.Int("http.status_code", http.StatusServiceUnavailable),
+	)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, "exception", span.Events()[0].Name)
+}
+
+func TestPropagation(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+	propagator := propagation.TraceContext{}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	parent.End()
+
+	app := fiber.New()
+	app.Use(otelfiber.Middleware(
+		"foobar",
+		otelfiber.WithTracerProvider(provider),
+		otelfiber.WithPropagators(propagator),
+	))
+	var got trace.SpanContext
+	app.Get("/user/:id", func(c *fiber.Ctx) error {
+		got = trace.SpanContextFromContext(c.UserContext())
+		return nil
+	})
+
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	_, err := app.Test(r)
+	require.NoError(t, err)
+
+	require.Len(t, sr.Ended(), 2)
+	span := sr.Ended()[1]
+	assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, span.SpanContext(), got)
+}
+
+func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) {
+	assert.Equal(t, name, span.Name())
+	assert.Equal(t, kind, span.SpanKind())
+
+	got := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
+	for _, a := range span.Attributes() {
+		got[a.Key] = a.Value
+	}
+	for _, want := range attrs {
+		if !assert.Contains(t, got, want.Key) {
+			continue
+		}
+		assert.Equal(t, got[want.Key], want.Value)
+	}
+}
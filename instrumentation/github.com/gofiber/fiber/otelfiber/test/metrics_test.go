@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gofiber/fiber/otelfiber"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	app := fiber.New()
+	app.Use(otelfiber.Middleware("foobar", otelfiber.WithMeterProvider(mp)))
+	app.Post("/user/:id", func(c *fiber.Ctx) error {
+		return c.Status(http.StatusCreated).SendString("created")
+	})
+
+	_, err := app.Test(httptest.NewRequest("POST", "/user/123", strings.NewReader("name=foo")))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest("GET", "/unknown", nil))
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("POST"),
+		semconv.HTTPRouteKey.String("/user/:id"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelfiber.ServerDuration, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(1), duration.Count)
+
+	requestSize, err := exp.GetByNameAndAttributes(otelfiber.ServerRequestSize, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), requestSize.Count)
+	assert.Equal(t, int64(len("name=foo")), requestSize.Sum.AsInt64())
+
+	responseSize, err := exp.GetByNameAndAttributes(otelfiber.ServerResponseSize, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), responseSize.Count)
+	assert.Equal(t, int64(len("created")), responseSize.Sum.AsInt64())
+
+	// The requests not matching a route have no route attribute.
+	notFound := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("GET"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusNotFound),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelfiber.ServerDuration, notFound)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+	for _, kv := range duration.Attributes {
+		assert.NotEqual(t, semconv.HTTPRouteKey, kv.Key)
+	}
+}
@@ -15,14 +15,19 @@
 package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 
 import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // config is used to configure the mux middleware.
 type config struct {
-	TracerProvider oteltrace.TracerProvider
-	Propagators    propagation.TextMapPropagator
+	TracerProvider    oteltrace.TracerProvider
+	MeterProvider     metric.MeterProvider
+	Propagators       propagation.TextMapPropagator
+	SpanNameFormatter func(string, *http.Request) string
 }
 
 // Option specifies instrumentation configuration options.
@@ -56,3 +61,28 @@ func WithTracerProvider(provider oteltrace.TracerProvider) Option {
 		}
 	})
 }
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the requests is only recorded if a meter provider is
+// specified, with the HTTP method, route template, and status code of the
+// requests as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithSpanNameFormatter specifies a function to use for generating a custom
+// span name from the route template of the request, e.g. "/users/{id}", and
+// the request. The route template is empty for the requests matching no
+// route. By default, the span is named after the route template, or
+// "HTTP <method> route not found" for the requests matching no route.
+func WithSpanNameFormatter(fn func(routeName string, r *http.Request) string) Option {
+	return optionFunc(func(cfg *config) {
+		if fn != nil {
+			cfg.SpanNameFormatter = fn
+		}
+	})
+}
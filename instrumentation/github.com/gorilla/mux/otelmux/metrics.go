@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Metric of the requests dispatched by a mux.Router to its matched routes,
+// the middlewares of the router are not applied to the other requests.
+const (
+	ServerDuration = "http.server.duration" // Duration of the requests, milliseconds
+)
+
+// serverMetrics holds the duration histogram of the traceware handlers the
+// mux middleware wraps the routes in.  A nil *serverMetrics records
+// nothing.
+type serverMetrics struct {
+	duration syncfloat64.Histogram
+}
+
+// newServerMetrics returns the instruments shared by the handlers wrapped by
+// one mux middleware, or nil if it has no meter provider.
+func newServerMetrics(mp metric.MeterProvider) *serverMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		tracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   serverMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ServerDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the requests"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &m
+}
+
+// record records the duration since start of a request with the method, the
+// route template, if the request matched a route, and the status code of the
+// response as attributes.
+func (m *serverMetrics) record(ctx context.Context, method, route string, status int, start time.Time) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, semconv.HTTPMethodKey.String(method))
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRouteKey.String(route))
+	}
+	attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(status))
+
+	m.duration.Record(ctx, elapsed, attrs...)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/felixge/httpsnoop"
 	"github.com/gorilla/mux"
@@ -34,7 +35,8 @@ const (
 )
 
 // Middleware sets up a handler to start tracing the incoming
-// requests.  The service parameter should describe the name of the
+// requests, and measuring them if a meter provider is specified with
+// WithMeterProvider.  The service parameter should describe the name of the
 // (virtual) server handling the request.
 func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 	cfg := config{}
@@ -51,12 +53,18 @@ func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 	if cfg.Propagators == nil {
 		cfg.Propagators = otel.GetTextMapPropagator()
 	}
+	if cfg.SpanNameFormatter == nil {
+		cfg.SpanNameFormatter = defaultSpanNameFormatter
+	}
+	metrics := newServerMetrics(cfg.MeterProvider)
 	return func(handler http.Handler) http.Handler {
 		return traceware{
-			service:     service,
-			tracer:      tracer,
-			propagators: cfg.Propagators,
-			handler:     handler,
+			service:           service,
+			tracer:            tracer,
+			metrics:           metrics,
+			propagators:       cfg.Propagators,
+			handler:           handler,
+			spanNameFormatter: cfg.SpanNameFormatter,
 		}
 	}
 }
@@ -64,8 +72,20 @@ func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 type traceware struct {
 	service     string
 	tracer      oteltrace.Tracer
+	metrics     *serverMetrics
 	propagators propagation.TextMapPropagator
 	handler     http.Handler
+
+	spanNameFormatter func(string, *http.Request) string
+}
+
+// defaultSpanNameFormatter names the span after the route template, so that
+// the span names of the requests matching no route are not the raw paths.
+func defaultSpanNameFormatter(routeName string, r *http.Request) string {
+	if routeName == "" {
+		return fmt.Sprintf("HTTP %s route not found", r.Method)
+	}
+	return routeName
 }
 
 type recordingResponseWriter struct {
@@ -111,33 +131,37 @@ func putRRW(rrw *recordingResponseWriter) {
 	rrwPool.Put(rrw)
 }
 
+// routeName returns the path template of the route matched by the request,
+// or its path regexp if it has no template, or "" if no route was matched,
+// e.g. if the middleware wraps a handler instead of being used by a
+// mux.Router.
+func routeName(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	if tmpl, err := route.GetPathTemplate(); err == nil {
+		return tmpl
+	}
+	if re, err := route.GetPathRegexp(); err == nil {
+		return re
+	}
+	return ""
+}
+
 // ServeHTTP implements the http.Handler interface. It does the actual
 // tracing of the request.
 func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
 	ctx := tw.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
-	spanName := ""
-	route := mux.CurrentRoute(r)
-	if route != nil {
-		var err error
-		spanName, err = route.GetPathTemplate()
-		if err != nil {
-			spanName, err = route.GetPathRegexp()
-			if err != nil {
-				spanName = ""
-			}
-		}
-	}
-	routeStr := spanName
-	if spanName == "" {
-		spanName = fmt.Sprintf("HTTP %s route not found", r.Method)
-	}
+	routeStr := routeName(r)
 	opts := []oteltrace.SpanStartOption{
 		oteltrace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", r)...),
 		oteltrace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(r)...),
 		oteltrace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(tw.service, routeStr, r)...),
 		oteltrace.WithSpanKind(oteltrace.SpanKindServer),
 	}
-	ctx, span := tw.tracer.Start(ctx, spanName, opts...)
+	ctx, span := tw.tracer.Start(ctx, tw.spanNameFormatter(routeStr, r), opts...)
 	defer span.End()
 	r2 := r.WithContext(ctx)
 	rrw := getRRW(w)
@@ -147,4 +171,5 @@ func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rrw.status, oteltrace.SpanKindServer)
 	span.SetAttributes(attrs...)
 	span.SetStatus(spanStatus, spanMessage)
+	tw.metrics.record(ctx, r.Method, routeStr, rrw.status, start)
 }
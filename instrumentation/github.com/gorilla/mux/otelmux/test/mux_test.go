@@ -99,6 +99,57 @@ func TestNotFoundIsNotError(t *testing.T) {
 	assert.Equal(t, sr.Ended()[0].Status().Code, codes.Unset)
 }
 
+func TestSpanNameFormatter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(
+		"foobar",
+		otelmux.WithTracerProvider(provider),
+		otelmux.WithSpanNameFormatter(func(routeName string, r *http.Request) string {
+			return r.Method + " " + routeName
+		}),
+	))
+	router.HandleFunc("/user/{id:[0-9]+}", ok)
+
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r0)
+
+	require.Len(t, sr.Ended(), 1)
+	assertSpan(t, sr.Ended()[0],
+		"GET /user/{id:[0-9]+}",
+		trace.SpanKindServer,
+		attribute.String("http.route", "/user/{id:[0-9]+}"),
+	)
+}
+
+func TestRouteNotFound(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	// The middleware wraps a handler of no route.
+	handler := otelmux.Middleware("foobar", otelmux.WithTracerProvider(provider))(http.HandlerFunc(notfound))
+
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r0)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assertSpan(t, span,
+		"HTTP GET route not found",
+		trace.SpanKindServer,
+		attribute.String("http.target", "/user/123"),
+	)
+	for _, a := range span.Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route"), a.Key)
+	}
+}
+
 func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) {
 	assert.Equal(t, name, span.Name())
 	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelwebsocket // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn is a WebSocket connection whose messages are measured if a meter
+// provider is specified with WithMeterProvider.  The messages are measured
+// when they are read or written through the methods of Conn, and when the
+// control messages are received by its ping, pong, and close handlers.  The
+// messages written with WritePreparedMessage are not measured.
+type Conn struct {
+	*websocket.Conn
+
+	ctx     context.Context
+	metrics *messageMetrics
+}
+
+// newConn returns a Conn measuring the messages of conn in ctx.
+func newConn(ctx context.Context, conn *websocket.Conn, metrics *messageMetrics) *Conn {
+	c := &Conn{Conn: conn, ctx: ctx, metrics: metrics}
+	c.SetPingHandler(conn.PingHandler())
+	c.SetPongHandler(conn.PongHandler())
+	c.SetCloseHandler(conn.CloseHandler())
+	return c
+}
+
+// Context returns the context of the span of the handshake of the
+// connection.
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// ReadMessage reads the next data message of the connection as the
+// ReadMessage method of websocket.Conn.
+func (c *Conn) ReadMessage() (messageType int, p []byte, err error) {
+	messageType, p, err = c.Conn.ReadMessage()
+	if err == nil {
+		c.metrics.recordReceived(c.ctx, messageType, len(p))
+	}
+	return messageType, p, err
+}
+
+// NextReader returns the reader of the next data message of the connection
+// as the NextReader method of websocket.Conn.  The message is measured once
+// it is read to its end.
+func (c *Conn) NextReader() (messageType int, r io.Reader, err error) {
+	messageType, r, err = c.Conn.NextReader()
+	if err != nil {
+		return messageType, r, err
+	}
+	return messageType, &messageReader{conn: c, messageType: messageType, r: r}, nil
+}
+
+// ReadJSON reads the next JSON-encoded message of the connection and stores
+// it in the value pointed to by v, as the ReadJSON method of websocket.Conn.
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, r, err := c.NextReader()
+	if err != nil {
+		return err
+	}
+	err = json.NewDecoder(r).Decode(v)
+	if err == io.EOF {
+		// One value is expected in the message.
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	// Read the message to its end for it to be measured.
+	_, err = io.Copy(io.Discard, r)
+	return err
+}
+
+// WriteMessage writes a message of the type with the payload data as the
+// WriteMessage method of websocket.Conn.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	err := c.Conn.WriteMessage(messageType, data)
+	if err == nil {
+		c.metrics.recordSent(c.ctx, messageType, len(data))
+	}
+	return err
+}
+
+// WriteControl writes a control message of the type with the payload data
+// as the WriteControl method of websocket.Conn.
+func (c *Conn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	err := c.Conn.WriteControl(messageType, data, deadline)
+	if err == nil {
+		c.metrics.recordSent(c.ctx, messageType, len(data))
+	}
+	return err
+}
+
+// NextWriter returns the writer of the next message of the type as the
+// NextWriter method of websocket.Conn.  The message is measured once the
+// writer is closed.
+func (c *Conn) NextWriter(messageType int) (io.WriteCloser, error) {
+	w, err := c.Conn.NextWriter(messageType)
+	if err != nil {
+		return nil, err
+	}
+	return &messageWriter{conn: c, messageType: messageType, w: w}, nil
+}
+
+// WriteJSON writes the JSON encoding of v as a text message, as the
+// WriteJSON method of websocket.Conn.
+func (c *Conn) WriteJSON(v interface{}) error {
+	w, err := c.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	err1 := json.NewEncoder(w).Encode(v)
+	err2 := w.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
+// SetPingHandler sets the handler of the ping messages received by the
+// connection as the SetPingHandler method of websocket.Conn.  The ping
+// messages are measured before being handled by h.
+func (c *Conn) SetPingHandler(h func(appData string) error) {
+	if h == nil {
+		c.Conn.SetPingHandler(nil)
+		h = c.Conn.PingHandler()
+	}
+	c.Conn.SetPingHandler(func(appData string) error {
+		c.metrics.recordReceived(c.ctx, websocket.PingMessage, len(appData))
+		return h(appData)
+	})
+}
+
+// SetPongHandler sets the handler of the pong messages received by the
+// connection as the SetPongHandler method of websocket.Conn.  The pong
+// messages are measured before being handled by h.
+func (c *Conn) SetPongHandler(h func(appData string) error) {
+	if h == nil {
+		c.Conn.SetPongHandler(nil)
+		h = c.Conn.PongHandler()
+	}
+	c.Conn.SetPongHandler(func(appData string) error {
+		c.metrics.recordReceived(c.ctx, websocket.PongMessage, len(appData))
+		return h(appData)
+	})
+}
+
+// SetCloseHandler sets the handler of the close messages received by the
+// connection as the SetCloseHandler method of websocket.Conn.  The close
+// messages are measured before being handled by h.
+func (c *Conn) SetCloseHandler(h func(code int, text string) error) {
+	if h == nil {
+		c.Conn.SetCloseHandler(nil)
+		h = c.Conn.CloseHandler()
+	}
+	c.Conn.SetCloseHandler(func(code int, text string) error {
+		size := 0
+		if code != websocket.CloseNoStatusReceived {
+			// The status code is followed by the text in the payload.
+			size = 2 + len(text)
+		}
+		c.metrics.recordReceived(c.ctx, websocket.CloseMessage, size)
+		return h(code, text)
+	})
+}
+
+// messageReader measures the message it reads once it is read to its end.
+type messageReader struct {
+	conn        *Conn
+	messageType int
+	r           io.Reader
+	n           int
+	done        bool
+}
+
+func (r *messageReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	if err == io.EOF && !r.done {
+		r.done = true
+		r.conn.metrics.recordReceived(r.conn.ctx, r.messageType, r.n)
+	}
+	return n, err
+}
+
+// messageWriter measures the message it writes once it is closed.
+type messageWriter struct {
+	conn        *Conn
+	messageType int
+	w           io.WriteCloser
+	n           int
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+func (w *messageWriter) Close() error {
+	err := w.w.Close()
+	if err == nil {
+		w.conn.metrics.recordSent(w.conn.ctx, w.messageType, w.n)
+	}
+	return err
+}
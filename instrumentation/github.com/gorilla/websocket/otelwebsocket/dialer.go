@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelwebsocket // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Dialer traces the handshakes of the connections opened by a
+// websocket.Dialer, and measures their messages if a meter provider is
+// specified with WithMeterProvider.
+type Dialer struct {
+	dialer      *websocket.Dialer
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	metrics     *messageMetrics
+}
+
+// NewDialer returns a Dialer opening the connections with dialer.  A nil
+// dialer is used as the zero websocket.Dialer, as by the websocket package.
+func NewDialer(dialer *websocket.Dialer, opts ...Option) *Dialer {
+	cfg := newConfig(opts...)
+	return &Dialer{
+		dialer:      dialer,
+		tracer:      cfg.tracer(),
+		propagators: cfg.Propagators,
+		metrics:     newMessageMetrics(cfg.MeterProvider),
+	}
+}
+
+// Dial opens a connection by calling DialContext with context.Background().
+func (d *Dialer) Dial(urlStr string, requestHeader http.Header) (*Conn, *http.Response, error) {
+	return d.DialContext(context.Background(), urlStr, requestHeader)
+}
+
+// DialContext opens a connection to the WebSocket server of urlStr, as the
+// DialContext method of websocket.Dialer, in a span child of the span of
+// ctx.  The trace context of the span is injected into a copy of
+// requestHeader sent with the handshake request.  The returned connection
+// holds the context of the span.
+func (d *Dialer) DialContext(ctx context.Context, urlStr string, requestHeader http.Header) (*Conn, *http.Response, error) {
+	ctx, span := d.tracer.Start(
+		ctx,
+		dialSpanName,
+		trace.WithAttributes(dialAttributes(urlStr)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	header := requestHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	d.propagators.Inject(ctx, propagation.HeaderCarrier(header))
+
+	conn, resp, err := d.dialer.DialContext(ctx, urlStr, header)
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, resp, err
+	}
+	return newConn(ctx, conn, d.metrics), resp, nil
+}
+
+// dialAttributes returns the attributes of the handshake of a connection to
+// urlStr.  The user information of the URL is left out.
+func dialAttributes(urlStr string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.HTTPMethodKey.String(http.MethodGet)}
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return attrs
+	}
+	u.User = nil
+	attrs = append(attrs, semconv.HTTPURLKey.String(u.String()))
+
+	host := u.Host
+	if host == "" {
+		return attrs
+	}
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return append(attrs, semconv.NetPeerNameKey.String(host))
+	}
+	attrs = append(attrs, semconv.NetPeerNameKey.String(name))
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelwebsocket instruments the github.com/gorilla/websocket package.
+//
+// The handshakes of the connections accepted by a websocket.Upgrader are
+// traced by the Upgrade method of the Upgrader wrapping it, with the trace
+// context extracted from the headers of the handshake requests:
+//
+//	upgrader := otelwebsocket.NewUpgrader(&websocket.Upgrader{})
+//	conn, err := upgrader.Upgrade(w, r, nil)
+//
+// The handshakes of the connections opened by a websocket.Dialer are traced
+// by the DialContext method of the Dialer wrapping it, with the trace context
+// injected into the headers of the handshake requests:
+//
+//	dialer := otelwebsocket.NewDialer(websocket.DefaultDialer)
+//	conn, resp, err := dialer.DialContext(ctx, "ws://example.com/chat", nil)
+//
+// The messages read and written through the returned connections are
+// counted and their sizes are recorded, per message type, if a meter provider
+// is specified with WithMeterProvider.
+package otelwebsocket // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
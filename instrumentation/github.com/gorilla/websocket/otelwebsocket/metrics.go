@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelwebsocket // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+
+import (
+	"context"
+
+	"github.com/gorilla/websocket"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// WebSocket message metrics.
+const (
+	SentMessages        = "websocket.sent.messages"         // Count of the sent messages
+	SentMessageSize     = "websocket.sent.message.size"     // Size of the sent messages, bytes
+	ReceivedMessages    = "websocket.received.messages"     // Count of the received messages
+	ReceivedMessageSize = "websocket.received.message.size" // Size of the received messages, bytes
+)
+
+// MessageTypeKey is the attribute Key conforming to the
+// "websocket.message.type" semantic conventions.  It represents the type of
+// the measured messages: "text", "binary", "close", "ping", or "pong".
+const MessageTypeKey = attribute.Key("websocket.message.type")
+
+// messageMetrics holds the instruments of the messages sent or received
+// through the connections.  A nil *messageMetrics records nothing.
+type messageMetrics struct {
+	sent         syncint64.Counter
+	sentSize     syncint64.Histogram
+	received     syncint64.Counter
+	receivedSize syncint64.Histogram
+}
+
+// newMessageMetrics returns the instruments of the messages, or nil if the
+// metrics are not enabled.
+func newMessageMetrics(mp metric.MeterProvider) *messageMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   messageMetrics
+		err error
+	)
+	m.sent, err = meter.SyncInt64().Counter(
+		SentMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Count of the sent messages"),
+	)
+	handleErr(err)
+
+	m.sentSize, err = meter.SyncInt64().Histogram(
+		SentMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the sent messages"),
+	)
+	handleErr(err)
+
+	m.received, err = meter.SyncInt64().Counter(
+		ReceivedMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Count of the received messages"),
+	)
+	handleErr(err)
+
+	m.receivedSize, err = meter.SyncInt64().Histogram(
+		ReceivedMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the received messages"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// recordSent records a sent message of the type and size.
+func (m *messageMetrics) recordSent(ctx context.Context, messageType int, size int) {
+	if m == nil {
+		return
+	}
+	attr := messageTypeAttribute(messageType)
+	m.sent.Add(ctx, 1, attr)
+	m.sentSize.Record(ctx, int64(size), attr)
+}
+
+// recordReceived records a received message of the type and size.
+func (m *messageMetrics) recordReceived(ctx context.Context, messageType int, size int) {
+	if m == nil {
+		return
+	}
+	attr := messageTypeAttribute(messageType)
+	m.received.Add(ctx, 1, attr)
+	m.receivedSize.Record(ctx, int64(size), attr)
+}
+
+// messageTypeAttribute returns the attribute of the WebSocket message type.
+func messageTypeAttribute(messageType int) attribute.KeyValue {
+	switch messageType {
+	case websocket.TextMessage:
+		return MessageTypeKey.String("text")
+	case websocket.BinaryMessage:
+		return MessageTypeKey.String("binary")
+	case websocket.CloseMessage:
+		return MessageTypeKey.String("close")
+	case websocket.PingMessage:
+		return MessageTypeKey.String("ping")
+	case websocket.PongMessage:
+		return MessageTypeKey.String("pong")
+	default:
+		return MessageTypeKey.String("unknown")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	url := echoServer(t, otelwebsocket.NewUpgrader(&websocket.Upgrader{}), nil)
+	dialer := otelwebsocket.NewDialer(websocket.DefaultDialer, otelwebsocket.WithMeterProvider(mp))
+	conn, _, err := dialer.DialContext(context.Background(), url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	pong := make(chan struct{})
+	conn.SetPongHandler(func(string) error {
+		close(pong)
+		return nil
+	})
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	require.NoError(t, conn.WriteJSON(map[string]int{"n": 1}))
+	var v map[string]int
+	_, _, err = conn.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, conn.ReadJSON(&v))
+	assert.Equal(t, map[string]int{"n": 1}, v)
+
+	require.NoError(t, conn.WriteControl(websocket.PingMessage, []byte("ping"), time.Now().Add(time.Second)))
+	// the pong handler is only invoked while reading
+	go func() {
+		_, _, _ = conn.ReadMessage()
+	}()
+	<-pong
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	text := []attribute.KeyValue{otelwebsocket.MessageTypeKey.String("text")}
+	sent, err := exp.GetByNameAndAttributes(otelwebsocket.SentMessages, text)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.SumKind, sent.AggregationKind)
+	assert.Equal(t, int64(2), sent.Sum.AsInt64())
+	sentSize, err := exp.GetByNameAndAttributes(otelwebsocket.SentMessageSize, text)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), sentSize.Count)
+	assert.Equal(t, int64(len("hello")+len("{\"n\":1}\n")), sentSize.Sum.AsInt64())
+
+	received, err := exp.GetByNameAndAttributes(otelwebsocket.ReceivedMessages, text)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), received.Sum.AsInt64())
+	receivedSize, err := exp.GetByNameAndAttributes(otelwebsocket.ReceivedMessageSize, text)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("hello")+len("{\"n\":1}\n")), receivedSize.Sum.AsInt64())
+
+	ping := []attribute.KeyValue{otelwebsocket.MessageTypeKey.String("ping")}
+	sent, err = exp.GetByNameAndAttributes(otelwebsocket.SentMessages, ping)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), sent.Sum.AsInt64())
+
+	pongAttrs := []attribute.KeyValue{otelwebsocket.MessageTypeKey.String("pong")}
+	receivedSize, err = exp.GetByNameAndAttributes(otelwebsocket.ReceivedMessageSize, pongAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("ping")), receivedSize.Sum.AsInt64())
+}
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// echoServer serves an echo WebSocket server upgrading the connections with
+// upgrader, and returns its URL.  The context of the span of the handshake
+// of the server connection is sent to got.
+func echoServer(t *testing.T, upgrader *otelwebsocket.Upgrader, got chan<- context.Context) string {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if got != nil {
+			got <- conn.Context()
+		}
+		for {
+			messageType, p, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, p); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func TestHandshake(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts := []otelwebsocket.Option{
+		otelwebsocket.WithTracerProvider(provider),
+		otelwebsocket.WithPropagators(propagation.TraceContext{}),
+	}
+
+	got := make(chan context.Context, 1)
+	url := echoServer(t, otelwebsocket.NewUpgrader(&websocket.Upgrader{}, opts...), got)
+	dialer := otelwebsocket.NewDialer(websocket.DefaultDialer, opts...)
+
+	conn, resp, err := dialer.DialContext(context.Background(), url+"/chat", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	messageType, p, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, messageType)
+	assert.Equal(t, "hello", string(p))
+
+	serverCtx := <-got
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	var server, client sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		switch span.SpanKind() {
+		case trace.SpanKindServer:
+			server = span
+		case trace.SpanKindClient:
+			client = span
+		}
+	}
+	require.NotNil(t, server)
+	require.NotNil(t, client)
+
+	assert.Equal(t, "WebSocket dial", client.Name())
+	assert.Contains(t, client.Attributes(), attribute.String("http.url", url+"/chat"))
+	assert.Contains(t, client.Attributes(), attribute.Int("http.status_code", http.StatusSwitchingProtocols))
+	assert.Equal(t, client.SpanContext(), trace.SpanContextFromContext(conn.Context()))
+
+	assert.Equal(t, "WebSocket upgrade", server.Name())
+	assert.Contains(t, server.Attributes(), attribute.String("http.target", "/chat"))
+	assert.Contains(t, server.Attributes(), attribute.Int("http.status_code", http.StatusSwitchingProtocols))
+	assert.Equal(t, client.SpanContext().TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, server.SpanContext(), trace.SpanContextFromContext(serverCtx))
+}
+
+func TestUpgradeError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	url := echoServer(t, otelwebsocket.NewUpgrader(&websocket.Upgrader{}, otelwebsocket.WithTracerProvider(provider)), nil)
+	// not a WebSocket handshake
+	resp, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestDialError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+	dialer := otelwebsocket.NewDialer(nil, otelwebsocket.WithTracerProvider(provider))
+
+	_, resp, err := dialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	resp.Body.Close()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
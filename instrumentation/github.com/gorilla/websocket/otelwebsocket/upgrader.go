@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelwebsocket // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/websocket/otelwebsocket"
+
+	upgradeSpanName = "WebSocket upgrade"
+	dialSpanName    = "WebSocket dial"
+)
+
+// Upgrader traces the handshakes of the connections accepted by a
+// websocket.Upgrader, and measures their messages if a meter provider is
+// specified with WithMeterProvider.
+type Upgrader struct {
+	upgrader    *websocket.Upgrader
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	metrics     *messageMetrics
+}
+
+// NewUpgrader returns an Upgrader accepting the connections with upgrader.
+func NewUpgrader(upgrader *websocket.Upgrader, opts ...Option) *Upgrader {
+	cfg := newConfig(opts...)
+	return &Upgrader{
+		upgrader:    upgrader,
+		tracer:      cfg.tracer(),
+		propagators: cfg.Propagators,
+		metrics:     newMessageMetrics(cfg.MeterProvider),
+	}
+}
+
+// Upgrade upgrades the HTTP server connection to the WebSocket protocol, as
+// the Upgrade method of websocket.Upgrader, in a span child of the span of
+// the trace context extracted from the headers of r.  The returned
+// connection holds the context of the span, derived from the context of r.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (*Conn, error) {
+	ctx := u.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := u.tracer.Start(
+		ctx,
+		upgradeSpanName,
+		trace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", r)...),
+		trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", "", r)...),
+		trace.WithSpanKind(trace.SpanKindServer),
+	)
+	defer span.End()
+
+	conn, err := u.upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		// The error response is written by the upgrader, with a status code
+		// which is not known here.
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(http.StatusSwitchingProtocols)...)
+	return newConn(ctx, conn, u.metrics), nil
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelpgx instruments the github.com/jackc/pgx/v5 package.
+//
+// The queries, batches, copies, preparations, and connections of the
+// connections of pgx, which does not use the database/sql package, are
+// traced, and their duration is measured if a meter provider is specified,
+// when the Tracer returned by NewTracer is the tracer of their configuration:
+//
+//	config, err := pgxpool.ParseConfig(dsn)
+//	if err != nil {
+//		panic(err)
+//	}
+//	config.ConnConfig.Tracer = otelpgx.NewTracer()
+//	pool, err := pgxpool.NewWithConfig(ctx, config)
+//
+// The spans record the SQL statements as the db.statement attribute, unless
+// disabled with WithStatementDisabled, and the first keyword of the
+// statements, e.g. "SELECT", as the db.operation attribute.  The contexts
+// passed to the methods of pgx are the parents of the spans.
+//
+// RecordPoolStats records the metrics of the connections of a pgxpool.Pool.
+package otelpgx // import "go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelpgx // import "go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Database client metrics.
+const (
+	ClientOperationDuration = "db.client.operation.duration" // Duration of the operations, milliseconds
+)
+
+// Connection pool metrics.
+const (
+	ClientConnectionsUsage            = "db.client.connections.usage"             // Number of the connections per state
+	ClientConnectionsMax              = "db.client.connections.max"               // Maximum number of the connections
+	ClientConnectionsAcquires         = "db.client.connections.acquires"          // Number of the connections acquired
+	ClientConnectionsAcquireTime      = "db.client.connections.acquire_time"      // Total duration of the acquisitions, milliseconds
+	ClientConnectionsEmptyAcquires    = "db.client.connections.empty_acquires"    // Number of the acquisitions waiting for a connection
+	ClientConnectionsCanceledAcquires = "db.client.connections.canceled_acquires" // Number of the acquisitions canceled
+)
+
+// ConnectionStateKey is the state of the connections, "idle", "used", or
+// "constructing", of the db.client.connections.usage metric.
+const ConnectionStateKey = attribute.Key("state")
+
+// clientMetrics holds the instruments of the operations of the
+// connections.  A nil *clientMetrics records nothing.
+type clientMetrics struct {
+	duration syncfloat64.Histogram
+}
+
+// newClientMetrics returns the instruments of the operations, or nil if the
+// metrics are not enabled.
+func newClientMetrics(mp metric.MeterProvider) *clientMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   clientMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ClientOperationDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the database operations"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// record records the duration since start of the operation with the
+// attributes.
+func (m *clientMetrics) record(ctx context.Context, operation string, start time.Time, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	if operation != "" {
+		attrs = append(attrs[:len(attrs):len(attrs)], semconv.DBOperationKey.String(operation))
+	}
+	m.duration.Record(ctx, elapsed, attrs...)
+}
+
+// RecordPoolStats records the metrics of the connections of the pool,
+// observed from its statistics, with the database and server of the pool
+// and the attributes specified with WithAttributes as attributes.  The
+// global meter provider is used if none is specified.
+func RecordPoolStats(pool *pgxpool.Pool, opts ...Option) error {
+	cfg := newConfig(opts...)
+	mp := cfg.MeterProvider
+	if mp == nil {
+		mp = global.MeterProvider()
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	usage, err := meter.AsyncInt64().UpDownCounter(
+		ClientConnectionsUsage,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections per state"),
+	)
+	if err != nil {
+		return err
+	}
+	maxConns, err := meter.AsyncInt64().UpDownCounter(
+		ClientConnectionsMax,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Maximum number of the connections"),
+	)
+	if err != nil {
+		return err
+	}
+	acquires, err := meter.AsyncInt64().Counter(
+		ClientConnectionsAcquires,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections acquired"),
+	)
+	if err != nil {
+		return err
+	}
+	acquireTime, err := meter.AsyncInt64().Counter(
+		ClientConnectionsAcquireTime,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Total duration of the acquisitions"),
+	)
+	if err != nil {
+		return err
+	}
+	emptyAcquires, err := meter.AsyncInt64().Counter(
+		ClientConnectionsEmptyAcquires,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the acquisitions waiting for a connection"),
+	)
+	if err != nil {
+		return err
+	}
+	canceledAcquires, err := meter.AsyncInt64().Counter(
+		ClientConnectionsCanceledAcquires,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the acquisitions canceled"),
+	)
+	if err != nil {
+		return err
+	}
+
+	attrs := append([]attribute.KeyValue{semconv.DBSystemPostgreSQL}, connAttributes(pool.Config().ConnConfig)...)
+	attrs = append(attrs, cfg.Attributes...)
+	idleAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("idle"))
+	usedAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("used"))
+	constructingAttrs := append(attrs[:len(attrs):len(attrs)], ConnectionStateKey.String("constructing"))
+	instruments := []instrument.Asynchronous{usage, maxConns, acquires, acquireTime, emptyAcquires, canceledAcquires}
+	return meter.RegisterCallback(instruments, func(ctx context.Context) {
+		stat := pool.Stat()
+		usage.Observe(ctx, int64(stat.IdleConns()), idleAttrs...)
+		usage.Observe(ctx, int64(stat.AcquiredConns()), usedAttrs...)
+		usage.Observe(ctx, int64(stat.ConstructingConns()), constructingAttrs...)
+		maxConns.Observe(ctx, int64(stat.MaxConns()), attrs...)
+		acquires.Observe(ctx, stat.AcquireCount(), attrs...)
+		acquireTime.Observe(ctx, stat.AcquireDuration().Milliseconds(), attrs...)
+		emptyAcquires.Observe(ctx, stat.EmptyAcquireCount(), attrs...)
+		canceledAcquires.Observe(ctx, stat.CanceledAcquireCount(), attrs...)
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	tracer := otelpgx.NewTracer(otelpgx.WithMeterProvider(mp))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+	}
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "INSERT INTO t VALUES (1)"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("fail")})
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	selectAttrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String("SELECT"),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelpgx.ClientOperationDuration, selectAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(2), duration.Count)
+
+	// The failed operations are measured too.
+	insert := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String("INSERT"),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelpgx.ClientOperationDuration, insert)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestPoolStats(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	// The connections are established on demand, none is.
+	config, err := pgxpool.ParseConfig("postgres://bob@127.0.0.1:1/shop")
+	require.NoError(t, err)
+	config.MaxConns = 8
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	require.NoError(t, otelpgx.RecordPoolStats(pool, otelpgx.WithMeterProvider(mp)))
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBNameKey.String("shop"),
+		semconv.DBUserKey.String("bob"),
+		semconv.NetPeerNameKey.String("127.0.0.1"),
+		semconv.NetPeerPortKey.Int(1),
+	}
+	maxConns, err := exp.GetByNameAndAttributes(otelpgx.ClientConnectionsMax, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), maxConns.Sum.AsInt64())
+
+	for _, state := range []string{"idle", "used", "constructing"} {
+		usage, err := exp.GetByNameAndAttributes(
+			otelpgx.ClientConnectionsUsage,
+			append(attrs[:len(attrs):len(attrs)], otelpgx.ConnectionStateKey.String(state)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), usage.Sum.AsInt64(), state)
+	}
+	for _, name := range []string{
+		otelpgx.ClientConnectionsAcquires,
+		otelpgx.ClientConnectionsAcquireTime,
+		otelpgx.ClientConnectionsEmptyAcquires,
+		otelpgx.ClientConnectionsCanceledAcquires,
+	} {
+		_, err := exp.GetByNameAndAttributes(name, attrs)
+		assert.NoError(t, err, name)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracer returns a tracer traced by the returned recorder, and the
+// context of a span parent of the spans of the operations.
+func newTracer(t *testing.T, opts ...otelpgx.Option) (*otelpgx.Tracer, *tracetest.SpanRecorder, context.Context) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts = append([]otelpgx.Option{otelpgx.WithTracerProvider(provider)}, opts...)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	t.Cleanup(func() { parent.End() })
+	return otelpgx.NewTracer(opts...), sr, ctx
+}
+
+func TestQuery(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "select n from t where id = $1", Args: []any{42}})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "pgx.query", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBSystemPostgreSQL)
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("SELECT"))
+	assert.Contains(t, attrs, semconv.DBStatementKey.String("select n from t where id = $1"))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestQueryError(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "DELETE FROM t"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("fail")})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "fail", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestStatementDisabled(t *testing.T) {
+	tracer, sr, ctx := newTracer(t, otelpgx.WithStatementDisabled(true))
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT secret FROM t"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBOperationKey.String("SELECT"))
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, semconv.DBStatementKey, kv.Key)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO t VALUES (1)")
+	batch.Queue("UPDATE t SET n = 2")
+	bctx := tracer.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{Batch: batch})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "INSERT INTO t VALUES (1)"})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "UPDATE t SET n = 2", Err: errors.New("fail")})
+	tracer.TraceBatchEnd(bctx, nil, pgx.TraceBatchEndData{Err: errors.New("fail")})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "pgx.batch", span.Name())
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("BATCH"))
+	assert.Contains(t, attrs, otelpgx.BatchQueriesKey.Int(2))
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	events := span.Events()
+	require.Len(t, events, 4)
+	assert.Equal(t, "query", events[0].Name)
+	assert.Contains(t, events[0].Attributes, semconv.DBOperationKey.String("INSERT"))
+	assert.Contains(t, events[0].Attributes, semconv.DBStatementKey.String("INSERT INTO t VALUES (1)"))
+	assert.Equal(t, "query", events[1].Name)
+	assert.Contains(t, events[1].Attributes, semconv.DBOperationKey.String("UPDATE"))
+	// The error of the second query, then of the batch.
+	assert.Equal(t, "exception", events[2].Name)
+	assert.Contains(t, events[2].Attributes, semconv.DBStatementKey.String("UPDATE t SET n = 2"))
+	assert.Equal(t, "exception", events[3].Name)
+}
+
+func TestCopyFrom(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	cctx := tracer.TraceCopyFromStart(ctx, nil, pgx.TraceCopyFromStartData{
+		TableName:   pgx.Identifier{"public", "t"},
+		ColumnNames: []string{"n"},
+	})
+	tracer.TraceCopyFromEnd(cctx, nil, pgx.TraceCopyFromEndData{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pgx.copy_from", spans[0].Name())
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("COPY"))
+	assert.Contains(t, attrs, semconv.DBSQLTableKey.String(`"public"."t"`))
+}
+
+func TestPrepare(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	pctx := tracer.TracePrepareStart(ctx, nil, pgx.TracePrepareStartData{Name: "stmt", SQL: "SELECT 1"})
+	tracer.TracePrepareEnd(pctx, nil, pgx.TracePrepareEndData{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pgx.prepare", spans[0].Name())
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, semconv.DBOperationKey.String("PREPARE"))
+	assert.Contains(t, attrs, semconv.DBStatementKey.String("SELECT 1"))
+}
+
+func TestConnect(t *testing.T) {
+	tracer, sr, ctx := newTracer(t)
+
+	config, err := pgx.ParseConfig("postgres://bob@db.example.com:5433/shop")
+	require.NoError(t, err)
+	cctx := tracer.TraceConnectStart(ctx, pgx.TraceConnectStartData{ConnConfig: config})
+	tracer.TraceConnectEnd(cctx, pgx.TraceConnectEndData{Err: errors.New("refused")})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pgx.connect", spans[0].Name())
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, semconv.DBNameKey.String("shop"))
+	assert.Contains(t, attrs, semconv.DBUserKey.String("bob"))
+	assert.Contains(t, attrs, semconv.NetPeerNameKey.String("db.example.com"))
+	assert.Contains(t, attrs, semconv.NetPeerPortKey.Int(5433))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
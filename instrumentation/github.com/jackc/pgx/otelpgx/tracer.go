@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelpgx // import "go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/jackc/pgx/otelpgx"
+
+// Names of the spans of the operations.
+const (
+	spanQuery    = "pgx.query"
+	spanBatch    = "pgx.batch"
+	spanCopyFrom = "pgx.copy_from"
+	spanPrepare  = "pgx.prepare"
+	spanConnect  = "pgx.connect"
+)
+
+// BatchQueriesKey is the number of the queries of a batch.
+const BatchQueriesKey = attribute.Key("db.postgresql.batch.queries")
+
+// Tracer traces and measures the operations of the connections it is the
+// tracer of.  It implements the pgx.QueryTracer, pgx.BatchTracer,
+// pgx.CopyFromTracer, pgx.PrepareTracer, and pgx.ConnectTracer interfaces.
+type Tracer struct {
+	tracer            trace.Tracer
+	metrics           *clientMetrics
+	attrs             []attribute.KeyValue
+	statementDisabled bool
+}
+
+var (
+	_ pgx.QueryTracer    = (*Tracer)(nil)
+	_ pgx.BatchTracer    = (*Tracer)(nil)
+	_ pgx.CopyFromTracer = (*Tracer)(nil)
+	_ pgx.PrepareTracer  = (*Tracer)(nil)
+	_ pgx.ConnectTracer  = (*Tracer)(nil)
+)
+
+// NewTracer returns a Tracer to set as the Tracer of the pgx.ConnConfig of
+// the connections to trace.
+func NewTracer(opts ...Option) *Tracer {
+	cfg := newConfig(opts...)
+	return &Tracer{
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		metrics:           newClientMetrics(cfg.MeterProvider),
+		attrs:             append([]attribute.KeyValue{semconv.DBSystemPostgreSQL}, cfg.Attributes...),
+		statementDisabled: cfg.StatementDisabled,
+	}
+}
+
+// TraceQueryStart starts the span of a query.
+func (t *Tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return t.start(ctx, connConfig(conn), spanQuery, statementOperation(data.SQL), data.SQL)
+}
+
+// TraceQueryEnd ends the span of a query.
+func (t *Tracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.Err)
+}
+
+// TraceBatchStart starts the span of a batch.
+func (t *Tracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	var attrs []attribute.KeyValue
+	if data.Batch != nil {
+		attrs = append(attrs, BatchQueriesKey.Int(data.Batch.Len()))
+	}
+	return t.start(ctx, connConfig(conn), spanBatch, "BATCH", "", attrs...)
+}
+
+// TraceBatchQuery adds an event for a query of a batch to the span of the
+// batch.
+func (t *Tracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+	s, ok := ctx.Value(startedKey{}).(started)
+	if !ok {
+		return
+	}
+	attrs := []attribute.KeyValue{semconv.DBOperationKey.String(statementOperation(data.SQL))}
+	if !t.statementDisabled {
+		attrs = append(attrs, semconv.DBStatementKey.String(data.SQL))
+	}
+	s.span.AddEvent("query", trace.WithAttributes(attrs...))
+	if data.Err != nil {
+		s.span.RecordError(data.Err, trace.WithAttributes(attrs...))
+	}
+}
+
+// TraceBatchEnd ends the span of a batch.
+func (t *Tracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.end(ctx, data.Err)
+}
+
+// TraceCopyFromStart starts the span of a copy, with the table copied to as
+// the db.sql.table attribute.
+func (t *Tracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return t.start(ctx, connConfig(conn), spanCopyFrom, "COPY", "",
+		semconv.DBSQLTableKey.String(data.TableName.Sanitize()))
+}
+
+// TraceCopyFromEnd ends the span of a copy.
+func (t *Tracer) TraceCopyFromEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.end(ctx, data.Err)
+}
+
+// TracePrepareStart starts the span of the preparation of a statement.
+func (t *Tracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	return t.start(ctx, connConfig(conn), spanPrepare, "PREPARE", data.SQL)
+}
+
+// TracePrepareEnd ends the span of the preparation of a statement.
+func (t *Tracer) TracePrepareEnd(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareEndData) {
+	t.end(ctx, data.Err)
+}
+
+// TraceConnectStart starts the span of the establishment of a connection.
+func (t *Tracer) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	return t.start(ctx, data.ConnConfig, spanConnect, "CONNECT", "")
+}
+
+// TraceConnectEnd ends the span of the establishment of a connection.
+func (t *Tracer) TraceConnectEnd(ctx context.Context, data pgx.TraceConnectEndData) {
+	t.end(ctx, data.Err)
+}
+
+// startedKey is the key of the started operation in the contexts returned by
+// the tracer.
+type startedKey struct{}
+
+// started is an operation started by the tracer.
+type started struct {
+	span      trace.Span
+	operation string
+	start     time.Time
+}
+
+// start starts the span of the operation of a connection with the config,
+// with the statement, if any, and the attributes, and returns the context
+// to end it with.
+func (t *Tracer) start(ctx context.Context, config *pgx.ConnConfig, name, operation, statement string, attrs ...attribute.KeyValue) context.Context {
+	attrs = append(attrs, connAttributes(config)...)
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationKey.String(operation))
+	}
+	if statement != "" && !t.statementDisabled {
+		attrs = append(attrs, semconv.DBStatementKey.String(statement))
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.attrs...),
+		trace.WithAttributes(attrs...),
+	}
+	ctx, span := t.tracer.Start(ctx, name, opts...)
+	return context.WithValue(ctx, startedKey{}, started{
+		span:      span,
+		operation: operation,
+		start:     time.Now(),
+	})
+}
+
+// end ends the span of the operation started with ctx, recording err, and
+// measures its duration.
+func (t *Tracer) end(ctx context.Context, err error) {
+	s, ok := ctx.Value(startedKey{}).(started)
+	if !ok {
+		return
+	}
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+	t.metrics.record(ctx, s.operation, s.start, t.attrs)
+}
+
+func connConfig(conn *pgx.Conn) *pgx.ConnConfig {
+	if conn == nil {
+		return nil
+	}
+	return conn.Config()
+}
+
+// connAttributes returns the attributes of the database and server of the
+// config.
+func connAttributes(config *pgx.ConnConfig) []attribute.KeyValue {
+	if config == nil {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if config.Database != "" {
+		attrs = append(attrs, semconv.DBNameKey.String(config.Database))
+	}
+	if config.User != "" {
+		attrs = append(attrs, semconv.DBUserKey.String(config.User))
+	}
+	// Unix domain sockets are specified by their directory.
+	if config.Host != "" && !strings.HasPrefix(config.Host, "/") {
+		attrs = append(attrs,
+			semconv.NetPeerNameKey.String(config.Host),
+			semconv.NetPeerPortKey.Int(int(config.Port)),
+		)
+	}
+	return attrs
+}
+
+// statementOperation returns the SQL keyword the statement starts with,
+// uppercased, e.g. "SELECT", or "" if it does not start with a keyword.
+func statementOperation(statement string) string {
+	statement = strings.TrimLeft(statement, " \t\r\n(")
+	end := 0
+	for end < len(statement) && isLetter(statement[end]) {
+		end++
+	}
+	return strings.ToUpper(statement[:end])
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
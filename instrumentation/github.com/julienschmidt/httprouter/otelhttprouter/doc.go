@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelhttprouter instruments the github.com/julienschmidt/httprouter
+// package.
+//
+// The requests served by the handles registered with a Router, which wraps
+// an httprouter.Router, are traced, and measured if a meter provider is
+// specified:
+//
+//	router := otelhttprouter.New("my-service")
+//	router.GET("/users/:id", handle)
+//
+// The spans are named after the paths the handles are registered with, e.g.
+// "/users/:id", rather than after the paths of the requests, which are
+// recorded as the http.route attribute of the spans.
+package otelhttprouter // import "go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter"
@@ -0,0 +1,11 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter
+
+go 1.17
+
+require (
+	github.com/felixge/httpsnoop v1.0.3
+	github.com/julienschmidt/httprouter v1.3.0
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttprouter // import "go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter"
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/julienschmidt/httprouter"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter"
+)
+
+// Router is an httprouter.Router tracing the requests to the handles
+// registered through its methods, and measuring them if a meter provider is
+// specified with WithMeterProvider.  The spans are named after the paths the
+// handles are registered with, e.g. "/users/:id", rather than after the
+// paths of the requests.
+//
+// The requests handled by the NotFound, MethodNotAllowed, and
+// GlobalOPTIONS handlers of the router are not traced.
+type Router struct {
+	*httprouter.Router
+
+	service     string
+	tracer      oteltrace.Tracer
+	metrics     *serverMetrics
+	propagators propagation.TextMapPropagator
+	filters     []Filter
+}
+
+// New returns a new initialized Router, with the default configuration of
+// httprouter.New.  The service parameter should describe the name of the
+// (virtual) server handling the requests.
+func New(service string, opts ...Option) *Router {
+	cfg := config{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+	if cfg.Propagators == nil {
+		cfg.Propagators = otel.GetTextMapPropagator()
+	}
+	return &Router{
+		Router:  httprouter.New(),
+		service: service,
+		tracer: cfg.TracerProvider.Tracer(
+			tracerName,
+			oteltrace.WithInstrumentationVersion(SemVersion()),
+		),
+		metrics:     newServerMetrics(cfg.MeterProvider),
+		propagators: cfg.Propagators,
+		filters:     cfg.Filters,
+	}
+}
+
+// GET is a shortcut for r.Handle(http.MethodGet, path, handle).
+func (r *Router) GET(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodGet, path, handle)
+}
+
+// HEAD is a shortcut for r.Handle(http.MethodHead, path, handle).
+func (r *Router) HEAD(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodHead, path, handle)
+}
+
+// OPTIONS is a shortcut for r.Handle(http.MethodOptions, path, handle).
+func (r *Router) OPTIONS(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodOptions, path, handle)
+}
+
+// POST is a shortcut for r.Handle(http.MethodPost, path, handle).
+func (r *Router) POST(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPost, path, handle)
+}
+
+// PUT is a shortcut for r.Handle(http.MethodPut, path, handle).
+func (r *Router) PUT(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPut, path, handle)
+}
+
+// PATCH is a shortcut for r.Handle(http.MethodPatch, path, handle).
+func (r *Router) PATCH(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodPatch, path, handle)
+}
+
+// DELETE is a shortcut for r.Handle(http.MethodDelete, path, handle).
+func (r *Router) DELETE(path string, handle httprouter.Handle) {
+	r.Handle(http.MethodDelete, path, handle)
+}
+
+// Handle registers a new traced request handle with the given path and
+// method, as the Handle method of httprouter.Router.
+func (r *Router) Handle(method, path string, handle httprouter.Handle) {
+	r.Router.Handle(method, path, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		r.serve(path, w, req, func(w http.ResponseWriter, req *http.Request) {
+			handle(w, req, ps)
+		})
+	})
+}
+
+// Handler registers a traced http.Handler with the given path and method,
+// as the Handler method of httprouter.Router.
+func (r *Router) Handler(method, path string, handler http.Handler) {
+	r.Router.Handler(method, path, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.serve(path, w, req, handler.ServeHTTP)
+	}))
+}
+
+// HandlerFunc registers a traced http.HandlerFunc with the given path and
+// method, as the HandlerFunc method of httprouter.Router.
+func (r *Router) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	r.Handler(method, path, handler)
+}
+
+// ServeFiles serves traced files from the given file system root, as the
+// ServeFiles method of httprouter.Router.  The path must end with
+// "/*filepath".
+func (r *Router) ServeFiles(path string, root http.FileSystem) {
+	if len(path) < 10 || path[len(path)-10:] != "/*filepath" {
+		panic("path must end with /*filepath in path '" + path + "'")
+	}
+
+	fileServer := http.FileServer(root)
+
+	r.GET(path, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		req.URL.Path = ps.ByName("filepath")
+		fileServer.ServeHTTP(w, req)
+	})
+}
+
+type recordingResponseWriter struct {
+	writer  http.ResponseWriter
+	written bool
+	status  int
+	size    int64
+}
+
+var rrwPool = &sync.Pool{
+	New: func() interface{} {
+		return &recordingResponseWriter{}
+	},
+}
+
+func getRRW(writer http.ResponseWriter) *recordingResponseWriter {
+	rrw := rrwPool.Get().(*recordingResponseWriter)
+	rrw.written = false
+	rrw.status = http.StatusOK
+	rrw.size = 0
+	rrw.writer = httpsnoop.Wrap(writer, httpsnoop.Hooks{
+		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return func(b []byte) (int, error) {
+				if !rrw.written {
+					rrw.written = true
+				}
+				n, err := next(b)
+				rrw.size += int64(n)
+				return n, err
+			}
+		},
+		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return func(statusCode int) {
+				if !rrw.written {
+					rrw.written = true
+					rrw.status = statusCode
+				}
+				next(statusCode)
+			}
+		},
+	})
+	return rrw
+}
+
+func putRRW(rrw *recordingResponseWriter) {
+	rrw.writer = nil
+	rrwPool.Put(rrw)
+}
+
+// traces returns whether the request passes all the filters and should be
+// traced.
+func (r *Router) traces(req *http.Request) bool {
+	for _, f := range r.filters {
+		if !f(req) {
+			return false
+		}
+	}
+	return true
+}
+
+// serve serves the request req, matching the route, with the handler in a
+// span named after the route.
+func (r *Router) serve(route string, w http.ResponseWriter, req *http.Request, handler http.HandlerFunc) {
+	if !r.traces(req) {
+		handler(w, req)
+		return
+	}
+	start := time.Now()
+	ctx := r.propagators.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	opts := []oteltrace.SpanStartOption{
+		oteltrace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", req)...),
+		oteltrace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(req)...),
+		oteltrace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(r.service, route, req)...),
+		oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+	}
+	ctx, span := r.tracer.Start(ctx, route, opts...)
+	defer span.End()
+	req2 := req.WithContext(ctx)
+	rrw := getRRW(w)
+	defer putRRW(rrw)
+	handler(rrw.writer, req2)
+
+	attrs := semconv.HTTPAttributesFromHTTPStatusCode(rrw.status)
+	spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rrw.status, oteltrace.SpanKindServer)
+	span.SetAttributes(attrs...)
+	span.SetStatus(spanStatus, spanMessage)
+	r.metrics.record(ctx, req, route, rrw.status, rrw.size, start)
+}
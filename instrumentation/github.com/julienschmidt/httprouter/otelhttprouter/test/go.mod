@@ -0,0 +1,15 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter/test
+
+go 1.17
+
+require (
+	github.com/julienschmidt/httprouter v1.3.0
+	github.com/stretchr/testify v1.8.0
+	go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter v0.34.0
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/sdk v1.9.0
+	go.opentelemetry.io/otel/sdk/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
+
+replace go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter => ../
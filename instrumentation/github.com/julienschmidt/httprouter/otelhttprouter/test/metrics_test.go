@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	router := otelhttprouter.New("foobar", otelhttprouter.WithMeterProvider(mp))
+	router.POST("/user/:id", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte("created"))
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	r := httptest.NewRequest("POST", "/user/123", strings.NewReader("name=foo"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("POST"),
+		semconv.HTTPRouteKey.String("/user/:id"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelhttprouter.ServerDuration, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(1), duration.Count)
+
+	requestSize, err := exp.GetByNameAndAttributes(otelhttprouter.ServerRequestSize, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), requestSize.Count)
+	assert.Equal(t, int64(len("name=foo")), requestSize.Sum.AsInt64())
+
+	responseSize, err := exp.GetByNameAndAttributes(otelhttprouter.ServerResponseSize, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), responseSize.Count)
+	assert.Equal(t, int64(len("created")), responseSize.Sum.AsInt64())
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/julienschmidt/httprouter/otelhttprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ok(http.ResponseWriter, *http.Request, httprouter.Params) {}
+
+func TestSDKIntegration(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := otelhttprouter.New("foobar", otelhttprouter.WithTracerProvider(provider))
+	var id string
+	router.GET("/user/:id", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		id = ps.ByName("id")
+	})
+	router.HandlerFunc("GET", "/book/*title", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/book/foo/bar", nil))
+	assert.Equal(t, "123", id)
+
+	require.Len(t, sr.Ended(), 2)
+	assertSpan(t, sr.Ended()[0],
+		"/user/:id",
+		attribute.String("http.server_name", "foobar"),
+		attribute.Int("http.status_code", http.StatusOK),
+		attribute.String("http.method", "GET"),
+		attribute.String("http.target", "/user/123"),
+		attribute.String("http.route", "/user/:id"),
+	)
+	assert.Equal(t, codes.Unset, sr.Ended()[0].Status().Code)
+	assertSpan(t, sr.Ended()[1],
+		"/book/*title",
+		attribute.Int("http.status_code", http.StatusInternalServerError),
+		attribute.String("http.target", "/book/foo/bar"),
+		attribute.String("http.route", "/book/*title"),
+	)
+	assert.Equal(t, codes.Error, sr.Ended()[1].Status().Code)
+}
+
+func TestRouteNotFound(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := otelhttprouter.New("foobar", otelhttprouter.WithTracerProvider(provider))
+	router.GET("/user/:id", ok)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, sr.Ended())
+}
+
+func TestFilter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+
+	router := otelhttprouter.New(
+		"foobar",
+		otelhttprouter.WithTracerProvider(provider),
+		otelhttprouter.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	)
+	var served int
+	handle := func(http.ResponseWriter, *http.Request, httprouter.Params) {
+		served++
+	}
+	router.GET("/healthz", handle)
+	router.GET("/user/:id", handle)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	assert.Equal(t, 2, served, "filtered requests should still be served")
+	require.Len(t, sr.Ended(), 1)
+	assert.Equal(t, "/user/:id", sr.Ended()[0].Name())
+}
+
+func TestPropagation(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider()
+	provider.RegisterSpanProcessor(sr)
+	propagator := propagation.TraceContext{}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	parent.End()
+
+	router := otelhttprouter.New(
+		"foobar",
+		otelhttprouter.WithTracerProvider(provider),
+		otelhttprouter.WithPropagators(propagator),
+	)
+	var got trace.SpanContext
+	router.Handler("GET", "/user/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = trace.SpanContextFromContext(r.Context())
+		assert.Equal(t, "123", httprouter.ParamsFromContext(r.Context()).ByName("id"))
+	}))
+
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r0.Header))
+	router.ServeHTTP(httptest.NewRecorder(), r0)
+
+	require.Len(t, sr.Ended(), 2)
+	span := sr.Ended()[1]
+	assert.Equal(t, span.SpanContext(), got)
+	assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+}
+
+func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, attrs ...attribute.KeyValue) {
+	t.Helper()
+
+	assert.Equal(t, name, span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+
+	got := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
+	for _, a := range span.Attributes() {
+		got[a.Key] = a.Value
+	}
+	for _, want := range attrs {
+		if !assert.Contains(t, got, want.Key) {
+			continue
+		}
+		assert.Equal(t, want.Value, got[want.Key])
+	}
+}
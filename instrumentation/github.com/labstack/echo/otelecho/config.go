@@ -15,8 +15,11 @@
 package otelecho // import "go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4/middleware"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
@@ -24,10 +27,21 @@ import (
 // config is used to configure the mux middleware.
 type config struct {
 	TracerProvider oteltrace.TracerProvider
+	MeterProvider  metric.MeterProvider
 	Propagators    propagation.TextMapPropagator
 	Skipper        middleware.Skipper
+	Filters        []Filter
+	ErrorStatus    ErrorStatus
 }
 
+// Filter is a predicate used to determine whether a given http.Request should
+// be traced. A Filter must return true if the request should be traced.
+type Filter func(*http.Request) bool
+
+// ErrorStatus maps an error returned by a handler to the status code of the
+// response it results in.
+type ErrorStatus func(error) int
+
 // Option specifies instrumentation configuration options.
 type Option interface {
 	apply(*config)
@@ -66,3 +80,41 @@ func WithSkipper(skipper middleware.Skipper) Option {
 		cfg.Skipper = skipper
 	})
 }
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration and the sizes of the requests are only recorded if a meter
+// provider is specified, with the HTTP method, route template, and status
+// code of the requests as attributes.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithFilter adds a filter to the list of filters used by the middleware.
+// If any filter indicates to exclude a request then the request is neither
+// traced nor measured, like the requests skipped by the Skipper. If no
+// filters are provided then all requests are traced. Filters are invoked for
+// each processed request, it is advised to make them simple and fast.
+func WithFilter(f Filter) Option {
+	return optionFunc(func(cfg *config) {
+		if f != nil {
+			cfg.Filters = append(cfg.Filters, f)
+		}
+	})
+}
+
+// WithErrorStatus specifies how the status code of a request is derived from
+// the error returned by its handler, when the HTTP error handler of Echo did
+// not write the response itself. The status code is recorded in the span and
+// in the metrics of the request. If none is specified, DefaultErrorStatus is
+// used.
+func WithErrorStatus(f ErrorStatus) Option {
+	return optionFunc(func(cfg *config) {
+		if f != nil {
+			cfg.ErrorStatus = f
+		}
+	})
+}
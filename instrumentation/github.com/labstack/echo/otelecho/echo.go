@@ -16,6 +16,8 @@ package otelecho // import "go.opentelemetry.io/contrib/instrumentation/github.c
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -23,6 +25,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	oteltrace "go.opentelemetry.io/otel/trace"
@@ -33,7 +36,11 @@ const (
 	tracerName = "go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
-// Middleware returns echo middleware which will trace incoming requests.
+// Middleware returns echo middleware which will trace incoming requests, and
+// measure them if a meter provider is specified with WithMeterProvider.  The
+// spans are named after the route templates of the requests, e.g.
+// "/users/:id", which must have been matched by the router: the middleware
+// is to be added with Echo.Use rather than Echo.Pre.
 func Middleware(service string, opts ...Option) echo.MiddlewareFunc {
 	cfg := config{}
 	for _, opt := range opts {
@@ -53,13 +60,19 @@ func Middleware(service string, opts ...Option) echo.MiddlewareFunc {
 	if cfg.Skipper == nil {
 		cfg.Skipper = middleware.DefaultSkipper
 	}
+	if cfg.ErrorStatus == nil {
+		cfg.ErrorStatus = DefaultErrorStatus
+	}
+	metrics := newServerMetrics(cfg.MeterProvider)
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if cfg.Skipper(c) {
+			if cfg.Skipper(c) || !cfg.traces(c.Request()) {
 				return next(c)
 			}
 
+			start := time.Now()
+
 			c.Set(tracerKey, tracer)
 			request := c.Request()
 			savedCtx := request.Context()
@@ -89,16 +102,49 @@ func Middleware(service string, opts ...Option) echo.MiddlewareFunc {
 			err := next(c)
 			if err != nil {
 				span.SetAttributes(attribute.String("echo.error", err.Error()))
+				span.RecordError(err)
 				// invokes the registered HTTP error handler
 				c.Error(err)
 			}
 
-			attrs := semconv.HTTPAttributesFromHTTPStatusCode(c.Response().Status)
-			spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(c.Response().Status, oteltrace.SpanKindServer)
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				// the error handler did not write the response
+				status = cfg.ErrorStatus(err)
+			}
+			attrs := semconv.HTTPAttributesFromHTTPStatusCode(status)
+			spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, oteltrace.SpanKindServer)
+			if spanStatus == codes.Error && err != nil {
+				spanMessage = err.Error()
+			}
 			span.SetAttributes(attrs...)
 			span.SetStatus(spanStatus, spanMessage)
+			metrics.record(ctx, c, status, start)
 
 			return nil
 		}
 	}
 }
+
+// DefaultErrorStatus returns the status code of the response written by the
+// default HTTP error handler of Echo for err: the code of err if it is an
+// *echo.HTTPError, or 500 otherwise.
+func DefaultErrorStatus(err error) int {
+	if he, ok := err.(*echo.HTTPError); ok {
+		if herr, ok := he.Internal.(*echo.HTTPError); ok {
+			he = herr
+		}
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// traces reports whether the request is not excluded by a filter.
+func (cfg *config) traces(r *http.Request) bool {
+	for _, f := range cfg.Filters {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -138,3 +138,144 @@ func TestError(t *testing.T) {
 	// server errors set the status
 	assert.Equal(t, codes.Error, span.Status().Code)
 }
+
+func TestFilter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	router := echo.New()
+	router.Use(otelecho.Middleware(
+		"foobar",
+		otelecho.WithTracerProvider(provider),
+		otelecho.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	))
+	router.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	router.GET("/user/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, target := range []string{"/healthz", "/user/123"} {
+		r := httptest.NewRequest("GET", target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/user/:id", spans[0].Name())
+}
+
+func TestHTTPError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	router := echo.New()
+	router.Use(otelecho.Middleware("foobar", otelecho.WithTracerProvider(provider)))
+	router.GET("/user/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "no such user")
+	})
+	router.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadGateway).SetInternal(errors.New("upstream"))
+	})
+
+	for _, tc := range []struct {
+		target string
+		status int
+		code   codes.Code
+	}{
+		{"/user/123", http.StatusNotFound, codes.Unset},
+		{"/fail", http.StatusBadGateway, codes.Error},
+	} {
+		r := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tc.status, w.Code)
+
+		spans := sr.Ended()
+		require.NotEmpty(t, spans)
+		span := spans[len(spans)-1]
+		assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", tc.status))
+		assert.Equal(t, tc.code, span.Status().Code)
+		require.Len(t, span.Events(), 1)
+		assert.Equal(t, "exception", span.Events()[0].Name)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	errConflict := errors.New("conflict")
+	for _, tc := range []struct {
+		name string
+		opts []otelecho.Option
+		want int
+	}{
+		{"default", nil, http.StatusInternalServerError},
+		{
+			"custom",
+			[]otelecho.Option{otelecho.WithErrorStatus(func(err error) int {
+				if errors.Is(err, errConflict) {
+					return http.StatusConflict
+				}
+				return otelecho.DefaultErrorStatus(err)
+			})},
+			http.StatusConflict,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+			router := echo.New()
+			// an error handler which leaves the response to be written
+			// by the caller
+			router.HTTPErrorHandler = func(error, echo.Context) {}
+			opts := append([]otelecho.Option{otelecho.WithTracerProvider(provider)}, tc.opts...)
+			router.Use(otelecho.Middleware("foobar", opts...))
+			router.GET("/item", func(c echo.Context) error {
+				return errConflict
+			})
+
+			r := httptest.NewRequest("GET", "/item", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", tc.want))
+		})
+	}
+}
+
+func TestErrorHandlerStatus(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	router := echo.New()
+	router.HTTPErrorHandler = func(err error, c echo.Context) {
+		_ = c.String(http.StatusTeapot, err.Error())
+	}
+	router.Use(otelecho.Middleware(
+		"foobar",
+		otelecho.WithTracerProvider(provider),
+		// ignored as the error handler writes the response
+		otelecho.WithErrorStatus(func(error) int { return http.StatusConflict }),
+	))
+	router.GET("/tea", func(c echo.Context) error {
+		return errors.New("coffee")
+	})
+
+	r := httptest.NewRequest("GET", "/tea", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusTeapot))
+	assert.Contains(t, span.Attributes(), attribute.String("echo.error", "coffee"))
+}
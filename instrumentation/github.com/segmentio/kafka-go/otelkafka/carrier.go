@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelkafka // import "go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
+
+import (
+	"github.com/segmentio/kafka-go"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ propagation.TextMapCarrier = (*MessageCarrier)(nil)
+
+// MessageCarrier injects and extracts traces from the headers of a
+// kafka.Message.
+type MessageCarrier struct {
+	msg *kafka.Message
+}
+
+// NewMessageCarrier creates a new MessageCarrier.
+func NewMessageCarrier(msg *kafka.Message) MessageCarrier {
+	return MessageCarrier{msg: msg}
+}
+
+// Get retrieves a single value for a given key.
+func (c MessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set sets a header.
+//
+// The headers are copied before being modified, as the Headers of the
+// messages written together can share the same backing array.
+func (c MessageCarrier) Set(key, val string) {
+	headers := make([]kafka.Header, 0, len(c.msg.Headers)+1)
+	// Ensure uniqueness of keys
+	for _, h := range c.msg.Headers {
+		if h.Key != key {
+			headers = append(headers, h)
+		}
+	}
+	c.msg.Headers = append(headers, kafka.Header{
+		Key:   key,
+		Value: []byte(val),
+	})
+}
+
+// Keys returns a slice of all key identifiers in the carrier.
+func (c MessageCarrier) Keys() []string {
+	out := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		out[i] = h.Key
+	}
+	return out
+}
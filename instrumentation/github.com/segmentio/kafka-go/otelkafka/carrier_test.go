@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelkafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageCarrierGet(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{
+		{Key: "foo", Value: []byte("bar")},
+	}}
+	carrier := NewMessageCarrier(&msg)
+
+	assert.Equal(t, "bar", carrier.Get("foo"))
+	assert.Equal(t, "", carrier.Get("baz"))
+}
+
+func TestMessageCarrierSet(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{
+		{Key: "foo", Value: []byte("bar")},
+	}}
+	carrier := NewMessageCarrier(&msg)
+
+	carrier.Set("foo", "bar2")
+	carrier.Set("foo2", "bar2")
+	carrier.Set("foo2", "bar3")
+	carrier.Set("foo3", "bar4")
+
+	assert.ElementsMatch(t, msg.Headers, []kafka.Header{
+		{Key: "foo", Value: []byte("bar2")},
+		{Key: "foo2", Value: []byte("bar3")},
+		{Key: "foo3", Value: []byte("bar4")},
+	})
+}
+
+func TestMessageCarrierSetSharedHeaders(t *testing.T) {
+	headers := make([]kafka.Header, 1, 4)
+	headers[0] = kafka.Header{Key: "foo", Value: []byte("bar")}
+	msgs := []kafka.Message{{Headers: headers}, {Headers: headers}}
+
+	NewMessageCarrier(&msgs[0]).Set("traceparent", "first")
+	NewMessageCarrier(&msgs[1]).Set("traceparent", "second")
+
+	assert.Equal(t, "first", NewMessageCarrier(&msgs[0]).Get("traceparent"))
+	assert.Equal(t, "second", NewMessageCarrier(&msgs[1]).Get("traceparent"))
+	assert.Equal(t, []kafka.Header{{Key: "foo", Value: []byte("bar")}}, headers)
+}
+
+func TestMessageCarrierKeys(t *testing.T) {
+	msg := kafka.Message{Headers: []kafka.Header{
+		{Key: "foo", Value: []byte("bar")},
+		{Key: "baz", Value: []byte("quux")},
+	}}
+
+	assert.Equal(t, []string{"foo", "baz"}, NewMessageCarrier(&msg).Keys())
+}
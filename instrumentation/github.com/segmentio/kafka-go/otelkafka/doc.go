@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelkafka instruments the github.com/segmentio/kafka-go package.
+//
+// The writes of a kafka.Writer wrapped with NewWriter are traced: each batch
+// of messages written is traced in a "kafka.produce_batch" span, parent of
+// the "kafka.produce" span of each message, whose context is propagated in
+// the headers of the message.
+//
+// The reads of a kafka.Reader wrapped with NewReader are traced: each
+// message read is traced in a "kafka.consume" span, child of the span of the
+// producer of the message, and each commit in a "kafka.commit" span linked
+// to the spans of the messages committed.
+//
+// With WithMeterProvider, the number and size of the messages written and
+// read are measured by topic, as well as the duration of the writes, the
+// fetches, and the commits.
+package otelkafka // import "go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
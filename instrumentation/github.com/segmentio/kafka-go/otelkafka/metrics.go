@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelkafka // import "go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Writer metrics.
+const (
+	WriterMessages    = "messaging.kafka.writer.messages"     // Number of the messages written
+	WriterMessageSize = "messaging.kafka.writer.message.size" // Size of the keys and values of the messages written, bytes
+	WriterDuration    = "messaging.kafka.writer.duration"     // Duration of the writes of the batches of messages, milliseconds
+)
+
+// Reader metrics.
+const (
+	ReaderMessages       = "messaging.kafka.reader.messages"        // Number of the messages fetched
+	ReaderMessageSize    = "messaging.kafka.reader.message.size"    // Size of the keys and values of the messages fetched, bytes
+	ReaderFetchDuration  = "messaging.kafka.reader.fetch.duration"  // Duration of the fetches of the messages, milliseconds
+	ReaderCommitDuration = "messaging.kafka.reader.commit.duration" // Duration of the commits of the messages, milliseconds
+)
+
+// writerMetrics holds the instruments of a writer. A nil *writerMetrics
+// records nothing.
+type writerMetrics struct {
+	messages syncint64.Counter
+	size     syncint64.Histogram
+	duration syncfloat64.Histogram
+}
+
+// newWriterMetrics returns the instruments of a writer, or nil if the
+// metrics are not enabled.
+func newWriterMetrics(mp metric.MeterProvider) *writerMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := newMeter(mp)
+
+	var (
+		m   writerMetrics
+		err error
+	)
+	m.messages, err = meter.SyncInt64().Counter(
+		WriterMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the messages written"),
+	)
+	handleErr(err)
+
+	m.size, err = meter.SyncInt64().Histogram(
+		WriterMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the keys and values of the messages written"),
+	)
+	handleErr(err)
+
+	m.duration, err = meter.SyncFloat64().Histogram(
+		WriterDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the writes of the batches of messages"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+// sent records the message of the topic sent to the writer.
+func (m *writerMetrics) sent(ctx context.Context, topic string, msg *kafka.Message) {
+	if m == nil {
+		return
+	}
+	attrs := topicAttrs(topic)
+	m.messages.Add(ctx, 1, attrs...)
+	m.size.Record(ctx, int64(len(msg.Key)+len(msg.Value)), attrs...)
+}
+
+// wrote records the duration since start of the write of a batch of the
+// topic, "" if its messages have different topics.
+func (m *writerMetrics) wrote(ctx context.Context, start time.Time, topic string) {
+	if m == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{kafkaSystem}
+	if topic != "" {
+		attrs = topicAttrs(topic)
+	}
+	m.duration.Record(ctx, elapsedMillis(start), attrs...)
+}
+
+// readerMetrics holds the instruments of a reader. A nil *readerMetrics
+// records nothing.
+type readerMetrics struct {
+	messages       syncint64.Counter
+	size           syncint64.Histogram
+	fetchDuration  syncfloat64.Histogram
+	commitDuration syncfloat64.Histogram
+}
+
+// newReaderMetrics returns the instruments of a reader, or nil if the
+// metrics are not enabled.
+func newReaderMetrics(mp metric.MeterProvider) *readerMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := newMeter(mp)
+
+	var (
+		m   readerMetrics
+		err error
+	)
+	m.messages, err = meter.SyncInt64().Counter(
+		ReaderMessages,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the messages fetched"),
+	)
+	handleErr(err)
+
+	m.size, err = meter.SyncInt64().Histogram(
+		ReaderMessageSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the keys and values of the messages fetched"),
+	)
+	handleErr(err)
+
+	m.fetchDuration, err = meter.SyncFloat64().Histogram(
+		ReaderFetchDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the fetches of the messages"),
+	)
+	handleErr(err)
+
+	m.commitDuration, err = meter.SyncFloat64().Histogram(
+		ReaderCommitDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the commits of the messages"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+// received records the message fetched after waiting for it since start,
+// with the attributes of the reader.
+func (m *readerMetrics) received(ctx context.Context, start time.Time, msg *kafka.Message, readerAttrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	attrs := append(readerAttrs[:len(readerAttrs):len(readerAttrs)], semconv.MessagingDestinationKey.String(msg.Topic))
+	m.messages.Add(ctx, 1, attrs...)
+	m.size.Record(ctx, int64(len(msg.Key)+len(msg.Value)), attrs...)
+	m.fetchDuration.Record(ctx, elapsedMillis(start), attrs...)
+}
+
+// committed records the duration since start of a commit with the
+// attributes of the reader.
+func (m *readerMetrics) committed(ctx context.Context, start time.Time, readerAttrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	m.commitDuration.Record(ctx, elapsedMillis(start), readerAttrs...)
+}
+
+func newMeter(mp metric.MeterProvider) metric.Meter {
+	return mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+}
+
+// topicAttrs returns the attributes of the metrics of the messages of the
+// topic.
+func topicAttrs(topic string) []attribute.KeyValue {
+	return []attribute.KeyValue{kafkaSystem, semconv.MessagingDestinationKey.String(topic)}
+}
+
+func elapsedMillis(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelkafka // import "go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Reader is a kafka.Reader whose reads and commits are traced.
+type Reader struct {
+	*kafka.Reader
+
+	cfg     *config
+	tracer  trace.Tracer
+	metrics *readerMetrics
+	// attrs are the attributes of the reader, its consumer group if any.
+	attrs []attribute.KeyValue
+}
+
+// NewReader returns a Reader tracing the reads and commits of r.
+func NewReader(r *kafka.Reader, opts ...Option) *Reader {
+	cfg := newConfig(opts...)
+	attrs := []attribute.KeyValue{kafkaSystem}
+	if group := r.Config().GroupID; group != "" {
+		attrs = append(attrs, semconv.MessagingKafkaConsumerGroupKey.String(group))
+	}
+	return &Reader{
+		Reader:  r,
+		cfg:     cfg,
+		tracer:  cfg.tracer(),
+		metrics: newReaderMetrics(cfg.MeterProvider),
+		attrs:   attrs,
+	}
+}
+
+// ReadMessage calls kafka.Reader.ReadMessage and traces the message read.
+// See FetchMessage.
+func (r *Reader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	start := time.Now()
+	msg, err := r.Reader.ReadMessage(ctx)
+	if err != nil {
+		return msg, err
+	}
+	r.received(start, &msg)
+	return msg, nil
+}
+
+// FetchMessage calls kafka.Reader.FetchMessage and traces the message
+// fetched in a "kafka.consume" span, child of the span whose context is in
+// the headers of the message. The context of the "kafka.consume" span is
+// then injected in the headers, so the processing of the message can be
+// traced as its child.
+func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	start := time.Now()
+	msg, err := r.Reader.FetchMessage(ctx)
+	if err != nil {
+		return msg, err
+	}
+	r.received(start, &msg)
+	return msg, nil
+}
+
+// received traces the message received after waiting for it since start.
+func (r *Reader) received(start time.Time, msg *kafka.Message) {
+	carrier := NewMessageCarrier(msg)
+	parent := r.cfg.Propagators.Extract(context.Background(), carrier)
+
+	attrs := append([]attribute.KeyValue{
+		semconv.MessagingDestinationKindTopic,
+		semconv.MessagingDestinationKey.String(msg.Topic),
+		semconv.MessagingOperationReceive,
+		semconv.MessagingMessageIDKey.String(strconv.FormatInt(msg.Offset, 10)),
+		semconv.MessagingKafkaPartitionKey.Int(msg.Partition),
+	}, r.attrs...)
+	ctx, span := r.tracer.Start(parent, "kafka.consume",
+		trace.WithAttributes(attrs...),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+	)
+	r.cfg.Propagators.Inject(ctx, carrier)
+	span.End()
+
+	r.metrics.received(ctx, start, msg, r.attrs)
+}
+
+// CommitMessages calls kafka.Reader.CommitMessages and traces the commit in
+// a "kafka.commit" span, child of the span of ctx and linked to the
+// "kafka.consume" spans of the messages.
+func (r *Reader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	links := make([]trace.Link, 0, len(msgs))
+	for i := range msgs {
+		msgCtx := r.cfg.Propagators.Extract(context.Background(), NewMessageCarrier(&msgs[i]))
+		if link := trace.LinkFromContext(msgCtx); link.SpanContext.IsValid() {
+			links = append(links, link)
+		}
+	}
+	attrs := append([]attribute.KeyValue{BatchMessageCountKey.Int(len(msgs))}, r.attrs...)
+	ctx, span := r.tracer.Start(ctx, "kafka.commit",
+		trace.WithAttributes(attrs...),
+		trace.WithLinks(links...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+
+	start := time.Now()
+	err := r.Reader.CommitMessages(ctx, msgs...)
+	r.metrics.committed(ctx, start, r.attrs)
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
+	"go.opentelemetry.io/contrib/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	broker = "127.0.0.1:9092"
+	group  = "otelkafka-test"
+)
+
+var kafkaSystem = semconv.MessagingSystemKey.String("kafka")
+
+func TestMain(m *testing.M) {
+	util.IntegrationShouldRun("test-kafka-go")
+	os.Exit(m.Run())
+}
+
+// newTopic creates a topic of one partition for the test.
+func newTopic(t *testing.T) string {
+	topic := "otelkafka-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	conn, err := kafka.Dial("tcp", broker)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     1,
+		ReplicationFactor: 1,
+	}))
+	return topic
+}
+
+// spansNamed returns the spans with the name.
+func spansNamed(spans []sdktrace.ReadOnlySpan, name string) []sdktrace.ReadOnlySpan {
+	var named []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Name() == name {
+			named = append(named, span)
+		}
+	}
+	return named
+}
+
+func TestWriteRead(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	mp, exp := metrictest.NewTestMeterProvider()
+	opts := []otelkafka.Option{
+		otelkafka.WithTracerProvider(tp),
+		otelkafka.WithMeterProvider(mp),
+		otelkafka.WithPropagators(propagation.TraceContext{}),
+	}
+	topic := newTopic(t)
+
+	w := otelkafka.NewWriter(&kafka.Writer{
+		Addr:         kafka.TCP(broker),
+		Topic:        topic,
+		BatchTimeout: 10 * time.Millisecond,
+	}, opts...)
+	defer w.Close()
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, w.WriteMessages(ctx,
+		kafka.Message{Key: []byte("foo"), Value: []byte("hello")},
+		kafka.Message{Value: []byte("world")},
+	))
+	parent.End()
+
+	r := otelkafka.NewReader(kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{broker},
+		Topic:   topic,
+		GroupID: group,
+	}), opts...)
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	msgs := make([]kafka.Message, 2)
+	for i := range msgs {
+		var err error
+		msgs[i], err = r.FetchMessage(ctx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.CommitMessages(ctx, msgs...))
+
+	spans := sr.Ended()
+	batches := spansNamed(spans, "kafka.produce_batch")
+	require.Len(t, batches, 1)
+	batch := batches[0]
+	assert.Equal(t, parent.SpanContext().SpanID(), batch.Parent().SpanID())
+	assert.Equal(t, trace.SpanKindProducer, batch.SpanKind())
+	assert.Contains(t, batch.Attributes(), otelkafka.BatchMessageCountKey.Int(2))
+	assert.Contains(t, batch.Attributes(), semconv.MessagingDestinationKey.String(topic))
+
+	produces := spansNamed(spans, "kafka.produce")
+	require.Len(t, produces, 2)
+	for _, produce := range produces {
+		assert.Equal(t, batch.SpanContext().SpanID(), produce.Parent().SpanID())
+		assert.Equal(t, trace.SpanKindProducer, produce.SpanKind())
+		assert.Contains(t, produce.Attributes(), semconv.MessagingDestinationKey.String(topic))
+	}
+
+	// The consumers' spans are children of the producers' spans.
+	consumes := spansNamed(spans, "kafka.consume")
+	require.Len(t, consumes, 2)
+	for i, consume := range consumes {
+		assert.Equal(t, produces[i].SpanContext().SpanID(), consume.Parent().SpanID())
+		assert.Equal(t, trace.SpanKindConsumer, consume.SpanKind())
+		attrs := consume.Attributes()
+		assert.Contains(t, attrs, semconv.MessagingDestinationKey.String(topic))
+		assert.Contains(t, attrs, semconv.MessagingOperationReceive)
+		assert.Contains(t, attrs, semconv.MessagingKafkaPartitionKey.Int(0))
+		assert.Contains(t, attrs, semconv.MessagingKafkaConsumerGroupKey.String(group))
+	}
+
+	commits := spansNamed(spans, "kafka.commit")
+	require.Len(t, commits, 1)
+	commit := commits[0]
+	assert.Contains(t, commit.Attributes(), otelkafka.BatchMessageCountKey.Int(2))
+	require.Len(t, commit.Links(), 2)
+	for i, link := range commit.Links() {
+		// The links are extracted from the headers of the messages.
+		assert.Equal(t, consumes[i].SpanContext().WithRemote(true), link.SpanContext)
+	}
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	topicAttrs := []attribute.KeyValue{kafkaSystem, semconv.MessagingDestinationKey.String(topic)}
+	written, err := exp.GetByNameAndAttributes(otelkafka.WriterMessages, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), written.Sum.AsInt64())
+	size, err := exp.GetByNameAndAttributes(otelkafka.WriterMessageSize, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(13), size.Sum.AsInt64())
+	duration, err := exp.GetByNameAndAttributes(otelkafka.WriterDuration, topicAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(1), duration.Count)
+
+	readerAttrs := []attribute.KeyValue{
+		kafkaSystem,
+		semconv.MessagingKafkaConsumerGroupKey.String(group),
+		semconv.MessagingDestinationKey.String(topic),
+	}
+	read, err := exp.GetByNameAndAttributes(otelkafka.ReaderMessages, readerAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), read.Sum.AsInt64())
+	fetches, err := exp.GetByNameAndAttributes(otelkafka.ReaderFetchDuration, readerAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), fetches.Count)
+	commitDuration, err := exp.GetByNameAndAttributes(otelkafka.ReaderCommitDuration, []attribute.KeyValue{
+		kafkaSystem,
+		semconv.MessagingKafkaConsumerGroupKey.String(group),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), commitDuration.Count)
+}
+
+func TestWriteError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	w := otelkafka.NewWriter(&kafka.Writer{
+		Addr:  kafka.TCP(broker),
+		Topic: "otelkafka-closed",
+	}, otelkafka.WithTracerProvider(tp))
+	require.NoError(t, w.Close())
+
+	err := w.WriteMessages(context.Background(), kafka.Message{Value: []byte("hello")})
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status().Code)
+	}
+}
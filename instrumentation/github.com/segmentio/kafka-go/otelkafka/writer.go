@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelkafka // import "go.opentelemetry.io/contrib/instrumentation/github.com/segmentio/kafka-go/otelkafka"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BatchMessageCountKey is the number of the messages of the batch of a
+// "kafka.produce_batch" or "kafka.commit" span.
+const BatchMessageCountKey = attribute.Key("messaging.batch.message_count")
+
+// kafkaSystem is the messaging.system of the spans and the metrics.
+var kafkaSystem = semconv.MessagingSystemKey.String("kafka")
+
+// Writer is a kafka.Writer whose writes are traced.
+type Writer struct {
+	*kafka.Writer
+
+	cfg     *config
+	tracer  trace.Tracer
+	metrics *writerMetrics
+}
+
+// NewWriter returns a Writer tracing the writes of w.
+func NewWriter(w *kafka.Writer, opts ...Option) *Writer {
+	cfg := newConfig(opts...)
+	return &Writer{
+		Writer:  w,
+		cfg:     cfg,
+		tracer:  cfg.tracer(),
+		metrics: newWriterMetrics(cfg.MeterProvider),
+	}
+}
+
+// WriteMessages calls kafka.Writer.WriteMessages and traces the batch of the
+// messages in a "kafka.produce_batch" span, child of the span of ctx and
+// parent of the "kafka.produce" span of each message. The span context of
+// each message is injected in its headers.
+//
+// If the writer is asynchronous, the spans end once the messages are
+// queued, not written.
+func (w *Writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	topic := w.batchTopic(msgs)
+	batchAttrs := []attribute.KeyValue{kafkaSystem, BatchMessageCountKey.Int(len(msgs))}
+	if topic != "" {
+		batchAttrs = append(batchAttrs,
+			semconv.MessagingDestinationKindTopic,
+			semconv.MessagingDestinationKey.String(topic),
+		)
+	}
+	ctx, batch := w.tracer.Start(ctx, "kafka.produce_batch",
+		trace.WithAttributes(batchAttrs...),
+		trace.WithSpanKind(trace.SpanKindProducer),
+	)
+
+	spans := make([]trace.Span, len(msgs))
+	for i := range msgs {
+		msg := &msgs[i]
+		msgCtx, span := w.tracer.Start(ctx, "kafka.produce",
+			trace.WithAttributes(
+				kafkaSystem,
+				semconv.MessagingDestinationKindTopic,
+				semconv.MessagingDestinationKey.String(w.topic(msg)),
+			),
+			trace.WithSpanKind(trace.SpanKindProducer),
+		)
+		// Inject the span context, so consumers can use it to propagate span.
+		w.cfg.Propagators.Inject(msgCtx, NewMessageCarrier(msg))
+		spans[i] = span
+		w.metrics.sent(ctx, w.topic(msg), msg)
+	}
+
+	start := time.Now()
+	err := w.Writer.WriteMessages(ctx, msgs...)
+	w.metrics.wrote(ctx, start, topic)
+
+	// The errors of the messages are reported individually if the batch
+	// was partially written.
+	var writeErrs kafka.WriteErrors
+	partial := errors.As(err, &writeErrs) && len(writeErrs) == len(msgs)
+	for i, span := range spans {
+		msgErr := err
+		if partial {
+			msgErr = writeErrs[i]
+		}
+		endSpan(span, msgErr)
+	}
+	endSpan(batch, err)
+	return err
+}
+
+// topic returns the topic of the message, the one of the writer if it is
+// not set.
+func (w *Writer) topic(msg *kafka.Message) string {
+	if msg.Topic != "" {
+		return msg.Topic
+	}
+	return w.Writer.Topic
+}
+
+// batchTopic returns the topic of all the messages, or "" if they do not
+// have the same.
+func (w *Writer) batchTopic(msgs []kafka.Message) string {
+	if len(msgs) == 0 {
+		return w.Writer.Topic
+	}
+	topic := w.topic(&msgs[0])
+	for i := range msgs[1:] {
+		if w.topic(&msgs[i+1]) != topic {
+			return ""
+		}
+	}
+	return topic
+}
+
+// endSpan ends the span with the error if any.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
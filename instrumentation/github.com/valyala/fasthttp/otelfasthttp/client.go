@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfasthttp // import "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Doer sends fasthttp requests.  It is implemented by the fasthttp clients,
+// e.g. *fasthttp.Client, *fasthttp.HostClient, and *fasthttp.PipelineClient.
+type Doer interface {
+	Do(req *fasthttp.Request, resp *fasthttp.Response) error
+}
+
+// Client traces the requests sent by a fasthttp client, and measures them if
+// a meter provider is specified with WithMeterProvider.
+type Client struct {
+	doer        Doer
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	metrics     *requestMetrics
+}
+
+// NewClient returns a Client sending the requests with doer.
+func NewClient(doer Doer, opts ...Option) *Client {
+	cfg := newConfig(opts...)
+	return &Client{
+		doer:        doer,
+		tracer:      cfg.tracer(),
+		propagators: cfg.Propagators,
+		metrics:     newRequestMetrics(cfg.MeterProvider, ClientDuration, ClientRequestSize, ClientResponseSize),
+	}
+}
+
+// Do sends the request req in a span, child of the span of ctx, and fills
+// resp with its response, as the Do method of the fasthttp clients.  The
+// trace context of the span is injected into the headers of req.
+func (c *Client) Do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	start := time.Now()
+	ctx, span := c.tracer.Start(
+		ctx,
+		"HTTP "+string(req.Header.Method()),
+		trace.WithAttributes(clientAttributes(req)...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	c.propagators.Inject(ctx, requestHeaderCarrier{&req.Header})
+
+	attrs := clientMetricAttributes(req)
+	err := c.doer.Do(req, resp)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		c.metrics.record(ctx, start, requestBodySize(req), 0, attrs)
+		return err
+	}
+
+	status := resp.StatusCode()
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindClient))
+	attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(status))
+	c.metrics.record(ctx, start, requestBodySize(req), responseBodySize(resp), attrs)
+	return nil
+}
+
+// clientAttributes returns the attributes of the request req.  The user
+// information of its URI is left out.
+func clientAttributes(req *fasthttp.Request) []attribute.KeyValue {
+	uri := req.URI()
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(string(req.Header.Method())),
+		semconv.HTTPURLKey.String(string(uri.Scheme()) + "://" + string(uri.Host()) + string(uri.RequestURI())),
+	}
+	attrs = append(attrs, peerAttributes(uri)...)
+	if ua := req.Header.UserAgent(); len(ua) > 0 {
+		attrs = append(attrs, semconv.HTTPUserAgentKey.String(string(ua)))
+	}
+	if n := req.Header.ContentLength(); n > 0 {
+		attrs = append(attrs, semconv.HTTPRequestContentLengthKey.Int(n))
+	}
+	return attrs
+}
+
+// clientMetricAttributes returns the attributes of the metrics of the
+// request req.  Only low cardinality attributes are used.
+func clientMetricAttributes(req *fasthttp.Request) []attribute.KeyValue {
+	uri := req.URI()
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(string(req.Header.Method())),
+		semconv.HTTPSchemeKey.String(string(uri.Scheme())),
+	}
+	return append(attrs, peerAttributes(uri)...)
+}
+
+// peerAttributes returns the name and port of the host of uri.
+func peerAttributes(uri *fasthttp.URI) []attribute.KeyValue {
+	host := string(uri.Host())
+	if host == "" {
+		return nil
+	}
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetPeerNameKey.String(host)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetPeerNameKey.String(name)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
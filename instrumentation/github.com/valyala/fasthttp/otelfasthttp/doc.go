@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelfasthttp instruments the github.com/valyala/fasthttp package.
+//
+// The requests served by a fasthttp.RequestHandler are traced, and measured
+// if a meter provider is specified, by wrapping it with NewHandler:
+//
+//	fasthttp.ListenAndServe(":8080", otelfasthttp.NewHandler(handler, "my-service"))
+//
+// The handlers get the context of the span of their request with
+// RequestContext.
+//
+// The requests sent by a fasthttp client are traced, and measured if a meter
+// provider is specified, by the Do method of the Client wrapping it:
+//
+//	client := otelfasthttp.NewClient(&fasthttp.Client{})
+//	err := client.Do(ctx, req, resp)
+//
+// The trace context is propagated through the fasthttp headers of the
+// requests.
+package otelfasthttp // import "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
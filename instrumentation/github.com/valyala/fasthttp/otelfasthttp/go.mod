@@ -0,0 +1,10 @@
+module go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp
+
+go 1.17
+
+require (
+	github.com/valyala/fasthttp v1.38.0
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
+	go.opentelemetry.io/otel/trace v1.9.0
+)
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfasthttp // import "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+
+import (
+	"context"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+
+	// contextKey is the key of the user value of a fasthttp.RequestCtx
+	// holding the context of the span of its request.
+	contextKey = instrumentationName + ".context"
+)
+
+// NewHandler wraps the passed handler in a span named after the operation,
+// and measures the requests it serves if a meter provider is specified with
+// WithMeterProvider.  The operation is also used as the name of the server
+// of the requests.
+//
+// The trace context of the requests is extracted from their fasthttp
+// headers, and the handler gets the context of the span with RequestContext.
+func NewHandler(handler fasthttp.RequestHandler, operation string, opts ...Option) fasthttp.RequestHandler {
+	cfg := newConfig(opts...)
+	tracer := cfg.tracer()
+	propagators := cfg.Propagators
+	metrics := newRequestMetrics(cfg.MeterProvider, ServerDuration, ServerRequestSize, ServerResponseSize)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		// The RequestCtx is not used as the parent context, as it is reused
+		// once the handler returns.
+		parent := propagators.Extract(context.Background(), requestHeaderCarrier{&ctx.Request.Header})
+		spanCtx, span := tracer.Start(
+			parent,
+			operation,
+			trace.WithAttributes(serverAttributes(operation, ctx)...),
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		defer span.End()
+
+		ctx.SetUserValue(contextKey, spanCtx)
+		handler(ctx)
+
+		status := ctx.Response.StatusCode()
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+		metrics.record(
+			spanCtx,
+			start,
+			requestBodySize(&ctx.Request),
+			responseBodySize(&ctx.Response),
+			serverMetricAttributes(operation, ctx, status),
+		)
+	}
+}
+
+// RequestContext returns the context of the span of the request served by
+// ctx, or context.Background() if the request is not served by a handler
+// returned by NewHandler.  The context must not be used once the handler
+// serving the request returns.
+func RequestContext(ctx *fasthttp.RequestCtx) context.Context {
+	if c, ok := ctx.UserValue(contextKey).(context.Context); ok {
+		return c
+	}
+	return context.Background()
+}
+
+// serverAttributes returns the attributes of the request served by ctx.
+func serverAttributes(serverName string, ctx *fasthttp.RequestCtx) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(string(ctx.Method())),
+		semconv.HTTPTargetKey.String(string(ctx.RequestURI())),
+		semconv.HTTPSchemeKey.String(scheme(ctx.IsTLS())),
+		flavor(ctx.Request.Header.IsHTTP11()),
+		semconv.NetPeerIPKey.String(ctx.RemoteIP().String()),
+	}
+	if serverName != "" {
+		attrs = append(attrs, semconv.HTTPServerNameKey.String(serverName))
+	}
+	if host := ctx.Host(); len(host) > 0 {
+		attrs = append(attrs, semconv.HTTPHostKey.String(string(host)))
+	}
+	if ua := ctx.UserAgent(); len(ua) > 0 {
+		attrs = append(attrs, semconv.HTTPUserAgentKey.String(string(ua)))
+	}
+	if n := ctx.Request.Header.ContentLength(); n > 0 {
+		attrs = append(attrs, semconv.HTTPRequestContentLengthKey.Int(n))
+	}
+	return attrs
+}
+
+// serverMetricAttributes returns the attributes of the metrics of the
+// request served by ctx.  Only low cardinality attributes are used.
+func serverMetricAttributes(serverName string, ctx *fasthttp.RequestCtx, status int) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(string(ctx.Method())),
+		semconv.HTTPSchemeKey.String(scheme(ctx.IsTLS())),
+		flavor(ctx.Request.Header.IsHTTP11()),
+		semconv.HTTPStatusCodeKey.Int(status),
+	}
+	if serverName != "" {
+		attrs = append(attrs, semconv.HTTPServerNameKey.String(serverName))
+	}
+	return attrs
+}
+
+func scheme(tls bool) string {
+	if tls {
+		return "https"
+	}
+	return "http"
+}
+
+func flavor(http11 bool) attribute.KeyValue {
+	if http11 {
+		return semconv.HTTPFlavorHTTP11
+	}
+	return semconv.HTTPFlavorHTTP10
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfasthttp // import "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+
+import (
+	"github.com/valyala/fasthttp"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// requestHeaderCarrier adapts the headers of a fasthttp request to a
+// propagation.TextMapCarrier.
+type requestHeaderCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+var _ propagation.TextMapCarrier = requestHeaderCarrier{}
+
+// Get returns the value of the header key.
+func (c requestHeaderCarrier) Get(key string) string {
+	return string(c.header.Peek(key))
+}
+
+// Set sets the value of the header key.
+func (c requestHeaderCarrier) Set(key string, value string) {
+	c.header.Set(key, value)
+}
+
+// Keys lists the keys of the headers.
+func (c requestHeaderCarrier) Keys() []string {
+	var keys []string
+	c.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// requestBodySize returns the size of the body of req.  The size of a body
+// stream is its content length, if known, as reading it would consume it.
+func requestBodySize(req *fasthttp.Request) int {
+	if req.IsBodyStream() {
+		return knownLength(req.Header.ContentLength())
+	}
+	return len(req.Body())
+}
+
+// responseBodySize returns the size of the body of resp, like
+// requestBodySize.
+func responseBodySize(resp *fasthttp.Response) int {
+	if resp.IsBodyStream() {
+		return knownLength(resp.Header.ContentLength())
+	}
+	return len(resp.Body())
+}
+
+// knownLength returns the content length n, or 0 if it is negative, i.e.
+// unknown.
+func knownLength(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelfasthttp // import "go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Server HTTP metrics.
+const (
+	ServerDuration     = "http.server.duration"      // Duration of the served requests, milliseconds
+	ServerRequestSize  = "http.server.request.size"  // Size of the served request bodies, bytes
+	ServerResponseSize = "http.server.response.size" // Size of the served response bodies, bytes
+)
+
+// Client HTTP metrics.
+const (
+	ClientDuration     = "http.client.duration"      // Duration of the sent requests, milliseconds
+	ClientRequestSize  = "http.client.request.size"  // Size of the sent request bodies, bytes
+	ClientResponseSize = "http.client.response.size" // Size of the received response bodies, bytes
+)
+
+// requestMetrics holds the instruments of the requests served by a handler
+// or sent by a client.  A nil *requestMetrics records nothing.
+type requestMetrics struct {
+	duration     syncfloat64.Histogram
+	requestSize  syncint64.Histogram
+	responseSize syncint64.Histogram
+}
+
+// newRequestMetrics returns the instruments of the requests with the
+// names, the Server ones for a handler and the Client ones for a client, or
+// nil if no meter provider is configured.
+func newRequestMetrics(mp metric.MeterProvider, duration, requestSize, responseSize string) *requestMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   requestMetrics
+		err error
+	)
+	if m.duration, err = meter.SyncFloat64().Histogram(
+		duration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the requests"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.requestSize, err = meter.SyncInt64().Histogram(
+		requestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the request bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.responseSize, err = meter.SyncInt64().Histogram(
+		responseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the response bodies"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	return &m
+}
+
+// record records the duration since start and the sizes of the bodies of a
+// request and its response with the attributes.
+func (m *requestMetrics) record(ctx context.Context, start time.Time, requestSize, responseSize int, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	m.duration.Record(ctx, elapsed, attrs...)
+	m.requestSize.Record(ctx, int64(requestSize), attrs...)
+	m.responseSize.Record(ctx, int64(responseSize), attrs...)
+}
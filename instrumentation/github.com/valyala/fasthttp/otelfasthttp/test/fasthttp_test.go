@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serve serves the requests of the returned client with handler.
+func serve(t *testing.T, handler fasthttp.RequestHandler) *fasthttp.Client {
+	ln := fasthttputil.NewInmemoryListener()
+	s := &fasthttp.Server{Handler: handler}
+	go func() {
+		if err := s.Serve(ln); err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, s.Shutdown())
+	})
+	return &fasthttp.Client{
+		Dial: func(string) (net.Conn, error) { return ln.Dial() },
+	}
+}
+
+func get(t *testing.T, do func(req *fasthttp.Request, resp *fasthttp.Response) error, uri string) int {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(uri)
+	require.NoError(t, do(req, resp))
+	return resp.StatusCode()
+}
+
+func TestHandler(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	var got trace.SpanContext
+	handler := otelfasthttp.NewHandler(func(ctx *fasthttp.RequestCtx) {
+		got = trace.SpanContextFromContext(otelfasthttp.RequestContext(ctx))
+		ctx.SetStatusCode(http.StatusCreated)
+	}, "foobar", otelfasthttp.WithTracerProvider(provider))
+	client := serve(t, handler)
+
+	status := get(t, client.Do, "http://example.com/user/123?q=1")
+	assert.Equal(t, http.StatusCreated, status)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assert.Equal(t, "foobar", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, span.SpanContext(), got)
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, attribute.String("http.server_name", "foobar"))
+	assert.Contains(t, attrs, attribute.String("http.method", "GET"))
+	assert.Contains(t, attrs, attribute.String("http.target", "/user/123?q=1"))
+	assert.Contains(t, attrs, attribute.String("http.scheme", "http"))
+	assert.Contains(t, attrs, attribute.String("http.host", "example.com"))
+	assert.Contains(t, attrs, attribute.Int("http.status_code", http.StatusCreated))
+}
+
+func TestHandlerSpanStatus(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	handler := otelfasthttp.NewHandler(func(ctx *fasthttp.RequestCtx) {
+		ctx.Error("internal error", http.StatusInternalServerError)
+	}, "foobar", otelfasthttp.WithTracerProvider(provider))
+	client := serve(t, handler)
+
+	status := get(t, client.Do, "http://example.com/")
+	assert.Equal(t, http.StatusInternalServerError, status)
+
+	require.Len(t, sr.Ended(), 1)
+	assert.Equal(t, codes.Error, sr.Ended()[0].Status().Code)
+}
+
+func TestClientPropagation(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	propagators := otelfasthttp.WithPropagators(propagation.TraceContext{})
+
+	handler := otelfasthttp.NewHandler(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString("ok")
+	}, "foobar", otelfasthttp.WithTracerProvider(provider), propagators)
+	client := otelfasthttp.NewClient(serve(t, handler), otelfasthttp.WithTracerProvider(provider), propagators)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	status := get(t, func(req *fasthttp.Request, resp *fasthttp.Response) error {
+		return client.Do(ctx, req, resp)
+	}, "http://example.com:8080/user/123")
+	parent.End()
+	assert.Equal(t, http.StatusOK, status)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	serverSpan, clientSpan := spans[0], spans[1]
+
+	assert.Equal(t, "HTTP GET", clientSpan.Name())
+	assert.Equal(t, trace.SpanKindClient, clientSpan.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	attrs := clientSpan.Attributes()
+	assert.Contains(t, attrs, attribute.String("http.method", "GET"))
+	assert.Contains(t, attrs, attribute.String("http.url", "http://example.com:8080/user/123"))
+	assert.Contains(t, attrs, attribute.String("net.peer.name", "example.com"))
+	assert.Contains(t, attrs, attribute.Int("net.peer.port", 8080))
+	assert.Contains(t, attrs, attribute.Int("http.status_code", http.StatusOK))
+
+	assert.Equal(t, clientSpan.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+	assert.True(t, serverSpan.Parent().IsRemote())
+}
+
+func TestClientError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ln := fasthttputil.NewInmemoryListener()
+	require.NoError(t, ln.Close())
+	client := otelfasthttp.NewClient(&fasthttp.Client{
+		Dial: func(string) (net.Conn, error) { return ln.Dial() },
+	}, otelfasthttp.WithTracerProvider(provider))
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI("http://example.com/")
+
+	require.Error(t, client.Do(context.Background(), req, resp))
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assert.Equal(t, codes.Error, span.Status().Code)
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, "exception", span.Events()[0].Name)
+}
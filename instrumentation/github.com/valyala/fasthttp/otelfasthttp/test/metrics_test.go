@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/valyala/fasthttp/otelfasthttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	handler := otelfasthttp.NewHandler(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(http.StatusCreated)
+		ctx.SetBodyString("created")
+	}, "foobar", otelfasthttp.WithMeterProvider(mp))
+	client := otelfasthttp.NewClient(serve(t, handler), otelfasthttp.WithMeterProvider(mp))
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI("http://example.com/user/123")
+	req.Header.SetMethod("POST")
+	req.SetBodyString("name=foo")
+	require.NoError(t, client.Do(context.Background(), req, resp))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	serverAttrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("POST"),
+		semconv.HTTPSchemeKey.String("http"),
+		semconv.HTTPFlavorHTTP11,
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+		semconv.HTTPServerNameKey.String("foobar"),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelfasthttp.ServerDuration, serverAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(1), duration.Count)
+	requestSize, err := exp.GetByNameAndAttributes(otelfasthttp.ServerRequestSize, serverAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("name=foo")), requestSize.Sum.AsInt64())
+	responseSize, err := exp.GetByNameAndAttributes(otelfasthttp.ServerResponseSize, serverAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("created")), responseSize.Sum.AsInt64())
+
+	clientAttrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String("POST"),
+		semconv.HTTPSchemeKey.String("http"),
+		semconv.NetPeerNameKey.String("example.com"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelfasthttp.ClientDuration, clientAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+	requestSize, err = exp.GetByNameAndAttributes(otelfasthttp.ClientRequestSize, clientAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("name=foo")), requestSize.Sum.AsInt64())
+	responseSize, err = exp.GetByNameAndAttributes(otelfasthttp.ClientResponseSize, clientAttrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("created")), responseSize.Sum.AsInt64())
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelclientv3 instruments the go.etcd.io/etcd/client/v3 package.
+//
+// The key-value, watch, and lease operations of a client are traced, and
+// their duration is measured if a meter provider is specified, when the
+// client is instrumented with Instrument:
+//
+//	cli, err := clientv3.New(clientv3.Config{Endpoints: endpoints})
+//	if err != nil {
+//		return err
+//	}
+//	otelclientv3.Instrument(cli, otelclientv3.WithMeterProvider(mp))
+//
+// or when its KV, Watcher, or Lease are wrapped with NewKV, NewWatcher, or
+// NewLease, e.g. after namespace.NewKV.
+//
+// The spans are named after the operations, e.g. "etcd.get" or
+// "etcd.lease.grant", and record the key of the operations and the revision
+// of the store they observed.  The span of a watch lasts as long as the
+// watch: each response received is recorded as an event of the span.
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
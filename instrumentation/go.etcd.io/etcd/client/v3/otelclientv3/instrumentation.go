@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+// Names of the operations, recorded as the db.operation attribute.  The
+// spans are named after them prefixed with "etcd.".
+const (
+	operationGet             = "get"
+	operationPut             = "put"
+	operationDelete          = "delete"
+	operationCompact         = "compact"
+	operationTxn             = "txn"
+	operationWatch           = "watch"
+	operationRequestProgress = "watch.request_progress"
+	operationGrant           = "lease.grant"
+	operationRevoke          = "lease.revoke"
+	operationTimeToLive      = "lease.time_to_live"
+	operationLeases          = "lease.leases"
+	operationKeepAlive       = "lease.keep_alive"
+	operationKeepAliveOnce   = "lease.keep_alive_once"
+)
+
+// Attribute keys of the spans.
+const (
+	// KeyKey is the key, or the first key of the range, of an operation.
+	KeyKey = attribute.Key("db.etcd.key")
+	// RevisionKey is the revision of the store observed by an operation.
+	RevisionKey = attribute.Key("db.etcd.revision")
+	// CountKey is the number of the keys in the range of a get operation.
+	CountKey = attribute.Key("db.etcd.count")
+	// DeletedKey is the number of the keys deleted by a delete operation.
+	DeletedKey = attribute.Key("db.etcd.deleted")
+	// TxnSucceededKey is whether the comparisons of a transaction succeeded.
+	TxnSucceededKey = attribute.Key("db.etcd.txn.succeeded")
+	// LeaseIDKey is the id of the lease of a lease operation.
+	LeaseIDKey = attribute.Key("db.etcd.lease.id")
+	// WatchEventsKey is the number of the events of a watch response.
+	WatchEventsKey = attribute.Key("db.etcd.watch.events")
+)
+
+// dbSystem is the db.system of etcd.
+var dbSystem = semconv.DBSystemKey.String("etcd")
+
+// Instrument instruments the KV, Watcher, and Lease of the client.
+func Instrument(client *clientv3.Client, opts ...Option) {
+	in := newInstrumentation(newConfig(opts...))
+	client.KV = &kv{base: client.KV, in: in}
+	client.Watcher = &watcher{base: client.Watcher, in: in}
+	client.Lease = &lease{base: client.Lease, in: in}
+}
+
+// instrumentation traces and measures the operations of a client.
+type instrumentation struct {
+	tracer  trace.Tracer
+	metrics *clientMetrics
+	attrs   []attribute.KeyValue
+}
+
+func newInstrumentation(cfg *config) *instrumentation {
+	return &instrumentation{
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		metrics: newClientMetrics(cfg.MeterProvider),
+		attrs:   cfg.Attributes,
+	}
+}
+
+// operation is an operation whose span is started.
+type operation struct {
+	in *instrumentation
+	// ctx is the context of the span of the operation.
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+	// attrs are the attributes of the metrics of the operation.
+	attrs []attribute.KeyValue
+}
+
+// start starts the span of the operation with the name, child of the span
+// of ctx, with the attributes.
+func (in *instrumentation) start(ctx context.Context, name string, attrs ...attribute.KeyValue) *operation {
+	metricAttrs := make([]attribute.KeyValue, 0, len(in.attrs)+2)
+	metricAttrs = append(metricAttrs, dbSystem, semconv.DBOperationKey.String(name))
+	metricAttrs = append(metricAttrs, in.attrs...)
+
+	ctx, span := in.tracer.Start(ctx, "etcd."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(metricAttrs...),
+		trace.WithAttributes(attrs...),
+	)
+	return &operation{
+		in:    in,
+		ctx:   ctx,
+		span:  span,
+		start: time.Now(),
+		attrs: metricAttrs,
+	}
+}
+
+// end ends the span of the operation, with the error if any, and records
+// its duration.
+func (op *operation) end(err error) {
+	op.endSpan(err)
+	op.in.metrics.record(op.ctx, op.start, op.attrs)
+}
+
+// endSpan ends the span of the operation with the error if any.
+func (op *operation) endSpan(err error) {
+	if err != nil {
+		op.span.RecordError(err)
+		op.span.SetStatus(codes.Error, err.Error())
+	}
+	op.span.End()
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// kv traces the operations of a clientv3.KV.
+type kv struct {
+	base clientv3.KV
+	in   *instrumentation
+}
+
+var _ clientv3.KV = (*kv)(nil)
+
+// NewKV returns a clientv3.KV tracing the operations of base.
+func NewKV(base clientv3.KV, opts ...Option) clientv3.KV {
+	return &kv{base: base, in: newInstrumentation(newConfig(opts...))}
+}
+
+func (k *kv) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	op := k.in.start(ctx, operationPut, KeyKey.String(key))
+	resp, err := k.base.Put(op.ctx, key, val, opts...)
+	if resp != nil {
+		op.span.SetAttributes(RevisionKey.Int64(resp.Header.GetRevision()))
+	}
+	op.end(err)
+	return resp, err
+}
+
+func (k *kv) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	op := k.in.start(ctx, operationGet, KeyKey.String(key))
+	resp, err := k.base.Get(op.ctx, key, opts...)
+	if resp != nil {
+		op.span.SetAttributes(
+			RevisionKey.Int64(resp.Header.GetRevision()),
+			CountKey.Int64(resp.Count),
+		)
+	}
+	op.end(err)
+	return resp, err
+}
+
+func (k *kv) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	op := k.in.start(ctx, operationDelete, KeyKey.String(key))
+	resp, err := k.base.Delete(op.ctx, key, opts...)
+	if resp != nil {
+		op.span.SetAttributes(
+			RevisionKey.Int64(resp.Header.GetRevision()),
+			DeletedKey.Int64(resp.Deleted),
+		)
+	}
+	op.end(err)
+	return resp, err
+}
+
+func (k *kv) Compact(ctx context.Context, rev int64, opts ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	op := k.in.start(ctx, operationCompact)
+	resp, err := k.base.Compact(op.ctx, rev, opts...)
+	if resp != nil {
+		op.span.SetAttributes(RevisionKey.Int64(resp.Header.GetRevision()))
+	}
+	op.end(err)
+	return resp, err
+}
+
+// Do traces the operation in a span named after its type, e.g. "etcd.get".
+func (k *kv) Do(ctx context.Context, o clientv3.Op) (clientv3.OpResponse, error) {
+	var op *operation
+	switch {
+	case o.IsGet():
+		op = k.in.start(ctx, operationGet, KeyKey.String(string(o.KeyBytes())))
+	case o.IsPut():
+		op = k.in.start(ctx, operationPut, KeyKey.String(string(o.KeyBytes())))
+	case o.IsDelete():
+		op = k.in.start(ctx, operationDelete, KeyKey.String(string(o.KeyBytes())))
+	case o.IsTxn():
+		op = k.in.start(ctx, operationTxn)
+	default:
+		return k.base.Do(ctx, o)
+	}
+	resp, err := k.base.Do(op.ctx, o)
+	if r := resp.Get(); r != nil {
+		op.span.SetAttributes(RevisionKey.Int64(r.Header.GetRevision()), CountKey.Int64(r.Count))
+	} else if r := resp.Put(); r != nil {
+		op.span.SetAttributes(RevisionKey.Int64(r.Header.GetRevision()))
+	} else if r := resp.Del(); r != nil {
+		op.span.SetAttributes(RevisionKey.Int64(r.Header.GetRevision()), DeletedKey.Int64(r.Deleted))
+	} else if r := resp.Txn(); r != nil {
+		op.span.SetAttributes(RevisionKey.Int64(r.Header.GetRevision()), TxnSucceededKey.Bool(r.Succeeded))
+	}
+	op.end(err)
+	return resp, err
+}
+
+// Txn returns a transaction whose commit is traced.
+func (k *kv) Txn(ctx context.Context) clientv3.Txn {
+	return &txn{base: k.base.Txn(ctx), ctx: ctx, in: k.in}
+}
+
+// txn traces the commit of a clientv3.Txn.
+type txn struct {
+	base clientv3.Txn
+	// ctx is the context of the transaction.
+	ctx context.Context
+	in  *instrumentation
+}
+
+var _ clientv3.Txn = (*txn)(nil)
+
+func (t *txn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.base = t.base.If(cs...)
+	return t
+}
+
+func (t *txn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.base = t.base.Then(ops...)
+	return t
+}
+
+func (t *txn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.base = t.base.Else(ops...)
+	return t
+}
+
+// Commit traces the commit of the transaction in a span child of the span
+// of the context of the transaction.
+func (t *txn) Commit() (*clientv3.TxnResponse, error) {
+	op := t.in.start(t.ctx, operationTxn)
+	resp, err := t.base.Commit()
+	if resp != nil {
+		op.span.SetAttributes(
+			RevisionKey.Int64(resp.Header.GetRevision()),
+			TxnSucceededKey.Bool(resp.Succeeded),
+		)
+	}
+	op.end(err)
+	return resp, err
+}
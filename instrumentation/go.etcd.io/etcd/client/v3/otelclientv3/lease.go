@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// lease traces the operations of a clientv3.Lease.
+type lease struct {
+	base clientv3.Lease
+	in   *instrumentation
+}
+
+var _ clientv3.Lease = (*lease)(nil)
+
+// NewLease returns a clientv3.Lease tracing the operations of base.
+func NewLease(base clientv3.Lease, opts ...Option) clientv3.Lease {
+	return &lease{base: base, in: newInstrumentation(newConfig(opts...))}
+}
+
+func (l *lease) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	op := l.in.start(ctx, operationGrant)
+	resp, err := l.base.Grant(op.ctx, ttl)
+	if resp != nil {
+		op.span.SetAttributes(LeaseIDKey.Int64(int64(resp.ID)))
+	}
+	op.end(err)
+	return resp, err
+}
+
+func (l *lease) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	op := l.in.start(ctx, operationRevoke, LeaseIDKey.Int64(int64(id)))
+	resp, err := l.base.Revoke(op.ctx, id)
+	op.end(err)
+	return resp, err
+}
+
+func (l *lease) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	op := l.in.start(ctx, operationTimeToLive, LeaseIDKey.Int64(int64(id)))
+	resp, err := l.base.TimeToLive(op.ctx, id, opts...)
+	op.end(err)
+	return resp, err
+}
+
+func (l *lease) Leases(ctx context.Context) (*clientv3.LeaseLeasesResponse, error) {
+	op := l.in.start(ctx, operationLeases)
+	resp, err := l.base.Leases(op.ctx)
+	op.end(err)
+	return resp, err
+}
+
+// KeepAlive traces the call starting to keep the lease alive, not the keep
+// alive requests sent afterwards.
+func (l *lease) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	op := l.in.start(ctx, operationKeepAlive, LeaseIDKey.Int64(int64(id)))
+	// The keep alives last as long as ctx: the context of the span is
+	// not passed to not make their requests children of an ended span.
+	ch, err := l.base.KeepAlive(ctx, id)
+	op.end(err)
+	return ch, err
+}
+
+func (l *lease) KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	op := l.in.start(ctx, operationKeepAliveOnce, LeaseIDKey.Int64(int64(id)))
+	resp, err := l.base.KeepAliveOnce(op.ctx, id)
+	op.end(err)
+	return resp, err
+}
+
+func (l *lease) Close() error {
+	return l.base.Close()
+}
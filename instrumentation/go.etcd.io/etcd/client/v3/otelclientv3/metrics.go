@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Client metrics.
+const (
+	ClientOperationDuration = "db.client.operation.duration" // Duration of the operations, milliseconds
+	ClientWatchEvents       = "db.client.etcd.watch.events"  // Number of the events received by the watches
+)
+
+// clientMetrics holds the instruments of the operations of the clients.  A
+// nil *clientMetrics records nothing.
+type clientMetrics struct {
+	duration    syncfloat64.Histogram
+	watchEvents syncint64.Counter
+}
+
+// newClientMetrics returns the instruments of the operations, or nil if the
+// metrics are not enabled.
+func newClientMetrics(mp metric.MeterProvider) *clientMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   clientMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ClientOperationDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the etcd operations"),
+	)
+	handleErr(err)
+
+	m.watchEvents, err = meter.SyncInt64().Counter(
+		ClientWatchEvents,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the events received by the watches"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// record records the duration since start of an operation with the
+// attributes.
+func (m *clientMetrics) record(ctx context.Context, start time.Time, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	m.duration.Record(ctx, elapsed, attrs...)
+}
+
+// watched records the number of events received by a watch with the
+// attributes.
+func (m *clientMetrics) watched(ctx context.Context, events int, attrs []attribute.KeyValue) {
+	if m == nil || events == 0 {
+		return
+	}
+	m.watchEvents.Add(ctx, int64(events), attrs...)
+}
@@ -0,0 +1,364 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var etcd = semconv.DBSystemKey.String("etcd")
+
+// fakeKV is a clientv3.KV answering at revision 7, failing with err if any.
+type fakeKV struct {
+	err error
+	// ctx is the context of the last operation.
+	ctx context.Context
+}
+
+func (kv *fakeKV) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: 7}
+}
+
+func (kv *fakeKV) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	kv.ctx = ctx
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	return &clientv3.PutResponse{Header: kv.header()}, nil
+}
+
+func (kv *fakeKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	kv.ctx = ctx
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	return &clientv3.GetResponse{Header: kv.header(), Count: 2}, nil
+}
+
+func (kv *fakeKV) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	kv.ctx = ctx
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	return &clientv3.DeleteResponse{Header: kv.header(), Deleted: 1}, nil
+}
+
+func (kv *fakeKV) Compact(ctx context.Context, rev int64, opts ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	kv.ctx = ctx
+	return &clientv3.CompactResponse{Header: kv.header()}, kv.err
+}
+
+func (kv *fakeKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	kv.ctx = ctx
+	switch {
+	case op.IsGet():
+		resp, err := kv.Get(ctx, string(op.KeyBytes()))
+		return resp.OpResponse(), err
+	case op.IsPut():
+		resp, err := kv.Put(ctx, string(op.KeyBytes()), string(op.ValueBytes()))
+		return resp.OpResponse(), err
+	case op.IsDelete():
+		resp, err := kv.Delete(ctx, string(op.KeyBytes()))
+		return resp.OpResponse(), err
+	}
+	resp := &clientv3.TxnResponse{Header: kv.header(), Succeeded: true}
+	return resp.OpResponse(), kv.err
+}
+
+func (kv *fakeKV) Txn(ctx context.Context) clientv3.Txn {
+	kv.ctx = ctx
+	return &fakeTxn{kv: kv}
+}
+
+// fakeTxn is a clientv3.Txn succeeding if it has comparisons.
+type fakeTxn struct {
+	kv   *fakeKV
+	cmps int
+}
+
+func (t *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps += len(cs)
+	return t
+}
+
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn { return t }
+
+func (t *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn { return t }
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	if t.kv.err != nil {
+		return nil, t.kv.err
+	}
+	return &clientv3.TxnResponse{Header: t.kv.header(), Succeeded: t.cmps > 0}, nil
+}
+
+// fakeWatcher is a clientv3.Watcher whose watches receive the responses
+// sent on ch.
+type fakeWatcher struct {
+	ch chan clientv3.WatchResponse
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	return w.ch
+}
+
+func (w *fakeWatcher) RequestProgress(ctx context.Context) error { return nil }
+
+func (w *fakeWatcher) Close() error { return nil }
+
+// fakeLease is a clientv3.Lease granting the lease 42.
+type fakeLease struct{}
+
+func (fakeLease) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	return &clientv3.LeaseGrantResponse{ID: 42, TTL: ttl}, nil
+}
+
+func (fakeLease) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+func (fakeLease) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	return &clientv3.LeaseTimeToLiveResponse{ID: id}, nil
+}
+
+func (fakeLease) Leases(ctx context.Context) (*clientv3.LeaseLeasesResponse, error) {
+	return &clientv3.LeaseLeasesResponse{}, nil
+}
+
+func (fakeLease) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	return make(chan *clientv3.LeaseKeepAliveResponse), nil
+}
+
+func (fakeLease) KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	return &clientv3.LeaseKeepAliveResponse{ID: id}, nil
+}
+
+func (fakeLease) Close() error { return nil }
+
+// newClient returns a client of the fakes instrumented with the options,
+// the recorder of its spans, and the context of a span parent of the spans
+// of the operations.
+func newClient(t *testing.T, kv *fakeKV, w *fakeWatcher, opts ...otelclientv3.Option) (*clientv3.Client, *tracetest.SpanRecorder, context.Context) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	opts = append([]otelclientv3.Option{otelclientv3.WithTracerProvider(provider)}, opts...)
+
+	client := &clientv3.Client{KV: kv, Watcher: w, Lease: fakeLease{}}
+	otelclientv3.Instrument(client, opts...)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	t.Cleanup(func() { parent.End() })
+	return client, sr, ctx
+}
+
+func TestKV(t *testing.T) {
+	kv := &fakeKV{}
+	client, sr, ctx := newClient(t, kv, nil)
+
+	_, err := client.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	// The span is the parent of the spans of the wrapped client.
+	assert.Equal(t, sr.Ended()[0].SpanContext(), trace.SpanContextFromContext(kv.ctx))
+	_, err = client.Get(ctx, "foo")
+	require.NoError(t, err)
+	_, err = client.Delete(ctx, "foo")
+	require.NoError(t, err)
+	_, err = client.Compact(ctx, 5)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+	expected := []struct {
+		name  string
+		attrs []attribute.KeyValue
+	}{
+		{"etcd.put", []attribute.KeyValue{otelclientv3.KeyKey.String("foo")}},
+		{"etcd.get", []attribute.KeyValue{otelclientv3.KeyKey.String("foo"), otelclientv3.CountKey.Int64(2)}},
+		{"etcd.delete", []attribute.KeyValue{otelclientv3.KeyKey.String("foo"), otelclientv3.DeletedKey.Int64(1)}},
+		{"etcd.compact", nil},
+	}
+	for i, e := range expected {
+		span := spans[i]
+		assert.Equal(t, e.name, span.Name())
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+		attrs := span.Attributes()
+		assert.Contains(t, attrs, etcd)
+		assert.Contains(t, attrs, semconv.DBOperationKey.String(e.name[len("etcd."):]))
+		assert.Contains(t, attrs, otelclientv3.RevisionKey.Int64(7))
+		for _, attr := range e.attrs {
+			assert.Contains(t, attrs, attr)
+		}
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	}
+}
+
+func TestKVError(t *testing.T) {
+	client, sr, ctx := newClient(t, &fakeKV{err: errors.New("fail")}, nil)
+
+	_, err := client.Get(ctx, "foo")
+	require.Error(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "fail", spans[0].Status().Description)
+	assert.NotContains(t, spans[0].Attributes(), otelclientv3.RevisionKey.Int64(7))
+}
+
+func TestDo(t *testing.T) {
+	client, sr, ctx := newClient(t, &fakeKV{}, nil)
+
+	for _, op := range []clientv3.Op{
+		clientv3.OpGet("foo"),
+		clientv3.OpPut("foo", "bar"),
+		clientv3.OpDelete("foo"),
+		clientv3.OpTxn(nil, nil, nil),
+	} {
+		_, err := client.Do(ctx, op)
+		require.NoError(t, err)
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+	for i, name := range []string{"etcd.get", "etcd.put", "etcd.delete", "etcd.txn"} {
+		assert.Equal(t, name, spans[i].Name())
+		assert.Contains(t, spans[i].Attributes(), otelclientv3.RevisionKey.Int64(7))
+	}
+	assert.Contains(t, spans[0].Attributes(), otelclientv3.KeyKey.String("foo"))
+	assert.Contains(t, spans[3].Attributes(), otelclientv3.TxnSucceededKey.Bool(true))
+}
+
+func TestTxn(t *testing.T) {
+	client, sr, ctx := newClient(t, &fakeKV{}, nil)
+
+	resp, err := client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value("foo"), "=", "bar")).
+		Then(clientv3.OpPut("foo", "baz")).
+		Else(clientv3.OpGet("foo")).
+		Commit()
+	require.NoError(t, err)
+	assert.True(t, resp.Succeeded)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "etcd.txn", spans[0].Name())
+	assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), otelclientv3.TxnSucceededKey.Bool(true))
+}
+
+func TestWatch(t *testing.T) {
+	w := &fakeWatcher{ch: make(chan clientv3.WatchResponse)}
+	client, sr, ctx := newClient(t, &fakeKV{}, w)
+
+	ch := client.Watch(ctx, "foo")
+	go func() {
+		w.ch <- clientv3.WatchResponse{
+			Header: pb.ResponseHeader{Revision: 8},
+			Events: []*clientv3.Event{{}, {}},
+		}
+		w.ch <- clientv3.WatchResponse{CompactRevision: 3}
+		close(w.ch)
+	}()
+
+	var responses []clientv3.WatchResponse
+	for resp := range ch {
+		responses = append(responses, resp)
+	}
+	require.Len(t, responses, 2)
+	assert.Len(t, responses[0].Events, 2)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "etcd.watch", span.Name())
+	assert.Contains(t, span.Attributes(), otelclientv3.KeyKey.String("foo"))
+	require.Len(t, span.Events(), 3)
+	assert.Equal(t, "etcd.watch.response", span.Events()[0].Name)
+	assert.Contains(t, span.Events()[0].Attributes, otelclientv3.RevisionKey.Int64(8))
+	assert.Contains(t, span.Events()[0].Attributes, otelclientv3.WatchEventsKey.Int(2))
+	// The compaction canceled the watch.
+	assert.Equal(t, "exception", span.Events()[2].Name)
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
+
+func TestWatchCanceled(t *testing.T) {
+	w := &fakeWatcher{ch: make(chan clientv3.WatchResponse)}
+	client, sr, ctx := newClient(t, &fakeKV{}, w)
+
+	ctx, cancel := context.WithCancel(ctx)
+	client.Watch(ctx, "foo")
+	cancel()
+
+	// The responses are not received anymore, the span ends once the
+	// watcher closes the channel.
+	w.ch <- clientv3.WatchResponse{Events: []*clientv3.Event{{}}}
+	assert.Empty(t, sr.Ended())
+	close(w.ch)
+	require.Eventually(t, func() bool {
+		return len(sr.Ended()) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, codes.Unset, sr.Ended()[0].Status().Code)
+}
+
+func TestLease(t *testing.T) {
+	client, sr, ctx := newClient(t, &fakeKV{}, nil)
+
+	resp, err := client.Grant(ctx, 10)
+	require.NoError(t, err)
+	_, err = client.KeepAlive(ctx, resp.ID)
+	require.NoError(t, err)
+	_, err = client.KeepAliveOnce(ctx, resp.ID)
+	require.NoError(t, err)
+	_, err = client.TimeToLive(ctx, resp.ID)
+	require.NoError(t, err)
+	_, err = client.Leases(ctx)
+	require.NoError(t, err)
+	_, err = client.Revoke(ctx, resp.ID)
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 6)
+	for i, name := range []string{
+		"etcd.lease.grant",
+		"etcd.lease.keep_alive",
+		"etcd.lease.keep_alive_once",
+		"etcd.lease.time_to_live",
+		"etcd.lease.leases",
+		"etcd.lease.revoke",
+	} {
+		assert.Equal(t, name, spans[i].Name())
+		assert.Contains(t, spans[i].Attributes(), semconv.DBOperationKey.String(name[len("etcd."):]))
+		if name != "etcd.lease.leases" {
+			assert.Contains(t, spans[i].Attributes(), otelclientv3.LeaseIDKey.Int64(42))
+		}
+	}
+}
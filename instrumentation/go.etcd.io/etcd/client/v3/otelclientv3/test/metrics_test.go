@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	attr := attribute.String("cluster", "main")
+	kv := otelclientv3.NewKV(&fakeKV{}, otelclientv3.WithMeterProvider(mp), otelclientv3.WithAttributes(attr))
+	failing := otelclientv3.NewKV(&fakeKV{err: errors.New("fail")}, otelclientv3.WithMeterProvider(mp), otelclientv3.WithAttributes(attr))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := kv.Get(ctx, "foo")
+		require.NoError(t, err)
+	}
+	_, err := failing.Put(ctx, "foo", "bar")
+	require.Error(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	duration, err := exp.GetByNameAndAttributes(otelclientv3.ClientOperationDuration, []attribute.KeyValue{
+		etcd,
+		semconv.DBOperationKey.String("get"),
+		attr,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(2), duration.Count)
+
+	// The failed operations are measured too.
+	duration, err = exp.GetByNameAndAttributes(otelclientv3.ClientOperationDuration, []attribute.KeyValue{
+		etcd,
+		semconv.DBOperationKey.String("put"),
+		attr,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+}
+
+func TestWatchMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	w := &fakeWatcher{ch: make(chan clientv3.WatchResponse)}
+	watcher := otelclientv3.NewWatcher(w, otelclientv3.WithMeterProvider(mp))
+
+	ch := watcher.Watch(context.Background(), "foo")
+	go func() {
+		w.ch <- clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: 8}, Events: []*clientv3.Event{{}, {}}}
+		w.ch <- clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: 9}, Events: []*clientv3.Event{{}}}
+		close(w.ch)
+	}()
+	for range ch {
+	}
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	attrs := []attribute.KeyValue{etcd, semconv.DBOperationKey.String("watch")}
+	events, err := exp.GetByNameAndAttributes(otelclientv3.ClientWatchEvents, attrs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), events.Sum.AsInt64())
+
+	// The duration of the watches is not the one of an operation.
+	_, err = exp.GetByNameAndAttributes(otelclientv3.ClientOperationDuration, attrs)
+	assert.Error(t, err)
+}
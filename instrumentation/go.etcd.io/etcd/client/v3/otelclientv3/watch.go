@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelclientv3 // import "go.opentelemetry.io/contrib/instrumentation/go.etcd.io/etcd/client/v3/otelclientv3"
+
+import (
+	"context"
+	"errors"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// watchResponseEvent is the name of the span events of the watch responses.
+const watchResponseEvent = "etcd.watch.response"
+
+// watcher traces the watches of a clientv3.Watcher.
+type watcher struct {
+	base clientv3.Watcher
+	in   *instrumentation
+}
+
+var _ clientv3.Watcher = (*watcher)(nil)
+
+// NewWatcher returns a clientv3.Watcher tracing the watches of base.
+func NewWatcher(base clientv3.Watcher, opts ...Option) clientv3.Watcher {
+	return &watcher{base: base, in: newInstrumentation(newConfig(opts...))}
+}
+
+// Watch traces the watch in a span ended when the returned channel is
+// closed.  The responses are recorded as events of the span.
+func (w *watcher) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	op := w.in.start(ctx, operationWatch, KeyKey.String(key))
+	src := w.base.Watch(op.ctx, key, opts...)
+	dst := make(chan clientv3.WatchResponse)
+	go forward(op, src, dst)
+	return dst
+}
+
+// forward forwards the responses of src to dst until src is closed, then
+// closes dst and ends the span of the watch.  Once the context of the watch
+// is done, the responses are no more forwarded.
+func forward(op *operation, src clientv3.WatchChan, dst chan<- clientv3.WatchResponse) {
+	defer close(dst)
+
+	var (
+		err      error
+		canceled bool
+	)
+	for resp := range src {
+		op.span.AddEvent(watchResponseEvent, trace.WithAttributes(
+			RevisionKey.Int64(resp.Header.Revision),
+			WatchEventsKey.Int(len(resp.Events)),
+		))
+		op.in.metrics.watched(op.ctx, len(resp.Events), op.attrs)
+		if e := resp.Err(); e != nil && !errors.Is(e, context.Canceled) {
+			err = e
+		}
+
+		if canceled {
+			continue
+		}
+		select {
+		case dst <- resp:
+		case <-op.ctx.Done():
+			// Nobody may receive from dst anymore: src is drained to
+			// end the span once the watcher closes it.
+			canceled = true
+		}
+	}
+	// The duration of a watch is not the one of an operation: only the
+	// span is ended.
+	op.endSpan(err)
+}
+
+// RequestProgress traces the request of a progress notification.
+func (w *watcher) RequestProgress(ctx context.Context) error {
+	op := w.in.start(ctx, operationRequestProgress)
+	err := w.base.RequestProgress(op.ctx)
+	op.end(err)
+	return err
+}
+
+func (w *watcher) Close() error {
+	return w.base.Close()
+}
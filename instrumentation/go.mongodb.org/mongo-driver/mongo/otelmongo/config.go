@@ -16,7 +16,10 @@ package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mong
 
 import (
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 const defaultTracerName = "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
@@ -24,16 +27,23 @@ const defaultTracerName = "go.opentelemetry.io/contrib/instrumentation/go.mongod
 // config is used to configure the mongo tracer.
 type config struct {
 	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
 
 	Tracer trace.Tracer
 
 	CommandAttributeDisabled bool
+	CommandSanitizer         CommandSanitizer
 }
 
+// CommandSanitizer returns the db.statement attribute of the spans of the
+// commands.
+type CommandSanitizer func(command bson.Raw) string
+
 // newConfig returns a config with all Options set.
 func newConfig(opts ...Option) config {
 	cfg := config{
-		TracerProvider: otel.GetTracerProvider(),
+		TracerProvider:   otel.GetTracerProvider(),
+		CommandSanitizer: sanitizeCommand,
 	}
 	for _, opt := range opts {
 		opt.apply(&cfg)
@@ -74,3 +84,29 @@ func WithCommandAttributeDisabled(disabled bool) Option {
 		cfg.CommandAttributeDisabled = disabled
 	})
 }
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// The duration of the commands and the number of the failed ones are only
+// recorded if a meter provider is specified, with the operation, collection,
+// and server address of the commands as attributes.  So are the metrics of
+// the connection pools monitored by NewPoolMonitor.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
+// WithCommandSanitizer specifies a sanitizer returning the MongoDB command
+// added as an attribute to Spans, e.g. RedactCommand to redact the values of
+// the commands.  By default, the full command is added as relaxed extended
+// JSON.  The sanitizer is not used if the attribute is disabled with
+// WithCommandAttributeDisabled.
+func WithCommandSanitizer(sanitizer CommandSanitizer) Option {
+	return optionFunc(func(cfg *config) {
+		if sanitizer != nil {
+			cfg.CommandSanitizer = sanitizer
+		}
+	})
+}
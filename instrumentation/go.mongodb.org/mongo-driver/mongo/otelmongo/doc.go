@@ -18,7 +18,14 @@
 // go.mongodb.org/mongo-driver/mongo.
 //
 // `NewMonitor` will return an event.CommandMonitor which is used to trace
-// requests.
+// requests.  The getMore and killCursors commands are linked to the span of
+// the command which returned their cursor, e.g. find or aggregate, and the
+// id of the cursor is recorded as the db.mongodb.cursor_id attribute.
+// Cursors not iterated for 10 minutes, the default idle timeout of the
+// servers, are not linked to anymore.
+//
+// `NewPoolMonitor` will return an event.PoolMonitor which is used to record
+// the metrics of the connection pools.
 //
 // This code was originally based on the following:
 // - https://github.com/DataDog/dd-trace-go/tree/02f0449efa3cb382d499fadc873957385dcb2192/contrib/go.mongodb.org/mongo-driver/mongo
@@ -16,6 +16,7 @@ package otelmongo_test
 
 import (
 	"context"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -50,3 +51,23 @@ func Example() {
 		panic(err)
 	}
 }
+
+func ExampleRedactCommand() {
+	command, err := bson.Marshal(bson.D{
+		{Key: "insert", Value: "inventory"},
+		{Key: "documents", Value: bson.A{
+			bson.D{
+				{Key: "item", Value: "canvas"},
+				{Key: "qty", Value: 100},
+				{Key: "attributes", Value: bson.A{"cotton"}},
+			},
+		}},
+		{Key: "ordered", Value: true},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(otelmongo.RedactCommand(command))
+	// Output: {"insert":"inventory","documents":[{"item":"?","qty":"?","attributes":["?"]}],"ordered":"?"}
+}
@@ -5,6 +5,7 @@ go 1.17
 require (
 	go.mongodb.org/mongo-driver v1.10.0
 	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/metric v0.31.0
 	go.opentelemetry.io/otel/trace v1.9.0
 )
 
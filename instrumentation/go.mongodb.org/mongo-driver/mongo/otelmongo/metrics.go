@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Client command metrics.
+const (
+	ClientOperationDuration = "db.client.operation.duration" // Duration of the commands, milliseconds
+	ClientOperationErrors   = "db.client.operation.errors"   // Number of the failed commands
+)
+
+// commandMetrics holds the instruments of the commands observed by the
+// monitor.  A nil *commandMetrics records nothing.
+type commandMetrics struct {
+	duration syncfloat64.Histogram
+	errors   syncint64.Counter
+}
+
+// newCommandMetrics returns the instruments of the commands, or nil if the
+// metrics are not enabled.
+func newCommandMetrics(mp metric.MeterProvider) *commandMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   commandMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		ClientOperationDuration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the commands"),
+	)
+	handleErr(err)
+
+	m.errors, err = meter.SyncInt64().Counter(
+		ClientOperationErrors,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the failed commands"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// record records the duration of a command, and counts it as an error if
+// failed, with the attributes.
+func (m *commandMetrics) record(ctx context.Context, duration time.Duration, failed bool, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	m.duration.Record(ctx, float64(duration)/float64(time.Millisecond), attrs...)
+	if failed {
+		m.errors.Add(ctx, 1, attrs...)
+	}
+}
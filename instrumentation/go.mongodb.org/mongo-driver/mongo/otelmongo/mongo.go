@@ -17,9 +17,11 @@ package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mong
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -31,15 +33,62 @@ import (
 	"go.mongodb.org/mongo-driver/event"
 )
 
+// CursorIDKey is the id of the cursor returned by a command, e.g. find or
+// aggregate, and iterated by the getMore commands.
+const CursorIDKey = attribute.Key("db.mongodb.cursor_id")
+
 type spanKey struct {
 	ConnectionID string
 	RequestID    int64
 }
 
+// cursorKey identifies a cursor, whose id is unique per server.
+type cursorKey struct {
+	address string
+	id      int64
+}
+
+const (
+	// cursorTTL is how long a cursor not iterated anymore is tracked: the
+	// default time after which servers close idle cursors.  It bounds how
+	// long the cursors timed out on the server, or abandoned without
+	// being closed, are tracked.
+	cursorTTL = 10 * time.Minute
+	// maxCursors is the maximum number of cursors tracked, the cursors
+	// returned when as many are tracked are not linked to.
+	maxCursors = 10000
+)
+
+// cursor is an open cursor.
+type cursor struct {
+	// sc is the span context of the command which returned the cursor.
+	sc trace.SpanContext
+	// used is when the cursor was returned or last iterated.
+	used time.Time
+}
+
+// command is a command started but not finished yet.
+type command struct {
+	span trace.Span
+	// attrs are the attributes of the metrics of the command.
+	attrs []attribute.KeyValue
+	// address is the address of the server of the command.
+	address string
+	// cursorID is the id of the cursor iterated by a getMore command.
+	cursorID int64
+}
+
 type monitor struct {
 	sync.Mutex
-	spans map[spanKey]trace.Span
-	cfg   config
+	commands map[spanKey]command
+	// cursors are the cursors still open, until exhausted, killed, or
+	// not iterated for cursorTTL.
+	cursors map[cursorKey]cursor
+	// swept is when the cursors not iterated for cursorTTL were last
+	// removed.
+	swept   time.Time
+	cfg     config
+	metrics *commandMetrics
 }
 
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
@@ -56,16 +105,59 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		semconv.NetTransportTCP,
 	}
 	if !m.cfg.CommandAttributeDisabled {
-		attrs = append(attrs, semconv.DBStatementKey.String(sanitizeCommand(evt.Command)))
+		attrs = append(attrs, semconv.DBStatementKey.String(m.cfg.CommandSanitizer(evt.Command)))
+	}
+	metricAttrs := []attribute.KeyValue{
+		semconv.DBSystemMongoDB,
+		semconv.DBOperationKey.String(evt.CommandName),
 	}
 	if collection, err := extractCollection(evt); err == nil && collection != "" {
 		spanName = collection + "."
 		attrs = append(attrs, semconv.DBMongoDBCollectionKey.String(collection))
+		metricAttrs = append(metricAttrs, semconv.DBMongoDBCollectionKey.String(collection))
 	}
+	metricAttrs = append(metricAttrs,
+		semconv.NetPeerNameKey.String(hostname),
+		semconv.NetPeerPortKey.Int(port),
+	)
 	spanName += evt.CommandName
+	address := net.JoinHostPort(hostname, strconv.Itoa(port))
+
+	// The getMore and killCursors commands are linked to the span of the
+	// command which returned their cursors.
+	var cursorID int64
+	var links []trace.Link
+	m.Lock()
+	switch evt.CommandName {
+	case "getMore":
+		if id, ok := evt.Command.Lookup("getMore").Int64OK(); ok {
+			cursorID = id
+			attrs = append(attrs, CursorIDKey.Int64(id))
+			if c, ok := m.cursors[cursorKey{address: address, id: id}]; ok {
+				links = append(links, trace.Link{SpanContext: c.sc})
+			}
+		}
+	case "killCursors":
+		cursors, _ := evt.Command.Lookup("cursors").ArrayOK()
+		ids, _ := cursors.Values()
+		for _, v := range ids {
+			id, ok := v.Int64OK()
+			if !ok {
+				continue
+			}
+			key := cursorKey{address: address, id: id}
+			if c, ok := m.cursors[key]; ok {
+				links = append(links, trace.Link{SpanContext: c.sc})
+				delete(m.cursors, key)
+			}
+		}
+	}
+	m.Unlock()
+
 	opts := []trace.SpanStartOption{
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(attrs...),
+		trace.WithLinks(links...),
 	}
 	_, span := m.cfg.Tracer.Start(ctx, spanName, opts...)
 	key := spanKey{
@@ -73,27 +165,33 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		RequestID:    evt.RequestID,
 	}
 	m.Lock()
-	m.spans[key] = span
+	m.commands[key] = command{
+		span:     span,
+		attrs:    metricAttrs,
+		address:  address,
+		cursorID: cursorID,
+	}
 	m.Unlock()
 }
 
 func (m *monitor) Succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
-	m.Finished(&evt.CommandFinishedEvent, nil)
+	m.Finished(ctx, &evt.CommandFinishedEvent, evt.Reply, nil)
 }
 
 func (m *monitor) Failed(ctx context.Context, evt *event.CommandFailedEvent) {
-	m.Finished(&evt.CommandFinishedEvent, fmt.Errorf("%s", evt.Failure))
+	m.Finished(ctx, &evt.CommandFinishedEvent, nil, fmt.Errorf("%s", evt.Failure))
 }
 
-func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
+func (m *monitor) Finished(ctx context.Context, evt *event.CommandFinishedEvent, reply bson.Raw, err error) {
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
 		RequestID:    evt.RequestID,
 	}
 	m.Lock()
-	span, ok := m.spans[key]
+	cmd, ok := m.commands[key]
 	if ok {
-		delete(m.spans, key)
+		delete(m.commands, key)
+		m.trackCursor(cmd, reply)
 	}
 	m.Unlock()
 	if !ok {
@@ -101,24 +199,117 @@ func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
 	}
 
 	if err != nil {
-		span.SetStatus(codes.Error, err.Error())
+		cmd.span.SetStatus(codes.Error, err.Error())
+	}
+
+	cmd.span.End()
+	m.metrics.record(ctx, time.Duration(evt.DurationNanos), err != nil, cmd.attrs)
+}
+
+// trackCursor records the cursor returned in the reply to the command, if
+// any, until it is exhausted.  The cursor iterated by a failed getMore
+// command, whose reply is nil, is no longer tracked.  It must be called
+// with m locked.
+func (m *monitor) trackCursor(cmd command, reply bson.Raw) {
+	now := time.Now()
+	var id int64
+	if reply != nil {
+		id, _ = reply.Lookup("cursor", "id").Int64OK()
+	}
+	if cmd.cursorID != 0 {
+		key := cursorKey{address: cmd.address, id: cmd.cursorID}
+		if id == 0 {
+			delete(m.cursors, key)
+		} else if c, ok := m.cursors[key]; ok {
+			c.used = now
+			m.cursors[key] = c
+		}
+		return
 	}
 
-	span.End()
+	if id == 0 {
+		return
+	}
+	cmd.span.SetAttributes(CursorIDKey.Int64(id))
+	if len(m.cursors) >= maxCursors || now.Sub(m.swept) >= cursorTTL {
+		m.sweepCursors(now)
+	}
+	if len(m.cursors) < maxCursors {
+		m.cursors[cursorKey{address: cmd.address, id: id}] = cursor{sc: cmd.span.SpanContext(), used: now}
+	}
+}
+
+// sweepCursors removes the cursors not iterated for cursorTTL.  It must be
+// called with m locked.
+func (m *monitor) sweepCursors(now time.Time) {
+	for key, c := range m.cursors {
+		if now.Sub(c.used) >= cursorTTL {
+			delete(m.cursors, key)
+		}
+	}
+	m.swept = now
 }
 
-// TODO sanitize values where possible
 // TODO limit maximum size.
 func sanitizeCommand(command bson.Raw) string {
 	b, _ := bson.MarshalExtJSON(command, false, false)
 	return string(b)
 }
 
+// RedactCommand is a CommandSanitizer returning the command as relaxed
+// extended JSON, like the default one, but with its values replaced by "?",
+// e.g. {"insert":"inventory","documents":[{"item":"?","qty":"?"}]}.  The
+// keys and the nesting of the documents and arrays of the command are kept,
+// as well as the value of its first key, the name of the collection the
+// command applies to if any.
+func RedactCommand(command bson.Raw) string {
+	b, _ := bson.MarshalExtJSON(redactDocument(command, true), false, false)
+	return string(b)
+}
+
+// redactDocument returns the document with its values redacted, except the
+// first one if keepFirst.
+func redactDocument(doc bson.Raw, keepFirst bool) bson.D {
+	elems, _ := doc.Elements()
+	d := make(bson.D, 0, len(elems))
+	for i, elem := range elems {
+		var v interface{} = elem.Value()
+		if i > 0 || !keepFirst {
+			v = redactValue(elem.Value())
+		}
+		d = append(d, bson.E{Key: elem.Key(), Value: v})
+	}
+	return d
+}
+
+// redactValue returns "?" for the value, or the redacted document or array.
+func redactValue(v bson.RawValue) interface{} {
+	switch v.Type {
+	case bsontype.EmbeddedDocument:
+		return redactDocument(v.Document(), false)
+	case bsontype.Array:
+		values, _ := v.Array().Values()
+		a := make(bson.A, 0, len(values))
+		for _, v := range values {
+			a = append(a, redactValue(v))
+		}
+		return a
+	default:
+		return "?"
+	}
+}
+
 // extractCollection extracts the collection for the given mongodb command event.
 // For CRUD operations, this is the first key/value string pair in the bson
 // document where key == "<operation>" (e.g. key == "insert").
+// For the getMore operations, it is the value of the "collection" key.
 // For database meta-level operations, such a key may not exist.
 func extractCollection(evt *event.CommandStartedEvent) (string, error) {
+	if evt.CommandName == "getMore" {
+		if collection, ok := evt.Command.Lookup("collection").StringValueOK(); ok {
+			return collection, nil
+		}
+	}
 	elt, err := evt.Command.IndexErr(0)
 	if err != nil {
 		return "", err
@@ -137,8 +328,10 @@ func extractCollection(evt *event.CommandStartedEvent) (string, error) {
 func NewMonitor(opts ...Option) *event.CommandMonitor {
 	cfg := newConfig(opts...)
 	m := &monitor{
-		spans: make(map[spanKey]trace.Span),
-		cfg:   cfg,
+		commands: make(map[spanKey]command),
+		cursors:  make(map[cursorKey]cursor),
+		cfg:      cfg,
+		metrics:  newCommandMetrics(cfg.MeterProvider),
 	}
 	return &event.CommandMonitor{
 		Started:   m.Started,
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Client connection pool metrics.
+const (
+	ClientConnectionsUsage           = "db.client.connections.usage"            // Number of the connections per state
+	ClientConnectionsMax             = "db.client.connections.max"              // Maximum number of the connections
+	ClientConnectionsPendingRequests = "db.client.connections.pending_requests" // Number of the pending checkouts
+	ClientConnectionsTimeouts        = "db.client.connections.timeouts"         // Number of the checkouts timed out
+	ClientConnectionsCreateTime      = "db.client.connections.create_time"      // Duration of the connection establishments, milliseconds
+	ClientConnectionsWaitTime        = "db.client.connections.wait_time"        // Duration of the checkouts, milliseconds
+)
+
+// Attributes of the connection pool metrics.
+const (
+	// PoolNameKey is the address of the server of the pool.
+	PoolNameKey = attribute.Key("pool.name")
+	// ConnectionStateKey is the state of the connections, "idle" or "used",
+	// of the db.client.connections.usage metric.
+	ConnectionStateKey = attribute.Key("state")
+)
+
+var (
+	stateIdle = ConnectionStateKey.String("idle")
+	stateUsed = ConnectionStateKey.String("used")
+)
+
+// poolMetrics holds the instruments of the connection pools.
+type poolMetrics struct {
+	usage           syncint64.UpDownCounter
+	max             syncint64.UpDownCounter
+	pendingRequests syncint64.UpDownCounter
+	timeouts        syncint64.Counter
+	createTime      syncfloat64.Histogram
+	waitTime        syncfloat64.Histogram
+}
+
+// newPoolMetrics returns the instruments of the connection pools, or nil if
+// the metrics are not enabled.
+func newPoolMetrics(mp metric.MeterProvider) *poolMetrics {
+	if mp == nil {
+		return nil
+	}
+	meter := mp.Meter(
+		defaultTracerName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   poolMetrics
+		err error
+	)
+	m.usage, err = meter.SyncInt64().UpDownCounter(
+		ClientConnectionsUsage,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the connections per state"),
+	)
+	handleErr(err)
+
+	m.max, err = meter.SyncInt64().UpDownCounter(
+		ClientConnectionsMax,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Maximum number of the connections"),
+	)
+	handleErr(err)
+
+	m.pendingRequests, err = meter.SyncInt64().UpDownCounter(
+		ClientConnectionsPendingRequests,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the pending checkouts"),
+	)
+	handleErr(err)
+
+	m.timeouts, err = meter.SyncInt64().Counter(
+		ClientConnectionsTimeouts,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of the checkouts timed out"),
+	)
+	handleErr(err)
+
+	m.createTime, err = meter.SyncFloat64().Histogram(
+		ClientConnectionsCreateTime,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the connection establishments"),
+	)
+	handleErr(err)
+
+	m.waitTime, err = meter.SyncFloat64().Histogram(
+		ClientConnectionsWaitTime,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the checkouts"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+type connectionKey struct {
+	Address string
+	ID      uint64
+}
+
+// connection is a connection of a pool, being established if its state is
+// not set yet.
+type connection struct {
+	created time.Time
+	state   attribute.KeyValue
+}
+
+type poolMonitor struct {
+	sync.Mutex
+	metrics *poolMetrics
+	// checkouts are the start times of the pending checkouts of the pools,
+	// oldest first.
+	checkouts   map[string][]time.Time
+	connections map[connectionKey]connection
+	maxSizes    map[string]int64
+}
+
+// NewPoolMonitor creates a new mongodb event PoolMonitor recording the
+// metrics of the connection pools, with the address of their server as
+// the pool.name attribute.  The metrics are only recorded if a meter
+// provider is specified with WithMeterProvider.
+//
+// The pool events do not identify the checkouts, so their duration is
+// measured from the start of the oldest pending checkout of the pool.
+func NewPoolMonitor(opts ...Option) *event.PoolMonitor {
+	cfg := newConfig(opts...)
+	m := &poolMonitor{
+		metrics:     newPoolMetrics(cfg.MeterProvider),
+		checkouts:   make(map[string][]time.Time),
+		connections: make(map[connectionKey]connection),
+		maxSizes:    make(map[string]int64),
+	}
+	return &event.PoolMonitor{
+		Event: m.Event,
+	}
+}
+
+func (m *poolMonitor) Event(evt *event.PoolEvent) {
+	if m.metrics == nil {
+		return
+	}
+	ctx := context.Background()
+	pool := PoolNameKey.String(evt.Address)
+	key := connectionKey{Address: evt.Address, ID: evt.ConnectionID}
+
+	m.Lock()
+	defer m.Unlock()
+
+	switch evt.Type {
+	case event.PoolCreated:
+		if evt.PoolOptions != nil && evt.PoolOptions.MaxPoolSize > 0 {
+			size := int64(evt.PoolOptions.MaxPoolSize)
+			m.maxSizes[evt.Address] = size
+			m.metrics.max.Add(ctx, size, pool)
+		}
+	case event.PoolClosedEvent:
+		if size, ok := m.maxSizes[evt.Address]; ok {
+			delete(m.maxSizes, evt.Address)
+			m.metrics.max.Add(ctx, -size, pool)
+		}
+	case event.ConnectionCreated:
+		m.connections[key] = connection{created: time.Now()}
+	case event.ConnectionReady:
+		if conn, ok := m.connections[key]; ok {
+			m.metrics.createTime.Record(ctx, sinceMillis(conn.created), pool)
+		}
+		m.setState(ctx, key, stateIdle, pool)
+	case event.ConnectionClosed:
+		m.setState(ctx, key, attribute.KeyValue{}, pool)
+		delete(m.connections, key)
+	case event.GetStarted:
+		m.checkouts[evt.Address] = append(m.checkouts[evt.Address], time.Now())
+		m.metrics.pendingRequests.Add(ctx, 1, pool)
+	case event.GetSucceeded:
+		m.checkedOut(ctx, evt.Address, pool)
+		m.setState(ctx, key, stateUsed, pool)
+	case event.GetFailed:
+		m.checkedOut(ctx, evt.Address, pool)
+		if evt.Reason == event.ReasonTimedOut {
+			m.metrics.timeouts.Add(ctx, 1, pool)
+		}
+	case event.ConnectionReturned:
+		m.setState(ctx, key, stateIdle, pool)
+	}
+}
+
+// checkedOut ends the oldest pending checkout of the pool.
+func (m *poolMonitor) checkedOut(ctx context.Context, address string, pool attribute.KeyValue) {
+	checkouts := m.checkouts[address]
+	if len(checkouts) == 0 {
+		return
+	}
+	m.metrics.waitTime.Record(ctx, sinceMillis(checkouts[0]), pool)
+	m.metrics.pendingRequests.Add(ctx, -1, pool)
+	if len(checkouts) == 1 {
+		delete(m.checkouts, address)
+	} else {
+		m.checkouts[address] = checkouts[1:]
+	}
+}
+
+// setState moves the connection to the state, if valid, from its previous
+// state, if any.
+func (m *poolMonitor) setState(ctx context.Context, key connectionKey, state, pool attribute.KeyValue) {
+	conn := m.connections[key]
+	if conn.state == state {
+		return
+	}
+	if conn.state.Valid() {
+		m.metrics.usage.Add(ctx, -1, pool, conn.state)
+	}
+	if state.Valid() {
+		m.metrics.usage.Add(ctx, 1, pool, state)
+	}
+	conn.state = state
+	m.connections[key] = conn
+}
+
+func sinceMillis(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+func TestCursorLinks(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	monitor := otelmongo.NewMonitor(otelmongo.WithTracerProvider(provider))
+
+	ctx := context.Background()
+	const connectionID = "localhost:27017[-1]"
+	var requestID int64
+	run := func(name string, command, reply bson.D) {
+		t.Helper()
+		requestID++
+		cmd, err := bson.Marshal(command)
+		require.NoError(t, err)
+		monitor.Started(ctx, &event.CommandStartedEvent{
+			Command:      cmd,
+			DatabaseName: "db",
+			CommandName:  name,
+			RequestID:    requestID,
+			ConnectionID: connectionID,
+		})
+		rep, err := bson.Marshal(reply)
+		require.NoError(t, err)
+		monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{
+				CommandName:  name,
+				RequestID:    requestID,
+				ConnectionID: connectionID,
+			},
+			Reply: rep,
+		})
+	}
+	cursor := func(id int64) bson.D {
+		return bson.D{{Key: "cursor", Value: bson.D{{Key: "id", Value: id}, {Key: "ns", Value: "db.books"}}}}
+	}
+
+	run("find", bson.D{{Key: "find", Value: "books"}}, cursor(42))
+	run("getMore", bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "books"}}, cursor(42))
+	run("getMore", bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "books"}}, cursor(0))
+	// The exhausted cursor is not linked anymore.
+	run("getMore", bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "books"}}, cursor(0))
+	run("aggregate", bson.D{{Key: "aggregate", Value: "books"}}, cursor(7))
+	run("killCursors", bson.D{{Key: "killCursors", Value: "books"}, {Key: "cursors", Value: bson.A{int64(7)}}}, bson.D{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 6)
+	find, aggregate := spans[0], spans[4]
+	assert.Equal(t, "books.find", find.Name())
+	assert.Contains(t, find.Attributes(), otelmongo.CursorIDKey.Int64(42))
+	assert.Empty(t, find.Links())
+
+	for _, getMore := range spans[1:3] {
+		assert.Equal(t, "books.getMore", getMore.Name())
+		assert.Contains(t, getMore.Attributes(), otelmongo.CursorIDKey.Int64(42))
+		assert.Contains(t, getMore.Attributes(), semconv.DBMongoDBCollectionKey.String("books"))
+		require.Len(t, getMore.Links(), 1)
+		assert.Equal(t, find.SpanContext(), getMore.Links()[0].SpanContext)
+	}
+	assert.Empty(t, spans[3].Links())
+
+	assert.Contains(t, aggregate.Attributes(), otelmongo.CursorIDKey.Int64(7))
+	killCursors := spans[5]
+	require.Len(t, killCursors.Links(), 1)
+	assert.Equal(t, aggregate.SpanContext(), killCursors.Links()[0].SpanContext)
+}
+
+func TestCursorFailedGetMore(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	monitor := otelmongo.NewMonitor(otelmongo.WithTracerProvider(provider))
+
+	ctx := context.Background()
+	const connectionID = "localhost:27017[-1]"
+	start := func(requestID int64, name string, command bson.D) {
+		t.Helper()
+		cmd, err := bson.Marshal(command)
+		require.NoError(t, err)
+		monitor.Started(ctx, &event.CommandStartedEvent{
+			Command:      cmd,
+			DatabaseName: "db",
+			CommandName:  name,
+			RequestID:    requestID,
+			ConnectionID: connectionID,
+		})
+	}
+	finished := func(requestID int64, name string) event.CommandFinishedEvent {
+		return event.CommandFinishedEvent{
+			CommandName:  name,
+			RequestID:    requestID,
+			ConnectionID: connectionID,
+		}
+	}
+	getMore := bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "books"}}
+
+	start(1, "find", bson.D{{Key: "find", Value: "books"}})
+	reply, err := bson.Marshal(bson.D{{Key: "cursor", Value: bson.D{{Key: "id", Value: int64(42)}}}})
+	require.NoError(t, err)
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1, "find"), Reply: reply})
+
+	start(2, "getMore", getMore)
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(2, "getMore"), Failure: "CursorNotFound"})
+
+	// The cursor of the failed getMore is not linked anymore.
+	start(3, "getMore", getMore)
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(3, "getMore"), Failure: "CursorNotFound"})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	require.Len(t, spans[1].Links(), 1)
+	assert.Equal(t, spans[0].SpanContext(), spans[1].Links()[0].SpanContext)
+	assert.Empty(t, spans[2].Links())
+}
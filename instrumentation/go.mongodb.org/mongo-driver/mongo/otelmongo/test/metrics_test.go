@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	addr := "mongodb://localhost:27017/?connect=direct"
+	opts := options.Client()
+	opts.Monitor = otelmongo.NewMonitor(otelmongo.WithMeterProvider(mp))
+	opts.ApplyURI(addr)
+	client, err := mongo.Connect(ctx, opts)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, client.Disconnect(context.Background()))
+	}()
+
+	db := client.Database("test-database")
+	_, err = db.Collection("test-collection").InsertOne(ctx, bson.D{{Key: "test-item", Value: "test-value"}})
+	require.NoError(t, err)
+	err = db.RunCommand(ctx, bson.D{{Key: "unknownCommand", Value: 1}}).Err()
+	require.Error(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	insert := []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.operation", "insert"),
+		attribute.String("db.mongodb.collection", "test-collection"),
+		attribute.String("net.peer.name", "localhost"),
+		attribute.Int("net.peer.port", 27017),
+	}
+	duration, err := exp.GetByNameAndAttributes(otelmongo.ClientOperationDuration, insert)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, duration.AggregationKind)
+	assert.Equal(t, uint64(1), duration.Count)
+	_, err = exp.GetByNameAndAttributes(otelmongo.ClientOperationErrors, insert)
+	assert.Error(t, err, "unexpected errors of the successful command")
+
+	unknown := []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.operation", "unknownCommand"),
+		attribute.String("net.peer.name", "localhost"),
+		attribute.Int("net.peer.port", 27017),
+	}
+	duration, err = exp.GetByNameAndAttributes(otelmongo.ClientOperationDuration, unknown)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), duration.Count)
+	errors, err := exp.GetByNameAndAttributes(otelmongo.ClientOperationErrors, unknown)
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.SumKind, errors.AggregationKind)
+	assert.Equal(t, int64(1), errors.Sum.AsInt64())
+}
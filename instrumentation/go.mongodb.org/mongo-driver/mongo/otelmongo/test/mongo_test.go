@@ -61,6 +61,7 @@ func TestDBCrudOperation(t *testing.T) {
 		title          string
 		operation      func(context.Context, *mongo.Database) (interface{}, error)
 		excludeCommand bool
+		sanitizer      otelmongo.CommandSanitizer
 		validators     []validator
 	}{
 		{
@@ -93,11 +94,30 @@ func TestDBCrudOperation(t *testing.T) {
 				return true
 			}),
 		},
+		{
+			title: "insert",
+			operation: func(ctx context.Context, db *mongo.Database) (interface{}, error) {
+				return db.Collection("test-collection").InsertOne(ctx, bson.D{{Key: "test-item", Value: "test-value"}})
+			},
+			sanitizer: otelmongo.RedactCommand,
+			validators: append(commonValidators, func(s sdktrace.ReadOnlySpan) bool {
+				for _, attr := range s.Attributes() {
+					if attr.Key == "db.statement" {
+						return assert.Contains(t, attr.Value.AsString(), `"insert":"test-collection"`) &&
+							assert.Contains(t, attr.Value.AsString(), `"test-item":"?"`) &&
+							assert.NotContains(t, attr.Value.AsString(), "test-value")
+					}
+				}
+				return false
+			}),
+		},
 	}
 	for _, tc := range tt {
 		title := tc.title
 		if tc.excludeCommand {
 			title = title + "/excludeCommand"
+		} else if tc.sanitizer != nil {
+			title = title + "/redactCommand"
 		} else {
 			title = title + "/includeCommand"
 		}
@@ -115,6 +135,7 @@ func TestDBCrudOperation(t *testing.T) {
 			opts.Monitor = otelmongo.NewMonitor(
 				otelmongo.WithTracerProvider(provider),
 				otelmongo.WithCommandAttributeDisabled(tc.excludeCommand),
+				otelmongo.WithCommandSanitizer(tc.sanitizer),
 			)
 			opts.ApplyURI(addr)
 			client, err := mongo.Connect(ctx, opts)
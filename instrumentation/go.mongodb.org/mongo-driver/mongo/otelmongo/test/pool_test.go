@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestPoolMonitor(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	monitor := otelmongo.NewPoolMonitor(otelmongo.WithMeterProvider(mp))
+
+	const address = "localhost:27017"
+	for _, evt := range []*event.PoolEvent{
+		{Type: event.PoolCreated, Address: address, PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 100}},
+		{Type: event.PoolReady, Address: address},
+		{Type: event.ConnectionCreated, Address: address, ConnectionID: 1},
+		{Type: event.ConnectionReady, Address: address, ConnectionID: 1},
+		{Type: event.ConnectionCreated, Address: address, ConnectionID: 2},
+		{Type: event.ConnectionReady, Address: address, ConnectionID: 2},
+		{Type: event.GetStarted, Address: address},
+		{Type: event.GetStarted, Address: address},
+		{Type: event.GetSucceeded, Address: address, ConnectionID: 1},
+		{Type: event.GetFailed, Address: address, Reason: event.ReasonTimedOut},
+		{Type: event.GetStarted, Address: address},
+		{Type: event.GetStarted, Address: address},
+		{Type: event.GetSucceeded, Address: address, ConnectionID: 2},
+		{Type: event.ConnectionReturned, Address: address, ConnectionID: 2},
+		{Type: event.ConnectionClosed, Address: address, ConnectionID: 2, Reason: event.ReasonStale},
+	} {
+		monitor.Event(evt)
+	}
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	pool := otelmongo.PoolNameKey.String(address)
+	assertSum := func(name string, want int64, attrs ...attribute.KeyValue) {
+		t.Helper()
+		r, err := exp.GetByNameAndAttributes(name, append([]attribute.KeyValue{pool}, attrs...))
+		require.NoError(t, err)
+		assert.Equal(t, aggregation.SumKind, r.AggregationKind)
+		assert.Equal(t, want, r.Sum.AsInt64())
+	}
+	assertSum(otelmongo.ClientConnectionsMax, 100)
+	assertSum(otelmongo.ClientConnectionsUsage, 1, otelmongo.ConnectionStateKey.String("used"))
+	assertSum(otelmongo.ClientConnectionsUsage, 0, otelmongo.ConnectionStateKey.String("idle"))
+	assertSum(otelmongo.ClientConnectionsPendingRequests, 1)
+	assertSum(otelmongo.ClientConnectionsTimeouts, 1)
+
+	createTime, err := exp.GetByNameAndAttributes(otelmongo.ClientConnectionsCreateTime, []attribute.KeyValue{pool})
+	require.NoError(t, err)
+	assert.Equal(t, aggregation.HistogramKind, createTime.AggregationKind)
+	assert.Equal(t, uint64(2), createTime.Count)
+
+	waitTime, err := exp.GetByNameAndAttributes(otelmongo.ClientConnectionsWaitTime, []attribute.KeyValue{pool})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), waitTime.Count)
+
+	monitor.Event(&event.PoolEvent{Type: event.PoolClosedEvent, Address: address})
+	require.NoError(t, exp.Collect(context.Background()))
+	assertSum(otelmongo.ClientConnectionsMax, 0)
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// previousAttemptsKey is the metadata key of the number of the previous
+// attempts of an RPC sent by a client retrying it.
+const previousAttemptsKey = "grpc-previous-rpc-attempts"
+
+type attemptsKey struct{}
+
+// contextWithAttempts returns a copy of ctx holding a counter of the
+// attempts of an RPC, incremented by the client stats handler for every
+// attempt made by the channel for the RPC.
+func contextWithAttempts(ctx context.Context) (context.Context, *int64) {
+	attempts := new(int64)
+	return context.WithValue(ctx, attemptsKey{}, attempts), attempts
+}
+
+// nextAttempt increments the counter of attempts of ctx, and returns the
+// attribute of the number of the previous attempts.  No attribute is
+// returned for the first attempt, or if ctx holds no counter.
+func nextAttempt(ctx context.Context) []attribute.KeyValue {
+	attempts, _ := ctx.Value(attemptsKey{}).(*int64)
+	if attempts == nil {
+		return nil
+	}
+	return resendCountAttr(atomic.AddInt64(attempts, 1) - 1)
+}
+
+// resendCountAttr returns the attribute of the number of the previous
+// attempts of an RPC, if any.
+func resendCountAttr(previous int64) []attribute.KeyValue {
+	if previous <= 0 {
+		return nil
+	}
+	return []attribute.KeyValue{RPCResendCountKey.Int64(previous)}
+}
+
+// previousAttemptsAttr returns the attribute of the number of the previous
+// attempts of an RPC sent by the client in the request metadata md.
+func previousAttemptsAttr(md metadata.MD) []attribute.KeyValue {
+	values := md.Get(previousAttemptsKey)
+	if len(values) == 0 {
+		return nil
+	}
+	previous, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return resendCountAttr(previous)
+}
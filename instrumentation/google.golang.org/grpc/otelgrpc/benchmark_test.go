@@ -112,3 +112,19 @@ func BenchmarkStreamClientInterceptor(b *testing.B) {
 		)),
 	}, nil)
 }
+
+func BenchmarkServerHandler(b *testing.B) {
+	benchmark(b, nil, []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler(
+			otelgrpc.WithTracerProvider(tracerProvider),
+		)),
+	})
+}
+
+func BenchmarkClientHandler(b *testing.B) {
+	benchmark(b, []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(tracerProvider),
+		)),
+	}, nil)
+}
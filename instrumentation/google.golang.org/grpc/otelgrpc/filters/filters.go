@@ -37,7 +37,7 @@ func splitFullMethod(i *otelgrpc.InterceptorInfo) gRPCPath {
 		name = i.UnaryServerInfo.FullMethod
 	case otelgrpc.StreamServer:
 		name = i.StreamServerInfo.FullMethod
-	case otelgrpc.UnaryClient, otelgrpc.StreamClient:
+	case otelgrpc.UnaryClient, otelgrpc.StreamClient, otelgrpc.ClientStatsHandler, otelgrpc.ServerStatsHandler:
 		name = i.Method
 	default:
 		name = i.Method
@@ -119,7 +119,7 @@ func FullMethodName(n string) otelgrpc.Filter {
 	return func(i *otelgrpc.InterceptorInfo) bool {
 		var fm string
 		switch i.Typ {
-		case otelgrpc.UnaryClient, otelgrpc.StreamClient:
+		case otelgrpc.UnaryClient, otelgrpc.StreamClient, otelgrpc.ClientStatsHandler, otelgrpc.ServerStatsHandler:
 			fm = i.Method
 		case otelgrpc.UnaryServer:
 			fm = i.UnaryServerInfo.FullMethod
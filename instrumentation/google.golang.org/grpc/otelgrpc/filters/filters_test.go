@@ -68,6 +68,18 @@ func TestMethodName(t *testing.T) {
 			f:    MethodName("Hello"),
 			want: true,
 		},
+		{
+			name: "client stats handler",
+			i:    &otelgrpc.InterceptorInfo{Method: dummyFullMethodName, Typ: otelgrpc.ClientStatsHandler},
+			f:    MethodName("Hello"),
+			want: true,
+		},
+		{
+			name: "server stats handler",
+			i:    &otelgrpc.InterceptorInfo{Method: dummyFullMethodName, Typ: otelgrpc.ServerStatsHandler},
+			f:    MethodName("Hello"),
+			want: true,
+		},
 		{
 			name: "unary client interceptor fail",
 			i:    &otelgrpc.InterceptorInfo{Method: dummyFullMethodName, Typ: otelgrpc.UnaryClient},
@@ -16,12 +16,16 @@ package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.g
 
 import (
 	"context"
+	"sync"
 
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -40,11 +44,33 @@ type Filter func(*InterceptorInfo) bool
 
 // config is a group of options for this instrumentation.
 type config struct {
-	Filter         Filter
-	Propagators    propagation.TextMapPropagator
-	TracerProvider trace.TracerProvider
+	Filter          Filter
+	PropagationOnly Filter
+	Propagators     propagation.TextMapPropagator
+	TracerProvider  trace.TracerProvider
+	MeterProvider   metric.MeterProvider
+
+	ReceivedEvent     bool
+	SentEvent         bool
+	MessageEventLimit int
+	UncompressedSizes bool
+	CompressedSizes   bool
+
+	RequestMetadata  []capturedMetadata
+	ResponseMetadata []capturedMetadata
+
+	PeerService string
+
+	StatusConverter StatusConverter
+
+	methods attrsCache
+	targets attrsCache
 }
 
+// StatusConverter returns the span status code and description of an RPC
+// that ended with the status s.
+type StatusConverter func(s *status.Status) (codes.Code, string)
+
 // Option applies an option value for a config.
 type Option interface {
 	apply(*config)
@@ -55,6 +81,13 @@ func newConfig(opts []Option) *config {
 	c := &config{
 		Propagators:    otel.GetTextMapPropagator(),
 		TracerProvider: otel.GetTracerProvider(),
+
+		StatusConverter: defaultStatusConverter,
+
+		ReceivedEvent:     true,
+		SentEvent:         true,
+		UncompressedSizes: true,
+		CompressedSizes:   true,
 	}
 	for _, o := range opts {
 		o.apply(c)
@@ -85,10 +118,21 @@ func (o tracerProviderOption) apply(c *config) {
 }
 
 // WithInterceptorFilter returns an Option to use the request filter.
+//
+// Deprecated: Use WithFilter instead.
 func WithInterceptorFilter(f Filter) Option {
 	return interceptorFilterOption{f: f}
 }
 
+// WithFilter returns an Option to use the request filter.  The filter is
+// applied by both the client and the server interceptors and stats handlers,
+// and the RPCs it excludes are neither traced nor measured, for example:
+//
+//	otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))
+func WithFilter(f Filter) Option {
+	return interceptorFilterOption{f: f}
+}
+
 type interceptorFilterOption struct {
 	f Filter
 }
@@ -99,14 +143,263 @@ func (o interceptorFilterOption) apply(c *config) {
 	}
 }
 
+type propagationOnlyOption struct{ f Filter }
+
+func (o propagationOnlyOption) apply(c *config) {
+	if o.f != nil {
+		c.PropagationOnly = o.f
+	}
+}
+
+// WithPropagationOnly returns an Option to only propagate the trace context
+// of the RPCs for which the filter returns true, e.g. the high-throughput
+// RPCs between internal services.  No span is created for these RPCs: the
+// span context of the caller is injected into the outgoing metadata by the
+// client, and the span context extracted from the incoming metadata is the
+// span context of the handler on the server, so that the traces are
+// continued across them.  The RPCs are still measured, for example:
+//
+//	otelgrpc.WithPropagationOnly(filters.ServiceName("internal.Cache"))
+func WithPropagationOnly(f Filter) Option {
+	return propagationOnlyOption{f: f}
+}
+
 // WithTracerProvider returns an Option to use the TracerProvider when
 // creating a Tracer.
 func WithTracerProvider(tp trace.TracerProvider) Option {
 	return tracerProviderOption{tp: tp}
 }
 
+type meterProviderOption struct{ mp metric.MeterProvider }
+
+func (o meterProviderOption) apply(c *config) {
+	if o.mp != nil {
+		c.MeterProvider = o.mp
+	}
+}
+
+// WithMeterProvider returns an Option to use the MeterProvider when
+// creating a Meter.  The duration, the size of the messages, and the number
+// of messages of the RPCs are only recorded if a MeterProvider is specified.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return meterProviderOption{mp: mp}
+}
+
+type event int
+
+// Different types of events that can be recorded, see WithMessageEvents.
+const (
+	ReceivedEvents event = iota
+	SentEvents
+)
+
+type messageEventsOption struct{ events []event }
+
+func (o messageEventsOption) apply(c *config) {
+	c.ReceivedEvent, c.SentEvent = false, false
+	for _, e := range o.events {
+		switch e {
+		case ReceivedEvents:
+			c.ReceivedEvent = true
+		case SentEvents:
+			c.SentEvent = true
+		}
+	}
+}
+
+// WithMessageEvents returns an Option to only record the specified message
+// events (span.AddEvent) on the spans of the RPCs.  By default the events of
+// both the received and the sent messages are recorded, no events are
+// recorded if none are specified.
+//
+// Valid events are:
+//   - ReceivedEvents: Record the number and size of every received message
+//   - SentEvents: Record the number and size of every sent message
+func WithMessageEvents(events ...event) Option {
+	return messageEventsOption{events: events}
+}
+
+type messageEventLimitOption struct{ n int }
+
+func (o messageEventLimitOption) apply(c *config) {
+	c.MessageEventLimit = o.n
+}
+
+// WithMessageEventLimit returns an Option to record at most n events of
+// received messages and n events of sent messages on the span of every RPC,
+// e.g. to limit the size of the spans of long-lived streams.  The events of
+// all messages are recorded if n is not positive, which is the default.
+func WithMessageEventLimit(n int) Option {
+	return messageEventLimitOption{n: n}
+}
+
+type messageSize int
+
+// Different sizes of the messages that can be recorded, see WithMessageSizes.
+const (
+	UncompressedSize messageSize = iota
+	CompressedSize
+)
+
+type messageSizesOption struct{ sizes []messageSize }
+
+func (o messageSizesOption) apply(c *config) {
+	c.UncompressedSizes, c.CompressedSizes = false, false
+	for _, s := range o.sizes {
+		switch s {
+		case UncompressedSize:
+			c.UncompressedSizes = true
+		case CompressedSize:
+			c.CompressedSizes = true
+		}
+	}
+}
+
+// WithMessageSizes returns an Option to only record the specified sizes on
+// the message events.  By default all the sizes known are recorded, no sizes
+// are recorded if none are specified.
+//
+// Valid sizes are:
+//   - UncompressedSize: The RPCMessageUncompressedSizeKey attribute
+//   - CompressedSize: The RPCMessageCompressedSizeKey attribute, only known
+//     by the stats handlers of NewServerHandler and NewClientHandler
+func WithMessageSizes(sizes ...messageSize) Option {
+	return messageSizesOption{sizes: sizes}
+}
+
+type capturedRequestMetadataOption struct{ keys []string }
+
+func (o capturedRequestMetadataOption) apply(c *config) {
+	c.RequestMetadata = newCapturedMetadata(requestMetadataPrefix, o.keys)
+}
+
+// WithCapturedRequestMetadata returns an Option to record the values of the
+// request metadata with the keys, e.g. a tenant or request ID, as the
+// rpc.grpc.request.metadata.<key> span attributes, <key> being the metadata
+// key lowercased with '-' replaced by '_'.  The keys absent from the
+// metadata of an RPC are not recorded, all the values of the others are.
+//
+// The request metadata is the outgoing metadata on the client and the
+// incoming metadata on the server.
+func WithCapturedRequestMetadata(keys []string) Option {
+	return capturedRequestMetadataOption{keys: keys}
+}
+
+type capturedResponseMetadataOption struct{ keys []string }
+
+func (o capturedResponseMetadataOption) apply(c *config) {
+	c.ResponseMetadata = newCapturedMetadata(responseMetadataPrefix, o.keys)
+}
+
+// WithCapturedResponseMetadata returns an Option to record the values of the
+// response header metadata with the keys as the
+// rpc.grpc.response.metadata.<key> span attributes, <key> being the metadata
+// key lowercased with '-' replaced by '_'.  The keys absent from the
+// metadata of an RPC are not recorded, all the values of the others are.
+//
+// The response header metadata is the header received on the client and the
+// header sent with grpc.SetHeader, grpc.SendHeader, or the methods of the
+// grpc.ServerStream on the server.  The trailer metadata is not recorded.
+func WithCapturedResponseMetadata(keys []string) Option {
+	return capturedResponseMetadataOption{keys: keys}
+}
+
+type peerServiceOption struct{ name string }
+
+func (o peerServiceOption) apply(c *config) {
+	c.PeerService = o.name
+}
+
+// WithPeerService returns an Option to set the peer.service attribute to
+// name on the client spans, e.g. to identify the downstream service called
+// in the traces when it is not instrumented.  The option has no effect on
+// the server.
+func WithPeerService(name string) Option {
+	return peerServiceOption{name: name}
+}
+
+type statusConverterOption struct{ f StatusConverter }
+
+func (o statusConverterOption) apply(c *config) {
+	if o.f != nil {
+		c.StatusConverter = o.f
+	}
+}
+
+// WithStatusConverter returns an Option to use the StatusConverter to set
+// the status of the spans of the RPCs that end with an error, instead of
+// the Error status with the message of the gRPC status as description.  For
+// example, the spans of a server can be left unset for expected codes:
+//
+//	otelgrpc.WithStatusConverter(func(s *status.Status) (codes.Code, string) {
+//		switch s.Code() {
+//		case grpc_codes.NotFound, grpc_codes.Canceled:
+//			return codes.Unset, ""
+//		}
+//		return codes.Error, s.Message()
+//	})
+func WithStatusConverter(f StatusConverter) Option {
+	return statusConverterOption{f: f}
+}
+
+// defaultStatusConverter sets the Error status for all the RPCs that end
+// with an error.
+func defaultStatusConverter(s *status.Status) (codes.Code, string) {
+	return codes.Error, s.Message()
+}
+
+// recordsMessageEvent returns whether the event of the message with the id
+// is recorded.
+func (c *config) recordsMessageEvent(m messageType, id int64) bool {
+	switch m {
+	case messageReceived:
+		if !c.ReceivedEvent {
+			return false
+		}
+	case messageSent:
+		if !c.SentEvent {
+			return false
+		}
+	}
+	return c.MessageEventLimit <= 0 || id <= int64(c.MessageEventLimit)
+}
+
 type metadataSupplier struct {
-	metadata *metadata.MD
+	metadata metadata.MD
+}
+
+// supplierPool holds the carriers used by the interceptors and stats
+// handlers, which are not retained by the propagators.
+var supplierPool = sync.Pool{
+	New: func() interface{} { return &metadataSupplier{} },
+}
+
+// tracer returns the Tracer of the instrumentation from the TracerProvider.
+func (c *config) tracer() trace.Tracer {
+	return c.TracerProvider.Tracer(
+		instrumentationName,
+		trace.WithInstrumentationVersion(SemVersion()),
+	)
+}
+
+// inject injects the cross-cutting concerns of ctx into md.
+func (c *config) inject(ctx context.Context, md metadata.MD) {
+	s := supplierPool.Get().(*metadataSupplier)
+	s.metadata = md
+	c.Propagators.Inject(ctx, s)
+	s.metadata = nil
+	supplierPool.Put(s)
+}
+
+// extract returns a copy of ctx with the cross-cutting concerns extracted
+// from md.
+func (c *config) extract(ctx context.Context, md metadata.MD) context.Context {
+	s := supplierPool.Get().(*metadataSupplier)
+	s.metadata = md
+	ctx = c.Propagators.Extract(ctx, s)
+	s.metadata = nil
+	supplierPool.Put(s)
+	return ctx
 }
 
 // assert that metadataSupplier implements the TextMapCarrier interface.
@@ -125,8 +418,8 @@ func (s *metadataSupplier) Set(key string, value string) {
 }
 
 func (s *metadataSupplier) Keys() []string {
-	out := make([]string, 0, len(*s.metadata))
-	for key := range *s.metadata {
+	out := make([]string, 0, len(s.metadata))
+	for key := range s.metadata {
 		out = append(out, key)
 	}
 	return out
@@ -138,7 +431,7 @@ func (s *metadataSupplier) Keys() []string {
 func Inject(ctx context.Context, md *metadata.MD, opts ...Option) {
 	c := newConfig(opts)
 	c.Propagators.Inject(ctx, &metadataSupplier{
-		metadata: md,
+		metadata: *md,
 	})
 }
 
@@ -148,7 +441,7 @@ func Inject(ctx context.Context, md *metadata.MD, opts ...Option) {
 func Extract(ctx context.Context, md *metadata.MD, opts ...Option) (baggage.Baggage, trace.SpanContext) {
 	c := newConfig(opts)
 	ctx = c.Propagators.Extract(ctx, &metadataSupplier{
-		metadata: md,
+		metadata: *md,
 	})
 
 	return baggage.FromContext(ctx), trace.SpanContextFromContext(ctx)
@@ -20,6 +20,12 @@ import (
 	"context"
 	"io"
 	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto" // nolint:staticcheck
 
@@ -27,12 +33,11 @@ import (
 	grpc_codes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/internal"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/baggage"
-	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -40,21 +45,21 @@ import (
 type messageType attribute.KeyValue
 
 // Event adds an event of the messageType to the span associated with the
-// passed context with id and size (if message is a proto message).
-func (m messageType) Event(ctx context.Context, id int, message interface{}) {
+// passed context with id and size (if message is a proto message), unless
+// the event is not recorded with the configuration c.
+func (m messageType) Event(ctx context.Context, c *config, id int, message interface{}) {
 	span := trace.SpanFromContext(ctx)
-	if p, ok := message.(proto.Message); ok {
-		span.AddEvent("message", trace.WithAttributes(
-			attribute.KeyValue(m),
-			RPCMessageIDKey.Int(id),
-			RPCMessageUncompressedSizeKey.Int(proto.Size(p)),
-		))
-	} else {
-		span.AddEvent("message", trace.WithAttributes(
-			attribute.KeyValue(m),
-			RPCMessageIDKey.Int(id),
-		))
+	if !span.IsRecording() || !c.recordsMessageEvent(m, int64(id)) {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.KeyValue(m),
+		RPCMessageIDKey.Int(id),
+	}
+	if p, ok := message.(proto.Message); ok && c.UncompressedSizes {
+		attrs = append(attrs, RPCMessageUncompressedSizeKey.Int(proto.Size(p)))
 	}
+	span.AddEvent("message", trace.WithAttributes(attrs...))
 }
 
 var (
@@ -65,6 +70,9 @@ var (
 // UnaryClientInterceptor returns a grpc.UnaryClientInterceptor suitable
 // for use in a grpc.Dial call.
 func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+	metrics := cfg.newRPCMetrics(clientMetricNames)
 	return func(
 		ctx context.Context,
 		method string,
@@ -73,49 +81,67 @@ func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 		invoker grpc.UnaryInvoker,
 		callOpts ...grpc.CallOption,
 	) error {
-		cfg := newConfig(opts)
-		i := &InterceptorInfo{
-			Method: method,
-			Typ:    UnaryClient,
-		}
-		if cfg.Filter != nil && !cfg.Filter(i) {
+		if cfg.Filter != nil && !cfg.Filter(&InterceptorInfo{Method: method, Typ: UnaryClient}) {
 			return invoker(ctx, method, req, reply, cc, callOpts...)
 		}
 
 		requestMetadata, _ := metadata.FromOutgoingContext(ctx)
-		metadataCopy := requestMetadata.Copy()
-
-		tracer := cfg.TracerProvider.Tracer(
-			instrumentationName,
-			trace.WithInstrumentationVersion(SemVersion()),
-		)
 
-		name, attr := spanInfo(method, cc.Target())
+		info := cfg.methodInfo(method)
 		var span trace.Span
-		ctx, span = tracer.Start(
-			ctx,
-			name,
-			trace.WithSpanKind(trace.SpanKindClient),
-			trace.WithAttributes(attr...),
-		)
+		if cfg.PropagationOnly != nil && cfg.PropagationOnly(&InterceptorInfo{Method: method, Typ: UnaryClient}) {
+			ctx, span = propagationOnly(ctx)
+		} else {
+			ctx, span = tracer.Start(
+				ctx,
+				info.name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(spanAttrs(
+					info.attrs,
+					cfg.targetAttrs(cc.Target()),
+					metadataAttributes(cfg.RequestMetadata, requestMetadata),
+				)...),
+			)
+		}
 		defer span.End()
 
-		Inject(ctx, &metadataCopy, opts...)
+		metadataCopy := requestMetadata.Copy()
+		cfg.inject(ctx, metadataCopy)
 		ctx = metadata.NewOutgoingContext(ctx, metadataCopy)
+		ctx, attempts := contextWithAttempts(ctx)
+
+		var mAttrs []attribute.KeyValue
+		if metrics != nil {
+			mAttrs = info.attrs
+		}
+		start := time.Now()
+
+		var header metadata.MD
+		if len(cfg.ResponseMetadata) > 0 {
+			callOpts = append(callOpts[:len(callOpts):len(callOpts)], grpc.Header(&header))
+		}
 
-		messageSent.Event(ctx, 1, req)
+		messageSent.Event(ctx, cfg, 1, req)
+		metrics.recordMessage(ctx, true, req, mAttrs)
 
 		err := invoker(ctx, method, req, reply, cc, callOpts...)
 
-		messageReceived.Event(ctx, 1, reply)
+		messageReceived.Event(ctx, cfg, 1, reply)
+		span.SetAttributes(metadataAttributes(cfg.ResponseMetadata, header)...)
+		span.SetAttributes(resendCountAttr(atomic.LoadInt64(attempts) - 1)...)
 
+		var responses int64
+		code := grpc_codes.OK
 		if err != nil {
 			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
-			span.SetAttributes(statusCodeAttr(s.Code()))
+			span.SetStatus(cfg.StatusConverter(s))
+			code = s.Code()
 		} else {
-			span.SetAttributes(statusCodeAttr(grpc_codes.OK))
+			metrics.recordMessage(ctx, false, reply, mAttrs)
+			responses = 1
 		}
+		span.SetAttributes(statusCodeAttr(code))
+		metrics.recordEnd(ctx, time.Since(start), 1, responses, code, mAttrs)
 
 		return err
 	}
@@ -138,6 +164,7 @@ const (
 type clientStream struct {
 	grpc.ClientStream
 
+	cfg        *config
 	desc       *grpc.StreamDesc
 	events     chan streamEvent
 	eventsDone chan struct{}
@@ -145,6 +172,11 @@ type clientStream struct {
 
 	receivedMessageID int
 	sentMessageID     int
+
+	metrics     *rpcMetrics
+	metricAttrs []attribute.KeyValue
+	requests    int64
+	responses   int64
 }
 
 var _ = proto.Marshal
@@ -152,6 +184,11 @@ var _ = proto.Marshal
 func (w *clientStream) RecvMsg(m interface{}) error {
 	err := w.ClientStream.RecvMsg(m)
 
+	if err == nil {
+		atomic.AddInt64(&w.responses, 1)
+		w.metrics.recordMessage(w.Context(), false, m, w.metricAttrs)
+	}
+
 	if err == nil && !w.desc.ServerStreams {
 		w.sendStreamEvent(receiveEndEvent, nil)
 	} else if err == io.EOF {
@@ -160,7 +197,7 @@ func (w *clientStream) RecvMsg(m interface{}) error {
 		w.sendStreamEvent(errorEvent, err)
 	} else {
 		w.receivedMessageID++
-		messageReceived.Event(w.Context(), w.receivedMessageID, m)
+		messageReceived.Event(w.Context(), w.cfg, w.receivedMessageID, m)
 	}
 
 	return err
@@ -170,10 +207,13 @@ func (w *clientStream) SendMsg(m interface{}) error {
 	err := w.ClientStream.SendMsg(m)
 
 	w.sentMessageID++
-	messageSent.Event(w.Context(), w.sentMessageID, m)
+	messageSent.Event(w.Context(), w.cfg, w.sentMessageID, m)
 
 	if err != nil {
 		w.sendStreamEvent(errorEvent, err)
+	} else {
+		atomic.AddInt64(&w.requests, 1)
+		w.metrics.recordMessage(w.Context(), true, m, w.metricAttrs)
 	}
 
 	return err
@@ -199,7 +239,7 @@ func (w *clientStream) CloseSend() error {
 	return err
 }
 
-func wrapClientStream(ctx context.Context, s grpc.ClientStream, desc *grpc.StreamDesc) *clientStream {
+func wrapClientStream(ctx context.Context, s grpc.ClientStream, cfg *config, desc *grpc.StreamDesc, metrics *rpcMetrics, metricAttrs []attribute.KeyValue) *clientStream {
 	events := make(chan streamEvent)
 	eventsDone := make(chan struct{})
 	finished := make(chan error)
@@ -227,10 +267,13 @@ func wrapClientStream(ctx context.Context, s grpc.ClientStream, desc *grpc.Strea
 
 	return &clientStream{
 		ClientStream: s,
+		cfg:          cfg,
 		desc:         desc,
 		events:       events,
 		eventsDone:   eventsDone,
 		finished:     finished,
+		metrics:      metrics,
+		metricAttrs:  metricAttrs,
 	}
 }
 
@@ -244,6 +287,9 @@ func (w *clientStream) sendStreamEvent(eventType streamEventType, err error) {
 // StreamClientInterceptor returns a grpc.StreamClientInterceptor suitable
 // for use in a grpc.Dial call.
 func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+	metrics := cfg.newRPCMetrics(clientMetricNames)
 	return func(
 		ctx context.Context,
 		desc *grpc.StreamDesc,
@@ -252,56 +298,71 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 		streamer grpc.Streamer,
 		callOpts ...grpc.CallOption,
 	) (grpc.ClientStream, error) {
-		cfg := newConfig(opts)
-		i := &InterceptorInfo{
-			Method: method,
-			Typ:    StreamClient,
-		}
-		if cfg.Filter != nil && !cfg.Filter(i) {
+		if cfg.Filter != nil && !cfg.Filter(&InterceptorInfo{Method: method, Typ: StreamClient}) {
 			return streamer(ctx, desc, cc, method, callOpts...)
 		}
 
 		requestMetadata, _ := metadata.FromOutgoingContext(ctx)
-		metadataCopy := requestMetadata.Copy()
-
-		tracer := cfg.TracerProvider.Tracer(
-			instrumentationName,
-			trace.WithInstrumentationVersion(SemVersion()),
-		)
 
-		name, attr := spanInfo(method, cc.Target())
+		info := cfg.methodInfo(method)
 		var span trace.Span
-		ctx, span = tracer.Start(
-			ctx,
-			name,
-			trace.WithSpanKind(trace.SpanKindClient),
-			trace.WithAttributes(attr...),
-		)
-
-		Inject(ctx, &metadataCopy, opts...)
+		if cfg.PropagationOnly != nil && cfg.PropagationOnly(&InterceptorInfo{Method: method, Typ: StreamClient}) {
+			ctx, span = propagationOnly(ctx)
+		} else {
+			ctx, span = tracer.Start(
+				ctx,
+				info.name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(spanAttrs(
+					info.attrs,
+					cfg.targetAttrs(cc.Target()),
+					metadataAttributes(cfg.RequestMetadata, requestMetadata),
+				)...),
+			)
+		}
+
+		metadataCopy := requestMetadata.Copy()
+		cfg.inject(ctx, metadataCopy)
 		ctx = metadata.NewOutgoingContext(ctx, metadataCopy)
+		ctx, attempts := contextWithAttempts(ctx)
+
+		var mAttrs []attribute.KeyValue
+		if metrics != nil {
+			mAttrs = info.attrs
+		}
+		start := time.Now()
 
 		s, err := streamer(ctx, desc, cc, method, callOpts...)
 		if err != nil {
 			grpcStatus, _ := status.FromError(err)
-			span.SetStatus(codes.Error, grpcStatus.Message())
+			span.SetStatus(cfg.StatusConverter(grpcStatus))
 			span.SetAttributes(statusCodeAttr(grpcStatus.Code()))
+			span.SetAttributes(resendCountAttr(atomic.LoadInt64(attempts) - 1)...)
+			metrics.recordEnd(ctx, time.Since(start), 0, 0, grpcStatus.Code(), mAttrs)
 			span.End()
 			return s, err
 		}
-		stream := wrapClientStream(ctx, s, desc)
+		stream := wrapClientStream(ctx, s, cfg, desc, metrics, mAttrs)
 
 		go func() {
 			err := <-stream.finished
 
+			if len(cfg.ResponseMetadata) > 0 {
+				if header, err := stream.ClientStream.Header(); err == nil {
+					span.SetAttributes(metadataAttributes(cfg.ResponseMetadata, header)...)
+				}
+			}
+			span.SetAttributes(resendCountAttr(atomic.LoadInt64(attempts) - 1)...)
+
+			code := grpc_codes.OK
 			if err != nil {
 				s, _ := status.FromError(err)
-				span.SetStatus(codes.Error, s.Message())
-				span.SetAttributes(statusCodeAttr(s.Code()))
-			} else {
-				span.SetAttributes(statusCodeAttr(grpc_codes.OK))
+				span.SetStatus(cfg.StatusConverter(s))
+				code = s.Code()
 			}
+			span.SetAttributes(statusCodeAttr(code))
 
+			metrics.recordEnd(ctx, time.Since(start), atomic.LoadInt64(&stream.requests), atomic.LoadInt64(&stream.responses), code, mAttrs)
 			span.End()
 		}()
 
@@ -312,53 +373,69 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 // UnaryServerInterceptor returns a grpc.UnaryServerInterceptor suitable
 // for use in a grpc.NewServer call.
 func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+	metrics := cfg.newRPCMetrics(serverMetricNames)
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-		cfg := newConfig(opts)
-		i := &InterceptorInfo{
-			UnaryServerInfo: info,
-			Typ:             UnaryServer,
-		}
-		if cfg.Filter != nil && !cfg.Filter(i) {
+		if cfg.Filter != nil && !cfg.Filter(&InterceptorInfo{UnaryServerInfo: info, Typ: UnaryServer}) {
 			return handler(ctx, req)
 		}
 
 		requestMetadata, _ := metadata.FromIncomingContext(ctx)
-		metadataCopy := requestMetadata.Copy()
+		ctx = cfg.extract(ctx, requestMetadata)
 
-		bags, spanCtx := Extract(ctx, &metadataCopy, opts...)
-		ctx = baggage.ContextWithBaggage(ctx, bags)
-
-		tracer := cfg.TracerProvider.Tracer(
-			instrumentationName,
-			trace.WithInstrumentationVersion(SemVersion()),
-		)
-
-		name, attr := spanInfo(info.FullMethod, peerFromCtx(ctx))
-		ctx, span := tracer.Start(
-			trace.ContextWithRemoteSpanContext(ctx, spanCtx),
-			name,
-			trace.WithSpanKind(trace.SpanKindServer),
-			trace.WithAttributes(attr...),
-		)
+		mInfo := cfg.methodInfo(info.FullMethod)
+		var span trace.Span
+		if cfg.PropagationOnly != nil && cfg.PropagationOnly(&InterceptorInfo{UnaryServerInfo: info, Typ: UnaryServer}) {
+			ctx, span = propagationOnly(ctx)
+		} else {
+			ctx, span = tracer.Start(
+				trace.ContextWithRemoteSpanContext(ctx, trace.SpanContextFromContext(ctx)),
+				mInfo.name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(spanAttrs(
+					mInfo.attrs,
+					peerAttr(peerFromCtx(ctx)),
+					metadataAttributes(cfg.RequestMetadata, requestMetadata),
+					previousAttemptsAttr(requestMetadata),
+				)...),
+			)
+		}
 		defer span.End()
 
-		messageReceived.Event(ctx, 1, req)
+		var mAttrs []attribute.KeyValue
+		if metrics != nil {
+			mAttrs = mInfo.attrs
+		}
+		start := time.Now()
+
+		ctx, header := cfg.captureResponseHeader(ctx)
+
+		messageReceived.Event(ctx, cfg, 1, req)
+		metrics.recordMessage(ctx, true, req, mAttrs)
 
 		resp, err := handler(ctx, req)
+		span.SetAttributes(metadataAttributes(cfg.ResponseMetadata, header.metadata())...)
+
+		var responses int64
+		code := grpc_codes.OK
 		if err != nil {
 			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
-			span.SetAttributes(statusCodeAttr(s.Code()))
-			messageSent.Event(ctx, 1, s.Proto())
+			span.SetStatus(cfg.StatusConverter(s))
+			code = s.Code()
+			messageSent.Event(ctx, cfg, 1, s.Proto())
 		} else {
-			span.SetAttributes(statusCodeAttr(grpc_codes.OK))
-			messageSent.Event(ctx, 1, resp)
+			messageSent.Event(ctx, cfg, 1, resp)
+			metrics.recordMessage(ctx, false, resp, mAttrs)
+			responses = 1
 		}
+		span.SetAttributes(statusCodeAttr(code))
+		metrics.recordEnd(ctx, time.Since(start), 1, responses, code, mAttrs)
 
 		return resp, err
 	}
@@ -369,21 +446,44 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 type serverStream struct {
 	grpc.ServerStream
 	ctx context.Context
+	cfg *config
 
 	receivedMessageID int
 	sentMessageID     int
+
+	metrics     *rpcMetrics
+	metricAttrs []attribute.KeyValue
+
+	header *responseHeader
 }
 
 func (w *serverStream) Context() context.Context {
 	return w.ctx
 }
 
+func (w *serverStream) SetHeader(md metadata.MD) error {
+	err := w.ServerStream.SetHeader(md)
+	if err == nil {
+		w.header.add(md)
+	}
+	return err
+}
+
+func (w *serverStream) SendHeader(md metadata.MD) error {
+	err := w.ServerStream.SendHeader(md)
+	if err == nil {
+		w.header.add(md)
+	}
+	return err
+}
+
 func (w *serverStream) RecvMsg(m interface{}) error {
 	err := w.ServerStream.RecvMsg(m)
 
 	if err == nil {
 		w.receivedMessageID++
-		messageReceived.Event(w.Context(), w.receivedMessageID, m)
+		messageReceived.Event(w.Context(), w.cfg, w.receivedMessageID, m)
+		w.metrics.recordMessage(w.Context(), true, m, w.metricAttrs)
 	}
 
 	return err
@@ -393,21 +493,31 @@ func (w *serverStream) SendMsg(m interface{}) error {
 	err := w.ServerStream.SendMsg(m)
 
 	w.sentMessageID++
-	messageSent.Event(w.Context(), w.sentMessageID, m)
+	messageSent.Event(w.Context(), w.cfg, w.sentMessageID, m)
+
+	if err == nil {
+		w.metrics.recordMessage(w.Context(), false, m, w.metricAttrs)
+	}
 
 	return err
 }
 
-func wrapServerStream(ctx context.Context, ss grpc.ServerStream) *serverStream {
+func wrapServerStream(ctx context.Context, ss grpc.ServerStream, cfg *config, metrics *rpcMetrics, metricAttrs []attribute.KeyValue) *serverStream {
 	return &serverStream{
 		ServerStream: ss,
 		ctx:          ctx,
+		cfg:          cfg,
+		metrics:      metrics,
+		metricAttrs:  metricAttrs,
 	}
 }
 
 // StreamServerInterceptor returns a grpc.StreamServerInterceptor suitable
 // for use in a grpc.NewServer call.
 func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+	tracer := cfg.tracer()
+	metrics := cfg.newRPCMetrics(serverMetricNames)
 	return func(
 		srv interface{},
 		ss grpc.ServerStream,
@@ -415,57 +525,129 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 		handler grpc.StreamHandler,
 	) error {
 		ctx := ss.Context()
-		cfg := newConfig(opts)
-		i := &InterceptorInfo{
-			StreamServerInfo: info,
-			Typ:              StreamServer,
-		}
-		if cfg.Filter != nil && !cfg.Filter(i) {
-			return handler(srv, wrapServerStream(ctx, ss))
+		if cfg.Filter != nil && !cfg.Filter(&InterceptorInfo{StreamServerInfo: info, Typ: StreamServer}) {
+			return handler(srv, wrapServerStream(ctx, ss, cfg, nil, nil))
 		}
 
 		requestMetadata, _ := metadata.FromIncomingContext(ctx)
-		metadataCopy := requestMetadata.Copy()
+		ctx = cfg.extract(ctx, requestMetadata)
 
-		bags, spanCtx := Extract(ctx, &metadataCopy, opts...)
-		ctx = baggage.ContextWithBaggage(ctx, bags)
-
-		tracer := cfg.TracerProvider.Tracer(
-			instrumentationName,
-			trace.WithInstrumentationVersion(SemVersion()),
-		)
-
-		name, attr := spanInfo(info.FullMethod, peerFromCtx(ctx))
-		ctx, span := tracer.Start(
-			trace.ContextWithRemoteSpanContext(ctx, spanCtx),
-			name,
-			trace.WithSpanKind(trace.SpanKindServer),
-			trace.WithAttributes(attr...),
-		)
+		mInfo := cfg.methodInfo(info.FullMethod)
+		var span trace.Span
+		if cfg.PropagationOnly != nil && cfg.PropagationOnly(&InterceptorInfo{StreamServerInfo: info, Typ: StreamServer}) {
+			ctx, span = propagationOnly(ctx)
+		} else {
+			ctx, span = tracer.Start(
+				trace.ContextWithRemoteSpanContext(ctx, trace.SpanContextFromContext(ctx)),
+				mInfo.name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(spanAttrs(
+					mInfo.attrs,
+					peerAttr(peerFromCtx(ctx)),
+					metadataAttributes(cfg.RequestMetadata, requestMetadata),
+					previousAttemptsAttr(requestMetadata),
+				)...),
+			)
+		}
 		defer span.End()
 
-		err := handler(srv, wrapServerStream(ctx, ss))
+		var mAttrs []attribute.KeyValue
+		if metrics != nil {
+			mAttrs = mInfo.attrs
+		}
+		start := time.Now()
+
+		ctx, header := cfg.captureResponseHeader(ctx)
+		stream := wrapServerStream(ctx, ss, cfg, metrics, mAttrs)
+		stream.header = header
+		err := handler(srv, stream)
+		span.SetAttributes(metadataAttributes(cfg.ResponseMetadata, header.metadata())...)
 
+		code := grpc_codes.OK
 		if err != nil {
 			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
-			span.SetAttributes(statusCodeAttr(s.Code()))
-		} else {
-			span.SetAttributes(statusCodeAttr(grpc_codes.OK))
+			span.SetStatus(cfg.StatusConverter(s))
+			code = s.Code()
 		}
+		span.SetAttributes(statusCodeAttr(code))
+		metrics.recordEnd(ctx, time.Since(start), int64(stream.receivedMessageID), int64(stream.sentMessageID), code, mAttrs)
 
 		return err
 	}
 }
 
-// spanInfo returns a span name and all appropriate attributes from the gRPC
-// method and peer address.
-func spanInfo(fullMethod, peerAddress string) (string, []attribute.KeyValue) {
-	attrs := []attribute.KeyValue{RPCSystemGRPC}
-	name, mAttrs := internal.ParseFullMethod(fullMethod)
-	attrs = append(attrs, mAttrs...)
-	attrs = append(attrs, peerAttr(peerAddress)...)
-	return name, attrs
+// maxCachedAttrs is the maximum number of entries of an attrsCache.
+const maxCachedAttrs = 1024
+
+// attrsCache caches values computed from the strings shared by many RPCs,
+// e.g. their gRPC method or the target of the client.  The number of
+// entries is bounded as servers can receive RPCs of arbitrary methods.
+type attrsCache struct {
+	m    sync.Map
+	size int64
+}
+
+// load returns the value cached for key, or computes it with fn and caches
+// it.
+func (c *attrsCache) load(key string, fn func(string) interface{}) interface{} {
+	if v, ok := c.m.Load(key); ok {
+		return v
+	}
+	v := fn(key)
+	if atomic.AddInt64(&c.size, 1) <= maxCachedAttrs {
+		c.m.Store(key, v)
+	}
+	return v
+}
+
+// methodInfo is the span name and attributes of the RPCs of a gRPC method.
+type methodInfo struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// methodInfo returns the span name and all appropriate attributes from the
+// gRPC method.  The attributes are shared, they must be copied before being
+// modified.
+func (c *config) methodInfo(fullMethod string) methodInfo {
+	return c.methods.load(fullMethod, func(fullMethod string) interface{} {
+		name, mAttrs := internal.ParseFullMethod(fullMethod)
+		attrs := append([]attribute.KeyValue{RPCSystemGRPC}, mAttrs...)
+		return methodInfo{name: name, attrs: attrs[:len(attrs):len(attrs)]}
+	}).(methodInfo)
+}
+
+// targetAttrs returns the attributes of the client spans identifying the
+// server at the target of the client, and the peer service.  The attributes
+// are shared, they must be copied before being modified.
+func (c *config) targetAttrs(target string) []attribute.KeyValue {
+	return c.targets.load(target, func(target string) interface{} {
+		attrs := append(peerAttr(target), c.clientAttrs(targetEndpoint(target))...)
+		return attrs[:len(attrs):len(attrs)]
+	}).([]attribute.KeyValue)
+}
+
+// spanAttrs returns the attributes of a span, the shared attributes of the
+// method followed by the other attributes.
+func spanAttrs(method []attribute.KeyValue, other ...[]attribute.KeyValue) []attribute.KeyValue {
+	n := len(method)
+	for _, attrs := range other {
+		n += len(attrs)
+	}
+	attrs := make([]attribute.KeyValue, 0, n)
+	attrs = append(attrs, method...)
+	for _, o := range other {
+		attrs = append(attrs, o...)
+	}
+	return attrs
+}
+
+// propagationOnly returns a copy of ctx with a non-recording span carrying
+// the span context of ctx, and the span, for the RPCs that only propagate
+// the trace context.
+func propagationOnly(ctx context.Context) (context.Context, trace.Span) {
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContextFromContext(ctx))
+	return ctx, trace.SpanFromContext(ctx)
 }
 
 // peerAttr returns attributes about the peer address.
@@ -485,6 +667,53 @@ func peerAttr(addr string) []attribute.KeyValue {
 	}
 }
 
+// clientAttrs returns the attributes of the client spans identifying the
+// server at the endpoint, a host and an optional port, and the peer
+// service.
+func (c *config) clientAttrs(endpoint string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if c.PeerService != "" {
+		attrs = append(attrs, semconv.PeerServiceKey.String(c.PeerService))
+	}
+	return append(attrs, serverAddrAttrs(endpoint)...)
+}
+
+// serverAddrAttrs returns the server.address and server.port attributes of
+// the endpoint, a host and an optional port.
+func serverAddrAttrs(endpoint string) []attribute.KeyValue {
+	if endpoint == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return []attribute.KeyValue{ServerAddressKey.String(endpoint)}
+	}
+	attrs := []attribute.KeyValue{ServerAddressKey.String(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, ServerPortKey.Int(p))
+	}
+	return attrs
+}
+
+// targetEndpoint returns the endpoint of the gRPC target the client is
+// connected to, e.g. "example.com:443" for "dns:///example.com:443", or ""
+// for Unix domain sockets.  Targets without a scheme registered as resolver
+// are endpoints.
+func targetEndpoint(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || resolver.Get(u.Scheme) == nil {
+		return target
+	}
+	switch u.Scheme {
+	case "unix", "unix-abstract":
+		return ""
+	}
+	if u.Opaque != "" {
+		return u.Opaque
+	}
+	return strings.TrimPrefix(u.Path, "/")
+}
+
 // peerFromCtx returns a peer address from a context, if one exists.
 func peerFromCtx(ctx context.Context) string {
 	p, ok := peer.FromContext(ctx)
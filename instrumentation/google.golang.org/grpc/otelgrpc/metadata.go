@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	requestMetadataPrefix  = "rpc.grpc.request.metadata."
+	responseMetadataPrefix = "rpc.grpc.response.metadata."
+)
+
+// capturedMetadata is a gRPC metadata key recorded as a span attribute.
+type capturedMetadata struct {
+	name string
+	key  attribute.Key
+}
+
+// newCapturedMetadata returns the metadata keys recorded as the attributes
+// prefixed by prefix followed by the key lowercased with '-' replaced by
+// '_'.
+func newCapturedMetadata(prefix string, keys []string) []capturedMetadata {
+	captured := make([]capturedMetadata, 0, len(keys))
+	for _, k := range keys {
+		name := strings.ToLower(k)
+		captured = append(captured, capturedMetadata{
+			name: name,
+			key:  attribute.Key(prefix + strings.ReplaceAll(name, "-", "_")),
+		})
+	}
+	return captured
+}
+
+// metadataAttributes returns the attributes of the captured metadata keys
+// present in md, with all their values.
+func metadataAttributes(captured []capturedMetadata, md metadata.MD) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, c := range captured {
+		if values := md.Get(c.name); len(values) > 0 {
+			attrs = append(attrs, c.key.StringSlice(values))
+		}
+	}
+	return attrs
+}
+
+// responseHeader accumulates the header metadata set by a server handler.
+// A nil *responseHeader records nothing.
+type responseHeader struct {
+	mu sync.Mutex
+	md metadata.MD
+}
+
+func (h *responseHeader) add(md metadata.MD) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.md = metadata.Join(h.md, md)
+	h.mu.Unlock()
+}
+
+func (h *responseHeader) metadata() metadata.MD {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.md
+}
+
+// serverTransportStream wraps around the grpc.ServerTransportStream of an
+// RPC to record the header metadata set with grpc.SetHeader and
+// grpc.SendHeader.
+type serverTransportStream struct {
+	grpc.ServerTransportStream
+	header *responseHeader
+}
+
+func (s *serverTransportStream) SetHeader(md metadata.MD) error {
+	err := s.ServerTransportStream.SetHeader(md)
+	if err == nil {
+		s.header.add(md)
+	}
+	return err
+}
+
+func (s *serverTransportStream) SendHeader(md metadata.MD) error {
+	err := s.ServerTransportStream.SendHeader(md)
+	if err == nil {
+		s.header.add(md)
+	}
+	return err
+}
+
+// captureResponseHeader returns a context whose grpc.ServerTransportStream
+// records the header metadata in the returned responseHeader, or ctx and
+// nil if no response metadata is captured.
+func (c *config) captureResponseHeader(ctx context.Context) (context.Context, *responseHeader) {
+	if len(c.ResponseMetadata) == 0 {
+		return ctx, nil
+	}
+	header := &responseHeader{}
+	if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &serverTransportStream{
+			ServerTransportStream: stream,
+			header:                header,
+		})
+	}
+	return ctx, header
+}
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto" // nolint:staticcheck
+
+	grpc_codes "google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Server and client RPC metrics.  The compressed sizes of the messages are
+// only recorded by the stats handlers of NewServerHandler and
+// NewClientHandler.
+const (
+	ServerDuration               = "rpc.server.duration"                 // Duration of incoming RPCs, milliseconds
+	ServerRequestSize            = "rpc.server.request.size"             // Size of incoming request messages, bytes
+	ServerResponseSize           = "rpc.server.response.size"            // Size of outgoing response messages, bytes
+	ServerRequestCompressedSize  = "rpc.server.request.compressed_size"  // Compressed size of incoming request messages, bytes
+	ServerResponseCompressedSize = "rpc.server.response.compressed_size" // Compressed size of outgoing response messages, bytes
+	ServerRequestsPerRPC         = "rpc.server.requests_per_rpc"         // Number of request messages received per RPC
+	ServerResponsesPerRPC        = "rpc.server.responses_per_rpc"        // Number of response messages sent per RPC
+	ServerRequests               = "rpc.server.requests"                 // Number of request messages received
+	ServerResponses              = "rpc.server.responses"                // Number of response messages sent
+	ClientDuration               = "rpc.client.duration"                 // Duration of outgoing RPCs, milliseconds
+	ClientRequestSize            = "rpc.client.request.size"             // Size of outgoing request messages, bytes
+	ClientResponseSize           = "rpc.client.response.size"            // Size of incoming response messages, bytes
+	ClientRequestCompressedSize  = "rpc.client.request.compressed_size"  // Compressed size of outgoing request messages, bytes
+	ClientResponseCompressedSize = "rpc.client.response.compressed_size" // Compressed size of incoming response messages, bytes
+	ClientRequestsPerRPC         = "rpc.client.requests_per_rpc"         // Number of request messages sent per RPC
+	ClientResponsesPerRPC        = "rpc.client.responses_per_rpc"        // Number of response messages received per RPC
+	ClientRequests               = "rpc.client.requests"                 // Number of request messages sent
+	ClientResponses              = "rpc.client.responses"                // Number of response messages received
+)
+
+// rpcMetricNames are the names of the metrics of either the server or the
+// client.
+type rpcMetricNames struct {
+	duration, requestSize, responseSize, requestsPerRPC, responsesPerRPC string
+	requests, responses                                                  string
+	requestCompressedSize, responseCompressedSize                        string
+}
+
+var (
+	serverMetricNames = rpcMetricNames{
+		duration:        ServerDuration,
+		requestSize:     ServerRequestSize,
+		responseSize:    ServerResponseSize,
+		requestsPerRPC:  ServerRequestsPerRPC,
+		responsesPerRPC: ServerResponsesPerRPC,
+		requests:        ServerRequests,
+		responses:       ServerResponses,
+
+		requestCompressedSize:  ServerRequestCompressedSize,
+		responseCompressedSize: ServerResponseCompressedSize,
+	}
+	clientMetricNames = rpcMetricNames{
+		duration:        ClientDuration,
+		requestSize:     ClientRequestSize,
+		responseSize:    ClientResponseSize,
+		requestsPerRPC:  ClientRequestsPerRPC,
+		responsesPerRPC: ClientResponsesPerRPC,
+		requests:        ClientRequests,
+		responses:       ClientResponses,
+
+		requestCompressedSize:  ClientRequestCompressedSize,
+		responseCompressedSize: ClientResponseCompressedSize,
+	}
+)
+
+// rpcMetrics holds the instruments of the RPCs of either the server or the
+// client.  A nil *rpcMetrics records nothing.
+//
+// The messages are counted as they are sent and received, unlike the
+// messages per RPC recorded at the end of the RPCs, so that the throughput
+// of long-lived streams is measured.
+type rpcMetrics struct {
+	duration        syncfloat64.Histogram
+	requestSize     syncint64.Histogram
+	responseSize    syncint64.Histogram
+	requestsPerRPC  syncint64.Histogram
+	responsesPerRPC syncint64.Histogram
+	requests        syncint64.Counter
+	responses       syncint64.Counter
+
+	requestCompressedSize  syncint64.Histogram
+	responseCompressedSize syncint64.Histogram
+}
+
+// newRPCMetrics returns the instruments with the given names, or nil if the
+// metrics are not enabled.
+func (c *config) newRPCMetrics(names rpcMetricNames) *rpcMetrics {
+	if c.MeterProvider == nil {
+		return nil
+	}
+	meter := c.MeterProvider.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	var (
+		m   rpcMetrics
+		err error
+	)
+	m.duration, err = meter.SyncFloat64().Histogram(
+		names.duration,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the RPCs"),
+	)
+	handleErr(err)
+
+	m.requestSize, err = meter.SyncInt64().Histogram(
+		names.requestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Uncompressed size of the request messages"),
+	)
+	handleErr(err)
+
+	m.responseSize, err = meter.SyncInt64().Histogram(
+		names.responseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Uncompressed size of the response messages"),
+	)
+	handleErr(err)
+
+	m.requestsPerRPC, err = meter.SyncInt64().Histogram(
+		names.requestsPerRPC,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of request messages per RPC"),
+	)
+	handleErr(err)
+
+	m.responsesPerRPC, err = meter.SyncInt64().Histogram(
+		names.responsesPerRPC,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of response messages per RPC"),
+	)
+	handleErr(err)
+
+	m.requests, err = meter.SyncInt64().Counter(
+		names.requests,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of request messages"),
+	)
+	handleErr(err)
+
+	m.responses, err = meter.SyncInt64().Counter(
+		names.responses,
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Number of response messages"),
+	)
+	handleErr(err)
+
+	m.requestCompressedSize, err = meter.SyncInt64().Histogram(
+		names.requestCompressedSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Compressed size of the request messages"),
+	)
+	handleErr(err)
+
+	m.responseCompressedSize, err = meter.SyncInt64().Histogram(
+		names.responseCompressedSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Compressed size of the response messages"),
+	)
+	handleErr(err)
+
+	return &m
+}
+
+func handleErr(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// recordRequest counts a request message and records its size.
+func (m *rpcMetrics) recordRequest(ctx context.Context, size int, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	m.requests.Add(ctx, 1, attrs...)
+	m.requestSize.Record(ctx, int64(size), attrs...)
+}
+
+// recordResponse counts a response message and records its size.
+func (m *rpcMetrics) recordResponse(ctx context.Context, size int, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	m.responses.Add(ctx, 1, attrs...)
+	m.responseSize.Record(ctx, int64(size), attrs...)
+}
+
+// recordCompressedSize records the compressed size of a request or a
+// response message.
+func (m *rpcMetrics) recordCompressedSize(ctx context.Context, request bool, size int, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	if request {
+		m.requestCompressedSize.Record(ctx, int64(size), attrs...)
+	} else {
+		m.responseCompressedSize.Record(ctx, int64(size), attrs...)
+	}
+}
+
+// recordMessage counts message as a request or a response, and records its
+// size if it is a proto message.
+func (m *rpcMetrics) recordMessage(ctx context.Context, request bool, message interface{}, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	p, ok := message.(proto.Message)
+	switch {
+	case ok && request:
+		m.recordRequest(ctx, proto.Size(p), attrs)
+	case ok:
+		m.recordResponse(ctx, proto.Size(p), attrs)
+	case request:
+		m.requests.Add(ctx, 1, attrs...)
+	default:
+		m.responses.Add(ctx, 1, attrs...)
+	}
+}
+
+// recordEnd records the duration and the number of messages of an RPC that
+// ended with the status code.
+func (m *rpcMetrics) recordEnd(ctx context.Context, elapsed time.Duration, requests, responses int64, code grpc_codes.Code, attrs []attribute.KeyValue) {
+	if m == nil {
+		return
+	}
+	attrs = append(attrs[:len(attrs):len(attrs)], statusCodeAttr(code))
+
+	// Use floating point division here for higher precision (instead of Millisecond method).
+	m.duration.Record(ctx, float64(elapsed)/float64(time.Millisecond), attrs...)
+	m.requestsPerRPC.Record(ctx, requests, attrs...)
+	m.responsesPerRPC.Record(ctx, responses, attrs...)
+}
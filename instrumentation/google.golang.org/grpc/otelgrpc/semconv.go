@@ -36,6 +36,16 @@ const (
 	// The uncompressed size of the message transmitted or received in
 	// bytes.
 	RPCMessageUncompressedSizeKey = attribute.Key("message.uncompressed_size")
+
+	// The domain name, or IP address if the name is unknown, of the server
+	// called by the client.
+	ServerAddressKey = attribute.Key("server.address")
+
+	// The port of the server called by the client.
+	ServerPortKey = attribute.Key("server.port")
+
+	// The number of the previous attempts of an RPC retried by the client.
+	RPCResendCountKey = attribute.Key("rpc.grpc.request.resend_count")
 )
 
 // Semantic conventions for common RPC attributes.
@@ -0,0 +1,287 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"sync/atomic"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type gRPCContextKey struct{}
+
+// gRPCContext holds the state of an RPC traced by a stats.Handler.
+type gRPCContext struct {
+	messagesReceived int64
+	messagesSent     int64
+	metricAttrs      []attribute.KeyValue
+}
+
+// NewServerHandler returns a stats.Handler suitable for use in a
+// grpc.NewServer call with grpc.StatsHandler, as an alternative to the
+// UnaryServerInterceptor and StreamServerInterceptor.  It records all the
+// types of RPCs uniformly, and the timing of every message.
+//
+// The RPCs excluded by the Filter set with WithFilter are neither traced nor
+// measured.
+func NewServerHandler(opts ...Option) stats.Handler {
+	c := newConfig(opts)
+	return &serverHandler{
+		config:  c,
+		tracer:  c.tracer(),
+		metrics: c.newRPCMetrics(serverMetricNames),
+	}
+}
+
+type serverHandler struct {
+	*config
+	tracer  trace.Tracer
+	metrics *rpcMetrics
+}
+
+// TagConn can attach some information to the given context.
+func (h *serverHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn processes the Conn stats.
+func (h *serverHandler) HandleConn(ctx context.Context, info stats.ConnStats) {
+}
+
+// TagRPC starts the span of the RPC and attaches it to the given context.
+func (h *serverHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if !h.traces(info.FullMethodName, ServerStatsHandler) {
+		return ctx
+	}
+
+	requestMetadata, _ := metadata.FromIncomingContext(ctx)
+	ctx = h.extract(ctx, requestMetadata)
+
+	mInfo := h.methodInfo(info.FullMethodName)
+	if h.propagatesOnly(info.FullMethodName, ServerStatsHandler) {
+		ctx, _ = propagationOnly(ctx)
+	} else {
+		ctx, _ = h.tracer.Start(
+			trace.ContextWithRemoteSpanContext(ctx, trace.SpanContextFromContext(ctx)),
+			mInfo.name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(spanAttrs(
+				mInfo.attrs,
+				peerAttr(peerFromCtx(ctx)),
+				previousAttemptsAttr(requestMetadata),
+			)...),
+		)
+	}
+
+	return context.WithValue(ctx, gRPCContextKey{}, newGRPCContext(h.metrics, mInfo))
+}
+
+// HandleRPC records the RPC stats on the span of the RPC.
+func (h *serverHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	h.handleRPC(ctx, rs, h.metrics, true)
+}
+
+// NewClientHandler returns a stats.Handler suitable for use in a grpc.Dial
+// call with grpc.WithStatsHandler, as an alternative to the
+// UnaryClientInterceptor and StreamClientInterceptor.  It records all the
+// types of RPCs uniformly, and the timing of every message.
+//
+// The RPCs excluded by the Filter set with WithFilter are neither traced nor
+// measured.
+//
+// The handler is called for every attempt of the RPCs retried by the
+// channel.  Used together with the UnaryClientInterceptor and
+// StreamClientInterceptor, the span of every attempt is a child of the span
+// of the interceptors, and both have the RPCResendCountKey attribute of the
+// number of the previous attempts.
+func NewClientHandler(opts ...Option) stats.Handler {
+	c := newConfig(opts)
+	return &clientHandler{
+		config:  c,
+		tracer:  c.tracer(),
+		metrics: c.newRPCMetrics(clientMetricNames),
+	}
+}
+
+type clientHandler struct {
+	*config
+	tracer  trace.Tracer
+	metrics *rpcMetrics
+}
+
+// TagRPC starts the span of the RPC, attaches it to the given context, and
+// injects it into the outgoing metadata.
+func (h *clientHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if !h.traces(info.FullMethodName, ClientStatsHandler) {
+		return ctx
+	}
+
+	mInfo := h.methodInfo(info.FullMethodName)
+	if h.propagatesOnly(info.FullMethodName, ClientStatsHandler) {
+		ctx, _ = propagationOnly(ctx)
+	} else {
+		ctx, _ = h.tracer.Start(
+			ctx,
+			mInfo.name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(spanAttrs(
+				mInfo.attrs,
+				h.clientAttrs(""),
+				nextAttempt(ctx),
+			)...),
+		)
+	}
+
+	requestMetadata, _ := metadata.FromOutgoingContext(ctx)
+	metadataCopy := requestMetadata.Copy()
+	h.inject(ctx, metadataCopy)
+	ctx = metadata.NewOutgoingContext(ctx, metadataCopy)
+
+	return context.WithValue(ctx, gRPCContextKey{}, newGRPCContext(h.metrics, mInfo))
+}
+
+// HandleRPC records the RPC stats on the span of the RPC.
+func (h *clientHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	h.handleRPC(ctx, rs, h.metrics, false)
+}
+
+// TagConn can attach some information to the given context.
+func (h *clientHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn processes the Conn stats.
+func (h *clientHandler) HandleConn(ctx context.Context, info stats.ConnStats) {
+}
+
+// traces reports whether the RPC of the method is not excluded by the
+// Filter.
+func (c *config) traces(fullMethod string, typ InterceptorType) bool {
+	return c.Filter == nil || c.Filter(&InterceptorInfo{Method: fullMethod, Typ: typ})
+}
+
+// propagatesOnly reports whether the RPC of the method is selected by the
+// PropagationOnly Filter.
+func (c *config) propagatesOnly(fullMethod string, typ InterceptorType) bool {
+	return c.PropagationOnly != nil && c.PropagationOnly(&InterceptorInfo{Method: fullMethod, Typ: typ})
+}
+
+func newGRPCContext(metrics *rpcMetrics, info methodInfo) *gRPCContext {
+	gctx := &gRPCContext{}
+	if metrics != nil {
+		gctx.metricAttrs = info.attrs
+	}
+	return gctx
+}
+
+// handleRPC records the RPC stats on the span of the RPC, and in metrics.
+// The received messages are the requests on the server and the responses on
+// the client.  The RPCs excluded by the Filter, which have no gRPCContext,
+// are ignored so that the span of the caller is left untouched.
+func (c *config) handleRPC(ctx context.Context, rs stats.RPCStats, metrics *rpcMetrics, isServer bool) {
+	gctx, _ := ctx.Value(gRPCContextKey{}).(*gRPCContext)
+	if gctx == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	var messageID int64
+
+	switch rs := rs.(type) {
+	case *stats.InHeader:
+		if isServer {
+			span.SetAttributes(metadataAttributes(c.RequestMetadata, rs.Header)...)
+		} else {
+			span.SetAttributes(metadataAttributes(c.ResponseMetadata, rs.Header)...)
+		}
+	case *stats.OutHeader:
+		if rs.Client && rs.RemoteAddr != nil {
+			// The target is unknown to the handler, the server is
+			// identified by its address instead.
+			span.SetAttributes(peerAttr(rs.RemoteAddr.String())...)
+			span.SetAttributes(serverAddrAttrs(rs.RemoteAddr.String())...)
+		}
+		if isServer {
+			span.SetAttributes(metadataAttributes(c.ResponseMetadata, rs.Header)...)
+		} else {
+			span.SetAttributes(metadataAttributes(c.RequestMetadata, rs.Header)...)
+		}
+	case *stats.InPayload:
+		messageID = atomic.AddInt64(&gctx.messagesReceived, 1)
+		if span.IsRecording() && c.recordsMessageEvent(messageReceived, messageID) {
+			span.AddEvent("message",
+				trace.WithAttributes(c.messageAttrs(messageReceived, messageID, rs.Length, rs.WireLength)...),
+				trace.WithTimestamp(rs.RecvTime),
+			)
+		}
+		if isServer {
+			metrics.recordRequest(ctx, rs.Length, gctx.metricAttrs)
+		} else {
+			metrics.recordResponse(ctx, rs.Length, gctx.metricAttrs)
+		}
+		metrics.recordCompressedSize(ctx, isServer, rs.WireLength, gctx.metricAttrs)
+	case *stats.OutPayload:
+		messageID = atomic.AddInt64(&gctx.messagesSent, 1)
+		if span.IsRecording() && c.recordsMessageEvent(messageSent, messageID) {
+			span.AddEvent("message",
+				trace.WithAttributes(c.messageAttrs(messageSent, messageID, rs.Length, rs.WireLength)...),
+				trace.WithTimestamp(rs.SentTime),
+			)
+		}
+		if isServer {
+			metrics.recordResponse(ctx, rs.Length, gctx.metricAttrs)
+		} else {
+			metrics.recordRequest(ctx, rs.Length, gctx.metricAttrs)
+		}
+		metrics.recordCompressedSize(ctx, !isServer, rs.WireLength, gctx.metricAttrs)
+	case *stats.End:
+		code := grpc_codes.OK
+		if rs.Error != nil {
+			s, _ := status.FromError(rs.Error)
+			span.SetStatus(c.StatusConverter(s))
+			code = s.Code()
+		}
+		span.SetAttributes(statusCodeAttr(code))
+		span.End(trace.WithTimestamp(rs.EndTime))
+
+		received, sent := atomic.LoadInt64(&gctx.messagesReceived), atomic.LoadInt64(&gctx.messagesSent)
+		if isServer {
+			metrics.recordEnd(ctx, rs.EndTime.Sub(rs.BeginTime), received, sent, code, gctx.metricAttrs)
+		} else {
+			metrics.recordEnd(ctx, rs.EndTime.Sub(rs.BeginTime), sent, received, code, gctx.metricAttrs)
+		}
+	}
+}
+
+// messageAttrs returns the attributes of a message event.
+func (c *config) messageAttrs(typ messageType, id int64, size, wireSize int) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.KeyValue(typ),
+		RPCMessageIDKey.Int64(id),
+	}
+	if c.UncompressedSizes {
+		attrs = append(attrs, RPCMessageUncompressedSizeKey.Int(size))
+	}
+	if c.CompressedSizes {
+		attrs = append(attrs, RPCMessageCompressedSizeKey.Int(wireSize))
+	}
+	return attrs
+}
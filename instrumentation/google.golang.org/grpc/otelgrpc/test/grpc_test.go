@@ -38,6 +38,18 @@ import (
 const bufSize = 2048
 
 func doCalls(cOpt []grpc.DialOption, sOpt []grpc.ServerOption) error {
+	return doCallsWith(cOpt, sOpt, func(client pb.TestServiceClient) {
+		interop.DoEmptyUnaryCall(client)
+		interop.DoLargeUnaryCall(client)
+		interop.DoClientStreaming(client)
+		interop.DoServerStreaming(client)
+		interop.DoPingPong(client)
+	})
+}
+
+// doCallsWith makes the calls to a test server, the client and the server
+// created with the options.
+func doCallsWith(cOpt []grpc.DialOption, sOpt []grpc.ServerOption, calls func(pb.TestServiceClient)) error {
 	l := bufconn.Listen(bufSize)
 	defer l.Close()
 
@@ -64,13 +76,7 @@ func doCalls(cOpt []grpc.DialOption, sOpt []grpc.ServerOption) error {
 		return err
 	}
 	defer conn.Close()
-	client := pb.NewTestServiceClient(conn)
-
-	interop.DoEmptyUnaryCall(client)
-	interop.DoLargeUnaryCall(client)
-	interop.DoClientStreaming(client)
-	interop.DoServerStreaming(client)
-	interop.DoPingPong(client)
+	calls(pb.NewTestServiceClient(conn))
 
 	return nil
 }
@@ -145,6 +151,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 		semconv.RPCServiceKey.String("grpc.testing.TestService"),
 		otelgrpc.RPCSystemGRPC,
 		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+		otelgrpc.ServerAddressKey.String("bufnet"),
 	}, emptySpan.Attributes())
 
 	largeSpan := spans[1]
@@ -175,6 +182,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 		semconv.RPCServiceKey.String("grpc.testing.TestService"),
 		otelgrpc.RPCSystemGRPC,
 		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+		otelgrpc.ServerAddressKey.String("bufnet"),
 	}, largeSpan.Attributes())
 }
 
@@ -225,6 +233,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 		semconv.RPCServiceKey.String("grpc.testing.TestService"),
 		otelgrpc.RPCSystemGRPC,
 		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+		otelgrpc.ServerAddressKey.String("bufnet"),
 	}, streamInput.Attributes())
 
 	streamOutput := spans[1]
@@ -278,6 +287,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 		semconv.RPCServiceKey.String("grpc.testing.TestService"),
 		otelgrpc.RPCSystemGRPC,
 		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+		otelgrpc.ServerAddressKey.String("bufnet"),
 	}, streamOutput.Attributes())
 
 	pingPong := spans[2]
@@ -354,6 +364,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 		semconv.RPCServiceKey.String("grpc.testing.TestService"),
 		otelgrpc.RPCSystemGRPC,
 		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+		otelgrpc.ServerAddressKey.String("bufnet"),
 	}, pingPong.Attributes())
 }
 
@@ -622,3 +633,38 @@ func assertEvents(t *testing.T, expected, actual []trace.Event) bool {
 
 	return !failed
 }
+
+func TestClientPeerService(t *testing.T) {
+	interceptorSR := tracetest.NewSpanRecorder()
+	interceptorOpts := []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(interceptorSR))),
+		otelgrpc.WithPeerService("billing"),
+	}
+	handlerSR := tracetest.NewSpanRecorder()
+	handlerOpts := []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(handlerSR))),
+		otelgrpc.WithPeerService("billing"),
+	}
+
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(interceptorOpts...)),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(interceptorOpts...)),
+		},
+		[]grpc.ServerOption{},
+	))
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler(handlerOpts...))},
+		[]grpc.ServerOption{},
+	))
+
+	// The stats handler identifies the server by the address of the
+	// connection instead of the target.
+	for sr, addr := range map[*tracetest.SpanRecorder]string{interceptorSR: "bufnet", handlerSR: "bufconn"} {
+		require.Len(t, sr.Ended(), 5)
+		for _, span := range sr.Ended() {
+			assert.Contains(t, span.Attributes(), semconv.PeerServiceKey.String("billing"), span.Name())
+			assert.Contains(t, span.Attributes(), otelgrpc.ServerAddressKey.String(addr), span.Name())
+		}
+	}
+}
@@ -110,6 +110,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				otelgrpc.GRPCStatusCodeKey.Int64(0),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -134,6 +135,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				otelgrpc.GRPCStatusCodeKey.Int64(0),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -158,6 +160,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				otelgrpc.GRPCStatusCodeKey.Int64(int64(grpc_codes.OK)),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -183,6 +186,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				otelgrpc.GRPCStatusCodeKey.Int64(int64(grpc_codes.Internal)),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -206,6 +210,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				otelgrpc.GRPCStatusCodeKey.Int64(0),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -230,6 +235,7 @@ func TestUnaryClientInterceptor(t *testing.T) {
 				semconv.RPCMethodKey.String("method"),
 				semconv.NetPeerIPKey.String("fake"),
 				semconv.NetPeerPortKey.String("connection"),
+				otelgrpc.ServerAddressKey.String("fake"),
 			},
 			eventsAttr: []map[attribute.Key]attribute.Value{
 				{
@@ -378,6 +384,7 @@ func TestStreamClientInterceptorOnBIDIStream(t *testing.T) {
 		semconv.RPCMethodKey.String("bar"),
 		semconv.NetPeerIPKey.String("fake"),
 		semconv.NetPeerPortKey.String("connection"),
+		otelgrpc.ServerAddressKey.String("fake"),
 	}
 	assert.ElementsMatch(t, expectedAttr, span.Attributes())
 
@@ -446,6 +453,7 @@ func TestStreamClientInterceptorOnUnidirectionalClientServerStream(t *testing.T)
 		semconv.RPCMethodKey.String("bar"),
 		semconv.NetPeerIPKey.String("fake"),
 		semconv.NetPeerPortKey.String("connection"),
+		otelgrpc.ServerAddressKey.String("fake"),
 	}
 	assert.ElementsMatch(t, expectedAttr, span.Attributes())
 
@@ -568,6 +576,7 @@ func TestStreamClientInterceptorWithError(t *testing.T) {
 		semconv.RPCMethodKey.String("bar"),
 		semconv.NetPeerIPKey.String("fake"),
 		semconv.NetPeerPortKey.String("connection"),
+		otelgrpc.ServerAddressKey.String("fake"),
 	}
 	assert.ElementsMatch(t, expectedAttr, span.Attributes())
 	assert.Equal(t, codes.Error, span.Status().Code)
@@ -609,6 +618,64 @@ func TestServerInterceptorError(t *testing.T) {
 	}, span.Events()[1].Attributes)
 }
 
+func TestServerInterceptorStatusConverter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+	usi := otelgrpc.UnaryServerInterceptor(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithStatusConverter(func(s *status.Status) (codes.Code, string) {
+			if s.Code() == grpc_codes.NotFound {
+				return codes.Unset, ""
+			}
+			return codes.Error, s.Code().String()
+		}),
+	)
+
+	for _, tc := range []struct {
+		code        grpc_codes.Code
+		status      codes.Code
+		description string
+	}{
+		{grpc_codes.NotFound, codes.Unset, ""},
+		{grpc_codes.PermissionDenied, codes.Error, "PermissionDenied"},
+	} {
+		handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+			return nil, status.Error(tc.code, "error")
+		}
+		_, err := usi(context.Background(), &mockProtoMessage{}, &grpc.UnaryServerInfo{}, handler)
+		require.Error(t, err)
+
+		spans := sr.Ended()
+		span := spans[len(spans)-1]
+		assert.Equal(t, tc.status, span.Status().Code, tc.code)
+		assert.Equal(t, tc.description, span.Status().Description, tc.code)
+		assert.Contains(t, span.Attributes(), otelgrpc.GRPCStatusCodeKey.Int64(int64(tc.code)), tc.code)
+	}
+}
+
+func TestServerInterceptorMessageEventOptions(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+	usi := otelgrpc.UnaryServerInterceptor(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents),
+		otelgrpc.WithMessageSizes(),
+	)
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return &mockProtoMessage{}, nil
+	}
+	_, err := usi(context.Background(), &mockProtoMessage{}, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+
+	span, ok := getSpanFromRecorder(sr, "")
+	require.True(t, ok)
+	require.Len(t, span.Events(), 1)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Key("message.type").String("RECEIVED"),
+		attribute.Key("message.id").Int(1),
+	}, span.Events()[0].Attributes)
+}
+
 func TestParseFullMethod(t *testing.T) {
 	tests := []struct {
 		fullMethod string
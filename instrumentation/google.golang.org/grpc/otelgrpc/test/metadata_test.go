@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/interop"
+	pb "google.golang.org/grpc/interop/grpc_testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// metadataOpts returns the options capturing the metadata echoed by the test
+// server in interop.DoCustomMetadata, and recording the spans in sr.
+func metadataOpts(sr *tracetest.SpanRecorder) []otelgrpc.Option {
+	return []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(sr))),
+		otelgrpc.WithCapturedRequestMetadata([]string{"X-Grpc-Test-Echo-Initial", "x-absent"}),
+		otelgrpc.WithCapturedResponseMetadata([]string{"x-grpc-test-echo-initial"}),
+	}
+}
+
+func doCustomMetadataCalls(t *testing.T, cOpt []grpc.DialOption, sOpt []grpc.ServerOption) {
+	assert.NoError(t, doCallsWith(cOpt, sOpt, func(client pb.TestServiceClient) {
+		interop.DoCustomMetadata(client)
+	}))
+}
+
+func assertMetadataAttributes(t *testing.T, sr *tracetest.SpanRecorder) {
+	for _, method := range []string{"UnaryCall", "FullDuplexCall"} {
+		span, ok := getSpanFromRecorder(sr, "grpc.testing.TestService/"+method)
+		require.True(t, ok, method)
+
+		attrs := span.Attributes()
+		value := []string{"test_initial_metadata_value"}
+		assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.request.metadata.x_grpc_test_echo_initial", value), method)
+		assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.response.metadata.x_grpc_test_echo_initial", value), method)
+		for _, kv := range attrs {
+			assert.NotEqual(t, attribute.Key("rpc.grpc.request.metadata.x_absent"), kv.Key, method)
+		}
+	}
+}
+
+func TestInterceptorsCapturedMetadata(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientOpts := metadataOpts(clientSR)
+	serverSR := tracetest.NewSpanRecorder()
+	serverOpts := metadataOpts(serverSR)
+
+	doCustomMetadataCalls(t,
+		[]grpc.DialOption{
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(clientOpts...)),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(clientOpts...)),
+		},
+		[]grpc.ServerOption{
+			grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor(serverOpts...)),
+			grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor(serverOpts...)),
+		},
+	)
+
+	t.Run("Client", func(t *testing.T) {
+		assertMetadataAttributes(t, clientSR)
+	})
+	t.Run("Server", func(t *testing.T) {
+		assertMetadataAttributes(t, serverSR)
+	})
+}
+
+func TestStatsHandlerCapturedMetadata(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientOpts := metadataOpts(clientSR)
+	serverSR := tracetest.NewSpanRecorder()
+	serverOpts := metadataOpts(serverSR)
+
+	doCustomMetadataCalls(t,
+		[]grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler(clientOpts...))},
+		[]grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler(serverOpts...))},
+	)
+
+	t.Run("Client", func(t *testing.T) {
+		assertMetadataAttributes(t, clientSR)
+	})
+	t.Run("Server", func(t *testing.T) {
+		assertMetadataAttributes(t, serverSR)
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	pb "google.golang.org/grpc/interop/grpc_testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+type rpcMetricNames struct {
+	duration, requestSize, responseSize, requestsPerRPC, responsesPerRPC string
+	requests, responses                                                  string
+}
+
+var (
+	serverMetrics = rpcMetricNames{
+		otelgrpc.ServerDuration,
+		otelgrpc.ServerRequestSize,
+		otelgrpc.ServerResponseSize,
+		otelgrpc.ServerRequestsPerRPC,
+		otelgrpc.ServerResponsesPerRPC,
+		otelgrpc.ServerRequests,
+		otelgrpc.ServerResponses,
+	}
+	clientMetrics = rpcMetricNames{
+		otelgrpc.ClientDuration,
+		otelgrpc.ClientRequestSize,
+		otelgrpc.ClientResponseSize,
+		otelgrpc.ClientRequestsPerRPC,
+		otelgrpc.ClientResponsesPerRPC,
+		otelgrpc.ClientRequests,
+		otelgrpc.ClientResponses,
+	}
+)
+
+func TestInterceptorsMetrics(t *testing.T) {
+	clientMP, clientExp := metrictest.NewTestMeterProvider()
+	clientSR := tracetest.NewSpanRecorder()
+	clientOpts := []otelgrpc.Option{
+		otelgrpc.WithMeterProvider(clientMP),
+		otelgrpc.WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))),
+	}
+	serverMP, serverExp := metrictest.NewTestMeterProvider()
+
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(clientOpts...)),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(clientOpts...)),
+		},
+		[]grpc.ServerOption{
+			grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor(otelgrpc.WithMeterProvider(serverMP))),
+			grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor(otelgrpc.WithMeterProvider(serverMP))),
+		},
+	))
+
+	// The stream client interceptor ends the RPCs asynchronously, after
+	// recording their metrics.
+	require.Eventually(t, func() bool {
+		return len(clientSR.Ended()) == 5
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("Client", func(t *testing.T) {
+		checkRPCMetrics(t, clientExp, clientMetrics)
+	})
+	t.Run("Server", func(t *testing.T) {
+		checkRPCMetrics(t, serverExp, serverMetrics)
+	})
+}
+
+func TestStatsHandlerMetrics(t *testing.T) {
+	clientMP, clientExp := metrictest.NewTestMeterProvider()
+	serverMP, serverExp := metrictest.NewTestMeterProvider()
+
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithMeterProvider(clientMP))),
+		},
+		[]grpc.ServerOption{
+			grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithMeterProvider(serverMP))),
+		},
+	))
+
+	t.Run("Client", func(t *testing.T) {
+		checkRPCMetrics(t, clientExp, clientMetrics)
+		checkCompressedSizes(t, clientExp, otelgrpc.ClientRequestCompressedSize, otelgrpc.ClientResponseCompressedSize)
+	})
+	t.Run("Server", func(t *testing.T) {
+		checkRPCMetrics(t, serverExp, serverMetrics)
+		checkCompressedSizes(t, serverExp, otelgrpc.ServerRequestCompressedSize, otelgrpc.ServerResponseCompressedSize)
+	})
+}
+
+func checkCompressedSizes(t *testing.T, exp *metrictest.Exporter, requestSize, responseSize string) {
+	attrs := []attribute.KeyValue{semconv.RPCMethodKey.String("UnaryCall")}
+
+	// The sizes of the messages include the 5 bytes gRPC message header.
+	rec, err := exp.GetByNameAndAttributes(requestSize, attrs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(271845), rec.Sum.AsInt64())
+	}
+	rec, err = exp.GetByNameAndAttributes(responseSize, attrs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(314172), rec.Sum.AsInt64())
+	}
+}
+
+func checkRPCMetrics(t *testing.T, exp *metrictest.Exporter, names rpcMetricNames) {
+	require.NoError(t, exp.Collect(context.Background()))
+
+	methodAttrs := func(method string) []attribute.KeyValue {
+		return []attribute.KeyValue{
+			otelgrpc.RPCSystemGRPC,
+			semconv.RPCServiceKey.String("grpc.testing.TestService"),
+			semconv.RPCMethodKey.String(method),
+		}
+	}
+	endAttrs := func(method string) []attribute.KeyValue {
+		return append(methodAttrs(method), otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)))
+	}
+
+	for _, method := range []string{"EmptyCall", "UnaryCall", "StreamingInputCall", "StreamingOutputCall", "FullDuplexCall"} {
+		rec, err := exp.GetByNameAndAttributes(names.duration, endAttrs(method))
+		if assert.NoError(t, err, method) {
+			assert.Equal(t, uint64(1), rec.Count, method)
+		}
+	}
+
+	// largeReqSize and largeRespSize from "google.golang.org/grpc/interop" + overhead.
+	rec, err := exp.GetByNameAndAttributes(names.requestSize, methodAttrs("UnaryCall"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(271840), rec.Sum.AsInt64())
+	}
+	rec, err = exp.GetByNameAndAttributes(names.responseSize, methodAttrs("UnaryCall"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(314167), rec.Sum.AsInt64())
+	}
+
+	// reqSizes and respSizes from "google.golang.org/grpc/interop".
+	messages := []struct {
+		method              string
+		requests, responses int64
+	}{
+		{"UnaryCall", 1, 1},
+		{"StreamingInputCall", 4, 1},
+		{"StreamingOutputCall", 1, 4},
+		{"FullDuplexCall", 4, 4},
+	}
+	for _, m := range messages {
+		rec, err := exp.GetByNameAndAttributes(names.requestsPerRPC, endAttrs(m.method))
+		if assert.NoError(t, err, m.method) {
+			assert.Equal(t, m.requests, rec.Sum.AsInt64(), m.method)
+		}
+		rec, err = exp.GetByNameAndAttributes(names.responsesPerRPC, endAttrs(m.method))
+		if assert.NoError(t, err, m.method) {
+			assert.Equal(t, m.responses, rec.Sum.AsInt64(), m.method)
+		}
+
+		rec, err = exp.GetByNameAndAttributes(names.requests, methodAttrs(m.method))
+		if assert.NoError(t, err, m.method) {
+			assert.Equal(t, m.requests, rec.Sum.AsInt64(), m.method)
+		}
+		rec, err = exp.GetByNameAndAttributes(names.responses, methodAttrs(m.method))
+		if assert.NoError(t, err, m.method) {
+			assert.Equal(t, m.responses, rec.Sum.AsInt64(), m.method)
+		}
+	}
+}
+
+func TestStreamMessageCounters(t *testing.T) {
+	serverMP, serverExp := metrictest.NewTestMeterProvider()
+
+	assert.NoError(t, doCallsWith(
+		[]grpc.DialOption{},
+		[]grpc.ServerOption{
+			grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor(otelgrpc.WithMeterProvider(serverMP))),
+		},
+		func(client pb.TestServiceClient) {
+			stream, err := client.FullDuplexCall(context.Background())
+			require.NoError(t, err)
+			for i := 0; i < 2; i++ {
+				require.NoError(t, stream.Send(&pb.StreamingOutputCallRequest{
+					ResponseParameters: []*pb.ResponseParameters{{Size: 1}},
+				}))
+				_, err = stream.Recv()
+				require.NoError(t, err)
+			}
+
+			// The messages of the stream are counted before it ends.
+			require.NoError(t, serverExp.Collect(context.Background()))
+			attrs := []attribute.KeyValue{semconv.RPCMethodKey.String("FullDuplexCall")}
+			for _, name := range []string{otelgrpc.ServerRequests, otelgrpc.ServerResponses} {
+				rec, err := serverExp.GetByNameAndAttributes(name, attrs)
+				if assert.NoError(t, err, name) {
+					assert.Equal(t, int64(2), rec.Sum.AsInt64(), name)
+				}
+			}
+			_, err = serverExp.GetByNameAndAttributes(otelgrpc.ServerDuration, attrs)
+			assert.Error(t, err)
+
+			require.NoError(t, stream.CloseSend())
+			_, err = stream.Recv()
+			require.ErrorIs(t, err, io.EOF)
+		},
+	))
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	pb "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// spanContextServer records the span context of the calls it handles.
+type spanContextServer struct {
+	pb.UnimplementedTestServiceServer
+
+	mu           sync.Mutex
+	spanContexts map[string]oteltrace.SpanContext
+}
+
+func (s *spanContextServer) record(ctx context.Context, method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.spanContexts[method] = oteltrace.SpanContextFromContext(ctx)
+}
+
+func (s *spanContextServer) EmptyCall(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	s.record(ctx, "EmptyCall")
+	return &pb.Empty{}, nil
+}
+
+func (s *spanContextServer) UnaryCall(ctx context.Context, _ *pb.SimpleRequest) (*pb.SimpleResponse, error) {
+	s.record(ctx, "UnaryCall")
+	return &pb.SimpleResponse{}, nil
+}
+
+func TestPropagationOnly(t *testing.T) {
+	propagationOnly := otelgrpc.WithPropagationOnly(filters.MethodName("EmptyCall"))
+	propagators := otelgrpc.WithPropagators(propagation.TraceContext{})
+
+	tests := []struct {
+		name string
+		cOpt func(...otelgrpc.Option) grpc.DialOption
+		sOpt func(...otelgrpc.Option) grpc.ServerOption
+	}{
+		{
+			name: "Interceptors",
+			cOpt: func(opts ...otelgrpc.Option) grpc.DialOption {
+				return grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(opts...))
+			},
+			sOpt: func(opts ...otelgrpc.Option) grpc.ServerOption {
+				return grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor(opts...))
+			},
+		},
+		{
+			name: "StatsHandlers",
+			cOpt: func(opts ...otelgrpc.Option) grpc.DialOption {
+				return grpc.WithStatsHandler(otelgrpc.NewClientHandler(opts...))
+			},
+			sOpt: func(opts ...otelgrpc.Option) grpc.ServerOption {
+				return grpc.StatsHandler(otelgrpc.NewServerHandler(opts...))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSR := tracetest.NewSpanRecorder()
+			clientTP := trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))
+			serverSR := tracetest.NewSpanRecorder()
+			serverTP := trace.NewTracerProvider(trace.WithSpanProcessor(serverSR))
+
+			l := bufconn.Listen(bufSize)
+			defer l.Close()
+			s := grpc.NewServer(tt.sOpt(otelgrpc.WithTracerProvider(serverTP), propagators, propagationOnly))
+			srv := &spanContextServer{spanContexts: make(map[string]oteltrace.SpanContext)}
+			pb.RegisterTestServiceServer(s, srv)
+			go func() {
+				if err := s.Serve(l); err != nil {
+					panic(err)
+				}
+			}()
+			defer s.Stop()
+
+			ctx := context.Background()
+			dial := func(context.Context, string) (net.Conn, error) { return l.Dial() }
+			conn, err := grpc.DialContext(
+				ctx,
+				"bufnet",
+				grpc.WithContextDialer(dial),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+				tt.cOpt(otelgrpc.WithTracerProvider(clientTP), propagators, propagationOnly),
+			)
+			require.NoError(t, err)
+			defer conn.Close()
+			client := pb.NewTestServiceClient(conn)
+
+			ctx, parent := clientTP.Tracer("test").Start(ctx, "parent")
+			_, err = client.EmptyCall(ctx, &pb.Empty{})
+			require.NoError(t, err)
+			_, err = client.UnaryCall(ctx, &pb.SimpleRequest{})
+			require.NoError(t, err)
+			parent.End()
+
+			// Only the RPC not selected by the filter is traced.
+			require.Len(t, clientSR.Ended(), 2)
+			clientSpan, ok := getSpanFromRecorder(clientSR, "grpc.testing.TestService/UnaryCall")
+			require.True(t, ok)
+			require.Len(t, serverSR.Ended(), 1)
+			serverSpan, ok := getSpanFromRecorder(serverSR, "grpc.testing.TestService/UnaryCall")
+			require.True(t, ok)
+			assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+			assert.Equal(t, serverSpan.SpanContext(), srv.spanContexts["UnaryCall"])
+
+			// The trace context is propagated through the other RPC.
+			sc := srv.spanContexts["EmptyCall"]
+			assert.Equal(t, parent.SpanContext().TraceID(), sc.TraceID())
+			assert.Equal(t, parent.SpanContext().SpanID(), sc.SpanID())
+			assert.True(t, sc.IsRemote())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	pb "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// unavailableServer fails the first calls with the Unavailable code.
+type unavailableServer struct {
+	pb.UnimplementedTestServiceServer
+	failures int64
+}
+
+func (s *unavailableServer) EmptyCall(context.Context, *pb.Empty) (*pb.Empty, error) {
+	if atomic.AddInt64(&s.failures, -1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	return &pb.Empty{}, nil
+}
+
+const retryServiceConfig = `{
+	"methodConfig": [{
+		"name": [{"service": "grpc.testing.TestService"}],
+		"retryPolicy": {
+			"maxAttempts": 3,
+			"initialBackoff": "0.001s",
+			"maxBackoff": "0.001s",
+			"backoffMultiplier": 1,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+func TestRetryAttempts(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientTP := trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))
+	serverSR := tracetest.NewSpanRecorder()
+	serverTP := trace.NewTracerProvider(trace.WithSpanProcessor(serverSR))
+
+	l := bufconn.Listen(bufSize)
+	defer l.Close()
+	s := grpc.NewServer(grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor(otelgrpc.WithTracerProvider(serverTP))))
+	pb.RegisterTestServiceServer(s, &unavailableServer{failures: 2})
+	go func() {
+		if err := s.Serve(l); err != nil {
+			panic(err)
+		}
+	}()
+	defer s.Stop()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(retryServiceConfig),
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(otelgrpc.WithTracerProvider(clientTP))),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(clientTP))),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = pb.NewTestServiceClient(conn).EmptyCall(context.Background(), &pb.Empty{})
+	require.NoError(t, err)
+
+	// The span of the interceptor and the spans of the 3 attempts.
+	spans := clientSR.Ended()
+	require.Len(t, spans, 4)
+	call := spans[len(spans)-1]
+	assert.Contains(t, call.Attributes(), otelgrpc.RPCResendCountKey.Int64(2))
+	for i, attempt := range spans[:3] {
+		assert.Equal(t, call.SpanContext().SpanID(), attempt.Parent().SpanID())
+		assert.Equal(t, oteltrace.SpanKindClient, attempt.SpanKind())
+		if i == 0 {
+			for _, kv := range attempt.Attributes() {
+				assert.NotEqual(t, otelgrpc.RPCResendCountKey, kv.Key)
+			}
+		} else {
+			assert.Contains(t, attempt.Attributes(), otelgrpc.RPCResendCountKey.Int64(int64(i)))
+		}
+	}
+
+	serverSpans := serverSR.Ended()
+	require.Len(t, serverSpans, 3)
+	assert.Contains(t, serverSpans[2].Attributes(), otelgrpc.RPCResendCountKey.Int64(2))
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestStatsHandler(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientTP := trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))
+
+	serverSR := tracetest.NewSpanRecorder()
+	serverTP := trace.NewTracerProvider(trace.WithSpanProcessor(serverSR))
+
+	propagators := otelgrpc.WithPropagators(propagation.TraceContext{})
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(clientTP), propagators)),
+		},
+		[]grpc.ServerOption{
+			grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(serverTP), propagators)),
+		},
+	))
+
+	methods := []string{
+		"EmptyCall",
+		"UnaryCall",
+		"StreamingInputCall",
+		"StreamingOutputCall",
+		"FullDuplexCall",
+	}
+	require.Len(t, clientSR.Ended(), len(methods))
+	require.Len(t, serverSR.Ended(), len(methods))
+
+	clientSpans := make(map[string]trace.ReadOnlySpan)
+	serverSpans := make(map[string]trace.ReadOnlySpan)
+	for _, method := range methods {
+		name := "grpc.testing.TestService/" + method
+		clientSpan, ok := getSpanFromRecorder(clientSR, name)
+		require.True(t, ok, name)
+		serverSpan, ok := getSpanFromRecorder(serverSR, name)
+		require.True(t, ok, name)
+		clientSpans[method], serverSpans[method] = clientSpan, serverSpan
+
+		assert.Equal(t, oteltrace.SpanKindClient, clientSpan.SpanKind())
+		assert.Equal(t, oteltrace.SpanKindServer, serverSpan.SpanKind())
+		assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+
+		for _, span := range []trace.ReadOnlySpan{clientSpan, serverSpan} {
+			attrs := span.Attributes()
+			assert.Contains(t, attrs, otelgrpc.RPCSystemGRPC)
+			assert.Contains(t, attrs, semconv.RPCServiceKey.String("grpc.testing.TestService"))
+			assert.Contains(t, attrs, semconv.RPCMethodKey.String(method))
+			assert.Contains(t, attrs, otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)))
+		}
+	}
+
+	// largeReqSize and largeRespSize from "google.golang.org/grpc/interop" +
+	// overhead, the wire size includes the 5 bytes gRPC message header.
+	assertEvents(t, []trace.Event{
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageIDKey.Int(1),
+				otelgrpc.RPCMessageUncompressedSizeKey.Int(271840),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(271845),
+			},
+		},
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageIDKey.Int(1),
+				otelgrpc.RPCMessageUncompressedSizeKey.Int(314167),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(314172),
+			},
+		},
+	}, clientSpans["UnaryCall"].Events())
+	assertEvents(t, []trace.Event{
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageIDKey.Int(1),
+				otelgrpc.RPCMessageUncompressedSizeKey.Int(271840),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(271845),
+			},
+		},
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageIDKey.Int(1),
+				otelgrpc.RPCMessageUncompressedSizeKey.Int(314167),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(314172),
+			},
+		},
+	}, serverSpans["UnaryCall"].Events())
+
+	// Every message of the streaming calls is recorded.
+	assert.Len(t, clientSpans["FullDuplexCall"].Events(), 8)
+	assert.Len(t, serverSpans["FullDuplexCall"].Events(), 8)
+}
+
+func TestStatsHandlerMessageEventOptions(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientTP := trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))
+
+	serverSR := tracetest.NewSpanRecorder()
+	serverTP := trace.NewTracerProvider(trace.WithSpanProcessor(serverSR))
+
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+				otelgrpc.WithTracerProvider(clientTP),
+				otelgrpc.WithMessageEvents(otelgrpc.SentEvents),
+				otelgrpc.WithMessageEventLimit(2),
+				otelgrpc.WithMessageSizes(otelgrpc.CompressedSize),
+			)),
+		},
+		[]grpc.ServerOption{
+			grpc.StatsHandler(otelgrpc.NewServerHandler(
+				otelgrpc.WithTracerProvider(serverTP),
+				otelgrpc.WithMessageEvents(),
+			)),
+		},
+	))
+
+	span, ok := getSpanFromRecorder(clientSR, "grpc.testing.TestService/FullDuplexCall")
+	require.True(t, ok)
+	// reqSizes from "google.golang.org/grpc/interop" + overhead + 5 bytes
+	// gRPC message header.
+	assertEvents(t, []trace.Event{
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageIDKey.Int(1),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(27201),
+			},
+		},
+		{
+			Name: "message",
+			Attributes: []attribute.KeyValue{
+				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageIDKey.Int(2),
+				otelgrpc.RPCMessageCompressedSizeKey.Int(21),
+			},
+		},
+	}, span.Events())
+
+	for _, span := range serverSR.Ended() {
+		assert.Empty(t, span.Events(), span.Name())
+	}
+}
+
+func TestStatsHandlerWithFilter(t *testing.T) {
+	clientSR := tracetest.NewSpanRecorder()
+	clientTP := trace.NewTracerProvider(trace.WithSpanProcessor(clientSR))
+	clientMP, clientExp := metrictest.NewTestMeterProvider()
+
+	serverSR := tracetest.NewSpanRecorder()
+	serverTP := trace.NewTracerProvider(trace.WithSpanProcessor(serverSR))
+	serverMP, serverExp := metrictest.NewTestMeterProvider()
+
+	filter := otelgrpc.WithFilter(filters.Not(filters.MethodName("EmptyCall")))
+	assert.NoError(t, doCalls(
+		[]grpc.DialOption{
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+				otelgrpc.WithTracerProvider(clientTP),
+				otelgrpc.WithMeterProvider(clientMP),
+				filter,
+			)),
+		},
+		[]grpc.ServerOption{
+			grpc.StatsHandler(otelgrpc.NewServerHandler(
+				otelgrpc.WithTracerProvider(serverTP),
+				otelgrpc.WithMeterProvider(serverMP),
+				filter,
+			)),
+		},
+	))
+
+	for _, sr := range []*tracetest.SpanRecorder{clientSR, serverSR} {
+		assert.Len(t, sr.Ended(), 4)
+		_, ok := getSpanFromRecorder(sr, "grpc.testing.TestService/EmptyCall")
+		assert.False(t, ok)
+	}
+
+	emptyCallAttrs := []attribute.KeyValue{
+		otelgrpc.RPCSystemGRPC,
+		semconv.RPCServiceKey.String("grpc.testing.TestService"),
+		semconv.RPCMethodKey.String("EmptyCall"),
+		otelgrpc.GRPCStatusCodeKey.Int64(int64(codes.OK)),
+	}
+	require.NoError(t, clientExp.Collect(context.Background()))
+	_, err := clientExp.GetByNameAndAttributes(otelgrpc.ClientDuration, emptyCallAttrs)
+	assert.Error(t, err)
+	require.NoError(t, serverExp.Collect(context.Background()))
+	_, err = serverExp.GetByNameAndAttributes(otelgrpc.ServerDuration, emptyCallAttrs)
+	assert.Error(t, err)
+}
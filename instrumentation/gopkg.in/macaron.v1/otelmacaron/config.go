@@ -15,7 +15,10 @@
 package otelmacaron // import "go.opentelemetry.io/contrib/instrumentation/gopkg.in/macaron.v1/otelmacaron"
 
 import (
+	"net/http"
+
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -24,8 +27,14 @@ import (
 type config struct {
 	TracerProvider trace.TracerProvider
 	Propagators    propagation.TextMapPropagator
+	MeterProvider  metric.MeterProvider
+	Filters        []Filter
 }
 
+// Filter is a predicate used to determine whether a given http.Request should
+// be traced. A Filter must return true if the request should be traced.
+type Filter func(*http.Request) bool
+
 // Option applies an option value for a config.
 type Option interface {
 	apply(*config)
@@ -43,6 +52,17 @@ func newConfig(opts []Option) *config {
 	return c
 }
 
+// traces returns whether the request passes all the Filters and should be
+// traced.
+func (c *config) traces(r *http.Request) bool {
+	for _, f := range c.Filters {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
+
 type propagatorsOption struct{ p propagation.TextMapPropagator }
 
 func (o propagatorsOption) apply(c *config) {
@@ -70,3 +90,31 @@ func (o tracerProviderOption) apply(c *config) {
 func WithTracerProvider(tp trace.TracerProvider) Option {
 	return tracerProviderOption{tp: tp}
 }
+
+type meterProviderOption struct{ mp metric.MeterProvider }
+
+func (o meterProviderOption) apply(c *config) {
+	if o.mp != nil {
+		c.MeterProvider = o.mp
+	}
+}
+
+// WithMeterProvider returns an Option to record the duration and sizes of
+// the requests with the MeterProvider. No metrics are recorded by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return meterProviderOption{mp: mp}
+}
+
+type filterOption struct{ f Filter }
+
+func (o filterOption) apply(c *config) {
+	if o.f != nil {
+		c.Filters = append(c.Filters, o.f)
+	}
+}
+
+// WithFilter returns an Option to add a Filter to the middleware. The
+// requests not passing all the Filters are neither traced nor measured.
+func WithFilter(f Filter) Option {
+	return filterOption{f: f}
+}
@@ -17,6 +17,9 @@ package otelmacaron // import "go.opentelemetry.io/contrib/instrumentation/gopkg
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/macaron.v1"
 
@@ -29,13 +32,24 @@ import (
 const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/gopkg.in/macaron.v1/otelmacaron"
 
 // Middleware returns a macaron Handler to trace requests to the server.
+//
+// The spans are named after the route pattern of the requests, eg /user/:id.
+// As macaron does not expose the pattern of the matched route, it is
+// rebuilt from the request path and the parameters of the route.
 func Middleware(service string, opts ...Option) macaron.Handler {
 	cfg := newConfig(opts)
 	tracer := cfg.TracerProvider.Tracer(
 		instrumentationName,
 		oteltrace.WithInstrumentationVersion(SemVersion()),
 	)
+	metrics := newServerMetrics(cfg.MeterProvider)
 	return func(res http.ResponseWriter, req *http.Request, c *macaron.Context) {
+		if !cfg.traces(c.Req.Request) {
+			c.Next()
+			return
+		}
+		start := time.Now()
+
 		savedCtx := c.Req.Request.Context()
 		defer func() {
 			c.Req.Request = c.Req.Request.WithContext(savedCtx)
@@ -48,12 +62,8 @@ func Middleware(service string, opts ...Option) macaron.Handler {
 			oteltrace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(service, "", c.Req.Request)...),
 			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
 		}
-		// TODO: span name should be router template not the actual request path, eg /user/:id vs /user/123
-		spanName := c.Req.RequestURI
-		if spanName == "" {
-			spanName = fmt.Sprintf("HTTP %s route not found", c.Req.Method)
-		}
-		ctx, span := tracer.Start(ctx, spanName, opts...)
+		route := routePattern(c)
+		ctx, span := tracer.Start(ctx, route, opts...)
 		defer span.End()
 
 		// pass the span through the request context
@@ -67,5 +77,67 @@ func Middleware(service string, opts ...Option) macaron.Handler {
 		spanStatus, spanMessage := semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, oteltrace.SpanKindServer)
 		span.SetAttributes(attrs...)
 		span.SetStatus(spanStatus, spanMessage)
+
+		// The requests to static routes cannot be told apart from the
+		// requests matching no route but by their status.
+		if len(c.AllParams()) == 0 && status == http.StatusNotFound {
+			route = ""
+			span.SetName(fmt.Sprintf("HTTP %s route not found", c.Req.Method))
+		}
+		if route != "" {
+			span.SetAttributes(semconv.HTTPRouteKey.String(route))
+		}
+		metrics.record(ctx, c, route, start)
+	}
+}
+
+// routePattern returns the pattern of the route matched by the request of c,
+// rebuilt by replacing the segments of the request path with the names of
+// the parameters they were matched to.
+func routePattern(c *macaron.Context) string {
+	path := c.Req.URL.Path
+	params := c.AllParams()
+	if len(params) == 0 {
+		return path
+	}
+
+	var suffix string
+	// The trailing segments matched by a "*" glob.
+	if splat, ok := params["*"]; ok && splat != "" && strings.HasSuffix(path, splat) {
+		path, suffix = path[:len(path)-len(splat)], "*"
+	}
+
+	// Sort the names so the segments matching several parameters are named
+	// consistently, after the named parameters first.
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "*" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if ni, nj := names[i][0] == ':', names[j][0] == ':'; ni != nj {
+			return ni
+		}
+		return names[i] < names[j]
+	})
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		for _, name := range names {
+			if params[name] != segment {
+				continue
+			}
+			if strings.HasPrefix(name, "*") {
+				// The numbered globs are matched by the "*" pattern.
+				name = "*"
+			}
+			segments[i] = name
+			break
+		}
 	}
+	return strings.Join(segments, "/") + suffix
 }
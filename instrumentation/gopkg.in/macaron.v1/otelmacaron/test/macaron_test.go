@@ -79,22 +79,107 @@ func TestChildSpanNames(t *testing.T) {
 	spans := sr.Ended()
 	require.Len(t, spans, 2)
 	span := spans[0]
-	assert.Equal(t, "/user/123", span.Name()) // TODO: span name should show router template, eg /user/:id
+	assert.Equal(t, "/user/:id", span.Name())
 	assert.Equal(t, oteltrace.SpanKindServer, span.SpanKind())
 	attrs := span.Attributes()
 	assert.Contains(t, attrs, attribute.String("http.server_name", "foobar"))
 	assert.Contains(t, attrs, attribute.Int("http.status_code", http.StatusOK))
 	assert.Contains(t, attrs, attribute.String("http.method", "GET"))
 	assert.Contains(t, attrs, attribute.String("http.target", "/user/123"))
+	assert.Contains(t, attrs, attribute.String("http.route", "/user/:id"))
 
 	span = spans[1]
-	assert.Equal(t, "/book/foo", span.Name()) // TODO: span name should show router template, eg /book/:title
+	assert.Equal(t, "/book/:title", span.Name())
 	assert.Equal(t, oteltrace.SpanKindServer, span.SpanKind())
 	attrs = span.Attributes()
 	assert.Contains(t, attrs, attribute.String("http.server_name", "foobar"))
 	assert.Contains(t, attrs, attribute.Int("http.status_code", http.StatusOK))
 	assert.Contains(t, attrs, attribute.String("http.method", "GET"))
 	assert.Contains(t, attrs, attribute.String("http.target", "/book/foo"))
+	assert.Contains(t, attrs, attribute.String("http.route", "/book/:title"))
+}
+
+func TestRoutePatterns(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		want    string
+	}{
+		{"/", "/", "/"},
+		{"/static/page", "/static/page", "/static/page"},
+		{"/user/:id/posts/:post", "/user/1/posts/2", "/user/:id/posts/:post"},
+		{"/user/:id/friends/:friend", "/user/1/friends/1", "/user/:friend/friends/:friend"},
+		{"/num/:id([0-9]+)", "/num/42", "/num/:id"},
+		{"/files/*", "/files/a/b/c", "/files/*"},
+		{"/glob/*/items", "/glob/foo/items", "/glob/*/items"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+			m := macaron.Classic()
+			m.Use(otelmacaron.Middleware("foobar", otelmacaron.WithTracerProvider(tp)))
+			m.Get(tc.pattern, func(ctx *macaron.Context) {
+				ctx.Resp.WriteHeader(http.StatusOK)
+			})
+
+			m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tc.path, nil))
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.want, spans[0].Name())
+		})
+	}
+}
+
+func TestRouteNotFound(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	m := macaron.Classic()
+	m.Use(otelmacaron.Middleware("foobar", otelmacaron.WithTracerProvider(tp)))
+	m.Get("/user/:id", func(ctx *macaron.Context) {
+		ctx.Resp.WriteHeader(http.StatusOK)
+	})
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET route not found", spans[0].Name())
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route"), kv.Key)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	m := macaron.Classic()
+	m.Use(otelmacaron.Middleware(
+		"foobar",
+		otelmacaron.WithTracerProvider(tp),
+		otelmacaron.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz"
+		}),
+	))
+	var served int
+	handler := func(ctx *macaron.Context) {
+		served++
+		ctx.Resp.WriteHeader(http.StatusOK)
+	}
+	m.Get("/healthz", handler)
+	m.Get("/user/:id", handler)
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	assert.Equal(t, 2, served, "filtered requests should still be served")
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/user/:id", spans[0].Name())
 }
 
 func TestSpanStatus(t *testing.T) {
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelgorm instruments the gorm.io/gorm package.
+//
+// The operations of a database are traced when it uses the plugin returned by
+// NewPlugin:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+//	if err != nil {
+//		return err
+//	}
+//	if err := db.Use(otelgorm.NewPlugin()); err != nil {
+//		return err
+//	}
+//
+// The creations, queries, updates, deletions, and raw SQL statements are
+// traced in spans named after them, e.g. "gorm.create", children of the span
+// of the context of the operations set with db.WithContext.  The spans record
+// the table as the db.sql.table attribute, the first keyword of the SQL
+// statement, e.g. "INSERT", as the db.operation attribute, and the number of
+// rows affected.  The SQL statements are only recorded, without the values of
+// their parameters, as the db.statement attribute with WithStatement.
+//
+// gorm.ErrRecordNotFound is not recorded as an error.
+package otelgorm // import "go.opentelemetry.io/contrib/instrumentation/gorm.io/gorm/otelgorm"
@@ -0,0 +1,16 @@
+module go.opentelemetry.io/contrib/instrumentation/gorm.io/gorm/otelgorm
+
+go 1.17
+
+require (
+	go.opentelemetry.io/otel v1.9.0
+	go.opentelemetry.io/otel/trace v1.9.0
+	gorm.io/gorm v1.23.8
+)
+
+require (
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+)
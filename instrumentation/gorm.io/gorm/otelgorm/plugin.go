@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelgorm // import "go.opentelemetry.io/contrib/instrumentation/gorm.io/gorm/otelgorm"
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.opentelemetry.io/contrib/instrumentation/gorm.io/gorm/otelgorm"
+
+// Names of the spans of the operations.
+const (
+	spanCreate = "gorm.create"
+	spanQuery  = "gorm.query"
+	spanUpdate = "gorm.update"
+	spanDelete = "gorm.delete"
+	spanRow    = "gorm.row"
+	spanRaw    = "gorm.raw"
+)
+
+// RowsAffectedKey is the number of rows affected by an operation.
+const RowsAffectedKey = attribute.Key("db.rows_affected")
+
+// startedKey is the key of the started operation of a statement, set with
+// gorm.DB.InstanceSet.
+const startedKey = "otel:started"
+
+// dbSystems are the db.system of the names of the dialectors.
+var dbSystems = map[string]attribute.KeyValue{
+	"mysql":     semconv.DBSystemMySQL,
+	"postgres":  semconv.DBSystemPostgreSQL,
+	"sqlite":    semconv.DBSystemSqlite,
+	"sqlserver": semconv.DBSystemMSSQL,
+}
+
+// plugin traces the operations of the databases it is used by.
+type plugin struct {
+	tracer    trace.Tracer
+	attrs     []attribute.KeyValue
+	statement bool
+}
+
+var _ gorm.Plugin = (*plugin)(nil)
+
+// started is an operation whose span is started.
+type started struct {
+	span trace.Span
+	// ctx is the context of the statement before the span was started.
+	ctx context.Context
+}
+
+// NewPlugin returns a plugin tracing the operations of the databases using
+// it, registered with gorm.DB.Use.
+func NewPlugin(opts ...Option) gorm.Plugin {
+	cfg := newConfig(opts...)
+	return &plugin{
+		tracer: cfg.TracerProvider.Tracer(
+			instrumentationName,
+			trace.WithInstrumentationVersion(SemVersion()),
+		),
+		attrs:     cfg.Attributes,
+		statement: cfg.Statement,
+	}
+}
+
+// Name returns the name of the plugin.
+func (p *plugin) Name() string {
+	return "otelgorm"
+}
+
+// Initialize registers the callbacks starting the spans before the
+// operations of db and ending them after.
+func (p *plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("otel:before_create", p.before(spanCreate)),
+		cb.Create().After("gorm:create").Register("otel:after_create", p.after("INSERT")),
+		cb.Query().Before("gorm:query").Register("otel:before_query", p.before(spanQuery)),
+		cb.Query().After("gorm:query").Register("otel:after_query", p.after("SELECT")),
+		cb.Update().Before("gorm:update").Register("otel:before_update", p.before(spanUpdate)),
+		cb.Update().After("gorm:update").Register("otel:after_update", p.after("UPDATE")),
+		cb.Delete().Before("gorm:delete").Register("otel:before_delete", p.before(spanDelete)),
+		cb.Delete().After("gorm:delete").Register("otel:after_delete", p.after("DELETE")),
+		cb.Row().Before("gorm:row").Register("otel:before_row", p.before(spanRow)),
+		cb.Row().After("gorm:row").Register("otel:after_row", p.after("")),
+		cb.Raw().Before("gorm:raw").Register("otel:before_raw", p.before(spanRaw)),
+		cb.Raw().After("gorm:raw").Register("otel:after_raw", p.after("")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before returns a callback starting a span with the name, child of the span
+// of the context of the statement, which is replaced by the context of the
+// span for the rest of the operation.
+func (p *plugin) before(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(p.attrs)+1)
+		if db.Dialector != nil {
+			attrs = append(attrs, dbSystem(db.Dialector.Name()))
+		}
+		attrs = append(attrs, p.attrs...)
+
+		spanCtx, span := p.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		db.Statement.Context = spanCtx
+		db.InstanceSet(startedKey, &started{span: span, ctx: ctx})
+	}
+}
+
+// after returns a callback ending the span started for the operation, if
+// any, and restoring the context of the statement.  The operation is
+// recorded as the db.operation attribute, unless the SQL statement starts
+// with another one.
+func (p *plugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startedKey)
+		if !ok {
+			return
+		}
+		s, ok := v.(*started)
+		if !ok {
+			return
+		}
+		db.Statement.Context = s.ctx
+
+		statement := db.Statement.SQL.String()
+		if op := statementOperation(statement); op != "" {
+			operation = op
+		}
+		attrs := make([]attribute.KeyValue, 0, 4)
+		if operation != "" {
+			attrs = append(attrs, semconv.DBOperationKey.String(operation))
+		}
+		if db.Statement.Table != "" {
+			attrs = append(attrs, semconv.DBSQLTableKey.String(db.Statement.Table))
+		}
+		attrs = append(attrs, RowsAffectedKey.Int64(db.RowsAffected))
+		if p.statement && statement != "" {
+			attrs = append(attrs, semconv.DBStatementKey.String(statement))
+		}
+		s.span.SetAttributes(attrs...)
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			s.span.RecordError(db.Error)
+			s.span.SetStatus(codes.Error, db.Error.Error())
+		}
+		s.span.End()
+	}
+}
+
+// dbSystem returns the db.system of the dialector with the name.
+func dbSystem(name string) attribute.KeyValue {
+	if system, ok := dbSystems[name]; ok {
+		return system
+	}
+	return semconv.DBSystemKey.String(name)
+}
+
+// statementOperation returns the first keyword of the SQL statement, in
+// upper case, e.g. "SELECT".
+func statementOperation(statement string) string {
+	statement = strings.TrimLeft(statement, " \t\r\n(")
+	end := 0
+	for end < len(statement) && isLetter(statement[end]) {
+		end++
+	}
+	return strings.ToUpper(statement[:end])
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
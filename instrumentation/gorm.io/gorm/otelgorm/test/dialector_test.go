@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// dialector is a gorm.Dialector building the SQL statements of the
+// operations, which are not executed in the DryRun mode.
+type dialector struct{}
+
+var _ gorm.Dialector = dialector{}
+
+func (dialector) Name() string {
+	return "sqlite"
+}
+
+func (dialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (dialector) Migrator(*gorm.DB) gorm.Migrator {
+	return nil
+}
+
+func (dialector) DataTypeOf(*schema.Field) string {
+	return "TEXT"
+}
+
+func (dialector) DefaultValueOf(*schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (dialector) BindVarTo(writer clause.Writer, _ *gorm.Statement, _ interface{}) {
+	_ = writer.WriteByte('?')
+}
+
+func (dialector) QuoteTo(writer clause.Writer, str string) {
+	_ = writer.WriteByte('"')
+	_, _ = writer.WriteString(str)
+	_ = writer.WriteByte('"')
+}
+
+func (dialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `"`, vars...)
+}
@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"go.opentelemetry.io/contrib/instrumentation/gorm.io/gorm/otelgorm"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Book struct {
+	ID    uint
+	Title string
+}
+
+// open returns a database using the plugin with the options, and the
+// context of a span parent of the spans of its operations.
+func open(t *testing.T, opts ...otelgorm.Option) (*gorm.DB, *tracetest.SpanRecorder, context.Context) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	db, err := gorm.Open(dialector{}, &gorm.Config{DryRun: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	opts = append([]otelgorm.Option{otelgorm.WithTracerProvider(provider)}, opts...)
+	require.NoError(t, db.Use(otelgorm.NewPlugin(opts...)))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	t.Cleanup(func() { parent.End() })
+	return db, sr, ctx
+}
+
+func TestOperations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		do        func(*gorm.DB) *gorm.DB
+		span      string
+		operation string
+		table     string
+	}{
+		{
+			name:      "create",
+			do:        func(db *gorm.DB) *gorm.DB { return db.Create(&Book{Title: "Dune"}) },
+			span:      "gorm.create",
+			operation: "INSERT",
+			table:     "books",
+		},
+		{
+			name:      "query",
+			do:        func(db *gorm.DB) *gorm.DB { return db.First(&Book{}) },
+			span:      "gorm.query",
+			operation: "SELECT",
+			table:     "books",
+		},
+		{
+			name:      "update",
+			do:        func(db *gorm.DB) *gorm.DB { return db.Model(&Book{ID: 1}).Update("title", "Emma") },
+			span:      "gorm.update",
+			operation: "UPDATE",
+			table:     "books",
+		},
+		{
+			name:      "delete",
+			do:        func(db *gorm.DB) *gorm.DB { return db.Delete(&Book{ID: 1}) },
+			span:      "gorm.delete",
+			operation: "DELETE",
+			table:     "books",
+		},
+		{
+			name:      "raw",
+			do:        func(db *gorm.DB) *gorm.DB { return db.Exec("TRUNCATE books") },
+			span:      "gorm.raw",
+			operation: "TRUNCATE",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, sr, ctx := open(t)
+			require.NoError(t, tc.do(db.WithContext(ctx)).Error)
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tc.span, span.Name())
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+			assert.Equal(t, trace.SpanContextFromContext(ctx).SpanID(), span.Parent().SpanID())
+			assert.Equal(t, codes.Unset, span.Status().Code)
+
+			attrs := span.Attributes()
+			assert.Contains(t, attrs, semconv.DBSystemSqlite)
+			assert.Contains(t, attrs, semconv.DBOperationKey.String(tc.operation))
+			assert.Contains(t, attrs, otelgorm.RowsAffectedKey.Int64(0))
+			if tc.table != "" {
+				assert.Contains(t, attrs, semconv.DBSQLTableKey.String(tc.table))
+			}
+			// The statements are not recorded by default.
+			for _, kv := range attrs {
+				assert.NotEqual(t, semconv.DBStatementKey, kv.Key)
+			}
+		})
+	}
+}
+
+func TestStatement(t *testing.T) {
+	db, sr, ctx := open(t, otelgorm.WithStatement(true))
+	require.NoError(t, db.WithContext(ctx).Exec("DELETE FROM books WHERE id = ?", 42).Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	// The values of the parameters are not recorded.
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatementKey.String("DELETE FROM books WHERE id = ?"))
+}
+
+func TestAttributes(t *testing.T) {
+	db, sr, ctx := open(t, otelgorm.WithAttributes(semconv.DBNameKey.String("library")))
+	require.NoError(t, db.WithContext(ctx).First(&Book{}).Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.DBNameKey.String("library"))
+}
+
+func TestRowsAffected(t *testing.T) {
+	db, sr, ctx := open(t)
+	require.NoError(t, db.Callback().Delete().Replace("gorm:delete", func(db *gorm.DB) {
+		db.RowsAffected = 3
+	}))
+	require.NoError(t, db.WithContext(ctx).Delete(&Book{}).Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), otelgorm.RowsAffectedKey.Int64(3))
+}
+
+func TestError(t *testing.T) {
+	db, sr, ctx := open(t)
+	require.NoError(t, db.Callback().Query().Replace("gorm:query", func(db *gorm.DB) {
+		_ = db.AddError(errors.New("fail"))
+	}))
+	assert.Error(t, db.WithContext(ctx).First(&Book{}).Error)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "fail", spans[0].Status().Description)
+	// The operation is known without the SQL statement.
+	assert.Contains(t, spans[0].Attributes(), semconv.DBOperationKey.String("SELECT"))
+}
+
+func TestRecordNotFound(t *testing.T) {
+	db, sr, ctx := open(t)
+	require.NoError(t, db.Callback().Query().Replace("gorm:query", func(db *gorm.DB) {
+		_ = db.AddError(gorm.ErrRecordNotFound)
+	}))
+	assert.ErrorIs(t, db.WithContext(ctx).First(&Book{}).Error, gorm.ErrRecordNotFound)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// cgroupStats are the resource usage and limits of the cgroup of the
+// current process.
+type cgroupStats struct {
+	// memoryUsage is the memory in use by the cgroup in bytes.
+	memoryUsage uint64
+	// memoryLimit is the memory limit of the cgroup in bytes, zero if
+	// the memory is not limited.
+	memoryLimit uint64
+	// cpuTime is the CPU time consumed by the cgroup in seconds.
+	cpuTime float64
+	// cpuLimit is the number of CPUs the cgroup may use, zero if the CPU
+	// is not limited.
+	cpuLimit float64
+}
+
+func (h *Host) registerCgroup() error {
+	var (
+		err error
+
+		containerMemoryUsage asyncint64.UpDownCounter
+		containerMemoryLimit asyncint64.UpDownCounter
+		containerCPUTime     asyncfloat64.Counter
+		containerCPULimit    asyncfloat64.Gauge
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	if _, err = readCgroupStats(); err != nil {
+		return fmt.Errorf("could not read cgroup limits: %w", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if containerMemoryUsage, err = h.meter.AsyncInt64().UpDownCounter(
+		"container.memory.usage",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Memory usage of the cgroup of this process"),
+	); err != nil {
+		return err
+	}
+
+	if containerMemoryLimit, err = h.meter.AsyncInt64().UpDownCounter(
+		"container.memory.limit",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Memory limit of the cgroup of this process"),
+	); err != nil {
+		return err
+	}
+
+	if containerCPUTime, err = h.meter.AsyncFloat64().Counter(
+		"container.cpu.time",
+		instrument.WithUnit("s"),
+		instrument.WithDescription("Accumulated CPU time spent by the cgroup of this process"),
+	); err != nil {
+		return err
+	}
+
+	if containerCPULimit, err = h.meter.AsyncFloat64().Gauge(
+		"container.cpu.limit",
+		instrument.WithUnit("{cpus}"),
+		instrument.WithDescription("Number of CPUs the cgroup of this process is limited to"),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			containerMemoryUsage,
+			containerMemoryLimit,
+			containerCPUTime,
+			containerCPULimit,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var stats cgroupStats
+			if err := h.collect(ctx, "cgroup", func(ctx context.Context) (err error) {
+				stats, err = readCgroupStats()
+				return err
+			}); err != nil {
+				h.handleError("cgroup", err)
+				return
+			}
+
+			containerMemoryUsage.Observe(ctx, int64(stats.memoryUsage))
+			containerCPUTime.Observe(ctx, stats.cpuTime)
+
+			// Limits are only reported if the cgroup is limited.
+			if stats.memoryLimit > 0 {
+				containerMemoryLimit.Observe(ctx, int64(stats.memoryLimit))
+			}
+			if stats.cpuLimit > 0 {
+				containerCPULimit.Observe(ctx, stats.cpuLimit)
+			}
+		})
+}
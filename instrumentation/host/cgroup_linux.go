@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// unlimitedCgroupV1Memory is the smallest value considered as an unlimited
+// memory.limit_in_bytes in cgroup v1.  The kernel reports the maximum
+// int64 rounded down to the page size if no limit is set.
+const unlimitedCgroupV1Memory = 1 << 62
+
+// cgroupFS reads cgroup information from a cgroup file system.
+type cgroupFS struct {
+	// root is the mount point of the cgroup file system.
+	root string
+	// procCgroup is the path of the cgroup file of the process.
+	procCgroup string
+}
+
+var defaultCgroupFS = cgroupFS{
+	root:       "/sys/fs/cgroup",
+	procCgroup: "/proc/self/cgroup",
+}
+
+// readCgroupStats reads the cgroup stats of the current process.
+func readCgroupStats() (cgroupStats, error) {
+	return defaultCgroupFS.stats()
+}
+
+// stats returns the cgroup stats read from fs.  The cgroup v2 (unified)
+// hierarchy is used if mounted at the root, otherwise the cgroup v1
+// memory, cpu, and cpuacct controllers are read.
+func (fs cgroupFS) stats() (cgroupStats, error) {
+	data, err := os.ReadFile(fs.procCgroup)
+	if err != nil {
+		return cgroupStats{}, err
+	}
+	paths := parseProcCgroup(data)
+
+	if _, err := os.Stat(filepath.Join(fs.root, "cgroup.controllers")); err == nil {
+		path, ok := paths[""]
+		if !ok {
+			return cgroupStats{}, errors.New("cgroup v2 path not found")
+		}
+		return fs.statsV2(fs.dir(fs.root, path))
+	}
+	return fs.statsV1(paths)
+}
+
+// dir returns the directory of the cgroup at path in the hierarchy mounted
+// at mount.  Within a cgroup namespace the path of the process cgroup may
+// not be visible, the mount point is then the directory of the cgroup.
+func (fs cgroupFS) dir(mount, path string) string {
+	dir := filepath.Join(mount, path)
+	if _, err := os.Stat(dir); err != nil {
+		return mount
+	}
+	return dir
+}
+
+func (fs cgroupFS) statsV2(dir string) (cgroupStats, error) {
+	var (
+		stats cgroupStats
+		err   error
+	)
+
+	if stats.memoryUsage, err = readUint(filepath.Join(dir, "memory.current")); err != nil {
+		return stats, err
+	}
+
+	memoryMax, err := readString(filepath.Join(dir, "memory.max"))
+	if err != nil {
+		return stats, err
+	}
+	if memoryMax != "max" {
+		if stats.memoryLimit, err = strconv.ParseUint(memoryMax, 10, 64); err != nil {
+			return stats, err
+		}
+	}
+
+	cpuStat, err := os.ReadFile(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return stats, err
+	}
+	usec, err := parseKeyedUint(cpuStat, "usage_usec")
+	if err != nil {
+		return stats, err
+	}
+	stats.cpuTime = float64(usec) / 1e6
+
+	// The cpu controller may not be enabled for the cgroup.
+	if cpuMax, err := readString(filepath.Join(dir, "cpu.max")); err == nil {
+		fields := strings.Fields(cpuMax)
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return stats, err
+			}
+			period, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return stats, err
+			}
+			if period > 0 {
+				stats.cpuLimit = quota / period
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func (fs cgroupFS) statsV1(paths map[string]string) (cgroupStats, error) {
+	var (
+		stats cgroupStats
+		err   error
+	)
+
+	memoryPath, ok := paths["memory"]
+	if !ok {
+		return stats, errors.New("cgroup v1 memory controller not found")
+	}
+	memoryDir := fs.dir(filepath.Join(fs.root, "memory"), memoryPath)
+	if stats.memoryUsage, err = readUint(filepath.Join(memoryDir, "memory.usage_in_bytes")); err != nil {
+		return stats, err
+	}
+	limit, err := readUint(filepath.Join(memoryDir, "memory.limit_in_bytes"))
+	if err != nil {
+		return stats, err
+	}
+	if limit < unlimitedCgroupV1Memory {
+		stats.memoryLimit = limit
+	}
+
+	cpuacctPath, ok := paths["cpuacct"]
+	if !ok {
+		return stats, errors.New("cgroup v1 cpuacct controller not found")
+	}
+	cpuacctDir := fs.dir(filepath.Join(fs.root, "cpuacct"), cpuacctPath)
+	nsec, err := readUint(filepath.Join(cpuacctDir, "cpuacct.usage"))
+	if err != nil {
+		return stats, err
+	}
+	stats.cpuTime = float64(nsec) / 1e9
+
+	// The cpu controller may not be mounted.
+	if cpuPath, ok := paths["cpu"]; ok {
+		cpuDir := fs.dir(filepath.Join(fs.root, "cpu"), cpuPath)
+		quota, errQuota := readString(filepath.Join(cpuDir, "cpu.cfs_quota_us"))
+		period, errPeriod := readUint(filepath.Join(cpuDir, "cpu.cfs_period_us"))
+		if errQuota == nil && errPeriod == nil && quota != "-1" && period > 0 {
+			q, err := strconv.ParseFloat(quota, 64)
+			if err != nil {
+				return stats, err
+			}
+			stats.cpuLimit = q / float64(period)
+		}
+	}
+
+	return stats, nil
+}
+
+// parseProcCgroup parses the content of a /proc/<pid>/cgroup file into the
+// cgroup path of each controller.  The cgroup v2 path is keyed by the empty
+// string.
+func parseProcCgroup(data []byte) map[string]string {
+	paths := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[1] == "" {
+			paths[""] = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+	return paths
+}
+
+// parseKeyedUint returns the value of key in the flat keyed file data.
+func parseKeyedUint(data []byte, key string) (uint64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("cgroup key %q not found", key)
+}
+
+func readString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readUint(path string) (uint64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
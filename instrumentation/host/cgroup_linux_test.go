@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package host
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestCgroupV2Stats(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"cgroup":                          "0::/kubepods/pod1\n",
+		"fs/cgroup.controllers":           "cpu memory\n",
+		"fs/kubepods/pod1/memory.current": "1048576\n",
+		"fs/kubepods/pod1/memory.max":     "4194304\n",
+		"fs/kubepods/pod1/cpu.stat":       "usage_usec 2500000\nuser_usec 2000000\nsystem_usec 500000\n",
+		"fs/kubepods/pod1/cpu.max":        "50000 100000\n",
+	})
+
+	stats, err := cgroupFS{
+		root:       filepath.Join(root, "fs"),
+		procCgroup: filepath.Join(root, "cgroup"),
+	}.stats()
+	require.NoError(t, err)
+	assert.Equal(t, cgroupStats{
+		memoryUsage: 1048576,
+		memoryLimit: 4194304,
+		cpuTime:     2.5,
+		cpuLimit:    0.5,
+	}, stats)
+}
+
+func TestCgroupV2StatsUnlimited(t *testing.T) {
+	root := t.TempDir()
+	// The cgroup path is not visible within a cgroup namespace.
+	writeFiles(t, root, map[string]string{
+		"cgroup":                "0::/not/visible\n",
+		"fs/cgroup.controllers": "cpu memory\n",
+		"fs/memory.current":     "1048576\n",
+		"fs/memory.max":         "max\n",
+		"fs/cpu.stat":           "usage_usec 1000000\n",
+		"fs/cpu.max":            "max 100000\n",
+	})
+
+	stats, err := cgroupFS{
+		root:       filepath.Join(root, "fs"),
+		procCgroup: filepath.Join(root, "cgroup"),
+	}.stats()
+	require.NoError(t, err)
+	assert.Equal(t, cgroupStats{
+		memoryUsage: 1048576,
+		cpuTime:     1,
+	}, stats)
+}
+
+func TestCgroupV1Stats(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"cgroup": "4:memory:/docker/abc\n2:cpu,cpuacct:/docker/abc\n0::/\n",
+		"fs/memory/docker/abc/memory.usage_in_bytes": "2097152\n",
+		"fs/memory/docker/abc/memory.limit_in_bytes": "9223372036854771712\n",
+		"fs/cpuacct/docker/abc/cpuacct.usage":        "3000000000\n",
+		"fs/cpu/docker/abc/cpu.cfs_quota_us":         "200000\n",
+		"fs/cpu/docker/abc/cpu.cfs_period_us":        "100000\n",
+	})
+
+	stats, err := cgroupFS{
+		root:       filepath.Join(root, "fs"),
+		procCgroup: filepath.Join(root, "cgroup"),
+	}.stats()
+	require.NoError(t, err)
+	assert.Equal(t, cgroupStats{
+		memoryUsage: 2097152,
+		cpuTime:     3,
+		cpuLimit:    2,
+	}, stats)
+}
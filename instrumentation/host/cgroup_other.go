@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"errors"
+	"runtime"
+)
+
+// readCgroupStats returns an error since cgroups are only supported on
+// Linux.
+func readCgroupStats() (cgroupStats, error) {
+	return cgroupStats{}, errors.New("cgroups are not supported on " + runtime.GOOS)
+}
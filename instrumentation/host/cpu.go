@@ -0,0 +1,369 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+func (h *Host) registerCPU() error {
+	var (
+		err error
+
+		hostCPUTime        asyncfloat64.Counter
+		hostCPUUtilization asyncfloat64.Gauge
+
+		lastCPUTimes map[string]cpuStateTimes
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if hostCPUTime, err = h.meter.AsyncFloat64().Counter(
+		"system.cpu.time",
+		instrument.WithUnit("s"),
+		instrument.WithDescription(
+			"Accumulated CPU time spent by this host attributeed by state (User, System, Other, Idle)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if hostCPUUtilization, err = h.meter.AsyncFloat64().Gauge(
+		"system.cpu.utilization",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription(
+			"Fraction of CPU time spent by this host since the last collection attributed by state (User, System, Other, Idle)",
+		),
+	); err != nil {
+		return err
+	}
+
+	// Take an initial reading so the first collection can report the
+	// CPU utilization since the instrumentation was started.
+	if hostTimeSlice, err := h.config.Provider.CPUTimes(context.Background(), h.config.PerCPU); err == nil {
+		lastCPUTimes = make(map[string]cpuStateTimes, len(hostTimeSlice))
+		for _, hostTime := range hostTimeSlice {
+			lastCPUTimes[hostTime.CPU] = newCPUStateTimes(hostTime)
+		}
+	}
+
+	err = h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			hostCPUTime,
+			hostCPUUtilization,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var hostTimeSlice []cpu.TimesStat
+			if err := h.collect(ctx, "cpu", func(ctx context.Context) (err error) {
+				hostTimeSlice, err = h.config.Provider.CPUTimes(ctx, h.config.PerCPU)
+				return err
+			}); err != nil {
+				h.handleError("cpu", err)
+				return
+			}
+			if !h.config.PerCPU && len(hostTimeSlice) != 1 {
+				h.handleError("cpu", errors.New("incorrect summary count"))
+				return
+			}
+
+			cpuTimes := make(map[string]cpuStateTimes, len(hostTimeSlice))
+			sums := h.float64Sums()
+			utilizations := h.cpuUtilizations()
+			for _, hostTime := range hostTimeSlice {
+				var cpuAttrs []attribute.KeyValue
+				if h.config.PerCPU {
+					cpuAttrs = []attribute.KeyValue{attribute.String("cpu", hostTime.CPU)}
+				}
+				times := newCPUStateTimes(hostTime)
+				addHostCPUTime(sums, times, cpuAttrs)
+				if last, ok := lastCPUTimes[hostTime.CPU]; ok {
+					utilizations.add(last, times, cpuAttrs)
+				}
+				cpuTimes[hostTime.CPU] = times
+			}
+			lastCPUTimes = cpuTimes
+
+			sums.observe(ctx, hostCPUTime)
+			utilizations.observe(ctx, hostCPUUtilization)
+		})
+	if err != nil {
+		return err
+	}
+
+	return h.registerCPUCount()
+}
+
+func (h *Host) registerCPUCount() error {
+	var (
+		err error
+
+		logicalCount  asyncint64.UpDownCounter
+		physicalCount asyncint64.UpDownCounter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if logicalCount, err = h.meter.AsyncInt64().UpDownCounter(
+		"system.cpu.logical.count",
+		instrument.WithUnit("{cpus}"),
+		instrument.WithDescription("Number of logical CPUs of this host"),
+	); err != nil {
+		return err
+	}
+
+	if physicalCount, err = h.meter.AsyncInt64().UpDownCounter(
+		"system.cpu.physical.count",
+		instrument.WithUnit("{cpus}"),
+		instrument.WithDescription("Number of physical CPU cores of this host"),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			logicalCount,
+			physicalCount,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var logical, physical int
+			if err := h.collect(ctx, "cpu count", func(ctx context.Context) (err error) {
+				if logical, err = h.config.Provider.CPUCounts(ctx, true); err != nil {
+					return err
+				}
+				physical, err = h.config.Provider.CPUCounts(ctx, false)
+				return err
+			}); err != nil {
+				h.handleError("cpu count", err)
+				return
+			}
+
+			logicalCount.Observe(ctx, int64(logical))
+			// The number of physical cores is not available on all
+			// platforms, in which case gopsutil reports zero.
+			if physical > 0 {
+				physicalCount.Observe(ctx, int64(physical))
+			}
+		})
+}
+
+// cpuStateTimes are the CPU times of a host attributed by the reported
+// states.
+type cpuStateTimes struct {
+	user, system, other, idle float64
+}
+
+func newCPUStateTimes(hostTime cpu.TimesStat) cpuStateTimes {
+	// TODO(#244): "other" is a placeholder for actually dealing
+	// with these states.  Do users actually want this
+	// (unconditionally)?  How should we handle "iowait"
+	// if not all systems expose it?  See:
+	// https://github.com/open-telemetry/opentelemetry-go-contrib/issues/244
+	other := hostTime.Nice +
+		hostTime.Iowait +
+		hostTime.Irq +
+		hostTime.Softirq +
+		hostTime.Steal +
+		hostTime.Guest +
+		hostTime.GuestNice
+
+	return cpuStateTimes{
+		user:   hostTime.User,
+		system: hostTime.System,
+		other:  other,
+		idle:   hostTime.Idle,
+	}
+}
+
+func (t cpuStateTimes) total() float64 {
+	return t.user + t.system + t.other + t.idle
+}
+
+// addHostCPUTime adds the per-state CPU times with the cpuAttrs added to
+// each state attribute set to sums.
+func addHostCPUTime(sums *float64Sums, times cpuStateTimes, cpuAttrs []attribute.KeyValue) {
+	sums.add(times.user, AttributeCPUTimeUser, cpuAttrs)
+	sums.add(times.system, AttributeCPUTimeSystem, cpuAttrs)
+	sums.add(times.other, AttributeCPUTimeOther, cpuAttrs)
+	sums.add(times.idle, AttributeCPUTimeIdle, cpuAttrs)
+}
+
+// cpuUtilization is the CPU time spent in a state and the total CPU time
+// of the CPUs observed with an attribute set since the last collection.
+type cpuUtilization struct {
+	attrs        []attribute.KeyValue
+	state, total float64
+}
+
+// cpuUtilizations computes the fraction of CPU time spent in each state
+// per attribute set after filtering.  The CPUs whose attribute sets are
+// equal after filtering are reported as the fraction of their combined CPU
+// time, so filtering the "cpu" attribute reports the utilization of the
+// host.
+type cpuUtilizations struct {
+	h            *Host
+	utilizations map[attribute.Distinct]*cpuUtilization
+	order        []*cpuUtilization
+}
+
+func (h *Host) cpuUtilizations() *cpuUtilizations {
+	return &cpuUtilizations{h: h, utilizations: make(map[attribute.Distinct]*cpuUtilization)}
+}
+
+// add adds the CPU time spent in each state between the last and current
+// CPU times with the cpuAttrs added to each state attribute set.  Nothing
+// is added if no CPU time has passed.
+func (u *cpuUtilizations) add(last, current cpuStateTimes, cpuAttrs []attribute.KeyValue) {
+	total := current.total() - last.total()
+	if total <= 0 {
+		return
+	}
+
+	// The total CPU time is only added once per attribute set, the states
+	// of a CPU have the same attribute set if the "state" attribute is
+	// filtered.
+	added := make(map[*cpuUtilization]bool, 4)
+	for _, s := range []struct {
+		attrs []attribute.KeyValue
+		time  float64
+	}{
+		{AttributeCPUTimeUser, current.user - last.user},
+		{AttributeCPUTimeSystem, current.system - last.system},
+		{AttributeCPUTimeOther, current.other - last.other},
+		{AttributeCPUTimeIdle, current.idle - last.idle},
+	} {
+		attrs := u.h.attributes(s.attrs, cpuAttrs)
+		key := equivalent(attrs)
+		utilization, ok := u.utilizations[key]
+		if !ok {
+			utilization = &cpuUtilization{attrs: attrs}
+			u.utilizations[key] = utilization
+			u.order = append(u.order, utilization)
+		}
+		utilization.state += s.time
+		if !added[utilization] {
+			utilization.total += total
+			added[utilization] = true
+		}
+	}
+}
+
+// observe observes the fraction of CPU time spent in each state with
+// hostCPUUtilization.
+func (u *cpuUtilizations) observe(ctx context.Context, hostCPUUtilization asyncfloat64.Gauge) {
+	for _, utilization := range u.order {
+		hostCPUUtilization.Observe(ctx, utilization.state/utilization.total, utilization.attrs...)
+	}
+}
+
+func (h *Host) registerLoadAverage() error {
+	var (
+		err error
+
+		load1  asyncfloat64.Gauge
+		load5  asyncfloat64.Gauge
+		load15 asyncfloat64.Gauge
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if load1, err = h.meter.AsyncFloat64().Gauge(
+		"system.cpu.load_average.1m",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Average CPU load over the last minute"),
+	); err != nil {
+		return err
+	}
+
+	if load5, err = h.meter.AsyncFloat64().Gauge(
+		"system.cpu.load_average.5m",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Average CPU load over the last 5 minutes"),
+	); err != nil {
+		return err
+	}
+
+	if load15, err = h.meter.AsyncFloat64().Gauge(
+		"system.cpu.load_average.15m",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Average CPU load over the last 15 minutes"),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			load1,
+			load5,
+			load15,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var avg *load.AvgStat
+			if err := h.collect(ctx, "load average", func(ctx context.Context) (err error) {
+				avg, err = load.AvgWithContext(ctx)
+				return err
+			}); err != nil {
+				h.handleError("load average", err)
+				return
+			}
+
+			load1.Observe(ctx, avg.Load1)
+			load5.Observe(ctx, avg.Load5)
+			load15.Observe(ctx, avg.Load15)
+		})
+}
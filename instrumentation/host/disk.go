@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Attribute sets used for Disk measurements.
+var (
+	AttributeDiskRead  = []attribute.KeyValue{attribute.String("direction", "read")}
+	AttributeDiskWrite = []attribute.KeyValue{attribute.String("direction", "write")}
+)
+
+func (h *Host) registerDisk() error {
+	if !diskSupported {
+		return nil
+	}
+
+	var (
+		err error
+
+		diskIO         asyncint64.Counter
+		diskOperations asyncint64.Counter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if diskIO, err = h.meter.AsyncInt64().Counter(
+		"system.disk.io",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription(
+			"Bytes transferred attributed by device and direction (Read, Write)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if diskOperations, err = h.meter.AsyncInt64().Counter(
+		"system.disk.operations",
+		instrument.WithUnit("{operations}"),
+		instrument.WithDescription(
+			"Disk operations attributed by device and direction (Read, Write)",
+		),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			diskIO,
+			diskOperations,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var ioStats map[string]disk.IOCountersStat
+			if err := h.collect(ctx, "disk", func(ctx context.Context) (err error) {
+				ioStats, err = h.config.Provider.DiskIOCounters(ctx)
+				return err
+			}); err != nil {
+				h.handleError("disk", err)
+				return
+			}
+
+			// Devices are reported individually, summing them would
+			// count the I/O of partitions twice on some platforms.
+			io, operations := h.int64Sums(), h.int64Sums()
+			for name, ioStat := range ioStats {
+				deviceAttrs := []attribute.KeyValue{attribute.String("device", name)}
+
+				io.add(int64(ioStat.ReadBytes), AttributeDiskRead, deviceAttrs)
+				io.add(int64(ioStat.WriteBytes), AttributeDiskWrite, deviceAttrs)
+
+				operations.add(int64(ioStat.ReadCount), AttributeDiskRead, deviceAttrs)
+				operations.add(int64(ioStat.WriteCount), AttributeDiskWrite, deviceAttrs)
+			}
+			io.observe(ctx, diskIO)
+			operations.observe(ctx, diskOperations)
+		})
+}
@@ -24,10 +24,65 @@
 //   Name			Attribute
 // ----------------------------------------------------------------------
 //   process.cpu.time           state=user|system
+//   process.memory.usage
+//   process.memory.virtual
+//   process.open_file_descriptor.count
+//   process.threads
 //   system.cpu.time            state=user|system|other|idle
+//   system.cpu.utilization     state=user|system|other|idle
+//   system.cpu.logical.count
+//   system.cpu.physical.count
 //   system.memory.usage        state=used|available
 //   system.memory.utilization  state=used|available
 //   system.network.io          direction=transmit|receive
+//   system.network.errors      direction=transmit|receive
+//   system.network.dropped     direction=transmit|receive
+//   system.uptime
+//   system.paging.usage        state=used|free
+//   system.paging.utilization  state=used|free
+//   system.paging.operations   direction=page_in|page_out type=major|minor
+//   system.paging.faults       type=major|minor
+//   system.disk.io             direction=read|write device=...
+//   system.disk.operations     direction=read|write device=...
+//
+// The following metric events are only produced when enabled with the
+// corresponding Option.
+//
+//   Name				Option
+// ----------------------------------------------------------------------
+//   system.cpu.load_average.1m   WithLoadAverage
+//   system.cpu.load_average.5m   WithLoadAverage
+//   system.cpu.load_average.15m  WithLoadAverage
+//   system.network.connections   WithNetworkConnections (protocol=tcp state=ESTABLISHED|TIME_WAIT|...)
+//   container.memory.usage       WithCgroupLimits
+//   container.memory.limit       WithCgroupLimits
+//   container.cpu.time           WithCgroupLimits
+//   container.cpu.limit          WithCgroupLimits
+//   system.processes.count       WithProcessCount (status=running|sleeping|stopped|blocked|zombie|other)
+//   system.processes.created     WithProcessCount
+//
+// The system.cpu.utilization is the fraction of CPU time spent in each
+// state since the previous collection.
+//
+// The WithPerCPU Option adds a cpu attribute to system.cpu.time and
+// system.cpu.utilization identifying the logical CPU the time was spent
+// on.  Similarly, the WithPerInterfaceNetwork Option adds a device
+// attribute to the network metrics identifying the network interface.
+//
+// The groups of default metrics to report can be selected with the
+// WithMetrics Option.  Each group is collected independently, so a failure
+// to read the information of one group does not prevent the others from
+// being reported.
+//
+// Not every metric is available on every platform.  The
+// system.paging.operations and system.paging.faults metrics are only
+// reported on Linux, other platforms only report the paging usage and
+// utilization.  On Windows these are the usage of the page files, gopsutil
+// does not expose the Windows page fault counters.  The disk metrics are
+// reported on Linux, Windows, FreeBSD, OpenBSD, Solaris, AIX, and macOS when
+// built with cgo enabled.  The process.open_file_descriptor.count metric is
+// only reported on Linux and Solaris, and the process.threads metric on
+// Linux, Windows, macOS, and FreeBSD.
 //
 // See https://github.com/open-telemetry/oteps/blob/main/text/0119-standard-system-metrics.md
 // for the definition of these metric instruments.
@@ -16,29 +16,29 @@ package host // import "go.opentelemetry.io/contrib/instrumentation/host"
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"sync"
-
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
-	"github.com/shirou/gopsutil/v3/process"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/global"
 	"go.opentelemetry.io/otel/metric/instrument"
-	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
-	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
-	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
 )
 
 // Host reports the work-in-progress conventional host metrics specified by OpenTelemetry.
-type host struct {
+type Host struct {
 	config config
 	meter  metric.Meter
+
+	// timeouts counts the reads that exceeded the collection timeout.
+	timeouts syncint64.Counter
+
+	// shutdown is set to 1 once Shutdown has been called.
+	shutdown int32
 }
 
 // config contains optional settings for reporting host metrics.
@@ -46,6 +46,47 @@ type config struct {
 	// MeterProvider sets the metric.MeterProvider.  If nil, the global
 	// Provider will be used.
 	MeterProvider metric.MeterProvider
+
+	// Provider provides the host information metrics are computed from.
+	Provider Provider
+
+	// AttributeFilter filters the attributes of all measurements.  If
+	// nil, all attributes are reported.
+	AttributeFilter func(attribute.KeyValue) bool
+
+	// CollectionTimeout bounds the time each read of host information
+	// may take.  No timeout is applied if zero.
+	CollectionTimeout time.Duration
+
+	// ErrorHandler handles the errors that occur while collecting
+	// metrics.  If nil, the global otel ErrorHandler will be used.
+	ErrorHandler func(error)
+
+	// Metrics are the groups of metrics to report.
+	Metrics MetricGroup
+
+	// LoadAverage enables reporting of the system load average.  It
+	// is disabled by default because not every platform supports it.
+	LoadAverage bool
+
+	// PerCPU enables reporting of system.cpu.time per logical CPU.
+	PerCPU bool
+
+	// PerInterfaceNetwork enables reporting of network metrics per
+	// network interface.
+	PerInterfaceNetwork bool
+
+	// NetworkConnections enables reporting of the number of network
+	// connections of the host.
+	NetworkConnections bool
+
+	// CgroupLimits enables reporting of the resource usage and limits of
+	// the cgroup of the current process.
+	CgroupLimits bool
+
+	// Processes enables reporting of the number of processes on the
+	// host.
+	Processes bool
 }
 
 // Option supports configuring optional settings for host metrics.
@@ -68,6 +109,212 @@ func (o metricProviderOption) apply(c *config) {
 	}
 }
 
+// WithProvider sets the Provider of the host information the CPU, Memory,
+// Paging, and Network metrics are computed from.  If this option is not
+// used, the information of the host is read using gopsutil.  `provider`
+// must be non-nil.
+func WithProvider(provider Provider) Option {
+	return providerOption{provider}
+}
+
+type providerOption struct{ Provider }
+
+func (o providerOption) apply(c *config) {
+	if o.Provider != nil {
+		c.Provider = o.Provider
+	}
+}
+
+// WithAttributeFilter sets a filter applied to the attributes of all
+// reported measurements, only the attributes for which filter returns true
+// are reported.  This can be used to drop high-cardinality attributes, e.g.
+// the "cpu" or "device" attributes.
+//
+// Measurements of an instrument that have the same attributes after
+// filtering are reported as their sum, e.g. the I/O of all disks if the
+// "device" attribute is filtered.  The CPU utilization is reported as the
+// fraction of the combined CPU time of the CPUs.
+func WithAttributeFilter(filter func(attribute.KeyValue) bool) Option {
+	return attributeFilterOption(filter)
+}
+
+type attributeFilterOption func(attribute.KeyValue) bool
+
+func (o attributeFilterOption) apply(c *config) {
+	c.AttributeFilter = o
+}
+
+// WithCollectionTimeout sets the maximum duration of each read of host
+// information during a collection.  Reads that do not complete in time,
+// e.g. because of a degraded file system, are skipped so they do not stall
+// the collection of other metrics.  Skipped reads are passed to the error
+// handler and counted by the host.collection.timeouts metric.  This setting
+// is ignored when `d` is not positive.
+func WithCollectionTimeout(d time.Duration) Option {
+	return collectionTimeoutOption(d)
+}
+
+type collectionTimeoutOption time.Duration
+
+func (o collectionTimeoutOption) apply(c *config) {
+	if o > 0 {
+		c.CollectionTimeout = time.Duration(o)
+	}
+}
+
+// WithErrorHandler sets the function called with the errors that occur
+// while collecting host metrics.  Errors passed to handler identify the
+// subsystem (e.g. "cpu", "network", "cgroup") that failed to be collected.
+// If this option is not used, errors are passed to the global otel
+// ErrorHandler.  `handler` must be non-nil.
+func WithErrorHandler(handler func(error)) Option {
+	return errorHandlerOption(handler)
+}
+
+type errorHandlerOption func(error)
+
+func (o errorHandlerOption) apply(c *config) {
+	if o != nil {
+		c.ErrorHandler = o
+	}
+}
+
+// MetricGroup identifies a group of host metrics that can be enabled with
+// the WithMetrics Option.
+type MetricGroup uint
+
+const (
+	// Process is the group of metrics of the current process:
+	// process.cpu.time, process.memory.*, process.open_file_descriptor.count,
+	// and process.threads.
+	Process MetricGroup = 1 << iota
+	// CPU is the group of host CPU metrics: system.cpu.time,
+	// system.cpu.utilization, system.cpu.logical.count, and
+	// system.cpu.physical.count.
+	CPU
+	// Memory is the group of host memory metrics: system.memory.usage and
+	// system.memory.utilization.
+	Memory
+	// Paging is the group of host paging metrics: system.paging.*.
+	Paging
+	// Network is the group of host network metrics: system.network.*.
+	Network
+	// Uptime is the group of the system.uptime metric.
+	Uptime
+	// Disk is the group of host disk metrics: system.disk.*.
+	Disk
+
+	// defaultMetricGroups are the metric groups reported if the
+	// WithMetrics Option is not used.
+	defaultMetricGroups = Process | CPU | Memory | Paging | Network | Uptime | Disk
+)
+
+// WithMetrics sets the groups of metrics to report.  If this option is not
+// used, all groups are reported.  Groups that are not listed are neither
+// registered nor collected, which avoids reading system information that
+// is not accessible in some environments.
+//
+// The metrics enabled by WithLoadAverage, WithNetworkConnections,
+// WithCgroupLimits, and WithProcessCount are not part of any group and are reported independently of this option.
+func WithMetrics(groups ...MetricGroup) Option {
+	var g MetricGroup
+	for _, group := range groups {
+		g |= group
+	}
+	return metricsOption(g)
+}
+
+type metricsOption MetricGroup
+
+func (o metricsOption) apply(c *config) {
+	c.Metrics = MetricGroup(o)
+}
+
+// WithLoadAverage enables reporting of the system.cpu.load_average.*
+// metrics.  These are not reported by default since the load average
+// is not available on all platforms (e.g. Windows).
+func WithLoadAverage() Option {
+	return loadAverageOption{}
+}
+
+type loadAverageOption struct{}
+
+func (loadAverageOption) apply(c *config) {
+	c.LoadAverage = true
+}
+
+// WithPerCPU enables reporting of system.cpu.time per logical CPU with a
+// "cpu" attribute identifying the CPU.  By default the aggregate CPU time
+// of the host is reported to limit the number of attribute sets.
+func WithPerCPU() Option {
+	return perCPUOption{}
+}
+
+type perCPUOption struct{}
+
+func (perCPUOption) apply(c *config) {
+	c.PerCPU = true
+}
+
+// WithPerInterfaceNetwork enables reporting of network metrics per network
+// interface with a "device" attribute identifying the interface.  By
+// default the sum over all interfaces of the host is reported.
+func WithPerInterfaceNetwork() Option {
+	return perInterfaceNetworkOption{}
+}
+
+type perInterfaceNetworkOption struct{}
+
+func (perInterfaceNetworkOption) apply(c *config) {
+	c.PerInterfaceNetwork = true
+}
+
+// WithNetworkConnections enables reporting of the
+// system.network.connections metric.  This is not reported by default
+// since it requires listing all TCP connections of the host for every
+// collection, which can be expensive on busy hosts.
+func WithNetworkConnections() Option {
+	return networkConnectionsOption{}
+}
+
+type networkConnectionsOption struct{}
+
+func (networkConnectionsOption) apply(c *config) {
+	c.NetworkConnections = true
+}
+
+// WithCgroupLimits enables reporting of the container.memory.usage,
+// container.memory.limit, container.cpu.time, and container.cpu.limit
+// metrics read from the cgroup (v1 or v2) of the current process.  Inside
+// a container the system.* metrics report the resources of the node, these
+// metrics report the resources available to the container instead.
+//
+// Cgroups are only supported on Linux, NewHost returns an error if the
+// cgroup of the current process cannot be read.
+func WithCgroupLimits() Option {
+	return cgroupLimitsOption{}
+}
+
+type cgroupLimitsOption struct{}
+
+func (cgroupLimitsOption) apply(c *config) {
+	c.CgroupLimits = true
+}
+
+// WithProcessCount enables reporting of the system.processes.count and
+// system.processes.created metrics.  These are not reported by default
+// since counting processes by status requires listing all processes of the
+// host for every collection, which can be expensive on busy hosts.
+func WithProcessCount() Option {
+	return processCountOption{}
+}
+
+type processCountOption struct{}
+
+func (processCountOption) apply(c *config) {
+	c.Processes = true
+}
+
 // Attribute sets.
 var (
 	// Attribute sets for CPU time measurements.
@@ -92,6 +339,9 @@ var (
 func newConfig(opts ...Option) config {
 	c := config{
 		MeterProvider: global.MeterProvider(),
+		Provider:      gopsutilProvider{},
+		ErrorHandler:  otel.Handle,
+		Metrics:       defaultMetricGroups,
 	}
 	for _, opt := range opts {
 		opt.apply(&c)
@@ -101,193 +351,240 @@ func newConfig(opts ...Option) config {
 
 // Start initializes reporting of host metrics using the supplied config.
 func Start(opts ...Option) error {
+	_, err := NewHost(opts...)
+	return err
+}
+
+// NewHost initializes reporting of host metrics using the supplied config
+// and returns a Host that can be used to stop the reporting.
+func NewHost(opts ...Option) (*Host, error) {
 	c := newConfig(opts...)
 	if c.MeterProvider == nil {
 		c.MeterProvider = global.MeterProvider()
 	}
-	h := &host{
+	h := &Host{
 		meter: c.MeterProvider.Meter(
 			"go.opentelemetry.io/contrib/instrumentation/host",
 			metric.WithInstrumentationVersion(SemVersion()),
 		),
 		config: c,
 	}
-	return h.register()
+	if c.CollectionTimeout > 0 {
+		var err error
+		if h.timeouts, err = h.meter.SyncInt64().Counter(
+			"host.collection.timeouts",
+			instrument.WithDescription("Number of reads of host information skipped because they exceeded the collection timeout"),
+		); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range []struct {
+		group    MetricGroup
+		register func() error
+	}{
+		{Process, h.registerProcess},
+		{CPU, h.registerCPU},
+		{Memory, h.registerMemory},
+		{Paging, h.registerPaging},
+		{Network, h.registerNetwork},
+		{Uptime, h.registerUptime},
+		{Disk, h.registerDisk},
+	} {
+		if c.Metrics&group.group == 0 {
+			continue
+		}
+		if err := group.register(); err != nil {
+			return nil, err
+		}
+	}
+	if c.LoadAverage {
+		if err := h.registerLoadAverage(); err != nil {
+			return nil, err
+		}
+	}
+	if c.NetworkConnections {
+		if err := h.registerNetworkConnections(); err != nil {
+			return nil, err
+		}
+	}
+	if c.CgroupLimits {
+		if err := h.registerCgroup(); err != nil {
+			return nil, err
+		}
+	}
+	if c.Processes {
+		if err := h.registerProcesses(); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
 }
 
-func (h *host) register() error {
-	var (
-		err error
+// Shutdown stops the reporting of host metrics.  After Shutdown returns no
+// further measurements are made by h and a new Host can be started with
+// the same MeterProvider without reporting duplicate measurements.
+//
+// The metric API does not support unregistering callbacks, the callbacks
+// registered by h are therefore kept by the MeterProvider but do not
+// observe any values.
+func (h *Host) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&h.shutdown, 1)
+	return ctx.Err()
+}
 
-		processCPUTime asyncfloat64.Counter
-		hostCPUTime    asyncfloat64.Counter
+// stopped returns whether h has been shut down.
+func (h *Host) stopped() bool {
+	return atomic.LoadInt32(&h.shutdown) == 1
+}
 
-		hostMemoryUsage       asyncint64.Gauge
-		hostMemoryUtilization asyncfloat64.Gauge
+// handleError passes err, which occurred while collecting the metrics of
+// subsystem, to the configured error handler.
+func (h *Host) handleError(subsystem string, err error) {
+	h.config.ErrorHandler(fmt.Errorf("host %s metrics: %w", subsystem, err))
+}
 
-		networkIOUsage asyncint64.Counter
+// collect calls read with ctx bounded by the configured collection timeout.
+// If read does not return in time, collect returns an error without
+// waiting for read to return and counts the timeout for subsystem.  Values
+// set by read must therefore only be used if collect returns no error.
+func (h *Host) collect(ctx context.Context, subsystem string, read func(context.Context) error) error {
+	timeout := h.config.CollectionTimeout
+	if timeout <= 0 {
+		return read(ctx)
+	}
 
-		// lock prevents a race between batch observer and instrument registration.
-		lock sync.Mutex
-	)
+	readCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	proc, err := process.NewProcess(int32(os.Getpid()))
-	if err != nil {
-		return fmt.Errorf("could not find this process: %w", err)
-	}
+	done := make(chan error, 1)
+	go func() { done <- read(readCtx) }()
 
-	lock.Lock()
-	defer lock.Unlock()
-
-	// TODO: .time units are in seconds, but "unit" package does
-	// not include this string.
-	// https://github.com/open-telemetry/opentelemetry-specification/issues/705
-	if processCPUTime, err = h.meter.AsyncFloat64().Counter(
-		"process.cpu.time",
-		instrument.WithUnit("s"),
-		instrument.WithDescription(
-			"Accumulated CPU time spent by this process attributeed by state (User, System, ...)",
-		),
-	); err != nil {
-		return err
+	var err error
+	select {
+	case err = <-done:
+	case <-readCtx.Done():
+		err = readCtx.Err()
 	}
-
-	if hostCPUTime, err = h.meter.AsyncFloat64().Counter(
-		"system.cpu.time",
-		instrument.WithUnit("s"),
-		instrument.WithDescription(
-			"Accumulated CPU time spent by this host attributeed by state (User, System, Other, Idle)",
-		),
-	); err != nil {
-		return err
+	// Only count timeouts of the read, not the cancellation of ctx.
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		h.timeouts.Add(ctx, 1, attribute.String("subsystem", subsystem))
+		return fmt.Errorf("collection timed out after %v: %w", timeout, err)
 	}
+	return err
+}
 
-	if hostMemoryUsage, err = h.meter.AsyncInt64().Gauge(
-		"system.memory.usage",
-		instrument.WithUnit(unit.Bytes),
-		instrument.WithDescription(
-			"Memory usage of this process attributed by memory state (Used, Available)",
-		),
-	); err != nil {
-		return err
+// attributes returns the attributes of sets, in order, that are accepted
+// by the configured attribute filter.
+func (h *Host) attributes(sets ...[]attribute.KeyValue) []attribute.KeyValue {
+	var n, nonEmpty int
+	for i, set := range sets {
+		if len(set) > 0 {
+			n += len(set)
+			nonEmpty = i
+		}
+	}
+	// Avoid copying a single set if there is nothing to filter.
+	if h.config.AttributeFilter == nil && n == len(sets[nonEmpty]) {
+		return sets[nonEmpty]
 	}
 
-	if hostMemoryUtilization, err = h.meter.AsyncFloat64().Gauge(
-		"system.memory.utilization",
-		instrument.WithUnit(unit.Dimensionless),
-		instrument.WithDescription(
-			"Memory utilization of this process attributeed by memory state (Used, Available)",
-		),
-	); err != nil {
-		return err
+	attrs := make([]attribute.KeyValue, 0, n)
+	for _, set := range sets {
+		for _, kv := range set {
+			if h.config.AttributeFilter == nil || h.config.AttributeFilter(kv) {
+				attrs = append(attrs, kv)
+			}
+		}
 	}
+	return attrs
+}
 
-	if networkIOUsage, err = h.meter.AsyncInt64().Counter(
-		"system.network.io",
-		instrument.WithUnit(unit.Bytes),
-		instrument.WithDescription(
-			"Bytes transferred attributeed by direction (Transmit, Receive)",
-		),
-	); err != nil {
-		return err
+// equivalent returns a value that is equal for equal attribute sets, to be
+// used as a map key.
+func equivalent(attrs []attribute.KeyValue) attribute.Distinct {
+	set := attribute.NewSet(attrs...)
+	return set.Equivalent()
+}
+
+// int64Sum is the sum of the values observed with an attribute set.
+type int64Sum struct {
+	attrs []attribute.KeyValue
+	value int64
+}
+
+// int64Sums sums the values observed with the same attribute set after
+// filtering, so measurements that only differ by filtered attributes are
+// reported as their total instead of overwriting each other.
+type int64Sums struct {
+	h    *Host
+	sums map[attribute.Distinct]*int64Sum
+	// order keeps the observation order of the attribute sets.
+	order []*int64Sum
+}
+
+func (h *Host) int64Sums() *int64Sums {
+	return &int64Sums{h: h, sums: make(map[attribute.Distinct]*int64Sum)}
+}
+
+// add adds value to the sum of the attributes of sets accepted by the
+// configured attribute filter.
+func (s *int64Sums) add(value int64, sets ...[]attribute.KeyValue) {
+	attrs := s.h.attributes(sets...)
+	key := equivalent(attrs)
+	if sum, ok := s.sums[key]; ok {
+		sum.value += value
+		return
 	}
+	sum := &int64Sum{attrs: attrs, value: value}
+	s.sums[key] = sum
+	s.order = append(s.order, sum)
+}
 
-	err = h.meter.RegisterCallback(
-		[]instrument.Asynchronous{
-			processCPUTime,
-			hostCPUTime,
-			hostMemoryUsage,
-			hostMemoryUtilization,
-			networkIOUsage,
-		},
-		func(ctx context.Context) {
-			lock.Lock()
-			defer lock.Unlock()
-
-			// This follows the OpenTelemetry Collector's "hostmetrics"
-			// receiver/hostmetricsreceiver/internal/scraper/processscraper
-			// measures User and System IOwait time.
-			// TODO: the Collector has per-OS compilation modules to support
-			// specific metrics that are not universal.
-			processTimes, err := proc.TimesWithContext(ctx)
-			if err != nil {
-				otel.Handle(err)
-				return
-			}
+// observe observes the sums with inst.
+func (s *int64Sums) observe(ctx context.Context, inst interface {
+	Observe(context.Context, int64, ...attribute.KeyValue)
+}) {
+	for _, sum := range s.order {
+		inst.Observe(ctx, sum.value, sum.attrs...)
+	}
+}
 
-			hostTimeSlice, err := cpu.TimesWithContext(ctx, false)
-			if err != nil {
-				otel.Handle(err)
-				return
-			}
-			if len(hostTimeSlice) != 1 {
-				otel.Handle(fmt.Errorf("host CPU usage: incorrect summary count"))
-				return
-			}
+// float64Sum is the sum of the values observed with an attribute set.
+type float64Sum struct {
+	attrs []attribute.KeyValue
+	value float64
+}
 
-			vmStats, err := mem.VirtualMemoryWithContext(ctx)
-			if err != nil {
-				otel.Handle(err)
-				return
-			}
+// float64Sums is the float64 counterpart of int64Sums.
+type float64Sums struct {
+	h     *Host
+	sums  map[attribute.Distinct]*float64Sum
+	order []*float64Sum
+}
 
-			ioStats, err := net.IOCountersWithContext(ctx, false)
-			if err != nil {
-				otel.Handle(err)
-				return
-			}
-			if len(ioStats) != 1 {
-				otel.Handle(fmt.Errorf("host network usage: incorrect summary count"))
-				return
-			}
+func (h *Host) float64Sums() *float64Sums {
+	return &float64Sums{h: h, sums: make(map[attribute.Distinct]*float64Sum)}
+}
 
-			// Process CPU time
-			processCPUTime.Observe(ctx, processTimes.User, AttributeCPUTimeUser...)
-			processCPUTime.Observe(ctx, processTimes.System, AttributeCPUTimeSystem...)
-
-			// Host CPU time
-			hostTime := hostTimeSlice[0]
-			hostCPUTime.Observe(ctx, hostTime.User, AttributeCPUTimeUser...)
-			hostCPUTime.Observe(ctx, hostTime.System, AttributeCPUTimeSystem...)
-
-			// TODO(#244): "other" is a placeholder for actually dealing
-			// with these states.  Do users actually want this
-			// (unconditionally)?  How should we handle "iowait"
-			// if not all systems expose it?  Should we break
-			// these down by CPU?  If so, are users going to want
-			// to aggregate in-process?  See:
-			// https://github.com/open-telemetry/opentelemetry-go-contrib/issues/244
-			other := hostTime.Nice +
-				hostTime.Iowait +
-				hostTime.Irq +
-				hostTime.Softirq +
-				hostTime.Steal +
-				hostTime.Guest +
-				hostTime.GuestNice
-
-			hostCPUTime.Observe(ctx, other, AttributeCPUTimeOther...)
-			hostCPUTime.Observe(ctx, hostTime.Idle, AttributeCPUTimeIdle...)
-
-			// Host memory usage
-			hostMemoryUsage.Observe(ctx, int64(vmStats.Used), AttributeMemoryUsed...)
-			hostMemoryUsage.Observe(ctx, int64(vmStats.Available), AttributeMemoryAvailable...)
-
-			// Host memory utilization
-			hostMemoryUtilization.Observe(ctx, float64(vmStats.Used)/float64(vmStats.Total), AttributeMemoryUsed...)
-			hostMemoryUtilization.Observe(ctx, float64(vmStats.Available)/float64(vmStats.Total), AttributeMemoryAvailable...)
-
-			// Host network usage
-			//
-			// TODO: These can be broken down by network
-			// interface, with similar questions to those posed
-			// about per-CPU measurements above.
-			networkIOUsage.Observe(ctx, int64(ioStats[0].BytesSent), AttributeNetworkTransmit...)
-			networkIOUsage.Observe(ctx, int64(ioStats[0].BytesRecv), AttributeNetworkReceive...)
-		})
-
-	if err != nil {
-		return err
+// add adds value to the sum of the attributes of sets accepted by the
+// configured attribute filter.
+func (s *float64Sums) add(value float64, sets ...[]attribute.KeyValue) {
+	attrs := s.h.attributes(sets...)
+	key := equivalent(attrs)
+	if sum, ok := s.sums[key]; ok {
+		sum.value += value
+		return
 	}
+	sum := &float64Sum{attrs: attrs, value: value}
+	s.sums[key] = sum
+	s.order = append(s.order, sum)
+}
 
-	return nil
+// observe observes the sums with inst.
+func (s *float64Sums) observe(ctx context.Context, inst interface {
+	Observe(context.Context, float64, ...attribute.KeyValue)
+}) {
+	for _, sum := range s.order {
+		inst.Observe(ctx, sum.value, sum.attrs...)
+	}
 }
@@ -19,10 +19,13 @@ import (
 	"fmt"
 	gonet "net"
 	"os"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
@@ -35,17 +38,9 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/metrictest"
 )
 
-func getMetric(exp *metrictest.Exporter, name string, lbl attribute.KeyValue) float64 {
+func getMetric(exp *metrictest.Exporter, name string, lbls ...attribute.KeyValue) float64 {
 	for _, r := range exp.GetRecords() {
-		foundAttribute := false
-		for _, haveLabel := range r.Attributes {
-			if haveLabel != lbl {
-				continue
-			}
-			foundAttribute = true
-			break
-		}
-		if !foundAttribute {
+		if !hasAttributes(r.Attributes, lbls) {
 			continue
 		}
 
@@ -64,6 +59,76 @@ func getMetric(exp *metrictest.Exporter, name string, lbl attribute.KeyValue) fl
 	panic("Could not locate a metric in test output")
 }
 
+// hasAttributes returns whether have contains every attribute of want.
+func hasAttributes(have, want []attribute.KeyValue) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHostShutdown(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	h, err := host.NewHost(
+		host.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+	_, err = exp.GetByName("system.memory.usage")
+	require.NoError(t, err)
+
+	require.NoError(t, h.Shutdown(ctx))
+	require.NoError(t, exp.Collect(ctx))
+	assert.Empty(t, exp.GetRecords())
+
+	// Restarting must not report duplicate measurements.
+	h, err = host.NewHost(
+		host.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+	require.NoError(t, exp.Collect(ctx))
+	var records int
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName == "system.memory.usage" {
+			records++
+		}
+	}
+	assert.Equal(t, len(host.AttributeMemoryUsed)+len(host.AttributeMemoryAvailable), records)
+	require.NoError(t, h.Shutdown(ctx))
+}
+
+func TestHostWithMetrics(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithMetrics(host.Memory, host.Network),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	for _, name := range []string{"system.memory.usage", "system.network.io"} {
+		_, err = exp.GetByName(name)
+		assert.NoErrorf(t, err, "missing %s", name)
+	}
+	for _, name := range []string{"process.cpu.time", "system.cpu.time", "system.paging.usage"} {
+		_, err = exp.GetByName(name)
+		assert.Errorf(t, err, "unexpected %s", name)
+	}
+}
+
 func TestHostCPU(t *testing.T) {
 	provider, exp := metrictest.NewTestMeterProvider()
 	err := host.Start(
@@ -135,6 +200,142 @@ func TestHostCPU(t *testing.T) {
 	// are difficult to test.
 }
 
+func TestProcessMemory(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	usage, err := exp.GetByName("process.memory.usage")
+	require.NoError(t, err)
+	rss := usage.Sum.CoerceToFloat64(usage.NumberKind)
+	assert.Greater(t, rss, 0.0)
+
+	virtual, err := exp.GetByName("process.memory.virtual")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, virtual.Sum.CoerceToFloat64(virtual.NumberKind), rss)
+}
+
+func TestProcessFileDescriptorsAndThreads(t *testing.T) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	if _, err = proc.NumFDsWithContext(ctx); err != nil {
+		t.Skipf("file descriptor count not supported: %v", err)
+	}
+
+	f, err := os.Open(os.Args[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	err = host.Start(
+		host.WithMeterProvider(provider),
+	)
+	assert.NoError(t, err)
+
+	require.NoError(t, exp.Collect(ctx))
+
+	fds, err := exp.GetByName("process.open_file_descriptor.count")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, fds.Sum.CoerceToFloat64(fds.NumberKind), 1.0)
+
+	threads, err := exp.GetByName("process.threads")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, threads.Sum.CoerceToFloat64(threads.NumberKind), 1.0)
+}
+
+func TestHostCPUUtilization(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	// Spend some CPU time so utilization can be computed.
+	start := time.Now()
+	for time.Since(start) < time.Second/2 {
+		_, err = cpu.TimesWithContext(ctx, false)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, exp.Collect(ctx))
+
+	var total float64
+	for _, attrs := range [][]attribute.KeyValue{
+		host.AttributeCPUTimeUser,
+		host.AttributeCPUTimeSystem,
+		host.AttributeCPUTimeOther,
+		host.AttributeCPUTimeIdle,
+	} {
+		util := getMetric(exp, "system.cpu.utilization", attrs[0])
+		assert.GreaterOrEqual(t, util, 0.0)
+		assert.LessOrEqual(t, util, 1.0)
+		total += util
+	}
+	assert.InDelta(t, 1.0, total, 0.0001)
+}
+
+func TestHostPerCPU(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithPerCPU(),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	perCPU, err := cpu.TimesWithContext(ctx, true)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(ctx))
+
+	var records int
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName == "system.cpu.time" {
+			records++
+		}
+	}
+	// One record per state for each CPU.
+	assert.Equal(t, 4*len(perCPU), records)
+
+	for _, stat := range perCPU {
+		assert.GreaterOrEqual(t, getMetric(exp, "system.cpu.time", attribute.String("cpu", stat.CPU)), 0.0)
+	}
+}
+
+func TestHostLoadAverage(t *testing.T) {
+	ctx := context.Background()
+	if _, err := load.AvgWithContext(ctx); err != nil {
+		t.Skipf("load average not supported: %v", err)
+	}
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithLoadAverage(),
+	)
+	assert.NoError(t, err)
+
+	require.NoError(t, exp.Collect(ctx))
+
+	for _, name := range []string{
+		"system.cpu.load_average.1m",
+		"system.cpu.load_average.5m",
+		"system.cpu.load_average.15m",
+	} {
+		r, err := exp.GetByName(name)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, r.LastValue.CoerceToFloat64(r.NumberKind), 0.0)
+	}
+}
+
 func TestHostMemory(t *testing.T) {
 	provider, exp := metrictest.NewTestMeterProvider()
 	err := host.Start(
@@ -171,6 +372,81 @@ func TestHostMemory(t *testing.T) {
 	}
 }
 
+func TestHostPaging(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	swap, err := mem.SwapMemoryWithContext(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(ctx))
+
+	pagingUsed := getMetric(exp, "system.paging.usage", host.AttributePagingUsed[0])
+	assert.GreaterOrEqual(t, pagingUsed, 0.0)
+	assert.LessOrEqual(t, pagingUsed, float64(swap.Total))
+
+	pagingFree := getMetric(exp, "system.paging.usage", host.AttributePagingFree[0])
+	assert.GreaterOrEqual(t, pagingFree, 0.0)
+	assert.LessOrEqual(t, pagingFree, float64(swap.Total))
+
+	if swap.Total > 0 {
+		pagingUsedUtil := getMetric(exp, "system.paging.utilization", host.AttributePagingUsed[0])
+		assert.GreaterOrEqual(t, pagingUsedUtil, 0.0)
+		assert.LessOrEqual(t, pagingUsedUtil, 1.0)
+	}
+
+	if runtime.GOOS != "linux" {
+		// Paging operations and faults are only reported on Linux.
+		_, err = exp.GetByName("system.paging.faults")
+		assert.Error(t, err)
+		return
+	}
+
+	majorFaults := getMetric(exp, "system.paging.faults", host.AttributePagingFaultMajor[0])
+	assert.GreaterOrEqual(t, majorFaults, 0.0)
+}
+
+func TestHostProcesses(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProcessCount(),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	var total float64
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName == "system.processes.count" {
+			total += r.Sum.CoerceToFloat64(r.NumberKind)
+		}
+	}
+	// At least this process is running.
+	assert.GreaterOrEqual(t, total, 1.0)
+	assert.GreaterOrEqual(t, getMetric(exp, "system.processes.count", host.AttributeProcessesRunning[0]), 0.0)
+}
+
+func TestHostUptime(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	uptime, err := exp.GetByName("system.uptime")
+	require.NoError(t, err)
+	assert.Greater(t, uptime.Sum.CoerceToFloat64(uptime.NumberKind), 0.0)
+}
+
 func sendBytes(t *testing.T, count int) error {
 	conn1, err := gonet.ListenPacket("udp", "127.0.0.1:0")
 	if err != nil {
@@ -238,4 +514,323 @@ func TestHostNetwork(t *testing.T) {
 	// Check that the recorded measurements reflect the same change:
 	require.LessOrEqual(t, uint64(howMuch), uint64(hostTransmit)-hostBefore[0].BytesSent)
 	require.LessOrEqual(t, uint64(howMuch), uint64(hostReceive)-hostBefore[0].BytesRecv)
+
+	for _, name := range []string{"system.network.errors", "system.network.dropped"} {
+		assert.GreaterOrEqual(t, getMetric(exp, name, host.AttributeNetworkTransmit[0]), 0.0)
+		assert.GreaterOrEqual(t, getMetric(exp, name, host.AttributeNetworkReceive[0]), 0.0)
+	}
+}
+
+func TestHostPerInterfaceNetwork(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithPerInterfaceNetwork(),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	perNIC, err := net.IOCountersWithContext(ctx, true)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(ctx))
+
+	var records int
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName == "system.network.io" {
+			records++
+		}
+	}
+	// One record per direction for each interface.
+	assert.Equal(t, 2*len(perNIC), records)
+
+	for _, stat := range perNIC {
+		assert.GreaterOrEqual(t, getMetric(exp, "system.network.io", attribute.String("device", stat.Name)), 0.0)
+	}
+}
+
+func TestHostNetworkConnections(t *testing.T) {
+	ln, err := gonet.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	err = host.Start(
+		host.WithMeterProvider(provider),
+		host.WithNetworkConnections(),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	listen := getMetric(exp, "system.network.connections", attribute.String("state", "LISTEN"))
+	assert.GreaterOrEqual(t, listen, 1.0)
+}
+
+func TestHostCgroupLimits(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	_, err := host.NewHost(
+		host.WithMeterProvider(provider),
+		host.WithCgroupLimits(),
+	)
+	if err != nil {
+		t.Skipf("cgroup limits not supported: %v", err)
+	}
+
+	ctx := context.Background()
+	require.NoError(t, exp.Collect(ctx))
+
+	usage, err := exp.GetByName("container.memory.usage")
+	require.NoError(t, err)
+	assert.Greater(t, usage.Sum.CoerceToFloat64(usage.NumberKind), 0.0)
+
+	cpuTime, err := exp.GetByName("container.cpu.time")
+	require.NoError(t, err)
+	assert.Greater(t, cpuTime.Sum.CoerceToFloat64(cpuTime.NumberKind), 0.0)
+}
+
+type fakeProvider struct {
+	err error
+}
+
+func (p fakeProvider) CPUTimes(context.Context, bool) ([]cpu.TimesStat, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []cpu.TimesStat{{CPU: "cpu-total", User: 1, System: 2, Nice: 1, Idle: 4}}, nil
+}
+
+func (p fakeProvider) CPUCounts(_ context.Context, logical bool) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	if logical {
+		return 8, nil
+	}
+	return 4, nil
+}
+
+func (p fakeProvider) VirtualMemory(context.Context) (*mem.VirtualMemoryStat, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &mem.VirtualMemoryStat{Total: 100, Used: 75, Available: 25}, nil
+}
+
+func (p fakeProvider) SwapMemory(context.Context) (*mem.SwapMemoryStat, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &mem.SwapMemoryStat{Total: 10, Used: 4, Free: 6, PgFault: 7, PgMajFault: 2}, nil
+}
+
+func (p fakeProvider) NetIOCounters(context.Context, bool) ([]net.IOCountersStat, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []net.IOCountersStat{{Name: "all", BytesSent: 10, BytesRecv: 20, Errin: 1, Dropout: 2}}, nil
+}
+
+func (p fakeProvider) DiskIOCounters(context.Context) (map[string]disk.IOCountersStat, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return map[string]disk.IOCountersStat{
+		"sda": {Name: "sda", ReadBytes: 30, WriteBytes: 40, ReadCount: 3, WriteCount: 4},
+	}, nil
+}
+
+func TestHostWithProvider(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(fakeProvider{}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	assert.Equal(t, 1.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeUser[0]))
+	assert.Equal(t, 2.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeSystem[0]))
+	assert.Equal(t, 1.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeOther[0]))
+	assert.Equal(t, 4.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeIdle[0]))
+
+	logical, err := exp.GetByName("system.cpu.logical.count")
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, logical.Sum.CoerceToFloat64(logical.NumberKind))
+	physical, err := exp.GetByName("system.cpu.physical.count")
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, physical.Sum.CoerceToFloat64(physical.NumberKind))
+
+	assert.Equal(t, 75.0, getMetric(exp, "system.memory.usage", host.AttributeMemoryUsed[0]))
+	assert.Equal(t, 0.25, getMetric(exp, "system.memory.utilization", host.AttributeMemoryAvailable[0]))
+
+	assert.Equal(t, 0.4, getMetric(exp, "system.paging.utilization", host.AttributePagingUsed[0]))
+	if runtime.GOOS == "linux" {
+		assert.Equal(t, 5.0, getMetric(exp, "system.paging.faults", host.AttributePagingFaultMinor[0]))
+	}
+
+	assert.Equal(t, 10.0, getMetric(exp, "system.network.io", host.AttributeNetworkTransmit[0]))
+	assert.Equal(t, 1.0, getMetric(exp, "system.network.errors", host.AttributeNetworkReceive[0]))
+	assert.Equal(t, 2.0, getMetric(exp, "system.network.dropped", host.AttributeNetworkTransmit[0]))
+}
+
+func TestHostDisk(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "windows" {
+		t.Skipf("disk metrics are not tested on %s", runtime.GOOS)
+	}
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(fakeProvider{}),
+		host.WithMetrics(host.Disk),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	assert.Equal(t, 30.0, getMetric(exp, "system.disk.io", host.AttributeDiskRead[0]))
+	assert.Equal(t, 40.0, getMetric(exp, "system.disk.io", host.AttributeDiskWrite[0]))
+	assert.Equal(t, 3.0, getMetric(exp, "system.disk.operations", host.AttributeDiskRead[0]))
+	assert.Equal(t, 4.0, getMetric(exp, "system.disk.operations", host.AttributeDiskWrite[0]))
+	assert.Equal(t, 30.0, getMetric(exp, "system.disk.io", host.AttributeDiskRead[0], attribute.String("device", "sda")))
+}
+
+func TestHostWithErrorHandler(t *testing.T) {
+	var errs []error
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(fakeProvider{err: os.ErrPermission}),
+		host.WithMetrics(host.Process, host.Memory),
+		host.WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], os.ErrPermission)
+	assert.Contains(t, errs[0].Error(), "memory")
+
+	// A failing group does not prevent the others from being reported.
+	_, err = exp.GetByName("process.cpu.time")
+	assert.NoError(t, err)
+}
+
+func TestHostWithAttributeFilter(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(fakeProvider{}),
+		host.WithMetrics(host.CPU, host.Network),
+		host.WithPerCPU(),
+		host.WithPerInterfaceNetwork(),
+		host.WithAttributeFilter(func(kv attribute.KeyValue) bool {
+			return kv.Key != "cpu" && kv.Key != "device"
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	for _, r := range exp.GetRecords() {
+		for _, kv := range r.Attributes {
+			assert.NotEqual(t, attribute.Key("cpu"), kv.Key)
+			assert.NotEqual(t, attribute.Key("device"), kv.Key)
+		}
+	}
+	assert.Equal(t, 1.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeUser[0]))
+	assert.Equal(t, 20.0, getMetric(exp, "system.network.io", host.AttributeNetworkReceive[0]))
+}
+
+// multiDeviceProvider reports two CPUs, disks and network interfaces.
+type multiDeviceProvider struct {
+	fakeProvider
+}
+
+func (p multiDeviceProvider) CPUTimes(context.Context, bool) ([]cpu.TimesStat, error) {
+	return []cpu.TimesStat{
+		{CPU: "cpu0", User: 1, System: 2, Idle: 5},
+		{CPU: "cpu1", User: 3, System: 2, Idle: 3},
+	}, nil
+}
+
+func (p multiDeviceProvider) NetIOCounters(context.Context, bool) ([]net.IOCountersStat, error) {
+	return []net.IOCountersStat{
+		{Name: "eth0", BytesSent: 10, BytesRecv: 20},
+		{Name: "eth1", BytesSent: 1, BytesRecv: 2},
+	}, nil
+}
+
+func (p multiDeviceProvider) DiskIOCounters(context.Context) (map[string]disk.IOCountersStat, error) {
+	return map[string]disk.IOCountersStat{
+		"sda": {Name: "sda", ReadBytes: 30, WriteBytes: 40},
+		"sdb": {Name: "sdb", ReadBytes: 3, WriteBytes: 4},
+	}, nil
+}
+
+func TestHostWithAttributeFilterSumsMeasurements(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(multiDeviceProvider{}),
+		host.WithMetrics(host.CPU, host.Network, host.Disk),
+		host.WithPerCPU(),
+		host.WithPerInterfaceNetwork(),
+		host.WithAttributeFilter(func(kv attribute.KeyValue) bool {
+			return kv.Key != "cpu" && kv.Key != "device"
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	assert.Equal(t, 4.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeUser[0]))
+	assert.Equal(t, 8.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeIdle[0]))
+	assert.Equal(t, 11.0, getMetric(exp, "system.network.io", host.AttributeNetworkTransmit[0]))
+	assert.Equal(t, 22.0, getMetric(exp, "system.network.io", host.AttributeNetworkReceive[0]))
+	assert.Equal(t, 33.0, getMetric(exp, "system.disk.io", host.AttributeDiskRead[0]))
+	assert.Equal(t, 44.0, getMetric(exp, "system.disk.io", host.AttributeDiskWrite[0]))
+}
+
+// blockingProvider blocks reading the memory statistics until unblock is
+// closed.
+type blockingProvider struct {
+	fakeProvider
+	unblock chan struct{}
+}
+
+func (p blockingProvider) VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	<-p.unblock
+	return p.fakeProvider.VirtualMemory(ctx)
+}
+
+func TestHostWithCollectionTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	var errs []error
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := host.Start(
+		host.WithMeterProvider(provider),
+		host.WithProvider(blockingProvider{unblock: unblock}),
+		host.WithMetrics(host.CPU, host.Memory),
+		host.WithCollectionTimeout(10*time.Millisecond),
+		host.WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	assert.Contains(t, errs[0].Error(), "memory")
+
+	assert.Equal(t, 1.0, getMetric(exp, "host.collection.timeouts", attribute.String("subsystem", "memory")))
+	_, err = exp.GetByName("system.memory.usage")
+	assert.Error(t, err)
+	assert.Equal(t, 1.0, getMetric(exp, "system.cpu.time", host.AttributeCPUTimeUser[0]))
 }
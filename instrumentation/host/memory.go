@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+func (h *Host) registerMemory() error {
+	var (
+		err error
+
+		hostMemoryUsage       asyncint64.Gauge
+		hostMemoryUtilization asyncfloat64.Gauge
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if hostMemoryUsage, err = h.meter.AsyncInt64().Gauge(
+		"system.memory.usage",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription(
+			"Memory usage of this process attributed by memory state (Used, Available)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if hostMemoryUtilization, err = h.meter.AsyncFloat64().Gauge(
+		"system.memory.utilization",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription(
+			"Memory utilization of this process attributeed by memory state (Used, Available)",
+		),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			hostMemoryUsage,
+			hostMemoryUtilization,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var vmStats *mem.VirtualMemoryStat
+			if err := h.collect(ctx, "memory", func(ctx context.Context) (err error) {
+				vmStats, err = h.config.Provider.VirtualMemory(ctx)
+				return err
+			}); err != nil {
+				h.handleError("memory", err)
+				return
+			}
+
+			// Host memory usage
+			usage := h.int64Sums()
+			usage.add(int64(vmStats.Used), AttributeMemoryUsed)
+			usage.add(int64(vmStats.Available), AttributeMemoryAvailable)
+			usage.observe(ctx, hostMemoryUsage)
+
+			// Host memory utilization
+			utilization := h.float64Sums()
+			utilization.add(float64(vmStats.Used)/float64(vmStats.Total), AttributeMemoryUsed)
+			utilization.add(float64(vmStats.Available)/float64(vmStats.Total), AttributeMemoryAvailable)
+			utilization.observe(ctx, hostMemoryUtilization)
+		})
+}
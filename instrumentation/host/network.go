@@ -0,0 +1,199 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+func (h *Host) registerNetwork() error {
+	var (
+		err error
+
+		networkIOUsage asyncint64.Counter
+		networkErrors  asyncint64.Counter
+		networkDropped asyncint64.Counter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if networkIOUsage, err = h.meter.AsyncInt64().Counter(
+		"system.network.io",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription(
+			"Bytes transferred attributeed by direction (Transmit, Receive)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if networkErrors, err = h.meter.AsyncInt64().Counter(
+		"system.network.errors",
+		instrument.WithUnit("{errors}"),
+		instrument.WithDescription(
+			"Packet errors attributed by direction (Transmit, Receive)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if networkDropped, err = h.meter.AsyncInt64().Counter(
+		"system.network.dropped",
+		instrument.WithUnit("{packets}"),
+		instrument.WithDescription(
+			"Packets dropped attributed by direction (Transmit, Receive)",
+		),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			networkIOUsage,
+			networkErrors,
+			networkDropped,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var ioStats []net.IOCountersStat
+			if err := h.collect(ctx, "network", func(ctx context.Context) (err error) {
+				ioStats, err = h.config.Provider.NetIOCounters(ctx, h.config.PerInterfaceNetwork)
+				return err
+			}); err != nil {
+				h.handleError("network", err)
+				return
+			}
+			if !h.config.PerInterfaceNetwork && len(ioStats) != 1 {
+				h.handleError("network", errors.New("incorrect summary count"))
+				return
+			}
+
+			usage, errs, dropped := h.int64Sums(), h.int64Sums(), h.int64Sums()
+			for _, ioStat := range ioStats {
+				var deviceAttrs []attribute.KeyValue
+				if h.config.PerInterfaceNetwork {
+					deviceAttrs = []attribute.KeyValue{attribute.String("device", ioStat.Name)}
+				}
+
+				usage.add(int64(ioStat.BytesSent), AttributeNetworkTransmit, deviceAttrs)
+				usage.add(int64(ioStat.BytesRecv), AttributeNetworkReceive, deviceAttrs)
+
+				errs.add(int64(ioStat.Errout), AttributeNetworkTransmit, deviceAttrs)
+				errs.add(int64(ioStat.Errin), AttributeNetworkReceive, deviceAttrs)
+
+				dropped.add(int64(ioStat.Dropout), AttributeNetworkTransmit, deviceAttrs)
+				dropped.add(int64(ioStat.Dropin), AttributeNetworkReceive, deviceAttrs)
+			}
+			usage.observe(ctx, networkIOUsage)
+			errs.observe(ctx, networkErrors)
+			dropped.observe(ctx, networkDropped)
+		})
+}
+
+// tcpStates are the TCP connection states reported by the
+// system.network.connections metric.
+var tcpStates = []string{
+	"ESTABLISHED",
+	"SYN_SENT",
+	"SYN_RECV",
+	"FIN_WAIT1",
+	"FIN_WAIT2",
+	"TIME_WAIT",
+	"CLOSE",
+	"CLOSE_WAIT",
+	"LAST_ACK",
+	"LISTEN",
+	"CLOSING",
+}
+
+func (h *Host) registerNetworkConnections() error {
+	var (
+		err error
+
+		networkConnections asyncint64.UpDownCounter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if networkConnections, err = h.meter.AsyncInt64().UpDownCounter(
+		"system.network.connections",
+		instrument.WithUnit("{connections}"),
+		instrument.WithDescription(
+			"Number of connections attributed by protocol (TCP) and state (ESTABLISHED, TIME_WAIT, ...)",
+		),
+	); err != nil {
+		return err
+	}
+
+	protocol := attribute.String("protocol", "tcp")
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			networkConnections,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var conns []net.ConnectionStat
+			if err := h.collect(ctx, "network connections", func(ctx context.Context) (err error) {
+				conns, err = net.ConnectionsWithContext(ctx, "tcp")
+				return err
+			}); err != nil {
+				h.handleError("network connections", err)
+				return
+			}
+
+			counts := make(map[string]int64, len(tcpStates))
+			for _, state := range tcpStates {
+				counts[state] = 0
+			}
+			for _, conn := range conns {
+				counts[conn.Status]++
+			}
+
+			sums := h.int64Sums()
+			for state, count := range counts {
+				sums.add(count, []attribute.KeyValue{protocol, attribute.String("state", state)})
+			}
+			sums.observe(ctx, networkConnections)
+		})
+}
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Attribute sets used for paging (swap) measurements.
+var (
+	AttributePagingUsed = []attribute.KeyValue{attribute.String("state", "used")}
+	AttributePagingFree = []attribute.KeyValue{attribute.String("state", "free")}
+
+	AttributePagingMajorPageIn = []attribute.KeyValue{
+		attribute.String("direction", "page_in"),
+		attribute.String("type", "major"),
+	}
+	AttributePagingMajorPageOut = []attribute.KeyValue{
+		attribute.String("direction", "page_out"),
+		attribute.String("type", "major"),
+	}
+	AttributePagingMinorPageIn = []attribute.KeyValue{
+		attribute.String("direction", "page_in"),
+		attribute.String("type", "minor"),
+	}
+	AttributePagingMinorPageOut = []attribute.KeyValue{
+		attribute.String("direction", "page_out"),
+		attribute.String("type", "minor"),
+	}
+
+	AttributePagingFaultMajor = []attribute.KeyValue{attribute.String("type", "major")}
+	AttributePagingFaultMinor = []attribute.KeyValue{attribute.String("type", "minor")}
+)
+
+func (h *Host) registerPaging() error {
+	var (
+		err error
+
+		pagingUsage       asyncint64.UpDownCounter
+		pagingUtilization asyncfloat64.Gauge
+		pagingOperations  asyncint64.Counter
+		pagingFaults      asyncint64.Counter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if pagingUsage, err = h.meter.AsyncInt64().UpDownCounter(
+		"system.paging.usage",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Swap (unix) or pagefile (windows) usage attributed by state (Used, Free)"),
+	); err != nil {
+		return err
+	}
+
+	if pagingUtilization, err = h.meter.AsyncFloat64().Gauge(
+		"system.paging.utilization",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Swap (unix) or pagefile (windows) utilization attributed by state (Used, Free)"),
+	); err != nil {
+		return err
+	}
+
+	instruments := []instrument.Asynchronous{
+		pagingUsage,
+		pagingUtilization,
+	}
+
+	// Paging operations and faults are only reported on platforms they
+	// are available on.
+	if pagingOperationsSupported {
+		if pagingOperations, err = h.meter.AsyncInt64().Counter(
+			"system.paging.operations",
+			instrument.WithUnit("{operations}"),
+			instrument.WithDescription("Paging operations attributed by direction (Page In, Page Out) and type (Major, Minor)"),
+		); err != nil {
+			return err
+		}
+
+		if pagingFaults, err = h.meter.AsyncInt64().Counter(
+			"system.paging.faults",
+			instrument.WithUnit("{faults}"),
+			instrument.WithDescription("Page faults attributed by type (Major, Minor)"),
+		); err != nil {
+			return err
+		}
+
+		instruments = append(instruments, pagingOperations, pagingFaults)
+	}
+
+	return h.meter.RegisterCallback(
+		instruments,
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var swap *mem.SwapMemoryStat
+			if err := h.collect(ctx, "paging", func(ctx context.Context) (err error) {
+				swap, err = h.config.Provider.SwapMemory(ctx)
+				return err
+			}); err != nil {
+				h.handleError("paging", err)
+				return
+			}
+
+			// Paging usage
+			usage := h.int64Sums()
+			usage.add(int64(swap.Used), AttributePagingUsed)
+			usage.add(int64(swap.Free), AttributePagingFree)
+			usage.observe(ctx, pagingUsage)
+
+			// Paging utilization, only defined when swap is configured.
+			if swap.Total > 0 {
+				utilization := h.float64Sums()
+				utilization.add(float64(swap.Used)/float64(swap.Total), AttributePagingUsed)
+				utilization.add(float64(swap.Free)/float64(swap.Total), AttributePagingFree)
+				utilization.observe(ctx, pagingUtilization)
+			}
+
+			if !pagingOperationsSupported {
+				return
+			}
+
+			// Paging operations
+			//
+			// This follows the OpenTelemetry Collector's "hostmetrics"
+			// receiver paging scraper: swap-ins and swap-outs are
+			// major operations, other page-ins and page-outs are minor.
+			operations := h.int64Sums()
+			operations.add(int64(swap.Sin), AttributePagingMajorPageIn)
+			operations.add(int64(swap.Sout), AttributePagingMajorPageOut)
+			operations.add(int64(swap.PgIn), AttributePagingMinorPageIn)
+			operations.add(int64(swap.PgOut), AttributePagingMinorPageOut)
+			operations.observe(ctx, pagingOperations)
+
+			// Page faults
+			faults := h.int64Sums()
+			faults.add(int64(swap.PgMajFault), AttributePagingFaultMajor)
+			if swap.PgFault >= swap.PgMajFault {
+				faults.add(int64(swap.PgFault-swap.PgMajFault), AttributePagingFaultMinor)
+			}
+			faults.observe(ctx, pagingFaults)
+		})
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// pagingOperationsSupported is whether the system.paging.operations and
+// system.paging.faults metrics are supported on this platform.  gopsutil
+// does not expose the page fault and paging I/O performance counters of
+// Windows, so only the page file usage is reported.
+const pagingOperationsSupported = false
+
+// swapMemory returns the usage of the page files of the host.
+//
+// gopsutil's SwapMemory reports the commit charge on Windows, which
+// includes the physical memory, instead of the usage of the page files.
+// The usage is instead summed over the page files returned by SwapDevices.
+func swapMemory(ctx context.Context) (*mem.SwapMemoryStat, error) {
+	devices, err := mem.SwapDevicesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stat := &mem.SwapMemoryStat{}
+	for _, d := range devices {
+		stat.Used += d.UsedBytes
+		stat.Free += d.FreeBytes
+	}
+	stat.Total = stat.Used + stat.Free
+	if stat.Total > 0 {
+		stat.UsedPercent = float64(stat.Used) / float64(stat.Total) * 100
+	}
+	return stat, nil
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+func (h *Host) registerProcess() error {
+	var (
+		err error
+
+		processCPUTime       asyncfloat64.Counter
+		processMemoryUsage   asyncint64.UpDownCounter
+		processMemoryVirtual asyncint64.UpDownCounter
+		processOpenFDs       asyncint64.UpDownCounter
+		processThreads       asyncint64.UpDownCounter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return fmt.Errorf("could not find this process: %w", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// TODO: .time units are in seconds, but "unit" package does
+	// not include this string.
+	// https://github.com/open-telemetry/opentelemetry-specification/issues/705
+	if processCPUTime, err = h.meter.AsyncFloat64().Counter(
+		"process.cpu.time",
+		instrument.WithUnit("s"),
+		instrument.WithDescription(
+			"Accumulated CPU time spent by this process attributeed by state (User, System, ...)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if processMemoryUsage, err = h.meter.AsyncInt64().UpDownCounter(
+		"process.memory.usage",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription(
+			"The amount of physical memory (resident set size) in use by this process",
+		),
+	); err != nil {
+		return err
+	}
+
+	if processMemoryVirtual, err = h.meter.AsyncInt64().UpDownCounter(
+		"process.memory.virtual",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription(
+			"The amount of committed virtual memory of this process",
+		),
+	); err != nil {
+		return err
+	}
+
+	instruments := []instrument.Asynchronous{
+		processCPUTime,
+		processMemoryUsage,
+		processMemoryVirtual,
+	}
+
+	// The file descriptors and threads are only reported on platforms
+	// they are available on, reading them on other platforms would fail
+	// on every collection.
+	if processOpenFDsSupported {
+		if processOpenFDs, err = h.meter.AsyncInt64().UpDownCounter(
+			"process.open_file_descriptor.count",
+			instrument.WithUnit("{count}"),
+			instrument.WithDescription(
+				"Number of file descriptors in use by this process",
+			),
+		); err != nil {
+			return err
+		}
+		instruments = append(instruments, processOpenFDs)
+	}
+
+	if processThreadsSupported {
+		if processThreads, err = h.meter.AsyncInt64().UpDownCounter(
+			"process.threads",
+			instrument.WithUnit("{threads}"),
+			instrument.WithDescription(
+				"Process threads count",
+			),
+		); err != nil {
+			return err
+		}
+		instruments = append(instruments, processThreads)
+	}
+
+	return h.meter.RegisterCallback(
+		instruments,
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			// This follows the OpenTelemetry Collector's "hostmetrics"
+			// receiver/hostmetricsreceiver/internal/scraper/processscraper
+			// measures User and System IOwait time.
+			// TODO: the Collector has per-OS compilation modules to support
+			// specific metrics that are not universal.
+			var processTimes *cpu.TimesStat
+			if err := h.collect(ctx, "process", func(ctx context.Context) (err error) {
+				processTimes, err = proc.TimesWithContext(ctx)
+				return err
+			}); err != nil {
+				h.handleError("process", err)
+				return
+			}
+
+			var memInfo *process.MemoryInfoStat
+			if err := h.collect(ctx, "process", func(ctx context.Context) (err error) {
+				memInfo, err = proc.MemoryInfoWithContext(ctx)
+				return err
+			}); err != nil {
+				h.handleError("process", err)
+				return
+			}
+
+			// Process CPU time
+			cpuTime := h.float64Sums()
+			cpuTime.add(processTimes.User, AttributeCPUTimeUser)
+			cpuTime.add(processTimes.System, AttributeCPUTimeSystem)
+			cpuTime.observe(ctx, processCPUTime)
+
+			// Process memory
+			processMemoryUsage.Observe(ctx, int64(memInfo.RSS))
+			processMemoryVirtual.Observe(ctx, int64(memInfo.VMS))
+
+			// Process file descriptors and threads
+			//
+			// Do not let them prevent the other process metrics from
+			// being reported.
+			if processOpenFDsSupported {
+				var fds int32
+				if err := h.collect(ctx, "process", func(ctx context.Context) (err error) {
+					fds, err = proc.NumFDsWithContext(ctx)
+					return err
+				}); err != nil {
+					h.handleError("process", err)
+				} else {
+					processOpenFDs.Observe(ctx, int64(fds))
+				}
+			}
+			if processThreadsSupported {
+				var threads int32
+				if err := h.collect(ctx, "process", func(ctx context.Context) (err error) {
+					threads, err = proc.NumThreadsWithContext(ctx)
+					return err
+				}); err != nil {
+					h.handleError("process", err)
+				} else {
+					processThreads.Observe(ctx, int64(threads))
+				}
+			}
+		})
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !solaris
+// +build !linux,!solaris
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+// processOpenFDsSupported is whether the process.open_file_descriptor.count
+// metric is supported on this platform.  gopsutil does not report the
+// number of file descriptors of a process on this platform.
+const processOpenFDsSupported = false
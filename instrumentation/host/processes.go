@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+)
+
+// Attribute sets used for process count measurements.
+var (
+	AttributeProcessesRunning  = []attribute.KeyValue{attribute.String("status", "running")}
+	AttributeProcessesSleeping = []attribute.KeyValue{attribute.String("status", "sleeping")}
+	AttributeProcessesStopped  = []attribute.KeyValue{attribute.String("status", "stopped")}
+	AttributeProcessesBlocked  = []attribute.KeyValue{attribute.String("status", "blocked")}
+	AttributeProcessesZombie   = []attribute.KeyValue{attribute.String("status", "zombie")}
+	AttributeProcessesOther    = []attribute.KeyValue{attribute.String("status", "other")}
+)
+
+// processStatuses are the process statuses reported, in order.
+var processStatuses = [][]attribute.KeyValue{
+	AttributeProcessesRunning,
+	AttributeProcessesSleeping,
+	AttributeProcessesStopped,
+	AttributeProcessesBlocked,
+	AttributeProcessesZombie,
+	AttributeProcessesOther,
+}
+
+// processStatusIndex maps a gopsutil process status to the index in
+// processStatuses it is counted under.  Unknown statuses are counted as
+// other.
+var processStatusIndex = map[string]int{
+	process.Running: 0,
+	process.Sleep:   1,
+	process.Idle:    1,
+	process.Stop:    2,
+	process.Blocked: 3,
+	process.Wait:    3,
+	process.Lock:    3,
+	process.Zombie:  4,
+}
+
+func (h *Host) registerProcesses() error {
+	var (
+		err error
+
+		processesCount   asyncint64.UpDownCounter
+		processesCreated asyncint64.Counter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if processesCount, err = h.meter.AsyncInt64().UpDownCounter(
+		"system.processes.count",
+		instrument.WithUnit("{processes}"),
+		instrument.WithDescription("Total number of processes attributed by status (Running, Sleeping, Stopped, Blocked, Zombie, Other)"),
+	); err != nil {
+		return err
+	}
+
+	if processesCreated, err = h.meter.AsyncInt64().Counter(
+		"system.processes.created",
+		instrument.WithUnit("{processes}"),
+		instrument.WithDescription("Total number of created processes"),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			processesCount,
+			processesCreated,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			counts := make([]int64, len(processStatuses))
+			if err := h.collect(ctx, "processes", func(ctx context.Context) error {
+				procs, err := process.ProcessesWithContext(ctx)
+				if err != nil {
+					return err
+				}
+				for _, p := range procs {
+					// Processes may exit while being listed, their
+					// status is then no longer available.
+					status, err := p.StatusWithContext(ctx)
+					if err != nil || len(status) == 0 {
+						continue
+					}
+					i, ok := processStatusIndex[status[0]]
+					if !ok {
+						i = len(processStatuses) - 1
+					}
+					counts[i]++
+				}
+				return nil
+			}); err != nil {
+				h.handleError("processes", err)
+				return
+			}
+
+			sums := h.int64Sums()
+			for i, attrs := range processStatuses {
+				sums.add(counts[i], attrs)
+			}
+			sums.observe(ctx, processesCount)
+
+			// The number of created processes is not available on
+			// all platforms.
+			var misc *load.MiscStat
+			if err := h.collect(ctx, "processes", func(ctx context.Context) (err error) {
+				misc, err = load.MiscWithContext(ctx)
+				return err
+			}); err == nil && misc.ProcsCreated > 0 {
+				processesCreated.Observe(ctx, int64(misc.ProcsCreated))
+			}
+		})
+}
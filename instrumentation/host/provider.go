@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// Provider provides the host information the CPU, Memory, Paging, Network,
+// and Disk metrics are computed from.  The default Provider reads the
+// information of the host using gopsutil.  A different Provider can be set
+// with the WithProvider Option, e.g. to test metric collection without
+// depending on the host the tests are run on.
+type Provider interface {
+	// CPUTimes returns the CPU times of the host.  If perCPU is true, the
+	// times of each logical CPU are returned, otherwise a single entry
+	// with the times of all CPUs.
+	CPUTimes(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error)
+
+	// CPUCounts returns the number of logical CPUs of the host if logical
+	// is true, otherwise the number of physical cores.
+	CPUCounts(ctx context.Context, logical bool) (int, error)
+
+	// VirtualMemory returns the memory statistics of the host.
+	VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error)
+
+	// SwapMemory returns the swap (unix) or page file (windows) and paging
+	// statistics of the host.
+	SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error)
+
+	// NetIOCounters returns the network I/O counters of the host.  If
+	// perNIC is true, the counters of each network interface are returned,
+	// otherwise a single entry with the counters of all interfaces.
+	NetIOCounters(ctx context.Context, perNIC bool) ([]net.IOCountersStat, error)
+
+	// DiskIOCounters returns the disk I/O counters of each device of the
+	// host keyed by device name.
+	DiskIOCounters(ctx context.Context) (map[string]disk.IOCountersStat, error)
+}
+
+// gopsutilProvider is the default Provider reading the information of the
+// host using gopsutil.
+type gopsutilProvider struct{}
+
+var _ Provider = gopsutilProvider{}
+
+func (gopsutilProvider) CPUTimes(ctx context.Context, perCPU bool) ([]cpu.TimesStat, error) {
+	return cpu.TimesWithContext(ctx, perCPU)
+}
+
+func (gopsutilProvider) CPUCounts(ctx context.Context, logical bool) (int, error) {
+	return cpu.CountsWithContext(ctx, logical)
+}
+
+func (gopsutilProvider) VirtualMemory(ctx context.Context) (*mem.VirtualMemoryStat, error) {
+	return mem.VirtualMemoryWithContext(ctx)
+}
+
+func (gopsutilProvider) SwapMemory(ctx context.Context) (*mem.SwapMemoryStat, error) {
+	return swapMemory(ctx)
+}
+
+func (gopsutilProvider) NetIOCounters(ctx context.Context, perNIC bool) ([]net.IOCountersStat, error) {
+	return net.IOCountersWithContext(ctx, perNIC)
+}
+
+func (gopsutilProvider) DiskIOCounters(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+	return disk.IOCountersWithContext(ctx)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"sync"
+
+	gopsutilhost "github.com/shirou/gopsutil/v3/host"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+)
+
+func (h *Host) registerUptime() error {
+	var (
+		err error
+
+		uptime asyncint64.Counter
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if uptime, err = h.meter.AsyncInt64().Counter(
+		"system.uptime",
+		instrument.WithUnit("s"),
+		instrument.WithDescription("Seconds since the host was booted"),
+	); err != nil {
+		return err
+	}
+
+	return h.meter.RegisterCallback(
+		[]instrument.Asynchronous{
+			uptime,
+		},
+		func(ctx context.Context) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if h.stopped() {
+				return
+			}
+
+			var seconds uint64
+			if err := h.collect(ctx, "uptime", func(ctx context.Context) (err error) {
+				seconds, err = gopsutilhost.UptimeWithContext(ctx)
+				return err
+			}); err != nil {
+				h.handleError("uptime", err)
+				return
+			}
+
+			uptime.Observe(ctx, int64(seconds))
+		})
+}
@@ -21,14 +21,18 @@ import (
 	"net/textproto"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
+const instrumentationName = "go.opentelemetry.io/otel/instrumentation/httptrace"
+
 // HTTP attributes.
 var (
 	HTTPStatus                 = attribute.Key("http.status")
@@ -52,6 +56,36 @@ var (
 	}
 )
 
+type phase int
+
+// Different phases of a request that can be recorded, see WithPhases.
+const (
+	GetConn phase = iota
+	DNS
+	Connect
+	TLS
+	Headers
+	Send
+	Receive
+)
+
+var hookPhases = map[string]phase{
+	"http.getconn": GetConn,
+	"http.dns":     DNS,
+	"http.connect": Connect,
+	"http.tls":     TLS,
+	"http.headers": Headers,
+	"http.send":    Send,
+	"http.receive": Receive,
+}
+
+func hookPhase(hook string) phase {
+	if strings.HasPrefix(hook, "http.connect") {
+		return Connect
+	}
+	return hookPhases[hook]
+}
+
 func parentHook(hook string) string {
 	if strings.HasPrefix(hook, "http.connect") {
 		return hookMap["http.connect"]
@@ -80,6 +114,44 @@ func WithoutSubSpans() ClientTraceOption {
 	})
 }
 
+// WithPhases will modify the httptrace.ClientTrace to only record the
+// specified phases of a request.  By default all phases are recorded.
+//
+// Valid phases are:
+//   - GetConn: Obtaining a connection, including DNS, Connect, and TLS
+//   - DNS: Looking up the address of the host
+//   - Connect: Establishing a new connection
+//   - TLS: Performing the TLS handshake
+//   - Headers: Writing the request headers
+//   - Send: Writing the request
+//   - Receive: Receiving the response
+//
+// The metrics enabled by WithMeterProvider are recorded regardless of the
+// selected phases.
+func WithPhases(phases ...phase) ClientTraceOption {
+	return clientTraceOptionFunc(func(ct *clientTracer) {
+		ct.phases = make(map[phase]bool, len(phases))
+		for _, p := range phases {
+			ct.phases[p] = true
+		}
+	})
+}
+
+// WithEventPhases will modify the httptrace.ClientTrace to record the
+// specified phases as Events on the span of the parent phase, or on the span
+// found in the context, instead of as sub-spans. See WithPhases for the
+// valid phases.
+func WithEventPhases(phases ...phase) ClientTraceOption {
+	return clientTraceOptionFunc(func(ct *clientTracer) {
+		if ct.eventPhases == nil {
+			ct.eventPhases = make(map[phase]bool, len(phases))
+		}
+		for _, p := range phases {
+			ct.eventPhases[p] = true
+		}
+	})
+}
+
 // WithRedactedHeaders will be replaced by fixed '****' values for the header
 // names provided.  These are in addition to the sensitive headers already
 // redacted by default: Authorization, WWW-Authenticate, Proxy-Authenticate
@@ -122,10 +194,24 @@ func WithTracerProvider(provider trace.TracerProvider) ClientTraceOption {
 	})
 }
 
+// WithMeterProvider specifies a meter provider used to record the duration
+// of the DNS lookup, TCP connect, TLS handshake, and time to first byte of
+// the request as histograms, with the requested host and port as attributes.
+// No metrics are recorded if this option is not specified.
+func WithMeterProvider(provider metric.MeterProvider) ClientTraceOption {
+	return clientTraceOptionFunc(func(ct *clientTracer) {
+		ct.meterProvider = provider
+	})
+}
+
 type clientTracer struct {
 	context.Context
 
 	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	metrics        *clientMetrics
+	startTimes     map[string]time.Time
+	host           string
 
 	tr trace.Tracer
 
@@ -135,6 +221,8 @@ type clientTracer struct {
 	redactedHeaders map[string]struct{}
 	addHeaders      bool
 	useSpans        bool
+	phases          map[phase]bool
+	eventPhases     map[phase]bool
 }
 
 // NewClientTrace returns an httptrace.ClientTrace implementation that will
@@ -171,10 +259,15 @@ func NewClientTrace(ctx context.Context, opts ...ClientTraceOption) *httptrace.C
 	}
 
 	ct.tr = ct.tracerProvider.Tracer(
-		"go.opentelemetry.io/otel/instrumentation/httptrace",
+		instrumentationName,
 		trace.WithInstrumentationVersion(SemVersion()),
 	)
 
+	if ct.meterProvider != nil {
+		ct.metrics = newClientMetrics(ct.meterProvider)
+		ct.startTimes = make(map[string]time.Time)
+	}
+
 	return &httptrace.ClientTrace{
 		GetConn:              ct.getConn,
 		GotConn:              ct.gotConn,
@@ -195,18 +288,30 @@ func NewClientTrace(ctx context.Context, opts ...ClientTraceOption) *httptrace.C
 	}
 }
 
+// records returns whether hook is recorded, and whether it is recorded as a
+// span rather than as events.
+func (ct *clientTracer) records(hook string) (recorded, asSpan bool) {
+	p := hookPhase(hook)
+	if ct.phases != nil && !ct.phases[p] {
+		return false, false
+	}
+	return true, ct.useSpans && !ct.eventPhases[p]
+}
+
 func (ct *clientTracer) start(hook, spanName string, attrs ...attribute.KeyValue) {
-	if !ct.useSpans {
-		if ct.root == nil {
-			ct.root = trace.SpanFromContext(ct.Context)
-		}
-		ct.root.AddEvent(hook+".start", trace.WithAttributes(attrs...))
+	recorded, asSpan := ct.records(hook)
+	if !recorded {
 		return
 	}
 
 	ct.mtx.Lock()
 	defer ct.mtx.Unlock()
 
+	if !asSpan {
+		trace.SpanFromContext(ct.getParentContext(hook)).AddEvent(hook+".start", trace.WithAttributes(attrs...))
+		return
+	}
+
 	if hookCtx, found := ct.activeHooks[hook]; !found {
 		var sp trace.Span
 		ct.activeHooks[hook], sp = ct.tr.Start(ct.getParentContext(hook), spanName, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindClient))
@@ -224,16 +329,21 @@ func (ct *clientTracer) start(hook, spanName string, attrs ...attribute.KeyValue
 }
 
 func (ct *clientTracer) end(hook string, err error, attrs ...attribute.KeyValue) {
-	if !ct.useSpans {
-		if err != nil {
-			attrs = append(attrs, attribute.String(hook+".error", err.Error()))
-		}
-		ct.root.AddEvent(hook+".done", trace.WithAttributes(attrs...))
+	recorded, asSpan := ct.records(hook)
+	if !recorded {
 		return
 	}
 
 	ct.mtx.Lock()
 	defer ct.mtx.Unlock()
+
+	if !asSpan {
+		if err != nil {
+			attrs = append(attrs, attribute.String(hook+".error", err.Error()))
+		}
+		trace.SpanFromContext(ct.getParentContext(hook)).AddEvent(hook+".done", trace.WithAttributes(attrs...))
+		return
+	}
 	if ctx, ok := ct.activeHooks[hook]; ok {
 		span := trace.SpanFromContext(ctx)
 		if err != nil {
@@ -262,6 +372,25 @@ func (ct *clientTracer) getParentContext(hook string) context.Context {
 	return ctx
 }
 
+// rootSpan returns the first sub-span started, or the span found in the
+// context if no sub-span was started.
+func (ct *clientTracer) rootSpan() trace.Span {
+	ct.mtx.Lock()
+	defer ct.mtx.Unlock()
+	if ct.root == nil {
+		ct.root = trace.SpanFromContext(ct.Context)
+	}
+	return ct.root
+}
+
+// receiveSpan returns the span informational responses are recorded on.
+func (ct *clientTracer) receiveSpan() trace.Span {
+	if span := ct.span("http.receive"); span != nil {
+		return span
+	}
+	return ct.rootSpan()
+}
+
 func (ct *clientTracer) span(hook string) trace.Span {
 	ct.mtx.Lock()
 	defer ct.mtx.Unlock()
@@ -272,6 +401,12 @@ func (ct *clientTracer) span(hook string) trace.Span {
 }
 
 func (ct *clientTracer) getConn(host string) {
+	if ct.metrics != nil {
+		ct.mtx.Lock()
+		ct.host = host
+		ct.mtx.Unlock()
+		ct.markStart("http.getconn")
+	}
 	ct.start("http.getconn", "http.getconn", semconv.HTTPHostKey.String(host))
 }
 
@@ -293,10 +428,14 @@ func (ct *clientTracer) putIdleConn(err error) {
 }
 
 func (ct *clientTracer) gotFirstResponseByte() {
+	if ct.metrics != nil {
+		ct.recordDuration(ct.metrics.ttfb, "http.getconn")
+	}
 	ct.start("http.receive", "http.receive")
 }
 
 func (ct *clientTracer) dnsStart(info httptrace.DNSStartInfo) {
+	ct.markStart("http.dns")
 	ct.start("http.dns", "http.dns", semconv.HTTPHostKey.String(info.Host))
 }
 
@@ -306,9 +445,13 @@ func (ct *clientTracer) dnsDone(info httptrace.DNSDoneInfo) {
 		addrs = append(addrs, netAddr.String())
 	}
 	ct.end("http.dns", info.Err, HTTPDNSAddrs.String(sliceToString(addrs)))
+	if ct.metrics != nil {
+		ct.recordDuration(ct.metrics.dns, "http.dns")
+	}
 }
 
 func (ct *clientTracer) connectStart(network, addr string) {
+	ct.markStart("http.connect." + addr)
 	ct.start("http.connect."+addr, "http.connect",
 		HTTPRemoteAddr.String(addr),
 		HTTPConnectionStartNetwork.String(network),
@@ -320,18 +463,25 @@ func (ct *clientTracer) connectDone(network, addr string, err error) {
 		HTTPConnectionDoneAddr.String(addr),
 		HTTPConnectionDoneNetwork.String(network),
 	)
+	if ct.metrics != nil {
+		ct.recordDuration(ct.metrics.connect, "http.connect."+addr)
+	}
 }
 
 func (ct *clientTracer) tlsHandshakeStart() {
+	ct.markStart("http.tls")
 	ct.start("http.tls", "http.tls")
 }
 
 func (ct *clientTracer) tlsHandshakeDone(_ tls.ConnectionState, err error) {
 	ct.end("http.tls", err)
+	if ct.metrics != nil {
+		ct.recordDuration(ct.metrics.tls, "http.tls")
+	}
 }
 
 func (ct *clientTracer) wroteHeaderField(k string, v []string) {
-	if ct.useSpans && ct.span("http.headers") == nil {
+	if _, asSpan := ct.records("http.headers"); asSpan && ct.span("http.headers") == nil {
 		ct.start("http.headers", "http.headers")
 	}
 	if !ct.addHeaders {
@@ -342,11 +492,11 @@ func (ct *clientTracer) wroteHeaderField(k string, v []string) {
 	if _, ok := ct.redactedHeaders[k]; ok {
 		value = "****"
 	}
-	ct.root.SetAttributes(attribute.String("http."+k, value))
+	ct.rootSpan().SetAttributes(attribute.String("http."+k, value))
 }
 
 func (ct *clientTracer) wroteHeaders() {
-	if ct.useSpans && ct.span("http.headers") != nil {
+	if ct.span("http.headers") != nil {
 		ct.end("http.headers", nil)
 	}
 	ct.start("http.send", "http.send")
@@ -354,33 +504,21 @@ func (ct *clientTracer) wroteHeaders() {
 
 func (ct *clientTracer) wroteRequest(info httptrace.WroteRequestInfo) {
 	if info.Err != nil {
-		ct.root.SetStatus(codes.Error, info.Err.Error())
+		ct.rootSpan().SetStatus(codes.Error, info.Err.Error())
 	}
 	ct.end("http.send", info.Err)
 }
 
 func (ct *clientTracer) got100Continue() {
-	span := ct.root
-	if ct.useSpans {
-		span = ct.span("http.receive")
-	}
-	span.AddEvent("GOT 100 - Continue")
+	ct.receiveSpan().AddEvent("GOT 100 - Continue")
 }
 
 func (ct *clientTracer) wait100Continue() {
-	span := ct.root
-	if ct.useSpans {
-		span = ct.span("http.receive")
-	}
-	span.AddEvent("GOT 100 - Wait")
+	ct.receiveSpan().AddEvent("GOT 100 - Wait")
 }
 
 func (ct *clientTracer) got1xxResponse(code int, header textproto.MIMEHeader) error {
-	span := ct.root
-	if ct.useSpans {
-		span = ct.span("http.receive")
-	}
-	span.AddEvent("GOT 1xx", trace.WithAttributes(
+	ct.receiveSpan().AddEvent("GOT 1xx", trace.WithAttributes(
 		HTTPStatus.Int(code),
 		HTTPHeaderMIME.String(sm2s(header)),
 	))
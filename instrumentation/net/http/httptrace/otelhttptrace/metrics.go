@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttptrace // import "go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// Client connection phase metrics.
+const (
+	DNSDuration     = "http.client.dns.duration"       // DNS lookup duration, milliseconds
+	ConnectDuration = "http.client.connect.duration"   // TCP connect duration, milliseconds
+	TLSDuration     = "http.client.tls.duration"       // TLS handshake duration, milliseconds
+	TimeToFirstByte = "http.client.time_to_first_byte" // Duration from requesting a connection to the first response byte, milliseconds
+)
+
+// clientMetrics holds the histograms recording the duration of the
+// connection phases of a request.
+type clientMetrics struct {
+	dns     syncfloat64.Histogram
+	connect syncfloat64.Histogram
+	tls     syncfloat64.Histogram
+	ttfb    syncfloat64.Histogram
+}
+
+func newClientMetrics(provider metric.MeterProvider) *clientMetrics {
+	meter := provider.Meter(
+		instrumentationName,
+		metric.WithInstrumentationVersion(SemVersion()),
+	)
+
+	m := &clientMetrics{}
+	m.dns = newDurationHistogram(meter, DNSDuration, "Duration of the DNS lookups of outgoing requests")
+	m.connect = newDurationHistogram(meter, ConnectDuration, "Duration of the establishment of new connections for outgoing requests")
+	m.tls = newDurationHistogram(meter, TLSDuration, "Duration of the TLS handshakes of outgoing requests")
+	m.ttfb = newDurationHistogram(meter, TimeToFirstByte, "Duration from requesting a connection to receiving the first response byte")
+	return m
+}
+
+func newDurationHistogram(meter metric.Meter, name, description string) syncfloat64.Histogram {
+	h, err := meter.SyncFloat64().Histogram(
+		name,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription(description),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return h
+}
+
+// markStart records the start time of hook for a later call to
+// recordDuration.
+func (ct *clientTracer) markStart(hook string) {
+	if ct.metrics == nil {
+		return
+	}
+	ct.mtx.Lock()
+	ct.startTimes[hook] = time.Now()
+	ct.mtx.Unlock()
+}
+
+// recordDuration records the time elapsed since hook started in h, with the
+// host of the request as attribute.
+func (ct *clientTracer) recordDuration(h syncfloat64.Histogram, hook string) {
+	if ct.metrics == nil || h == nil {
+		return
+	}
+	ct.mtx.Lock()
+	start, ok := ct.startTimes[hook]
+	delete(ct.startTimes, hook)
+	host := ct.host
+	ct.mtx.Unlock()
+	if !ok {
+		return
+	}
+
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+	h.Record(ct.Context, elapsed, hostAttributes(host)...)
+}
+
+func hostAttributes(hostport string) []attribute.KeyValue {
+	if hostport == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetPeerNameKey.String(hostport)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetPeerNameKey.String(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+	return attrs
+}
@@ -16,9 +16,11 @@ package test
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,8 +29,10 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 )
 
 func getSpanFromRecorder(sr *tracetest.SpanRecorder, name string) (trace.ReadOnlySpan, bool) {
@@ -504,3 +508,81 @@ func TestHTTPRequestWithTraceContext(t *testing.T) {
 	require.Equal(t, parent.SpanContext().TraceID(), getconn.SpanContext().TraceID())
 	require.Equal(t, parent.SpanContext().SpanID(), getconn.Parent().SpanID())
 }
+
+func TestWithMeterProvider(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		}),
+	)
+	defer ts.Close()
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// Use a host name for the request to go through the DNS lookup.
+	transport := ts.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	client := &http.Client{Transport: transport}
+
+	ctx := context.Background()
+	req, err := http.NewRequest("GET", "https://localhost:"+port, nil)
+	require.NoError(t, err)
+	req = req.WithContext(httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx,
+		otelhttptrace.WithMeterProvider(meterProvider),
+	)))
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	require.NoError(t, metricExporter.Collect(ctx))
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	attrs := []attribute.KeyValue{
+		semconv.NetPeerNameKey.String("localhost"),
+		semconv.NetPeerPortKey.Int(p),
+	}
+	for _, name := range []string{
+		otelhttptrace.DNSDuration,
+		otelhttptrace.ConnectDuration,
+		otelhttptrace.TLSDuration,
+		otelhttptrace.TimeToFirstByte,
+	} {
+		rec, err := metricExporter.GetByNameAndAttributes(name, attrs)
+		if assert.NoError(t, err, name) {
+			assert.GreaterOrEqual(t, rec.Count, uint64(1), name)
+		}
+	}
+}
+
+func TestWithPhases(t *testing.T) {
+	fixture := prepareClientTraceTest(t)
+
+	ctx, span := otel.Tracer("oteltest").Start(context.Background(), "root")
+	ctx = httptrace.WithClientTrace(ctx,
+		otelhttptrace.NewClientTrace(ctx,
+			otelhttptrace.WithPhases(otelhttptrace.GetConn, otelhttptrace.Connect, otelhttptrace.Receive),
+			otelhttptrace.WithEventPhases(otelhttptrace.Connect),
+		),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fixture.URL, nil)
+	require.NoError(t, err)
+	resp, err := fixture.Client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	span.End()
+
+	var names []string
+	for _, s := range fixture.SpanRecorder.Ended() {
+		names = append(names, s.Name())
+	}
+	assert.ElementsMatch(t, []string{"root", "http.getconn", "http.receive"}, names)
+
+	getconn, ok := getSpanFromRecorder(fixture.SpanRecorder, "http.getconn")
+	require.True(t, ok)
+	var events []string
+	for _, e := range getconn.Events() {
+		events = append(events, e.Name)
+	}
+	assert.Equal(t, []string{"http.connect." + fixture.Address + ".start", "http.connect." + fixture.Address + ".done"}, events)
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"mime"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// bodyCaptureConfig configures the capture of request or response bodies.
+type bodyCaptureConfig struct {
+	maxBytes     int
+	contentTypes []string
+}
+
+// newBodyCaptureConfig returns the configuration capturing up to maxBytes
+// of the bodies of contentTypes, or nil if maxBytes is not positive.
+func newBodyCaptureConfig(maxBytes int, contentTypes []string) *bodyCaptureConfig {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &bodyCaptureConfig{maxBytes: maxBytes, contentTypes: contentTypes}
+}
+
+// allows returns if the bodies of contentType are captured.
+func (c *bodyCaptureConfig) allows(contentType string) bool {
+	if len(c.contentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, ct := range c.contentTypes {
+		if ct == mediaType || (strings.HasSuffix(ct, "/*") && strings.HasPrefix(mediaType, ct[:len(ct)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCapture returns a bodyCapture for a body of contentType, or nil if the
+// body is not captured.
+func (c *bodyCaptureConfig) newCapture(contentType string) *bodyCapture {
+	if c == nil || !c.allows(contentType) {
+		return nil
+	}
+	return &bodyCapture{max: c.maxBytes}
+}
+
+// bodyCapture is an io.Writer keeping the first bytes written to it.
+type bodyCapture struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func (c *bodyCapture) Write(p []byte) (int, error) {
+	n := c.max - len(c.buf)
+	if n > len(p) {
+		n = len(p)
+	}
+	if n < len(p) {
+		c.truncated = true
+	}
+	c.buf = append(c.buf, p[:n]...)
+	return len(p), nil
+}
+
+// attributes returns the attributes of the captured body, with the key and
+// truncatedKey keys.
+func (c *bodyCapture) attributes(key, truncatedKey attribute.Key) []attribute.KeyValue {
+	if c == nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{key.String(string(c.buf))}
+	if c.truncated {
+		attrs = append(attrs, truncatedKey.Bool(true))
+	}
+	return attrs
+}
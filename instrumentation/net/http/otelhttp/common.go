@@ -23,10 +23,18 @@ import (
 
 // Attribute keys that can be added to a span.
 const (
-	ReadBytesKey  = attribute.Key("http.read_bytes")  // if anything was read from the request body, the total number of bytes read
-	ReadErrorKey  = attribute.Key("http.read_error")  // If an error occurred while reading a request, the string of the error (io.EOF is not recorded)
-	WroteBytesKey = attribute.Key("http.wrote_bytes") // if anything was written to the response writer, the total number of bytes written
-	WriteErrorKey = attribute.Key("http.write_error") // if an error occurred while writing a reply, the string of the error (io.EOF is not recorded)
+	ReadBytesKey             = attribute.Key("http.read_bytes")              // if anything was read from the request body, the total number of bytes read
+	ReadErrorKey             = attribute.Key("http.read_error")              // If an error occurred while reading a request, the string of the error (io.EOF is not recorded)
+	WroteBytesKey            = attribute.Key("http.wrote_bytes")             // if anything was written to the response writer, the total number of bytes written
+	WriteErrorKey            = attribute.Key("http.write_error")             // if an error occurred while writing a reply, the string of the error (io.EOF is not recorded)
+	ResponseBodySizeKey      = attribute.Key("http.response.body.size")      // the total number of bytes written to the response body, including streamed and chunked responses
+	UpgradedKey              = attribute.Key("http.upgraded")                // if the connection was hijacked from the response writer, e.g. to upgrade it to the WebSocket protocol
+	ResendCountKey           = attribute.Key("http.request.resend_count")    // the number of times a client request was already sent, see WithResendCount
+	RequestBodyKey           = attribute.Key("http.request.body")            // the beginning of the request body, see WithCapturedRequestBody
+	ResponseBodyKey          = attribute.Key("http.response.body")           // the beginning of the response body, see WithCapturedResponseBody
+	RequestBodyTruncatedKey  = attribute.Key("http.request.body.truncated")  // if the request body was longer than the captured one
+	ResponseBodyTruncatedKey = attribute.Key("http.response.body.truncated") // if the response body was longer than the captured one
+	ErrorTypeKey             = attribute.Key("error.type")                   // the class of error of a client request, as returned by the function given to WithClientErrorClassifier
 )
 
 // Server HTTP metrics.
@@ -35,6 +43,15 @@ const (
 	RequestContentLength  = "http.server.request_content_length"  // Incoming request bytes total
 	ResponseContentLength = "http.server.response_content_length" // Incoming response bytes total
 	ServerLatency         = "http.server.duration"                // Incoming end to end duration, microseconds
+	RequestSize           = "http.server.request.size"            // Incoming request body size, bytes
+	ResponseSize          = "http.server.response.size"           // Outgoing response body size, bytes
+)
+
+// Client HTTP metrics.
+const (
+	ClientLatency      = "http.client.duration"      // Outgoing end to end duration, milliseconds
+	ClientRequestSize  = "http.client.request.size"  // Outgoing request body size, bytes
+	ClientResponseSize = "http.client.response.size" // Outgoing response body size, bytes
 )
 
 // Filter is a predicate used to determine whether a given http.request should
@@ -18,8 +18,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptrace"
+	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/global"
 	"go.opentelemetry.io/otel/propagation"
@@ -33,17 +36,35 @@ const (
 // config represents the configuration options available for the http.Handler
 // and http.Transport types.
 type config struct {
-	Tracer            trace.Tracer
-	Meter             metric.Meter
-	Propagators       propagation.TextMapPropagator
-	SpanStartOptions  []trace.SpanStartOption
-	PublicEndpoint    bool
-	PublicEndpointFn  func(*http.Request) bool
-	ReadEvent         bool
-	WriteEvent        bool
-	Filters           []Filter
-	SpanNameFormatter func(string, *http.Request) string
-	ClientTrace       func(context.Context) *httptrace.ClientTrace
+	Tracer             trace.Tracer
+	Meter              metric.Meter
+	Propagators        propagation.TextMapPropagator
+	RespPropagators    propagation.TextMapPropagator
+	SpanStartOptions   []trace.SpanStartOption
+	SpanOptionsFn      func(*http.Request) []trace.SpanStartOption
+	SpanAttributesFn   func(*http.Request) []attribute.KeyValue
+	MetricAttributesFn func(*http.Request, []attribute.KeyValue) []attribute.KeyValue
+	PublicEndpoint     bool
+	PublicEndpointFn   func(*http.Request) bool
+	ReadEvent          bool
+	WriteEvent         bool
+	FirstByteEvent     bool
+	ProgressInterval   time.Duration
+	Filters            []Filter
+	SpanNameFormatter  func(string, *http.Request) string
+	ClientTrace        func(context.Context) *httptrace.ClientTrace
+	Metrics            map[string]bool
+	RouteExtractor     func(*http.Request) string
+	SpanStatusFn       func(int) (codes.Code, string)
+	ErrorClassifier    ClientErrorClassifier
+	ResendCount        bool
+	RequestHeaders     []string
+	ResponseHeaders    []string
+	BaggageKeys        []string
+	RecoverPanics      bool
+	RequestBody        *bodyCaptureConfig
+	ResponseBody       *bodyCaptureConfig
+	Repanic            bool
 
 	TracerProvider trace.TracerProvider
 	MeterProvider  metric.MeterProvider
@@ -60,6 +81,19 @@ func (o optionFunc) apply(c *config) {
 	o(c)
 }
 
+// requestSpanOptions returns the span options of r returned by optsFn and
+// attrsFn, which may be nil.
+func requestSpanOptions(r *http.Request, optsFn func(*http.Request) []trace.SpanStartOption, attrsFn func(*http.Request) []attribute.KeyValue) []trace.SpanStartOption {
+	var opts []trace.SpanStartOption
+	if optsFn != nil {
+		opts = append(opts, optsFn(r)...)
+	}
+	if attrsFn != nil {
+		opts = append(opts, trace.WithAttributes(attrsFn(r)...))
+	}
+	return opts
+}
+
 // newConfig creates a new config struct and applies opts to it.
 func newConfig(opts ...Option) *config {
 	c := &config{
@@ -105,14 +139,15 @@ func WithMeterProvider(provider metric.MeterProvider) Option {
 
 // WithPublicEndpoint configures the Handler to link the span with an incoming
 // span context. If this option is not provided, then the association is a child
-// association instead of a link.
+// association instead of a link. The span then starts a new trace, so that
+// untrusted clients can neither dictate its trace ID nor its sampling.
 func WithPublicEndpoint() Option {
 	return optionFunc(func(c *config) {
 		c.PublicEndpoint = true
 	})
 }
 
-// WithPublicEndpointFn runs with every request, and allows conditionnally
+// WithPublicEndpointFn runs with every request, and allows conditionally
 // configuring the Handler to link the span with an incoming span context. If
 // this option is not provided or returns false, then the association is a
 // child association instead of a link.
@@ -133,6 +168,50 @@ func WithPropagators(ps propagation.TextMapPropagator) Option {
 	})
 }
 
+// WithSpanOptionsFn takes a function that will be called on every request and
+// the returned options will be added to the options of its span, after the
+// ones configured with WithSpanOptions.  As the options are given when the
+// span is started, they can be used by samplers, e.g. attributes computed from
+// the URL or headers of the request, or trace.WithNewRoot for health checks.
+// For the Handler, the context of the request holds the span context
+// extracted from it.
+func WithSpanOptionsFn(fn func(*http.Request) []trace.SpanStartOption) Option {
+	return optionFunc(func(c *config) {
+		c.SpanOptionsFn = fn
+	})
+}
+
+// WithSpanAttributesFn takes a function that will be called on every request
+// and the returned attributes will be set on its span when it is started,
+// e.g. to record a tenant or a user.
+func WithSpanAttributesFn(fn func(*http.Request) []attribute.KeyValue) Option {
+	return optionFunc(func(c *config) {
+		c.SpanAttributesFn = fn
+	})
+}
+
+// WithMetricAttributesFn takes a function that will be called on every
+// request with the attributes of its metric measurements, and whose returned
+// attributes are used instead.  It can be used to drop or rewrite attributes,
+// e.g. to keep the number of time series of the metrics low.  The attributes
+// of the span are not affected.
+func WithMetricAttributesFn(fn func(r *http.Request, attrs []attribute.KeyValue) []attribute.KeyValue) Option {
+	return optionFunc(func(c *config) {
+		c.MetricAttributesFn = fn
+	})
+}
+
+// WithResponsePropagators configures the Handler to inject the span context
+// into the response headers with the given propagators, e.g. TraceResponse
+// or ServerTiming, so that clients such as browsers can correlate their
+// requests with the server traces.  If this option isn't specified, the span
+// context is not injected into the responses.
+func WithResponsePropagators(ps propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *config) {
+		c.RespPropagators = ps
+	})
+}
+
 // WithSpanOptions configures an additional set of
 // trace.SpanOptions, which are applied to each new span.
 func WithSpanOptions(opts ...trace.SpanStartOption) Option {
@@ -159,6 +238,7 @@ type event int
 const (
 	ReadEvents event = iota
 	WriteEvents
+	FirstByteEvents
 )
 
 // WithMessageEvents configures the Handler to record the specified events
@@ -170,6 +250,12 @@ const (
 //       using the ReadBytesKey
 //     * WriteEvents: Record the number of bytes written after every http.ResponeWriter.Write
 //       using the WriteBytesKey
+//     * FirstByteEvents: Record the time the first byte of the response body is
+//       written, i.e. the time to first byte of the response
+//
+// If the connection is hijacked, e.g. to upgrade it to the WebSocket protocol,
+// the events are also recorded for every Read and Write of the hijacked
+// net.Conn until the end of the span.
 func WithMessageEvents(events ...event) Option {
 	return optionFunc(func(c *config) {
 		for _, e := range events {
@@ -178,11 +264,23 @@ func WithMessageEvents(events ...event) Option {
 				c.ReadEvent = true
 			case WriteEvents:
 				c.WriteEvent = true
+			case FirstByteEvents:
+				c.FirstByteEvent = true
 			}
 		}
 	})
 }
 
+// WithProgressEvents configures the Handler to record a "progress" event with
+// the number of bytes of the response body written so far, using the
+// WroteBytesKey, at most once per interval while the response is written.
+// It is meant for long-lived streaming responses, e.g. server-sent events.
+func WithProgressEvents(interval time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.ProgressInterval = interval
+	})
+}
+
 // WithSpanNameFormatter takes a function that will be called on every
 // request and the returned string will become the Span Name.
 func WithSpanNameFormatter(f func(operation string, r *http.Request) string) Option {
@@ -191,6 +289,131 @@ func WithSpanNameFormatter(f func(operation string, r *http.Request) string) Opt
 	})
 }
 
+// WithRouteExtractor takes a function that will be called on every request
+// handled by a Handler to extract its route, e.g. "/users/{id}".  A
+// non-empty route is set as the http.route attribute of the span and the
+// metrics, and used as the span name unless a span name formatter is set
+// with WithSpanNameFormatter.  It is meant for muxes able to provide
+// low-cardinality routes without wrapping each of them with WithRouteTag.
+func WithRouteExtractor(f func(r *http.Request) string) Option {
+	return optionFunc(func(c *config) {
+		c.RouteExtractor = f
+	})
+}
+
+// WithSpanStatusFn takes a function that will be called with the status code
+// of every response and the returned code and description will become the
+// span status.  By default, the Handler sets the span status to Error for 5xx
+// status codes only, and the Transport for 4xx and 5xx status codes.
+func WithSpanStatusFn(f func(statusCode int) (codes.Code, string)) Option {
+	return optionFunc(func(c *config) {
+		c.SpanStatusFn = f
+	})
+}
+
+// WithCapturedRequestHeaders configures the Handler to record the values of
+// the named request headers as the http.request.header.<name> span
+// attributes, <name> being the header name lowercased with '-' replaced by
+// '_'.  Headers absent from a request are not recorded.
+func WithCapturedRequestHeaders(headers []string) Option {
+	return optionFunc(func(c *config) {
+		c.RequestHeaders = headers
+	})
+}
+
+// WithCapturedResponseHeaders configures the Handler and Transport to record
+// the values of the named response headers as the
+// http.response.header.<name> span attributes, <name> being the header name
+// lowercased with '-' replaced by '_'.  Headers absent from a response are
+// not recorded.
+func WithCapturedResponseHeaders(headers []string) Option {
+	return optionFunc(func(c *config) {
+		c.ResponseHeaders = headers
+	})
+}
+
+// ClientErrorClassifier returns the span status code and description, and
+// the error.type attribute value, of a request made by a Transport from its
+// response or the error returned by the base http.RoundTripper.  An empty
+// error type is not recorded.
+type ClientErrorClassifier func(res *http.Response, err error) (code codes.Code, description string, errorType string)
+
+// WithClientErrorClassifier configures the Transport to set the span status and
+// the error.type attribute of every request from the result of f.  It takes
+// precedence over WithSpanStatusFn for the Transport.  By default, the span
+// status is set to Error for errors and 4xx and 5xx status codes, and the
+// error.type attribute is not recorded.
+func WithClientErrorClassifier(f ClientErrorClassifier) Option {
+	return optionFunc(func(c *config) {
+		c.ErrorClassifier = f
+	})
+}
+
+// WithResendCount configures the Transport to set the
+// http.request.resend_count attribute on the span of every attempt to send a
+// request after the first one, so that redirects and retries are visible in
+// traces.  Redirects followed by a http.Client are counted from the responses
+// that caused them; other attempts, e.g. retries made by a http.RoundTripper
+// wrapping the Transport, are counted when the requests have a context
+// returned by ContextWithResendCount.
+func WithResendCount() Option {
+	return optionFunc(func(c *config) {
+		c.ResendCount = true
+	})
+}
+
+// WithBaggageAttributes configures the Handler and Transport to set the members
+// of the baggage named by keys as attributes of their spans, with the same
+// keys.  The Handler reads the baggage extracted from the incoming requests by
+// the configured propagators, and the Transport the one of the outgoing
+// requests context.  Members absent from the baggage are not recorded.
+func WithBaggageAttributes(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.BaggageKeys = keys
+	})
+}
+
+// WithPanicRecovery configures the Handler to recover the panics of the
+// handler it wraps, record them on the span as exception events with their
+// stack trace, and set the span status to Error.  The span is ended and the
+// metrics recorded as for any other request.  If repanic is true, the panic is
+// then propagated, otherwise a 500 Internal Server Error response is written
+// if no header was written yet.  The http.ErrAbortHandler panics used to abort
+// requests are always propagated.
+func WithPanicRecovery(repanic bool) Option {
+	return optionFunc(func(c *config) {
+		c.RecoverPanics = true
+		c.Repanic = repanic
+	})
+}
+
+// WithCapturedRequestBody configures the Handler to record up to maxBytes of
+// the body read from the requests as the http.request.body span attribute,
+// setting the http.request.body.truncated attribute if more was read.  Only
+// the bodies whose Content-Type header matches one of contentTypes are
+// recorded, e.g. "application/json" or "text/*"; all of them are if none is
+// given.  Bodies may contain sensitive data, this is meant for debugging
+// low-volume internal APIs.  No body is recorded if maxBytes is not
+// positive.
+func WithCapturedRequestBody(maxBytes int, contentTypes ...string) Option {
+	return optionFunc(func(c *config) {
+		c.RequestBody = newBodyCaptureConfig(maxBytes, contentTypes)
+	})
+}
+
+// WithCapturedResponseBody configures the Handler to record up to maxBytes of
+// the response bodies as the http.response.body span attribute, setting the
+// http.response.body.truncated attribute if more was written.  Only the
+// bodies whose content type matches one of contentTypes are recorded, see
+// WithCapturedRequestBody.  The content type is the Content-Type header of
+// the response, or the one detected from the first bytes written if it is not
+// set.  No body is recorded if maxBytes is not positive.
+func WithCapturedResponseBody(maxBytes int, contentTypes ...string) Option {
+	return optionFunc(func(c *config) {
+		c.ResponseBody = newBodyCaptureConfig(maxBytes, contentTypes)
+	})
+}
+
 // WithClientTrace takes a function that returns client trace instance that will be
 // applied to the requests sent through the otelhttp Transport.
 func WithClientTrace(f func(context.Context) *httptrace.ClientTrace) Option {
@@ -198,3 +421,26 @@ func WithClientTrace(f func(context.Context) *httptrace.ClientTrace) Option {
 		c.ClientTrace = f
 	})
 }
+
+// WithMetrics configures the Handler and Transport to only record the
+// named metrics, e.g. ServerLatency or ClientRequestSize.  If this option
+// is not used, all the metrics are recorded.  If it is used without any
+// name, no metric is recorded.
+func WithMetrics(names ...string) Option {
+	return optionFunc(func(c *config) {
+		c.Metrics = make(map[string]bool, len(names))
+		for _, name := range names {
+			c.Metrics[name] = true
+		}
+	})
+}
+
+// meterFor returns the Meter to create the instrument of the named metric
+// with: the configured Meter if the metric is recorded, a no-op Meter
+// otherwise.
+func (c *config) meterFor(name string) metric.Meter {
+	if c.Metrics != nil && !c.Metrics[name] {
+		return metric.NewNoopMeter()
+	}
+	return c.Meter
+}
@@ -15,17 +15,23 @@
 package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 import (
+	"fmt"
 	"io"
 	"net/http"
+	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/felixge/httpsnoop"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
 	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
@@ -35,30 +41,51 @@ var _ http.Handler = &Handler{}
 
 // Handler is http middleware that corresponds to the http.Handler interface and
 // is designed to wrap a http.Mux (or equivalent), while individual routes on
-// the mux are wrapped with WithRouteTag. A Handler will add various attributes
+// the mux are wrapped with WithRouteTag, or their routes are extracted with
+// the function given to WithRouteExtractor. A Handler will add various attributes
 // to the span using the attribute.Keys defined in this package.
 type Handler struct {
 	operation string
 	handler   http.Handler
 
-	tracer            trace.Tracer
-	meter             metric.Meter
-	propagators       propagation.TextMapPropagator
-	spanStartOptions  []trace.SpanStartOption
-	readEvent         bool
-	writeEvent        bool
-	filters           []Filter
-	spanNameFormatter func(string, *http.Request) string
-	counters          map[string]syncint64.Counter
-	valueRecorders    map[string]syncfloat64.Histogram
-	publicEndpoint    bool
-	publicEndpointFn  func(*http.Request) bool
+	tracer             trace.Tracer
+	meter              metric.Meter
+	propagators        propagation.TextMapPropagator
+	respPropagators    propagation.TextMapPropagator
+	spanStartOptions   []trace.SpanStartOption
+	spanOptionsFn      func(*http.Request) []trace.SpanStartOption
+	spanAttributesFn   func(*http.Request) []attribute.KeyValue
+	metricAttributesFn func(*http.Request, []attribute.KeyValue) []attribute.KeyValue
+	readEvent          bool
+	writeEvent         bool
+	firstByteEvent     bool
+	progressInterval   time.Duration
+	filters            []Filter
+	spanNameFormatter  func(string, *http.Request) string
+	routeExtractor     func(*http.Request) string
+	spanStatusFn       func(int) (codes.Code, string)
+	requestHeaders     []capturedHeader
+	responseHeaders    []capturedHeader
+	baggageKeys        []string
+	recoverPanics      bool
+	requestBody        *bodyCaptureConfig
+	responseBody       *bodyCaptureConfig
+	repanic            bool
+	counters           map[string]syncint64.Counter
+	valueRecorders     map[string]syncfloat64.Histogram
+	sizeRecorders      map[string]syncint64.Histogram
+	publicEndpoint     bool
+	publicEndpointFn   func(*http.Request) bool
 }
 
 func defaultHandlerFormatter(operation string, _ *http.Request) string {
 	return operation
 }
 
+func defaultHandlerSpanStatus(statusCode int) (codes.Code, string) {
+	return semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(statusCode, trace.SpanKindServer)
+}
+
 // NewHandler wraps the passed handler, functioning like middleware, in a span
 // named after the operation and with any provided Options.
 func NewHandler(handler http.Handler, operation string, opts ...Option) http.Handler {
@@ -69,12 +96,12 @@ func NewHandler(handler http.Handler, operation string, opts ...Option) http.Han
 
 	defaultOpts := []Option{
 		WithSpanOptions(trace.WithSpanKind(trace.SpanKindServer)),
-		WithSpanNameFormatter(defaultHandlerFormatter),
+		WithSpanStatusFn(defaultHandlerSpanStatus),
 	}
 
 	c := newConfig(append(defaultOpts, opts...)...)
 	h.configure(c)
-	h.createMeasures()
+	h.createMeasures(c)
 
 	return &h
 }
@@ -83,11 +110,26 @@ func (h *Handler) configure(c *config) {
 	h.tracer = c.Tracer
 	h.meter = c.Meter
 	h.propagators = c.Propagators
+	h.respPropagators = c.RespPropagators
 	h.spanStartOptions = c.SpanStartOptions
+	h.spanOptionsFn = c.SpanOptionsFn
+	h.spanAttributesFn = c.SpanAttributesFn
+	h.metricAttributesFn = c.MetricAttributesFn
 	h.readEvent = c.ReadEvent
 	h.writeEvent = c.WriteEvent
+	h.firstByteEvent = c.FirstByteEvent
+	h.progressInterval = c.ProgressInterval
 	h.filters = c.Filters
 	h.spanNameFormatter = c.SpanNameFormatter
+	h.routeExtractor = c.RouteExtractor
+	h.spanStatusFn = c.SpanStatusFn
+	h.requestHeaders = newCapturedHeaders("http.request.header.", c.RequestHeaders)
+	h.responseHeaders = newCapturedHeaders("http.response.header.", c.ResponseHeaders)
+	h.baggageKeys = c.BaggageKeys
+	h.recoverPanics = c.RecoverPanics
+	h.requestBody = c.RequestBody
+	h.responseBody = c.ResponseBody
+	h.repanic = c.Repanic
 	h.publicEndpoint = c.PublicEndpoint
 	h.publicEndpointFn = c.PublicEndpointFn
 }
@@ -98,22 +140,39 @@ func handleErr(err error) {
 	}
 }
 
-func (h *Handler) createMeasures() {
+func (h *Handler) createMeasures(c *config) {
 	h.counters = make(map[string]syncint64.Counter)
 	h.valueRecorders = make(map[string]syncfloat64.Histogram)
+	h.sizeRecorders = make(map[string]syncint64.Histogram)
+
+	requestBytesCounter, err := c.meterFor(RequestContentLength).SyncInt64().Counter(RequestContentLength)
+	handleErr(err)
+
+	responseBytesCounter, err := c.meterFor(ResponseContentLength).SyncInt64().Counter(ResponseContentLength)
+	handleErr(err)
 
-	requestBytesCounter, err := h.meter.SyncInt64().Counter(RequestContentLength)
+	serverLatencyMeasure, err := c.meterFor(ServerLatency).SyncFloat64().Histogram(ServerLatency)
 	handleErr(err)
 
-	responseBytesCounter, err := h.meter.SyncInt64().Counter(ResponseContentLength)
+	requestSizeMeasure, err := c.meterFor(RequestSize).SyncInt64().Histogram(
+		RequestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the body of the incoming requests"),
+	)
 	handleErr(err)
 
-	serverLatencyMeasure, err := h.meter.SyncFloat64().Histogram(ServerLatency)
+	responseSizeMeasure, err := c.meterFor(ResponseSize).SyncInt64().Histogram(
+		ResponseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the body of the responses to the incoming requests"),
+	)
 	handleErr(err)
 
 	h.counters[RequestContentLength] = requestBytesCounter
 	h.counters[ResponseContentLength] = responseBytesCounter
 	h.valueRecorders[ServerLatency] = serverLatencyMeasure
+	h.sizeRecorders[RequestSize] = requestSizeMeasure
+	h.sizeRecorders[ResponseSize] = responseSizeMeasure
 }
 
 // ServeHTTP serves HTTP requests (http.Handler).
@@ -128,7 +187,25 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := h.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
-	opts := h.spanStartOptions
+	var route string
+	if h.routeExtractor != nil {
+		route = h.routeExtractor(r)
+	}
+
+	// Start with the request attributes, then the configured options. The
+	// options are copied in a new slice so appending to them does not race
+	// with other requests sharing their backing array.
+	attrs := semconv.NetAttributesFromHTTPRequest("tcp", r)
+	attrs = append(attrs, semconv.EndUserAttributesFromHTTPRequest(r)...)
+	attrs = append(attrs, semconv.HTTPServerAttributesFromHTTPRequest(h.operation, route, r)...)
+	attrs = append(attrs, headerAttributes(h.requestHeaders, r.Header)...)
+	attrs = append(attrs, baggageAttributes(ctx, h.baggageKeys)...)
+	opts := make([]trace.SpanStartOption, 0, len(h.spanStartOptions)+4)
+	opts = append(opts, trace.WithAttributes(attrs...))
+	opts = append(opts, h.spanStartOptions...)
+	if h.spanOptionsFn != nil || h.spanAttributesFn != nil {
+		opts = append(opts, requestSpanOptions(r.WithContext(ctx), h.spanOptionsFn, h.spanAttributesFn)...)
+	}
 	if h.publicEndpoint || (h.publicEndpointFn != nil && h.publicEndpointFn(r.WithContext(ctx))) {
 		opts = append(opts, trace.WithNewRoot())
 		// Linking incoming span context if any for public endpoint.
@@ -137,12 +214,6 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	opts = append([]trace.SpanStartOption{
-		trace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", r)...),
-		trace.WithAttributes(semconv.EndUserAttributesFromHTTPRequest(r)...),
-		trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(h.operation, "", r)...),
-	}, opts...) // start with the configured options
-
 	tracer := h.tracer
 
 	if tracer == nil {
@@ -153,8 +224,23 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	ctx, span := tracer.Start(ctx, h.spanNameFormatter(h.operation, r), opts...)
-	defer span.End()
+	// A configured span name formatter takes precedence over the route.
+	var spanName string
+	switch {
+	case h.spanNameFormatter != nil:
+		spanName = h.spanNameFormatter(h.operation, r)
+	case route != "":
+		spanName = route
+	default:
+		spanName = defaultHandlerFormatter(h.operation, r)
+	}
+	ctx, span := tracer.Start(ctx, spanName, opts...)
+	var endOpts []trace.SpanEndOption
+	defer func() { span.End(endOpts...) }()
+
+	if h.respPropagators != nil {
+		h.respPropagators.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+	}
 
 	readRecordFunc := func(int64) {}
 	if h.readEvent {
@@ -170,6 +256,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Body != nil {
 		bw.ReadCloser = r.Body
 		bw.record = readRecordFunc
+		bw.capture = h.requestBody.newCapture(r.Header.Get("Content-Type"))
 		r.Body = &bw
 	}
 
@@ -180,44 +267,160 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	rww := &respWriterWrapper{ResponseWriter: w, record: writeRecordFunc, ctx: ctx, props: h.propagators}
+	// The hijacked connection may be written concurrently, it only records
+	// the write events.
+	hijackedWriteRecordFunc := writeRecordFunc
+	if h.firstByteEvent || h.progressInterval > 0 {
+		writeRecordFunc = h.streamRecordFunc(span, requestStartTime, writeRecordFunc)
+	}
+
+	rww := respWriterWrapperPool.Get().(*respWriterWrapper)
+	defer func() {
+		rww.reset()
+		respWriterWrapperPool.Put(rww)
+	}()
+	rww.ResponseWriter = w
+	rww.record = writeRecordFunc
+	rww.ctx = ctx
+	rww.props = h.propagators
+	rww.captureConfig = h.responseBody
+	rww.hijackReadRecord = readRecordFunc
+	rww.hijackWriteRecord = hijackedWriteRecordFunc
 
 	// Wrap w to use our ResponseWriter methods while also exposing
 	// other interfaces that w may implement (http.CloseNotifier,
 	// http.Flusher, http.Hijacker, http.Pusher, io.ReaderFrom).
-
-	w = httpsnoop.Wrap(w, httpsnoop.Hooks{
-		Header: func(httpsnoop.HeaderFunc) httpsnoop.HeaderFunc {
-			return rww.Header
-		},
-		Write: func(httpsnoop.WriteFunc) httpsnoop.WriteFunc {
-			return rww.Write
-		},
-		WriteHeader: func(httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
-			return rww.WriteHeader
-		},
-	})
+	w = httpsnoop.Wrap(w, rww.hooks)
 
 	labeler := &Labeler{}
 	ctx = injectLabeler(ctx, labeler)
 
-	h.handler.ServeHTTP(w, r.WithContext(ctx))
+	p := h.serve(w, r.WithContext(ctx))
+	if p != nil && !p.repanic && !rww.wroteHeader {
+		rww.WriteHeader(http.StatusInternalServerError)
+	}
 
-	setAfterServeAttributes(span, bw.read, rww.written, rww.statusCode, bw.err, rww.err)
+	read, written, endTime := bw.read, rww.written, time.Now()
+	// The span start attributes may be retained by the tracer, use a new slice.
+	attrs = nil
+	if rww.conn != nil {
+		// The connection was upgraded: the request ends at the upgrade, but
+		// its data includes the one exchanged on the connection so far.
+		read += atomic.LoadInt64(&rww.conn.read)
+		written += atomic.LoadInt64(&rww.conn.written)
+		endTime = rww.upgradedAt
+		endOpts = append(endOpts, trace.WithTimestamp(endTime))
+		attrs = append(attrs, UpgradedKey.Bool(true))
+	}
+	attrs = append(attrs, bw.capture.attributes(RequestBodyKey, RequestBodyTruncatedKey)...)
+	attrs = append(attrs, rww.capture.attributes(ResponseBodyKey, ResponseBodyTruncatedKey)...)
+	attrs = append(attrs, headerAttributes(h.responseHeaders, rww.Header())...)
+
+	setAfterServeAttributes(span, h.spanStatusFn, attrs, read, written, rww.statusCode, bw.err, rww.err)
+	if p != nil {
+		p.record(span)
+	}
 
 	// Add metrics
-	attributes := append(labeler.Get(), semconv.HTTPServerMetricAttributesFromHTTPRequest(h.operation, r)...)
-	h.counters[RequestContentLength].Add(ctx, bw.read, attributes...)
-	h.counters[ResponseContentLength].Add(ctx, rww.written, attributes...)
+	attributes := semconv.HTTPServerMetricAttributesFromHTTPRequest(h.operation, r)
+	if route != "" {
+		attributes = append(attributes, semconv.HTTPRouteKey.String(route))
+	}
+	attributes = labeler.appendTo(attributes)
+	if h.metricAttributesFn != nil {
+		attributes = h.metricAttributesFn(r, attributes)
+	}
+	h.counters[RequestContentLength].Add(ctx, read, attributes...)
+	h.counters[ResponseContentLength].Add(ctx, written, attributes...)
+	h.sizeRecorders[RequestSize].Record(ctx, read, attributes...)
+	h.sizeRecorders[ResponseSize].Record(ctx, written, attributes...)
 
 	// Use floating point division here for higher precision (instead of Millisecond method).
-	elapsedTime := float64(time.Since(requestStartTime)) / float64(time.Millisecond)
+	elapsedTime := float64(endTime.Sub(requestStartTime)) / float64(time.Millisecond)
 
 	h.valueRecorders[ServerLatency].Record(ctx, elapsedTime, attributes...)
+
+	if p != nil && p.repanic {
+		panic(p.value)
+	}
+}
+
+// recoveredPanic is a panic recovered from the handler wrapped by a Handler.
+type recoveredPanic struct {
+	value   interface{}
+	stack   []byte
+	repanic bool
+}
+
+// record records p as an exception event of span, and sets the span status
+// to Error.
+func (p *recoveredPanic) record(span trace.Span) {
+	if p.value == http.ErrAbortHandler {
+		// The request was aborted on purpose.
+		return
+	}
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionTypeKey.String(fmt.Sprintf("%T", p.value)),
+		semconv.ExceptionMessageKey.String(fmt.Sprint(p.value)),
+		semconv.ExceptionStacktraceKey.String(string(p.stack)),
+		semconv.ExceptionEscapedKey.Bool(p.repanic),
+	))
+	span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", p.value))
+}
+
+// serve calls the handler wrapped by h, recovering its panic if h is
+// configured to.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) (p *recoveredPanic) {
+	if !h.recoverPanics {
+		h.handler.ServeHTTP(w, r)
+		return nil
+	}
+
+	// recover returns nil for panic(nil), rely on a flag instead.
+	panicked := true
+	defer func() {
+		if panicked {
+			v := recover()
+			p = &recoveredPanic{
+				value:   v,
+				stack:   debug.Stack(),
+				repanic: h.repanic || v == http.ErrAbortHandler,
+			}
+		}
+	}()
+	h.handler.ServeHTTP(w, r)
+	panicked = false
+	return nil
+}
+
+// streamRecordFunc returns a function recording the bytes written to the
+// response body with record, as well as the first byte and progress events of
+// h on span.
+func (h *Handler) streamRecordFunc(span trace.Span, start time.Time, record func(int64)) func(int64) {
+	var (
+		written      int64
+		lastProgress = start
+	)
+	return func(n int64) {
+		record(n)
+		if n <= 0 {
+			return
+		}
+		if written == 0 && h.firstByteEvent {
+			span.AddEvent("first_byte")
+		}
+		written += n
+		if h.progressInterval > 0 {
+			if now := time.Now(); now.Sub(lastProgress) >= h.progressInterval {
+				lastProgress = now
+				span.AddEvent("progress", trace.WithAttributes(WroteBytesKey.Int64(written)), trace.WithTimestamp(now))
+			}
+		}
+	}
 }
 
-func setAfterServeAttributes(span trace.Span, read, wrote int64, statusCode int, rerr, werr error) {
-	attributes := []attribute.KeyValue{}
+func setAfterServeAttributes(span trace.Span, spanStatus func(int) (codes.Code, string), attributes []attribute.KeyValue, read, wrote int64, statusCode int, rerr, werr error) {
+	attributes = append(attributes, ResponseBodySizeKey.Int64(wrote))
 
 	// TODO: Consider adding an event after each read and write, possibly as an
 	// option (defaulting to off), so as to not create needlessly verbose spans.
@@ -232,7 +435,7 @@ func setAfterServeAttributes(span trace.Span, read, wrote int64, statusCode int,
 	}
 	if statusCode > 0 {
 		attributes = append(attributes, semconv.HTTPAttributesFromHTTPStatusCode(statusCode)...)
-		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(statusCode, trace.SpanKindServer))
+		span.SetStatus(spanStatus(statusCode))
 	}
 	if werr != nil && werr != io.EOF {
 		attributes = append(attributes, WriteErrorKey.String(werr.Error()))
@@ -15,6 +15,7 @@
 package otelhttp_test
 
 import (
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +25,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestResponseWriterImplementsFlusher(t *testing.T) {
@@ -39,6 +42,34 @@ func TestResponseWriterImplementsFlusher(t *testing.T) {
 	h.ServeHTTP(httptest.NewRecorder(), r)
 }
 
+func TestResponseWriterPreservesInterfaces(t *testing.T) {
+	var (
+		hijacker   bool
+		readerFrom bool
+	)
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, hijacker = w.(http.Hijacker)
+			_, readerFrom = w.(io.ReaderFrom)
+		}), "test_handler",
+	)
+
+	// The http.ResponseWriter of the net/http server implements both interfaces.
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.True(t, hijacker, "should implement http.Hijacker")
+	assert.True(t, readerFrom, "should implement io.ReaderFrom")
+
+	// httptest.ResponseRecorder does not implement http.Hijacker.
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	assert.False(t, hijacker, "should not implement http.Hijacker")
+}
+
 // This use case is important as we make sure the body isn't mutated
 // when it is nil. This is a common use case for tests where the request
 // is directly passed to the handler.
@@ -59,3 +90,20 @@ func TestHandlerReadingNilBodySuccess(t *testing.T) {
 	h.ServeHTTP(rr, r)
 	assert.Equal(t, 200, rr.Result().StatusCode)
 }
+
+func BenchmarkHandlerNoop(b *testing.B) {
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(trace.NewNoopTracerProvider()),
+		otelhttp.WithMeterProvider(metric.NewNoopMeterProvider()),
+	)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// capturedHeader is an HTTP header recorded as a span attribute.
+type capturedHeader struct {
+	name string
+	key  attribute.Key
+}
+
+// newCapturedHeaders returns the headers named by names recorded as the
+// attributes prefixed by prefix, e.g. "http.request.header.", followed by the
+// name of the header lowercased with '-' replaced by '_'.
+func newCapturedHeaders(prefix string, names []string) []capturedHeader {
+	headers := make([]capturedHeader, 0, len(names))
+	for _, name := range names {
+		key := strings.ReplaceAll(strings.ToLower(name), "-", "_")
+		headers = append(headers, capturedHeader{
+			name: http.CanonicalHeaderKey(name),
+			key:  attribute.Key(prefix + key),
+		})
+	}
+	return headers
+}
+
+// headerAttributes returns the attributes of the captured headers present
+// in h, with all their values.
+func headerAttributes(headers []capturedHeader, h http.Header) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, header := range headers {
+		if values := h.Values(header.name); len(values) > 0 {
+			attrs = append(attrs, header.key.StringSlice(values))
+		}
+	}
+	return attrs
+}
@@ -44,6 +44,13 @@ func (l *Labeler) Get() []attribute.KeyValue {
 	return ret
 }
 
+// appendTo appends the attributes added to the Labeler to attrs.
+func (l *Labeler) appendTo(attrs []attribute.KeyValue) []attribute.KeyValue {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append(attrs, l.attributes...)
+}
+
 type labelerContextKeyType int
 
 const lablelerContextKey labelerContextKeyType = 0
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// resendCounter counts the attempts made by a Transport to send a request.
+type resendCounter struct {
+	attempts int64 // accessed atomically
+}
+
+type resendContextKeyType int
+
+const resendContextKey resendContextKeyType = 0
+
+// ContextWithResendCount returns a copy of ctx in which the attempts made by a
+// Transport configured with WithResendCount to send requests with the
+// returned context are counted.  It is meant to be used when the requests are
+// resent by a http.RoundTripper wrapping the Transport, e.g. to retry them:
+// the first attempt has no http.request.resend_count attribute, the next ones
+// have it set to 1, 2, and so on.
+func ContextWithResendCount(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resendContextKey, &resendCounter{})
+}
+
+// resendCount returns the number of times r was already sent: counted in its
+// context if it was returned by ContextWithResendCount, or the number of
+// redirects followed by a http.Client to create it otherwise.
+func resendCount(r *http.Request) int64 {
+	if c, ok := r.Context().Value(resendContextKey).(*resendCounter); ok {
+		return atomic.AddInt64(&c.attempts, 1) - 1
+	}
+	var n int64
+	for res := r.Response; res != nil && res.Request != nil; res = res.Request.Response {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceResponseHeader = "traceresponse"
+	serverTimingHeader  = "Server-Timing"
+)
+
+// TraceResponse is a propagator writing the span context to the
+// traceresponse header of the W3C Trace Context Level 2 specification.  It
+// is meant to be used with WithResponsePropagators; its Extract method does
+// nothing.
+type TraceResponse struct{}
+
+var _ propagation.TextMapPropagator = TraceResponse{}
+
+// Inject sets the traceresponse header from the span context in ctx.
+func (TraceResponse) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if v, ok := traceparent(ctx); ok {
+		carrier.Set(traceResponseHeader, v)
+	}
+}
+
+// Extract returns ctx unchanged.
+func (TraceResponse) Extract(ctx context.Context, _ propagation.TextMapCarrier) context.Context {
+	return ctx
+}
+
+// Fields returns the header set by Inject.
+func (TraceResponse) Fields() []string {
+	return []string{traceResponseHeader}
+}
+
+// ServerTiming is a propagator adding the span context as the traceparent
+// metric of the Server-Timing header, which can be read by browsers.  It is
+// meant to be used with WithResponsePropagators; its Extract method does
+// nothing.
+type ServerTiming struct{}
+
+var _ propagation.TextMapPropagator = ServerTiming{}
+
+// Inject adds the traceparent metric to the Server-Timing header from the span
+// context in ctx.
+func (ServerTiming) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	v, ok := traceparent(ctx)
+	if !ok {
+		return
+	}
+	v = fmt.Sprintf("traceparent;desc=%q", v)
+	if timing := carrier.Get(serverTimingHeader); timing != "" {
+		v = timing + ", " + v
+	}
+	carrier.Set(serverTimingHeader, v)
+}
+
+// Extract returns ctx unchanged.
+func (ServerTiming) Extract(ctx context.Context, _ propagation.TextMapCarrier) context.Context {
+	return ctx
+}
+
+// Fields returns the header set by Inject.
+func (ServerTiming) Fields() []string {
+	return []string{serverTimingHeader}
+}
+
+// traceparent returns the span context in ctx formatted as a W3C traceparent
+// header value, and false if ctx has no valid span context.
+func traceparent(ctx context.Context) (string, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", false
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags()&trace.FlagsSampled), true
+}
@@ -15,15 +15,18 @@
 package test
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -112,6 +115,766 @@ func TestHandlerBasics(t *testing.T) {
 	}
 }
 
+func TestHandlerSizeMetrics(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := ioutil.ReadAll(r.Body); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.WriteString(w, "hello world"); err != nil {
+				t.Fatal(err)
+			}
+		}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+	)
+
+	r, err := http.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader("foo"))
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+
+	rec, err := metricExporter.GetByName(otelhttp.RequestSize)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), rec.Count)
+	assert.Equal(t, int64(3), rec.Sum.AsInt64())
+
+	rec, err = metricExporter.GetByName(otelhttp.ResponseSize)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), rec.Count)
+	assert.Equal(t, int64(11), rec.Sum.AsInt64())
+}
+
+func TestHandlerWithMetrics(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := io.WriteString(w, "hello world"); err != nil {
+				t.Fatal(err)
+			}
+		}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithMetrics(otelhttp.ServerLatency, otelhttp.ResponseSize),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+
+	var names []string
+	for _, rec := range metricExporter.GetRecords() {
+		names = append(names, rec.InstrumentName)
+	}
+	assert.ElementsMatch(t, []string{otelhttp.ServerLatency, otelhttp.ResponseSize}, names)
+}
+
+func TestHandlerLabelerRetained(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	var retained *otelhttp.Labeler
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l, _ := otelhttp.LabelerFromContext(r.Context())
+			if retained == nil {
+				retained = l
+			}
+		}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// A Labeler retained past its request does not label other requests.
+	retained.Add(attribute.String("retained", "true"))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+	for _, rec := range metricExporter.GetRecords() {
+		assert.NotContains(t, rec.Attributes, attribute.String("retained", "true"))
+	}
+}
+
+func TestHandlerWithRouteExtractor(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithRouteExtractor(func(r *http.Request) string {
+			if strings.HasPrefix(r.URL.Path, "/users/") {
+				return "/users/{id}"
+			}
+			return ""
+		}),
+	)
+
+	for _, path := range []string{"/users/42", "/other"} {
+		r, err := http.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		require.NoError(t, err)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "/users/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRouteKey.String("/users/{id}"))
+	assert.Equal(t, "test_handler", spans[1].Name())
+	for _, kv := range spans[1].Attributes() {
+		assert.NotEqual(t, semconv.HTTPRouteKey, kv.Key)
+	}
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+	_, err := metricExporter.GetByNameAndAttributes(otelhttp.ServerLatency, []attribute.KeyValue{
+		semconv.HTTPRouteKey.String("/users/{id}"),
+	})
+	assert.NoError(t, err)
+}
+
+func TestHandlerWithRouteExtractorAndSpanNameFormatter(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return operation + " " + r.Method
+		}),
+		otelhttp.WithRouteExtractor(func(r *http.Request) string {
+			return "/users/{id}"
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/users/42", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test_handler GET", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRouteKey.String("/users/{id}"))
+}
+
+func TestHandlerWithCapturedRequestHeaders(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedRequestHeaders([]string{"X-Tenant-ID", "x-correlation-id", "X-Missing"}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	r.Header.Set("X-Tenant-Id", "tenant")
+	r.Header.Add("X-Correlation-Id", "a")
+	r.Header.Add("X-Correlation-Id", "b")
+	r.Header.Set("X-Other", "other")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, attribute.StringSlice("http.request.header.x_tenant_id", []string{"tenant"}))
+	assert.Contains(t, attrs, attribute.StringSlice("http.request.header.x_correlation_id", []string{"a", "b"}))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("http.request.header.x_missing"), kv.Key)
+		assert.NotEqual(t, attribute.Key("http.request.header.x_other"), kv.Key)
+	}
+}
+
+func TestHandlerWithCapturedResponseHeaders(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("X-Other", "other")
+			w.WriteHeader(http.StatusOK)
+		}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedResponseHeaders([]string{"content-encoding", "Retry-After"}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.header.content_encoding", []string{"gzip"}))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("http.response.header.retry_after"), kv.Key)
+		assert.NotEqual(t, attribute.Key("http.response.header.x_other"), kv.Key)
+	}
+}
+
+func TestHandlerWithResponsePropagators(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Server-Timing", "db;dur=53")
+		}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithResponsePropagators(propagation.NewCompositeTextMapPropagator(
+			otelhttp.TraceResponse{},
+			otelhttp.ServerTiming{},
+		)),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	sc := spans[0].SpanContext()
+	traceparent := fmt.Sprintf("00-%s-%s-01", sc.TraceID(), sc.SpanID())
+
+	res := rr.Result()
+	assert.Equal(t, traceparent, res.Header.Get("traceresponse"))
+	assert.Equal(t, []string{
+		fmt.Sprintf("traceparent;desc=%q", traceparent),
+		"db;dur=53",
+	}, res.Header.Values("Server-Timing"))
+}
+
+func TestHandlerWithoutResponsePropagators(t *testing.T) {
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(sdktrace.NewTracerProvider()),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	assert.Empty(t, rr.Result().Header.Get("traceresponse"))
+	assert.Empty(t, rr.Result().Header.Get("Server-Timing"))
+}
+
+func TestHandlerWithSpanAttributesFn(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanAttributesFn(func(r *http.Request) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("tenant", r.Header.Get("X-Tenant"))}
+		}),
+		otelhttp.WithSpanOptionsFn(func(r *http.Request) []trace.SpanStartOption {
+			return []trace.SpanStartOption{trace.WithAttributes(attribute.Bool("flag", true))}
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	r.Header.Set("X-Tenant", "acme")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("tenant", "acme"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Bool("flag", true))
+}
+
+func TestHandlerWithMetricAttributesFn(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithMetricAttributesFn(func(r *http.Request, attrs []attribute.KeyValue) []attribute.KeyValue {
+			var filtered []attribute.KeyValue
+			for _, kv := range attrs {
+				if kv.Key != semconv.HTTPHostKey {
+					filtered = append(filtered, kv)
+				}
+			}
+			return append(filtered, attribute.String("tenant", r.Header.Get("X-Tenant")))
+		}),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	r.Header.Set("X-Tenant", "acme")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+	records := metricExporter.GetRecords()
+	require.NotEmpty(t, records)
+	for _, rec := range records {
+		assert.NotContains(t, rec.Attributes, semconv.HTTPHostKey.String("localhost"))
+		assert.Contains(t, rec.Attributes, attribute.String("tenant", "acme"))
+	}
+
+	// The span attributes are not affected.
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPHostKey.String("localhost"))
+	assert.NotContains(t, spans[0].Attributes(), attribute.String("tenant", "acme"))
+}
+
+func TestHandlerReadFromAndFlush(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.(http.Flusher).Flush()
+			n, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("hello world"))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(11), n)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+	)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, "hello world", string(body))
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), otelhttp.WroteBytesKey.Int64(11))
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPStatusCodeKey.Int(http.StatusOK))
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+	rec, err := metricExporter.GetByName(otelhttp.ResponseSize)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), rec.Sum.AsInt64())
+}
+
+func TestHandlerUpgrade(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	upgraded := make(chan time.Time, 1)
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			defer conn.Close()
+			upgraded <- time.Now()
+
+			_, err = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\n")
+			require.NoError(t, err)
+			msg, err := rw.Reader.ReadString('\n')
+			require.NoError(t, err)
+			_, err = io.WriteString(conn, msg)
+			require.NoError(t, err)
+
+			// The duration of the request should not include the time spent on
+			// the upgraded connection.
+			time.Sleep(50 * time.Millisecond)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMessageEvents(otelhttp.WriteEvents),
+	)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\nhello\n")
+	require.NoError(t, err)
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+
+	upgradedAt := <-upgraded
+	require.Eventually(t, func() bool { return len(spanRecorder.Ended()) == 1 }, time.Second, 10*time.Millisecond)
+
+	span := spanRecorder.Ended()[0]
+	attrs := span.Attributes()
+	assert.Contains(t, attrs, otelhttp.UpgradedKey.Bool(true))
+	assert.Less(t, span.EndTime().Sub(upgradedAt), 50*time.Millisecond)
+
+	var written int64
+	for _, kv := range attrs {
+		if kv.Key == otelhttp.WroteBytesKey {
+			written = kv.Value.AsInt64()
+		}
+	}
+	assert.Greater(t, written, int64(len("hello\n")))
+	assert.NotEmpty(t, span.Events())
+}
+
+func TestHandlerStreamingEvents(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i < 3; i++ {
+				time.Sleep(20 * time.Millisecond)
+				_, err := io.WriteString(w, "data: hello\n\n")
+				assert.NoError(t, err)
+				w.(http.Flusher).Flush()
+			}
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMessageEvents(otelhttp.FirstByteEvents),
+		otelhttp.WithProgressEvents(10*time.Millisecond),
+	)
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, []string{"chunked"}, res.TransferEncoding)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), otelhttp.ResponseBodySizeKey.Int64(int64(len(body))))
+
+	var names []string
+	var progress []int64
+	for _, e := range spans[0].Events() {
+		names = append(names, e.Name)
+		if e.Name == "progress" {
+			require.Len(t, e.Attributes, 1)
+			progress = append(progress, e.Attributes[0].Value.AsInt64())
+		}
+	}
+	require.NotEmpty(t, names)
+	assert.Equal(t, "first_byte", names[0])
+	assert.Equal(t, []int64{13, 26, 39}, progress)
+}
+
+func TestHandlerResponseBodySizeEmpty(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), otelhttp.ResponseBodySizeKey.Int64(0))
+	assert.Empty(t, spans[0].Events())
+}
+
+func TestHandlerWithBaggageAttributes(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithPropagators(propagation.Baggage{}),
+		otelhttp.WithBaggageAttributes("tenant", "missing"),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	r.Header.Set("baggage", "tenant=acme,other=value")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, attribute.String("tenant", "acme"))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("other"), kv.Key)
+		assert.NotEqual(t, attribute.Key("missing"), kv.Key)
+	}
+}
+
+type attributeSampler struct {
+	key attribute.Key
+}
+
+func (s attributeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, kv := range p.Attributes {
+		if kv.Key == s.key && kv.Value.AsBool() {
+			return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+		}
+	}
+	return sdktrace.AlwaysSample().ShouldSample(p)
+}
+
+func (s attributeSampler) Description() string {
+	return "attributeSampler"
+}
+
+func TestHandlerWithSpanOptionsFnSampling(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(attributeSampler{key: "health_check"}),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+	remoteSpan := trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}
+	prop := propagation.TraceContext{}
+
+	var remote []bool
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithPropagators(prop),
+		otelhttp.WithSpanOptionsFn(func(r *http.Request) []trace.SpanStartOption {
+			remote = append(remote, trace.SpanContextFromContext(r.Context()).IsRemote())
+			if r.URL.Path == "/healthz" {
+				return []trace.SpanStartOption{
+					trace.WithNewRoot(),
+					trace.WithAttributes(attribute.Bool("health_check", true)),
+				}
+			}
+			return nil
+		}),
+	)
+
+	for _, path := range []string{"/healthz", "/api"} {
+		r, err := http.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		require.NoError(t, err)
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(remoteSpan))
+		prop.Inject(ctx, propagation.HeaderCarrier(r.Header))
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// The function should see the extracted span context.
+	assert.Equal(t, []bool{true, true}, remote)
+
+	// The health check should be dropped by the sampler.
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "test_handler", spans[0].Name())
+	assert.Equal(t, remoteSpan.TraceID, spans[0].SpanContext().TraceID())
+}
+
+func TestHandlerWithPanicRecovery(t *testing.T) {
+	for _, repanic := range []bool{false, true} {
+		t.Run(strconv.FormatBool(repanic), func(t *testing.T) {
+			spanRecorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+			meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+			h := otelhttp.NewHandler(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					panic("boom")
+				}), "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithMeterProvider(meterProvider),
+				otelhttp.WithPanicRecovery(repanic),
+			)
+
+			rr := httptest.NewRecorder()
+			serve := func() { h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil)) }
+			if repanic {
+				assert.PanicsWithValue(t, "boom", serve)
+			} else {
+				assert.NotPanics(t, serve)
+				assert.Equal(t, http.StatusInternalServerError, rr.Result().StatusCode)
+			}
+
+			spans := spanRecorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, codes.Error, spans[0].Status().Code)
+			assert.Equal(t, "panic: boom", spans[0].Status().Description)
+			require.Len(t, spans[0].Events(), 1)
+			e := spans[0].Events()[0]
+			assert.Equal(t, semconv.ExceptionEventName, e.Name)
+			assert.Contains(t, e.Attributes, semconv.ExceptionMessageKey.String("boom"))
+			assert.Contains(t, e.Attributes, semconv.ExceptionEscapedKey.Bool(repanic))
+			for _, kv := range e.Attributes {
+				if kv.Key == semconv.ExceptionStacktraceKey {
+					assert.Contains(t, kv.Value.AsString(), "TestHandlerWithPanicRecovery")
+				}
+			}
+
+			require.NoError(t, metricExporter.Collect(context.Background()))
+			_, err := metricExporter.GetByName(otelhttp.ServerLatency)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHandlerWithPanicRecoveryAbortHandler(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithPanicRecovery(false),
+	)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Empty(t, spans[0].Events())
+}
+
+func TestHandlerWithCapturedBodies(t *testing.T) {
+	testCases := []struct {
+		name            string
+		requestType     string
+		responseType    string
+		wantRequest     []attribute.KeyValue
+		wantResponse    []attribute.KeyValue
+		missingResponse bool
+	}{
+		{
+			name:         "captured",
+			requestType:  "application/json; charset=utf-8",
+			responseType: "text/plain",
+			wantRequest: []attribute.KeyValue{
+				otelhttp.RequestBodyKey.String(`{"name":`),
+				otelhttp.RequestBodyTruncatedKey.Bool(true),
+			},
+			wantResponse: []attribute.KeyValue{
+				otelhttp.ResponseBodyKey.String("hello"),
+			},
+		},
+		{
+			name:         "detected response content type",
+			requestType:  "application/json",
+			responseType: "",
+			wantRequest: []attribute.KeyValue{
+				otelhttp.RequestBodyKey.String(`{"name":`),
+				otelhttp.RequestBodyTruncatedKey.Bool(true),
+			},
+			wantResponse: []attribute.KeyValue{
+				otelhttp.ResponseBodyKey.String("hello"),
+			},
+		},
+		{
+			name:            "not allowed",
+			requestType:     "application/octet-stream",
+			responseType:    "image/png",
+			missingResponse: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spanRecorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+			h := otelhttp.NewHandler(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, err := ioutil.ReadAll(r.Body)
+					assert.NoError(t, err)
+					if tc.responseType != "" {
+						w.Header().Set("Content-Type", tc.responseType)
+					}
+					_, err = io.WriteString(w, "hello")
+					assert.NoError(t, err)
+				}), "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithCapturedRequestBody(8, "application/json"),
+				otelhttp.WithCapturedResponseBody(8, "text/*"),
+			)
+
+			r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"value"}`))
+			r.Header.Set("Content-Type", tc.requestType)
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			spans := spanRecorder.Ended()
+			require.Len(t, spans, 1)
+			attrs := spans[0].Attributes()
+			for _, kv := range append(tc.wantRequest, tc.wantResponse...) {
+				assert.Contains(t, attrs, kv)
+			}
+			for _, kv := range attrs {
+				if len(tc.wantRequest) == 0 {
+					assert.NotEqual(t, otelhttp.RequestBodyKey, kv.Key)
+				}
+				if tc.missingResponse {
+					assert.NotEqual(t, otelhttp.ResponseBodyKey, kv.Key)
+				}
+				assert.NotEqual(t, otelhttp.ResponseBodyTruncatedKey, kv.Key)
+			}
+		})
+	}
+}
+
+func TestHandlerWithCapturedBodyNegativeLimit(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			_, err = io.WriteString(w, "hello")
+			assert.NoError(t, err)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedRequestBody(-1),
+		otelhttp.WithCapturedResponseBody(-1),
+	)
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"value"}`))
+	h.ServeHTTP(rr, r)
+	assert.Equal(t, "hello", rr.Body.String())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, otelhttp.RequestBodyKey, kv.Key)
+		assert.NotEqual(t, otelhttp.ResponseBodyKey, kv.Key)
+	}
+}
+
 func TestHandlerRequestWithTraceContext(t *testing.T) {
 	rr := httptest.NewRecorder()
 
@@ -191,6 +954,41 @@ func TestWithPublicEndpoint(t *testing.T) {
 	require.True(t, sc.Equal(done[0].Links()[0].SpanContext), "should link incoming span context")
 }
 
+func TestWithPublicEndpointIgnoresRemoteSampling(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+	// Not sampled remote span context.
+	remoteSpan := trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x01},
+		Remote:  true,
+	}
+	prop := propagation.TraceContext{}
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		"test_handler",
+		otelhttp.WithPublicEndpoint(),
+		otelhttp.WithPropagators(prop),
+		otelhttp.WithTracerProvider(provider),
+	)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(remoteSpan))
+	prop.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// The span should be sampled by the local sampler as a root span.
+	done := spanRecorder.Ended()
+	require.Len(t, done, 1)
+	assert.True(t, done[0].SpanContext().IsSampled())
+	assert.False(t, done[0].Parent().IsValid())
+}
+
 func TestWithPublicEndpointFn(t *testing.T) {
 	remoteSpan := trace.SpanContextConfig{
 		TraceID:    trace.TraceID{0x01},
@@ -303,3 +1101,26 @@ func TestSpanStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSpanStatusFn(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanStatusFn(func(statusCode int) (codes.Code, string) {
+			if statusCode >= 400 {
+				return codes.Error, http.StatusText(statusCode)
+			}
+			return codes.Unset, ""
+		}),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	require.Len(t, sr.Ended(), 1, "should emit a span")
+	assert.Equal(t, codes.Error, sr.Ended()[0].Status().Code)
+	assert.Equal(t, "Conflict", sr.Ended()[0].Status().Description)
+}
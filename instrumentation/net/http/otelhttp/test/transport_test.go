@@ -20,7 +20,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
+	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -28,10 +30,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -131,6 +137,284 @@ func TestTransportErrorStatus(t *testing.T) {
 	}
 }
 
+func TestTransportWithSpanStatusFn(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tr := otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanStatusFn(func(statusCode int) (codes.Code, string) {
+			if statusCode == http.StatusNotFound {
+				return codes.Ok, ""
+			}
+			return semconv.SpanStatusFromHTTPStatusCode(statusCode)
+		}),
+	)
+	c := http.Client{Transport: tr}
+	res, err := c.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Ok, spans[0].Status().Code)
+}
+
+func TestTransportWithCapturedResponseHeaders(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tr := otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedResponseHeaders([]string{"Retry-After"}),
+	)
+	c := http.Client{Transport: tr}
+	res, err := c.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.StringSlice("http.response.header.retry_after", []string{"120"}))
+}
+
+func TestTransportWithSpanAttributesFn(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tr := otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanAttributesFn(func(r *http.Request) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("tenant", "acme")}
+		}),
+	)
+	c := http.Client{Transport: tr}
+	res, err := c.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("tenant", "acme"))
+}
+
+func TestTransportWithClientErrorClassifier(t *testing.T) {
+	classifier := func(res *http.Response, err error) (codes.Code, string, string) {
+		switch {
+		case err != nil:
+			return codes.Error, err.Error(), "connection"
+		case res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable:
+			return codes.Unset, "", "retryable"
+		case res.StatusCode >= 400:
+			return codes.Error, "", strconv.Itoa(res.StatusCode)
+		}
+		return codes.Unset, "", ""
+	}
+
+	for _, tc := range []struct {
+		statusCode    int
+		wantCode      codes.Code
+		wantErrorType string
+	}{
+		{http.StatusOK, codes.Unset, ""},
+		{http.StatusTooManyRequests, codes.Unset, "retryable"},
+		{http.StatusServiceUnavailable, codes.Unset, "retryable"},
+		{http.StatusBadRequest, codes.Error, "400"},
+	} {
+		t.Run(strconv.Itoa(tc.statusCode), func(t *testing.T) {
+			spanRecorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			tr := otelhttp.NewTransport(
+				http.DefaultTransport,
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithClientErrorClassifier(classifier),
+			)
+			c := http.Client{Transport: tr}
+			res, err := c.Get(server.URL)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			spans := spanRecorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.wantCode, spans[0].Status().Code)
+			var errorType string
+			for _, kv := range spans[0].Attributes() {
+				if kv.Key == otelhttp.ErrorTypeKey {
+					errorType = kv.Value.AsString()
+				}
+			}
+			assert.Equal(t, tc.wantErrorType, errorType)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		tr := otelhttp.NewTransport(
+			http.DefaultTransport,
+			otelhttp.WithTracerProvider(provider),
+			otelhttp.WithClientErrorClassifier(classifier),
+		)
+		c := http.Client{Transport: tr}
+		_, err := c.Get(server.URL)
+		require.Error(t, err)
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), otelhttp.ErrorTypeKey.String("connection"))
+	})
+}
+
+func resendCounts(spans []sdktrace.ReadOnlySpan) []int64 {
+	counts := make([]int64, len(spans))
+	for i, s := range spans {
+		for _, kv := range s.Attributes() {
+			if kv.Key == otelhttp.ResendCountKey {
+				counts[i] = kv.Value.AsInt64()
+			}
+		}
+	}
+	return counts
+}
+
+func TestTransportResendCountRedirects(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	mux := http.NewServeMux()
+	mux.Handle("/a", http.RedirectHandler("/b", http.StatusFound))
+	mux.Handle("/b", http.RedirectHandler("/c", http.StatusFound))
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithResendCount(),
+	)}
+	res, err := c.Get(server.URL + "/a")
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, []int64{0, 1, 2}, resendCounts(spans))
+}
+
+type retryTransport struct {
+	rt       http.RoundTripper
+	attempts int
+}
+
+func (t retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var (
+		res *http.Response
+		err error
+	)
+	for i := 0; i < t.attempts; i++ {
+		res, err = t.rt.RoundTrip(r)
+		if err == nil && res.StatusCode < 500 {
+			break
+		}
+		if err == nil && i < t.attempts-1 {
+			_ = res.Body.Close()
+		}
+	}
+	return res, err
+}
+
+func TestTransportResendCountRetries(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := http.Client{Transport: retryTransport{
+		rt: otelhttp.NewTransport(
+			http.DefaultTransport,
+			otelhttp.WithTracerProvider(provider),
+			otelhttp.WithResendCount(),
+		),
+		attempts: 3,
+	}}
+	ctx := otelhttp.ContextWithResendCount(context.Background())
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	res, err := c.Do(r)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, []int64{0, 1, 2}, resendCounts(spans))
+}
+
+func TestTransportWithBaggageAttributes(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithBaggageAttributes("tenant"),
+	)}
+
+	m, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	b, err := baggage.New(m)
+	require.NoError(t, err)
+	r, err := http.NewRequestWithContext(baggage.ContextWithBaggage(context.Background(), b), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	res, err := c.Do(r)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("tenant", "acme"))
+}
+
 func TestTransportRequestWithTraceContext(t *testing.T) {
 	spanRecorder := tracetest.NewSpanRecorder()
 	provider := sdktrace.NewTracerProvider(
@@ -238,3 +522,82 @@ func TestWithHTTPTrace(t *testing.T) {
 	assert.Equal(t, spans[2].SpanContext().SpanID(), spans[0].Parent().SpanID())
 	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[2].Parent().SpanID())
 }
+
+func TestTransportMetrics(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+	content := []byte("Hello, world!")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusCreated)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	r, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("request"))
+	require.NoError(t, err)
+
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithMeterProvider(meterProvider),
+	)}
+	res, err := c.Do(r)
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	expectedAttributes := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(http.MethodPost),
+		semconv.HTTPSchemeHTTP,
+		semconv.NetPeerNameKey.String(u.Hostname()),
+		semconv.NetPeerPortKey.Int(port),
+		semconv.HTTPStatusCodeKey.Int(http.StatusCreated),
+	}
+
+	requestSize, err := metricExporter.GetByNameAndAttributes(otelhttp.ClientRequestSize, expectedAttributes)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("request")), requestSize.Sum.AsInt64())
+
+	responseSize, err := metricExporter.GetByNameAndAttributes(otelhttp.ClientResponseSize, expectedAttributes)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), responseSize.Sum.AsInt64())
+
+	latency, err := metricExporter.GetByNameAndAttributes(otelhttp.ClientLatency, expectedAttributes)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), latency.Count)
+}
+
+func TestTransportWithMetricAttributesFn(t *testing.T) {
+	meterProvider, metricExporter := metrictest.NewTestMeterProvider()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	r, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithMetricAttributesFn(func(r *http.Request, attrs []attribute.KeyValue) []attribute.KeyValue {
+			return []attribute.KeyValue{semconv.HTTPMethodKey.String(r.Method)}
+		}),
+	)}
+	res, err := c.Do(r)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.NoError(t, metricExporter.Collect(context.Background()))
+	latency, err := metricExporter.GetByName(otelhttp.ClientLatency)
+	require.NoError(t, err)
+	assert.Equal(t, []attribute.KeyValue{semconv.HTTPMethodKey.String(http.MethodGet)}, latency.Attributes)
+}
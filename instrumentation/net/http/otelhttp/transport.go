@@ -19,9 +19,17 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptrace"
+	"strconv"
+	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
@@ -32,12 +40,25 @@ import (
 type Transport struct {
 	rt http.RoundTripper
 
-	tracer            trace.Tracer
-	propagators       propagation.TextMapPropagator
-	spanStartOptions  []trace.SpanStartOption
-	filters           []Filter
-	spanNameFormatter func(string, *http.Request) string
-	clientTrace       func(context.Context) *httptrace.ClientTrace
+	tracer             trace.Tracer
+	meter              metric.Meter
+	propagators        propagation.TextMapPropagator
+	spanStartOptions   []trace.SpanStartOption
+	spanOptionsFn      func(*http.Request) []trace.SpanStartOption
+	spanAttributesFn   func(*http.Request) []attribute.KeyValue
+	metricAttributesFn func(*http.Request, []attribute.KeyValue) []attribute.KeyValue
+	filters            []Filter
+	spanNameFormatter  func(string, *http.Request) string
+	clientTrace        func(context.Context) *httptrace.ClientTrace
+	spanStatusFn       func(int) (codes.Code, string)
+	responseHeaders    []capturedHeader
+	errorClassifier    ClientErrorClassifier
+	resendCount        bool
+	baggageKeys        []string
+
+	latencyMeasure syncfloat64.Histogram
+	requestSize    syncint64.Histogram
+	responseSize   syncint64.Histogram
 }
 
 var _ http.RoundTripper = &Transport{}
@@ -59,24 +80,76 @@ func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
 	defaultOpts := []Option{
 		WithSpanOptions(trace.WithSpanKind(trace.SpanKindClient)),
 		WithSpanNameFormatter(defaultTransportFormatter),
+		WithSpanStatusFn(semconv.SpanStatusFromHTTPStatusCode),
 	}
 
 	c := newConfig(append(defaultOpts, opts...)...)
 	t.applyConfig(c)
+	t.createMeasures(c)
 
 	return &t
 }
 
 func (t *Transport) applyConfig(c *config) {
 	t.tracer = c.Tracer
+	t.meter = c.Meter
 	t.propagators = c.Propagators
 	t.spanStartOptions = c.SpanStartOptions
+	t.spanOptionsFn = c.SpanOptionsFn
+	t.spanAttributesFn = c.SpanAttributesFn
+	t.metricAttributesFn = c.MetricAttributesFn
 	t.filters = c.Filters
 	t.spanNameFormatter = c.SpanNameFormatter
 	t.clientTrace = c.ClientTrace
+	t.spanStatusFn = c.SpanStatusFn
+	t.errorClassifier = c.ErrorClassifier
+	t.resendCount = c.ResendCount
+	t.baggageKeys = c.BaggageKeys
+	t.responseHeaders = newCapturedHeaders("http.response.header.", c.ResponseHeaders)
+}
+
+func (t *Transport) createMeasures(c *config) {
+	var err error
+	t.latencyMeasure, err = c.meterFor(ClientLatency).SyncFloat64().Histogram(
+		ClientLatency,
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the outgoing requests until their response body is read"),
+	)
+	handleErr(err)
+
+	t.requestSize, err = c.meterFor(ClientRequestSize).SyncInt64().Histogram(
+		ClientRequestSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the body of the outgoing requests"),
+	)
+	handleErr(err)
+
+	t.responseSize, err = c.meterFor(ClientResponseSize).SyncInt64().Histogram(
+		ClientResponseSize,
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Size of the body of the responses to the outgoing requests"),
+	)
+	handleErr(err)
+}
+
+// transportSpanNames are the default span names of the requests of the
+// standard methods, to avoid building them for each request.
+var transportSpanNames = map[string]string{
+	http.MethodGet:     "HTTP GET",
+	http.MethodHead:    "HTTP HEAD",
+	http.MethodPost:    "HTTP POST",
+	http.MethodPut:     "HTTP PUT",
+	http.MethodPatch:   "HTTP PATCH",
+	http.MethodDelete:  "HTTP DELETE",
+	http.MethodConnect: "HTTP CONNECT",
+	http.MethodOptions: "HTTP OPTIONS",
+	http.MethodTrace:   "HTTP TRACE",
 }
 
 func defaultTransportFormatter(_ string, r *http.Request) string {
+	if name, ok := transportSpanNames[r.Method]; ok {
+		return name
+	}
 	return "HTTP " + r.Method
 }
 
@@ -84,6 +157,7 @@ func defaultTransportFormatter(_ string, r *http.Request) string {
 // before handing the request to the configured base RoundTripper. The created span will
 // end when the response body is closed or when a read from the body returns io.EOF.
 func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	requestStartTime := time.Now()
 	for _, f := range t.filters {
 		if !f(r) {
 			// Simply pass through to the base RoundTripper if a filter rejects the request
@@ -102,6 +176,17 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 
 	opts := append([]trace.SpanStartOption{}, t.spanStartOptions...) // start with the configured options
+	if t.spanOptionsFn != nil || t.spanAttributesFn != nil {
+		opts = append(opts, requestSpanOptions(r, t.spanOptionsFn, t.spanAttributesFn)...)
+	}
+	if len(t.baggageKeys) > 0 {
+		opts = append(opts, trace.WithAttributes(baggageAttributes(r.Context(), t.baggageKeys)...))
+	}
+	if t.resendCount {
+		if n := resendCount(r); n > 0 {
+			opts = append(opts, trace.WithAttributes(ResendCountKey.Int64(n)))
+		}
+	}
 
 	ctx, span := tracer.Start(r.Context(), t.spanNameFormatter("", r), opts...)
 
@@ -113,35 +198,106 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	span.SetAttributes(semconv.HTTPClientAttributesFromHTTPRequest(r)...)
 	t.propagators.Inject(ctx, propagation.HeaderCarrier(r.Header))
 
+	// Wrapping http.NoBody would make the request length unknown to the
+	// base RoundTripper.
+	var bw bodyWrapper
+	if r.Body != nil && r.Body != http.NoBody {
+		bw.ReadCloser = r.Body
+		bw.record = func(int64) {}
+		r.Body = &bw
+	}
+
+	attributes := clientMetricAttributes(r)
+	recordMetrics := func(statusCode int, responseSize int64) {
+		attrs := attributes
+		if statusCode > 0 {
+			attrs = append(attrs, semconv.HTTPStatusCodeKey.Int(statusCode))
+		}
+		if t.metricAttributesFn != nil {
+			attrs = t.metricAttributesFn(r, attrs)
+		}
+		t.requestSize.Record(ctx, bw.read, attrs...)
+		t.responseSize.Record(ctx, responseSize, attrs...)
+
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedTime := float64(time.Since(requestStartTime)) / float64(time.Millisecond)
+		t.latencyMeasure.Record(ctx, elapsedTime, attrs...)
+	}
+
 	res, err := t.rt.RoundTrip(r)
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		if t.errorClassifier != nil {
+			t.classify(span, nil, err)
+		} else {
+			span.SetStatus(codes.Error, err.Error())
+		}
 		span.End()
+		recordMetrics(0, 0)
 		return res, err
 	}
 
 	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(res.StatusCode)...)
-	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(res.StatusCode))
-	res.Body = newWrappedBody(span, res.Body)
+	span.SetAttributes(headerAttributes(t.responseHeaders, res.Header)...)
+	if t.errorClassifier != nil {
+		t.classify(span, res, nil)
+	} else {
+		span.SetStatus(t.spanStatusFn(res.StatusCode))
+	}
+	res.Body = newWrappedBody(span, res.Body, func(read int64) {
+		recordMetrics(res.StatusCode, read)
+	})
 
 	return res, err
 }
 
+// classify sets the span status and error.type attribute returned by the
+// errorClassifier of t.
+func (t *Transport) classify(span trace.Span, res *http.Response, err error) {
+	code, description, errorType := t.errorClassifier(res, err)
+	span.SetStatus(code, description)
+	if errorType != "" {
+		span.SetAttributes(ErrorTypeKey.String(errorType))
+	}
+}
+
+// clientMetricAttributes returns the attributes of the metrics of the
+// outgoing request r.  Only low cardinality attributes are used.
+func clientMetricAttributes(r *http.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(r.Method),
+	}
+	if r.URL != nil {
+		if r.URL.Scheme != "" {
+			attrs = append(attrs, semconv.HTTPSchemeKey.String(r.URL.Scheme))
+		}
+		if host := r.URL.Hostname(); host != "" {
+			attrs = append(attrs, semconv.NetPeerNameKey.String(host))
+		}
+		if port := r.URL.Port(); port != "" {
+			if p, err := strconv.Atoi(port); err == nil {
+				attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+			}
+		}
+	}
+	return attrs
+}
+
 // newWrappedBody returns a new and appropriately scoped *wrappedBody as an
 // io.ReadCloser. If the passed body implements io.Writer, the returned value
-// will implement io.ReadWriteCloser.
-func newWrappedBody(span trace.Span, body io.ReadCloser) io.ReadCloser {
+// will implement io.ReadWriteCloser. If onEnd is not nil, it is called once
+// with the number of bytes read from the body when the span ends.
+func newWrappedBody(span trace.Span, body io.ReadCloser, onEnd func(read int64)) io.ReadCloser {
 	// The successful protocol switch responses will have a body that
 	// implement an io.ReadWriteCloser. Ensure this interface type continues
 	// to be satisfied if that is the case.
 	if _, ok := body.(io.ReadWriteCloser); ok {
-		return &wrappedBody{span: span, body: body}
+		return &wrappedBody{span: span, body: body, onEnd: onEnd}
 	}
 
 	// Remove the implementation of the io.ReadWriteCloser and only implement
 	// the io.ReadCloser.
-	return struct{ io.ReadCloser }{&wrappedBody{span: span, body: body}}
+	return struct{ io.ReadCloser }{&wrappedBody{span: span, body: body, onEnd: onEnd}}
 }
 
 // wrappedBody is the response body type returned by the transport
@@ -153,8 +309,12 @@ func newWrappedBody(span trace.Span, body io.ReadCloser) io.ReadCloser {
 // If the response body implements the io.Writer interface (i.e. for
 // successful protocol switches), the wrapped body also will.
 type wrappedBody struct {
-	span trace.Span
-	body io.ReadCloser
+	span  trace.Span
+	body  io.ReadCloser
+	onEnd func(read int64)
+
+	read  int64
+	ended bool
 }
 
 var _ io.ReadWriteCloser = &wrappedBody{}
@@ -171,12 +331,13 @@ func (wb *wrappedBody) Write(p []byte) (int, error) {
 
 func (wb *wrappedBody) Read(b []byte) (int, error) {
 	n, err := wb.body.Read(b)
+	wb.read += int64(n)
 
 	switch err {
 	case nil:
 		// nothing to do here but fall through to the return
 	case io.EOF:
-		wb.span.End()
+		wb.end()
 	default:
 		wb.span.RecordError(err)
 		wb.span.SetStatus(codes.Error, err.Error())
@@ -185,9 +346,18 @@ func (wb *wrappedBody) Read(b []byte) (int, error) {
 }
 
 func (wb *wrappedBody) Close() error {
-	wb.span.End()
+	wb.end()
 	if wb.body != nil {
 		return wb.body.Close()
 	}
 	return nil
 }
+
+// end ends the span tracking the response.
+func (wb *wrappedBody) end() {
+	wb.span.End()
+	if !wb.ended && wb.onEnd != nil {
+		wb.onEnd(wb.read)
+	}
+	wb.ended = true
+}
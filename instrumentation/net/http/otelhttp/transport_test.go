@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -84,6 +85,11 @@ func TestTransportFormatter(t *testing.T) {
 			http.MethodTrace,
 			"HTTP TRACE",
 		},
+		{
+			"extension method",
+			"PROPFIND",
+			"HTTP PROPFIND",
+		},
 	}
 
 	for _, tc := range httpMethods {
@@ -92,7 +98,7 @@ func TestTransportFormatter(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			formattedName := "HTTP " + r.Method
+			formattedName := defaultTransportFormatter("", r)
 
 			if formattedName != tc.expected {
 				t.Fatalf("unexpected name: got %s, expected %s", formattedName, tc.expected)
@@ -283,7 +289,7 @@ func TestWrappedBodyClose(t *testing.T) {
 func TestWrappedBodyClosePanic(t *testing.T) {
 	s := new(span)
 	var body io.ReadCloser
-	wb := newWrappedBody(s, body)
+	wb := newWrappedBody(s, body, nil)
 	assert.NotPanics(t, func() { wb.Close() }, "nil body should not panic on close")
 }
 
@@ -308,12 +314,12 @@ func (rwc readWriteCloser) Write([]byte) (int, error) {
 }
 
 func TestNewWrappedBodyReadWriteCloserImplementation(t *testing.T) {
-	wb := newWrappedBody(nil, readWriteCloser{})
+	wb := newWrappedBody(nil, readWriteCloser{}, nil)
 	assert.Implements(t, (*io.ReadWriteCloser)(nil), wb)
 }
 
 func TestNewWrappedBodyReadCloserImplementation(t *testing.T) {
-	wb := newWrappedBody(nil, readCloser{})
+	wb := newWrappedBody(nil, readCloser{}, nil)
 	assert.Implements(t, (*io.ReadCloser)(nil), wb)
 
 	_, ok := wb.(io.ReadWriteCloser)
@@ -324,7 +330,7 @@ func TestWrappedBodyWrite(t *testing.T) {
 	s := new(span)
 	var rwc io.ReadWriteCloser
 	assert.NotPanics(t, func() {
-		rwc = newWrappedBody(s, readWriteCloser{}).(io.ReadWriteCloser)
+		rwc = newWrappedBody(s, readWriteCloser{}, nil).(io.ReadWriteCloser)
 	})
 
 	n, err := rwc.Write([]byte{})
@@ -340,7 +346,7 @@ func TestWrappedBodyWriteError(t *testing.T) {
 	assert.NotPanics(t, func() {
 		rwc = newWrappedBody(s, readWriteCloser{
 			writeErr: expectedErr,
-		}).(io.ReadWriteCloser)
+		}, nil).(io.ReadWriteCloser)
 	})
 	n, err := rwc.Write([]byte{})
 	assert.Equal(t, writeSize, n, "wrappedBody returned wrong bytes")
@@ -381,3 +387,30 @@ func TestTransportProtocolSwitch(t *testing.T) {
 
 	assert.Implements(t, (*io.ReadWriteCloser)(nil), res.Body, "invalid body returned for protocol switch")
 }
+
+func BenchmarkTransportNoop(b *testing.B) {
+	tr := NewTransport(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		WithTracerProvider(trace.NewNoopTracerProvider()),
+		WithMeterProvider(metric.NewNoopMeterProvider()),
+	)
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := tr.RoundTrip(r)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
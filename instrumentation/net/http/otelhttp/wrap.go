@@ -15,9 +15,16 @@
 package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 import (
+	"bufio"
 	"context"
 	"io"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/felixge/httpsnoop"
 
 	"go.opentelemetry.io/otel/propagation"
 )
@@ -28,7 +35,8 @@ var _ io.ReadCloser = &bodyWrapper{}
 // of bytes read and the last error.
 type bodyWrapper struct {
 	io.ReadCloser
-	record func(n int64) // must not be nil
+	record  func(n int64) // must not be nil
+	capture *bodyCapture
 
 	read int64
 	err  error
@@ -36,6 +44,9 @@ type bodyWrapper struct {
 
 func (w *bodyWrapper) Read(b []byte) (int, error) {
 	n, err := w.ReadCloser.Read(b)
+	if w.capture != nil {
+		_, _ = w.capture.Write(b[:n])
+	}
 	n1 := int64(n)
 	w.read += n1
 	w.err = err
@@ -50,10 +61,11 @@ func (w *bodyWrapper) Close() error {
 var _ http.ResponseWriter = &respWriterWrapper{}
 
 // respWriterWrapper wraps a http.ResponseWriter in order to track the number of
-// bytes written, the last error, and to catch the returned statusCode
-// TODO: The wrapped http.ResponseWriter doesn't implement any of the optional
-// types (http.Hijacker, http.Pusher, http.CloseNotifier, http.Flusher, etc)
-// that may be useful when using it in real life situations.
+// bytes written, the last error, and to catch the returned statusCode.
+// It does not implement any of the optional interfaces of the wrapped
+// http.ResponseWriter (http.Hijacker, http.Pusher, http.CloseNotifier,
+// http.Flusher, io.ReaderFrom); the Handler exposes them with httpsnoop,
+// using the ReadFrom and Flush methods to keep track of the response.
 type respWriterWrapper struct {
 	http.ResponseWriter
 	record func(n int64) // must not be nil
@@ -67,6 +79,65 @@ type respWriterWrapper struct {
 	statusCode  int
 	err         error
 	wroteHeader bool
+
+	// set at the first write if the body is captured
+	captureConfig  *bodyCaptureConfig
+	captureStarted bool
+	capture        *bodyCapture
+
+	// set when the connection is hijacked
+	hijackReadRecord  func(n int64) // must not be nil
+	hijackWriteRecord func(n int64) // must not be nil
+	conn              *hijackedConn
+	upgradedAt        time.Time
+
+	// hooks exposing the methods of the wrapper with httpsnoop, built once
+	// as they do not depend on the request
+	hooks httpsnoop.Hooks
+}
+
+// respWriterWrapperPool holds the respWriterWrappers of the requests served by
+// Handlers.  A http.ResponseWriter may not be used after the handler returns,
+// so its wrapper can be reused for another request.
+var respWriterWrapperPool = sync.Pool{
+	New: func() interface{} { return newRespWriterWrapper() },
+}
+
+func newRespWriterWrapper() *respWriterWrapper {
+	w := &respWriterWrapper{}
+	header, write, writeHeader := w.Header, w.Write, w.WriteHeader
+	w.hooks = httpsnoop.Hooks{
+		Header: func(httpsnoop.HeaderFunc) httpsnoop.HeaderFunc {
+			return header
+		},
+		Write: func(httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return write
+		},
+		WriteHeader: func(httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return writeHeader
+		},
+		ReadFrom: func(next httpsnoop.ReadFromFunc) httpsnoop.ReadFromFunc {
+			return func(src io.Reader) (int64, error) {
+				return w.ReadFrom(next, src)
+			}
+		},
+		Flush: func(next httpsnoop.FlushFunc) httpsnoop.FlushFunc {
+			return func() {
+				w.Flush(next)
+			}
+		},
+		Hijack: func(next httpsnoop.HijackFunc) httpsnoop.HijackFunc {
+			return func() (net.Conn, *bufio.ReadWriter, error) {
+				return w.Hijack(next)
+			}
+		},
+	}
+	return w
+}
+
+// reset clears the state of the request w wrapped, keeping its hooks.
+func (w *respWriterWrapper) reset() {
+	*w = respWriterWrapper{hooks: w.hooks}
 }
 
 func (w *respWriterWrapper) Header() http.Header {
@@ -78,6 +149,10 @@ func (w *respWriterWrapper) Write(p []byte) (int, error) {
 		w.WriteHeader(http.StatusOK)
 	}
 	n, err := w.ResponseWriter.Write(p)
+	w.startCapture(p)
+	if w.capture != nil {
+		_, _ = w.capture.Write(p[:n])
+	}
 	n1 := int64(n)
 	w.record(n1)
 	w.written += n1
@@ -85,6 +160,60 @@ func (w *respWriterWrapper) Write(p []byte) (int, error) {
 	return n, err
 }
 
+// ReadFrom calls readFrom, the io.ReaderFrom implementation of the wrapped
+// http.ResponseWriter, counting the bytes it writes.
+func (w *respWriterWrapper) ReadFrom(readFrom func(io.Reader) (int64, error), src io.Reader) (int64, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.startCapture(nil)
+	if w.capture != nil {
+		src = io.TeeReader(src, w.capture)
+	}
+	n, err := readFrom(src)
+	w.record(n)
+	w.written += n
+	w.err = err
+	return n, err
+}
+
+// startCapture starts the capture of the body on the first write of p, if
+// its content type is captured.
+func (w *respWriterWrapper) startCapture(p []byte) {
+	if w.captureConfig == nil || w.captureStarted {
+		return
+	}
+	w.captureStarted = true
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" && len(p) > 0 {
+		contentType = http.DetectContentType(p)
+	}
+	w.capture = w.captureConfig.newCapture(contentType)
+}
+
+// Flush calls flush, the http.Flusher implementation of the wrapped
+// http.ResponseWriter, which implicitly writes the header if not done yet.
+func (w *respWriterWrapper) Flush(flush func()) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	flush()
+}
+
+// Hijack calls hijack, the http.Hijacker implementation of the wrapped
+// http.ResponseWriter, and tracks the bytes read and written through the
+// hijacked connection, recording them with w.hijackReadRecord and
+// w.hijackWriteRecord.
+func (w *respWriterWrapper) Hijack(hijack func() (net.Conn, *bufio.ReadWriter, error)) (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := hijack()
+	if err != nil {
+		return conn, rw, err
+	}
+	w.upgradedAt = time.Now()
+	w.conn = &hijackedConn{Conn: conn, readRecord: w.hijackReadRecord, writeRecord: w.hijackWriteRecord}
+	return w.conn, rw, nil
+}
+
 func (w *respWriterWrapper) WriteHeader(statusCode int) {
 	if w.wroteHeader {
 		return
@@ -93,3 +222,35 @@ func (w *respWriterWrapper) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
 }
+
+var _ net.Conn = &hijackedConn{}
+
+// hijackedConn wraps a net.Conn hijacked from a http.ResponseWriter to track
+// the number of bytes read and written.  It may be used concurrently by
+// several goroutines, outliving the handler that hijacked it.
+type hijackedConn struct {
+	net.Conn
+	readRecord  func(n int64) // must not be nil
+	writeRecord func(n int64) // must not be nil
+
+	read    int64 // accessed atomically
+	written int64 // accessed atomically
+}
+
+func (c *hijackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		atomic.AddInt64(&c.read, int64(n))
+		c.readRecord(int64(n))
+	}
+	return n, err
+}
+
+func (c *hijackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		atomic.AddInt64(&c.written, int64(n))
+		c.writeRecord(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// mutexWaitMetric is the runtime/metric of the time goroutines have spent
+// blocked on a sync.Mutex or sync.RWMutex.
+const mutexWaitMetric = "/sync/mutex/wait/total:seconds"
+
+// newContentionCollector returns the collector of the lock contention
+// metrics.
+func (r *runtime) newContentionCollector() (collector, error) {
+	mutexWait, err := r.meter.AsyncFloat64().Counter(
+		"process.runtime.go.sync.mutex.wait",
+		instrument.WithUnit("s"),
+		instrument.WithDescription("Cumulative time goroutines have spent blocked on a sync.Mutex or sync.RWMutex"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	return collector{
+		instruments: []instrument.Asynchronous{
+			mutexWait,
+		},
+		metrics: []string{mutexWaitMetric},
+		collect: func(ctx context.Context, snapshot *runtimeMetrics) {
+			// Only supported by Go 1.20 and later.
+			if wait, ok := snapshot.float64(mutexWaitMetric); ok {
+				mutexWait.Observe(ctx, wait)
+			}
+		},
+	}, nil
+}
@@ -17,16 +17,81 @@
 // The metric events produced are:
 //   runtime.go.cgo.calls         -          Number of cgo calls made by the current process
 //   runtime.go.gc.count          -          Number of completed garbage collection cycles
+//   runtime.go.gc.cpu_fraction   -          Fraction of the CPU time of the program spent in the GC since the program started (Go 1.20 or later)
+//   runtime.go.gc.cycles         -          Number of completed GC cycles attributed by cause (cause=automatic|forced)
+//   runtime.go.gc.gogc           (%)        Heap size target percentage configured by GOGC or debug.SetGCPercent (Go 1.21 or later)
+//   runtime.go.gc.pause          (s)        Duration of GC stop-the-world pauses
 //   runtime.go.gc.pause_ns       (ns)       Amount of nanoseconds in GC stop-the-world pauses
-//   runtime.go.gc.pause_total_ns (ns)       Cumulative nanoseconds in GC stop-the-world pauses since the program started
+//   runtime.go.gc.pause_total_ns (ns)       Approximate cumulative nanoseconds in GC stop-the-world pauses since the program started
 //   runtime.go.goroutines        -          Number of goroutines that currently exist
-//   runtime.go.lookups           -          Number of pointer lookups performed by the runtime
+//   runtime.go.gomaxprocs        -          Maximum number of CPUs that can be executing Go code simultaneously (GOMAXPROCS)
+//   runtime.go.mem.allocs        (bytes)    Cumulative bytes allocated for heap objects
+//   runtime.go.mem.alloc_objects -          Cumulative count of heap objects allocated
 //   runtime.go.mem.heap_alloc    (bytes)    Bytes of allocated heap objects
+//   runtime.go.mem.heap_goal     (bytes)    Heap size target for the end of the GC cycle
 //   runtime.go.mem.heap_idle     (bytes)    Bytes in idle (unused) spans
 //   runtime.go.mem.heap_inuse    (bytes)    Bytes in in-use spans
 //   runtime.go.mem.heap_objects  -          Number of allocated heap objects
 //   runtime.go.mem.heap_released (bytes)    Bytes of idle spans whose physical memory has been returned to the OS
 //   runtime.go.mem.heap_sys      (bytes)    Bytes of heap memory obtained from the OS
+//   runtime.go.mem.limit         (bytes)    Go runtime memory limit configured by GOMEMLIMIT or debug.SetMemoryLimit (Go 1.21 or later, only when set)
 //   runtime.go.mem.live_objects  -          Number of live objects is the number of cumulative Mallocs - Frees
+//   runtime.go.num_cpu           -          Number of logical CPUs usable by the current process
+//   runtime.go.sync.mutex.wait   (s)        Cumulative time goroutines have spent blocked on a sync.Mutex or sync.RWMutex (Go 1.20 or later)
+//   runtime.go.sched.goroutines.runnable - Number of goroutines ready to run waiting for a processor (Go 1.26 or later)
+//   runtime.go.sched.latency     (s)        Quantiles of the time goroutines have spent runnable before running since the last collection
+//   runtime.go.sched.latency.count -        Number of goroutine scheduling latencies measured
 //   runtime.uptime               (ms)       Milliseconds since application was initialized
+//   go.memory.used               (bytes)    Memory used by the Go runtime attributed by memory class (go.memory.type=heap|stack|mspan|mcache|other)
+//
+// The metrics named by both the legacy and the Go runtime semantic
+// conventions are reported with the names of the versions selected with
+// the WithSemconvVersion Option, the legacy names by default:
+//
+//   Legacy name                  Go name
+// ----------------------------------------------------------------------
+//   runtime.go.goroutines        go.goroutine.count
+//   runtime.go.gomaxprocs        go.processor.limit
+//   runtime.go.mem.allocs        go.memory.allocated
+//   runtime.go.mem.alloc_objects go.memory.allocations
+//   runtime.go.mem.heap_goal     go.memory.gc.goal
+//   runtime.go.mem.limit         go.memory.limit
+//   runtime.go.gc.gogc           go.config.gogc
+//
+// The memory and garbage collection metrics are read from the
+// runtime/metrics package, which does not stop the world.  A single
+// snapshot of the runtime/metrics is read for each collection and shared
+// by all the metrics so their values are consistent with each other.  The runtime
+// only reports GC pauses as a histogram, the runtime.go.gc.pause,
+// runtime.go.gc.pause_ns, and runtime.go.gc.pause_total_ns values are
+// therefore approximated by the midpoint of the runtime histogram bucket of
+// each pause.  In particular runtime.go.gc.pause_total_ns is no longer the
+// exact MemStats.PauseTotalNs value but a sum of these approximations.
+//
+// The runtime.go.gc.pause histogram is recorded in seconds and is meant to
+// compute GC pause percentiles.  The runtime.go.gc.pause_ns histogram
+// records the same pauses in nanoseconds and is kept for compatibility.
+//
+// The runtime measures the scheduling latency of every goroutine it runs.
+// Rather than recording each of these with a histogram, the
+// runtime.go.sched.latency gauge reports the 0.5, 0.9, 0.99, and 1 (max)
+// quantiles, identified by the quantile attribute, of the latencies
+// measured since the previous collection.  It requires Go 1.17 or later.
+//
+// The runtime.go.sched.goroutines.runnable gauge is the length of the run
+// queues, global and per processor, and rises when the program is
+// saturated before the latency of its work degrades.  With Go versions
+// prior to 1.26, which do not report it, the rate of
+// runtime.go.sched.latency.count and the high quantiles of
+// runtime.go.sched.latency can be used instead.
+//
+// The GC cycles triggered by the memory limit are not distinguished by
+// the runtime from the other automatic cycles, they are reported with the
+// automatic cause.
+//
+// The go.memory.used metric follows the Go runtime semantic conventions.
+// It reports the memory mapped by the Go runtime that has not been
+// released to the OS by class so growth can be attributed: heap spans in
+// use, goroutine stacks, mspan and mcache runtime metadata, and the other
+// memory (free heap spans, profiling buckets, and other metadata).
 package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
@@ -48,9 +48,7 @@ func main() {
 	}
 	global.SetMeterProvider(cont)
 
-	if err := runtime.Start(
-		runtime.WithMinimumReadMemStatsInterval(time.Second),
-	); err != nil {
+	if err := runtime.Start(); err != nil {
 		log.Fatalln("failed to start runtime instrumentation:", err)
 	}
 
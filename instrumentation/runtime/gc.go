@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
+
+import (
+	"context"
+	"math"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Names of the runtime/metrics read to compute the garbage collection
+// tuning metrics.
+const (
+	gcCyclesAutomaticMetric = "/gc/cycles/automatic:gc-cycles"
+	gcCyclesForcedMetric    = "/gc/cycles/forced:gc-cycles"
+	gcMemoryLimitMetric     = "/gc/gomemlimit:bytes"
+	gcPercentMetric         = "/gc/gogc:percent"
+	gcCPUMetric             = "/cpu/classes/gc/total:cpu-seconds"
+	totalCPUMetric          = "/cpu/classes/total:cpu-seconds"
+)
+
+// Attribute sets used for the GC cycle measurements.
+var (
+	AttributeGCCauseAutomatic = []attribute.KeyValue{attribute.String("cause", "automatic")}
+	AttributeGCCauseForced    = []attribute.KeyValue{attribute.String("cause", "forced")}
+)
+
+// newGCTuningCollector returns the collector of the metrics showing the
+// effect of the GOGC and GOMEMLIMIT settings.
+func (r *runtime) newGCTuningCollector() (collector, error) {
+	cycles, err := r.meter.AsyncInt64().Counter(
+		"process.runtime.go.gc.cycles",
+		instrument.WithDescription("Number of completed GC cycles attributed by cause (automatic, forced)"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	memoryLimit, err := r.upDownCounters(
+		"process.runtime.go.mem.limit",
+		"go.memory.limit",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Go runtime memory limit configured by GOMEMLIMIT or debug.SetMemoryLimit"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	gcPercent, err := r.upDownCounters(
+		"process.runtime.go.gc.gogc",
+		"go.config.gogc",
+		instrument.WithUnit("%"),
+		instrument.WithDescription("Heap size target percentage configured by GOGC or debug.SetGCPercent"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	cpuFraction, err := r.meter.AsyncFloat64().Gauge(
+		"process.runtime.go.gc.cpu_fraction",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("Fraction of the CPU time of the program spent in the GC since the program started"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	return collector{
+		instruments: append(
+			[]instrument.Asynchronous{cycles, cpuFraction},
+			asynchronous(memoryLimit, gcPercent)...,
+		),
+		metrics: []string{
+			gcCyclesAutomaticMetric,
+			gcCyclesForcedMetric,
+			gcMemoryLimitMetric,
+			gcPercentMetric,
+			gcCPUMetric,
+			totalCPUMetric,
+		},
+		collect: func(ctx context.Context, snapshot *runtimeMetrics) {
+			// The runtime does not distinguish the GC cycles triggered
+			// by the memory limit from the other automatic ones.
+			cycles.Observe(ctx, int64(snapshot.uint64(gcCyclesAutomaticMetric)), AttributeGCCauseAutomatic...)
+			cycles.Observe(ctx, int64(snapshot.uint64(gcCyclesForcedMetric)), AttributeGCCauseForced...)
+
+			// The following are only supported by Go 1.21 and later.
+			// No memory limit is configured when it is math.MaxInt64.
+			if limit := snapshot.uint64(gcMemoryLimitMetric); limit > 0 && limit < math.MaxInt64 {
+				memoryLimit.Observe(ctx, int64(limit))
+			}
+			if snapshot.supported(gcPercentMetric) {
+				gcPercent.Observe(ctx, int64(snapshot.uint64(gcPercentMetric)))
+			}
+
+			// The following are only supported by Go 1.20 and later.
+			gc, ok := snapshot.float64(gcCPUMetric)
+			if !ok {
+				return
+			}
+			if total, _ := snapshot.float64(totalCPUMetric); total > 0 {
+				cpuFraction.Observe(ctx, gc/total)
+			}
+		},
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// Names of the runtime/metrics memory classes.
+const (
+	memoryTotalMetric = "/memory/classes/total:bytes"
+	heapStacksMetric  = "/memory/classes/heap/stacks:bytes"
+	osStacksMetric    = "/memory/classes/os-stacks:bytes"
+	mspanInuseMetric  = "/memory/classes/metadata/mspan/inuse:bytes"
+	mspanFreeMetric   = "/memory/classes/metadata/mspan/free:bytes"
+	mcacheInuseMetric = "/memory/classes/metadata/mcache/inuse:bytes"
+	mcacheFreeMetric  = "/memory/classes/metadata/mcache/free:bytes"
+)
+
+// Attribute sets used for the go.memory.used measurements.
+var (
+	AttributeMemoryHeap   = []attribute.KeyValue{attribute.String("go.memory.type", "heap")}
+	AttributeMemoryStack  = []attribute.KeyValue{attribute.String("go.memory.type", "stack")}
+	AttributeMemoryMSpan  = []attribute.KeyValue{attribute.String("go.memory.type", "mspan")}
+	AttributeMemoryMCache = []attribute.KeyValue{attribute.String("go.memory.type", "mcache")}
+	AttributeMemoryOther  = []attribute.KeyValue{attribute.String("go.memory.type", "other")}
+)
+
+// newMemoryClassesCollector returns the collector of the go.memory.used
+// metric reporting the memory mapped by the Go runtime, not yet released to
+// the OS, by class.
+func (r *runtime) newMemoryClassesCollector() (collector, error) {
+	memoryUsed, err := r.meter.AsyncInt64().UpDownCounter(
+		"go.memory.used",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Memory used by the Go runtime attributed by memory class (heap, stack, mspan, mcache, other)"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	return collector{
+		instruments: []instrument.Asynchronous{
+			memoryUsed,
+		},
+		metrics: []string{
+			memoryTotalMetric,
+			heapReleasedBytesMetric,
+			heapObjectsBytesMetric,
+			heapUnusedBytesMetric,
+			heapStacksMetric,
+			osStacksMetric,
+			mspanInuseMetric,
+			mspanFreeMetric,
+			mcacheInuseMetric,
+			mcacheFreeMetric,
+		},
+		collect: func(ctx context.Context, rm *runtimeMetrics) {
+			heap := rm.uint64(heapObjectsBytesMetric) + rm.uint64(heapUnusedBytesMetric)
+			stack := rm.uint64(heapStacksMetric) + rm.uint64(osStacksMetric)
+			mspan := rm.uint64(mspanInuseMetric) + rm.uint64(mspanFreeMetric)
+			mcache := rm.uint64(mcacheInuseMetric) + rm.uint64(mcacheFreeMetric)
+
+			// Other is the remainder of the memory not released to the
+			// OS: the free heap spans, the profiling buckets, and the
+			// other runtime metadata.
+			used := rm.uint64(memoryTotalMetric) - rm.uint64(heapReleasedBytesMetric)
+			var other uint64
+			if classes := heap + stack + mspan + mcache; used > classes {
+				other = used - classes
+			}
+
+			memoryUsed.Observe(ctx, int64(heap), AttributeMemoryHeap...)
+			memoryUsed.Observe(ctx, int64(stack), AttributeMemoryStack...)
+			memoryUsed.Observe(ctx, int64(mspan), AttributeMemoryMSpan...)
+			memoryUsed.Observe(ctx, int64(mcache), AttributeMemoryMCache...)
+			memoryUsed.Observe(ctx, int64(other), AttributeMemoryOther...)
+		},
+	}, nil
+}
@@ -16,14 +16,18 @@ package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
 
 import (
 	"context"
+	"math"
 	goruntime "runtime"
+	"runtime/metrics"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/global"
 	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
 	"go.opentelemetry.io/otel/metric/instrument/syncint64"
 	"go.opentelemetry.io/otel/metric/unit"
 )
@@ -32,18 +36,21 @@ import (
 type runtime struct {
 	config config
 	meter  metric.Meter
+
+	// unregistered is set to 1 once the Registration of the runtime has
+	// been unregistered.
+	unregistered int32
 }
 
 // config contains optional settings for reporting runtime metrics.
 type config struct {
-	// MinimumReadMemStatsInterval sets the mininum interval
-	// between calls to runtime.ReadMemStats().  Negative values
-	// are ignored.
-	MinimumReadMemStatsInterval time.Duration
-
 	// MeterProvider sets the metric.MeterProvider.  If nil, the global
 	// Provider will be used.
 	MeterProvider metric.MeterProvider
+
+	// SemconvVersion sets the versions of the semantic conventions the
+	// metrics are named after.
+	SemconvVersion SemconvVersion
 }
 
 // Option supports configuring optional settings for runtime metrics.
@@ -51,26 +58,26 @@ type Option interface {
 	apply(*config)
 }
 
-// DefaultMinimumReadMemStatsInterval is the default minimum interval
-// between calls to runtime.ReadMemStats().  Use the
-// WithMinimumReadMemStatsInterval() option to modify this setting in
-// Start().
+// DefaultMinimumReadMemStatsInterval was the default minimum interval
+// between calls to runtime.ReadMemStats().
+//
+// Deprecated: the runtime metrics are read once per collection, this value
+// is no longer used.
 const DefaultMinimumReadMemStatsInterval time.Duration = 15 * time.Second
 
-// WithMinimumReadMemStatsInterval sets a minimum interval between calls to
-// runtime.ReadMemStats(), which is a relatively expensive call to make
-// frequently.  This setting is ignored when `d` is negative.
+// WithMinimumReadMemStatsInterval was used to set a minimum interval
+// between calls to runtime.ReadMemStats().
+//
+// Deprecated: the runtime metrics are read once per collection from the
+// runtime/metrics package, which does not stop the world, this option has
+// no effect.
 func WithMinimumReadMemStatsInterval(d time.Duration) Option {
 	return minimumReadMemStatsIntervalOption(d)
 }
 
 type minimumReadMemStatsIntervalOption time.Duration
 
-func (o minimumReadMemStatsIntervalOption) apply(c *config) {
-	if o >= 0 {
-		c.MinimumReadMemStatsInterval = time.Duration(o)
-	}
-}
+func (o minimumReadMemStatsIntervalOption) apply(*config) {}
 
 // WithMeterProvider sets the Metric implementation to use for
 // reporting.  If this option is not used, the global metric.MeterProvider
@@ -90,8 +97,8 @@ func (o metricProviderOption) apply(c *config) {
 // newConfig computes a config from the supplied Options.
 func newConfig(opts ...Option) config {
 	c := config{
-		MeterProvider:               global.MeterProvider(),
-		MinimumReadMemStatsInterval: DefaultMinimumReadMemStatsInterval,
+		MeterProvider:  global.MeterProvider(),
+		SemconvVersion: SemconvLegacy,
 	}
 	for _, opt := range opts {
 		opt.apply(&c)
@@ -101,10 +108,20 @@ func newConfig(opts ...Option) config {
 
 // Start initializes reporting of runtime metrics using the supplied config.
 func Start(opts ...Option) error {
+	_, err := Register(opts...)
+	return err
+}
+
+// Registration is the registration of the runtime metrics reported from a
+// call to Register.
+type Registration struct {
+	r *runtime
+}
+
+// Register initializes reporting of runtime metrics using the supplied
+// config and returns a Registration that can be used to stop the reporting.
+func Register(opts ...Option) (*Registration, error) {
 	c := newConfig(opts...)
-	if c.MinimumReadMemStatsInterval < 0 {
-		c.MinimumReadMemStatsInterval = DefaultMinimumReadMemStatsInterval
-	}
 	if c.MeterProvider == nil {
 		c.MeterProvider = global.MeterProvider()
 	}
@@ -115,10 +132,93 @@ func Start(opts ...Option) error {
 		),
 		config: c,
 	}
-	return r.register()
+	if err := r.register(); err != nil {
+		return nil, err
+	}
+	return &Registration{r: r}, nil
 }
 
+// Unregister stops the reporting of the runtime metrics of reg.  After
+// Unregister returns no further measurements are made for reg and the
+// runtime metrics can be registered again with the same MeterProvider
+// without reporting duplicate measurements.
+//
+// The metric API does not support removing registered callbacks, the
+// instruments of reg are therefore kept by the MeterProvider but do not
+// observe any values.
+func (reg *Registration) Unregister() error {
+	atomic.StoreInt32(&reg.r.unregistered, 1)
+	return nil
+}
+
+// stopped returns whether the Registration of r has been unregistered.
+func (r *runtime) stopped() bool {
+	return atomic.LoadInt32(&r.unregistered) == 1
+}
+
+// collector collects a group of runtime metrics.
+type collector struct {
+	// instruments are the asynchronous instruments observed by collect.
+	instruments []instrument.Asynchronous
+	// metrics are the names of the runtime/metrics collect reads.
+	metrics []string
+	// collect observes the instruments of the collector from snapshot.
+	collect func(ctx context.Context, snapshot *runtimeMetrics)
+}
+
+// register registers a single callback observing the instruments of all the
+// collectors.  The runtime/metrics of all the collectors are read once per
+// collection into a single snapshot so the values observed by the
+// instruments of one collection are consistent with each other.
 func (r *runtime) register() error {
+	var (
+		collectors  []collector
+		instruments []instrument.Asynchronous
+		names       []string
+
+		// lock prevents a race between batch observer and instrument registration.
+		lock sync.Mutex
+	)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, newCollector := range []func() (collector, error){
+		r.newBaseCollector,
+		r.newMemStatsCollector,
+		r.newMemoryClassesCollector,
+		r.newSchedCollector,
+		r.newContentionCollector,
+		r.newGCTuningCollector,
+	} {
+		c, err := newCollector()
+		if err != nil {
+			return err
+		}
+		collectors = append(collectors, c)
+		instruments = append(instruments, c.instruments...)
+		names = append(names, c.metrics...)
+	}
+
+	snapshot := newRuntimeMetrics(names...)
+	return r.meter.RegisterCallback(instruments, func(ctx context.Context) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.stopped() {
+			return
+		}
+
+		snapshot.read()
+		for _, c := range collectors {
+			c.collect(ctx, snapshot)
+		}
+	})
+}
+
+// newBaseCollector returns the collector of the metrics that are not read
+// from the runtime/metrics.
+func (r *runtime) newBaseCollector() (collector, error) {
 	startTime := time.Now()
 	uptime, err := r.meter.AsyncInt64().UpDownCounter(
 		"runtime.uptime",
@@ -126,45 +226,140 @@ func (r *runtime) register() error {
 		instrument.WithDescription("Milliseconds since application was initialized"),
 	)
 	if err != nil {
-		return err
+		return collector{}, err
 	}
 
-	goroutines, err := r.meter.AsyncInt64().UpDownCounter(
+	goroutines, err := r.upDownCounters(
 		"process.runtime.go.goroutines",
+		"go.goroutine.count",
 		instrument.WithDescription("Number of goroutines that currently exist"),
 	)
 	if err != nil {
-		return err
+		return collector{}, err
 	}
 
-	cgoCalls, err := r.meter.AsyncInt64().UpDownCounter(
+	// runtime.NumCgoCall is monotonic, it is reported as a counter so
+	// the rate of cgo calls can be computed.
+	cgoCalls, err := r.meter.AsyncInt64().Counter(
 		"process.runtime.go.cgo.calls",
+		instrument.WithUnit("{calls}"),
 		instrument.WithDescription("Number of cgo calls made by the current process"),
 	)
 	if err != nil {
-		return err
+		return collector{}, err
 	}
 
-	err = r.meter.RegisterCallback(
-		[]instrument.Asynchronous{
-			uptime,
-			goroutines,
-			cgoCalls,
-		},
-		func(ctx context.Context) {
+	gomaxprocs, err := r.upDownCounters(
+		"process.runtime.go.gomaxprocs",
+		"go.processor.limit",
+		instrument.WithDescription("Maximum number of CPUs that can be executing Go code simultaneously (GOMAXPROCS)"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	numCPU, err := r.meter.AsyncInt64().UpDownCounter(
+		"process.runtime.go.num_cpu",
+		instrument.WithDescription("Number of logical CPUs usable by the current process"),
+	)
+	if err != nil {
+		return collector{}, err
+	}
+
+	return collector{
+		instruments: append(
+			[]instrument.Asynchronous{uptime, cgoCalls, numCPU},
+			asynchronous(goroutines, gomaxprocs)...,
+		),
+		collect: func(ctx context.Context, _ *runtimeMetrics) {
 			uptime.Observe(ctx, time.Since(startTime).Milliseconds())
 			goroutines.Observe(ctx, int64(goruntime.NumGoroutine()))
 			cgoCalls.Observe(ctx, goruntime.NumCgoCall())
+			gomaxprocs.Observe(ctx, int64(goruntime.GOMAXPROCS(0)))
+			numCPU.Observe(ctx, int64(goruntime.NumCPU()))
 		},
-	)
-	if err != nil {
-		return err
+	}, nil
+}
+
+// Names of the runtime/metrics read to compute the memory and garbage
+// collection metrics.
+const (
+	heapObjectsBytesMetric  = "/memory/classes/heap/objects:bytes"
+	heapUnusedBytesMetric   = "/memory/classes/heap/unused:bytes"
+	heapFreeBytesMetric     = "/memory/classes/heap/free:bytes"
+	heapReleasedBytesMetric = "/memory/classes/heap/released:bytes"
+	heapObjectsMetric       = "/gc/heap/objects:objects"
+	heapAllocsBytesMetric   = "/gc/heap/allocs:bytes"
+	heapAllocsMetric        = "/gc/heap/allocs:objects"
+	heapGoalMetric          = "/gc/heap/goal:bytes"
+	gcCyclesMetric          = "/gc/cycles/total:gc-cycles"
+	gcPausesMetric          = "/gc/pauses:seconds"
+)
+
+// runtimeMetrics is a set of runtime/metrics read together.
+type runtimeMetrics struct {
+	samples []metrics.Sample
+	index   map[string]int
+}
+
+func newRuntimeMetrics(names ...string) *runtimeMetrics {
+	m := &runtimeMetrics{
+		samples: make([]metrics.Sample, 0, len(names)),
+		index:   make(map[string]int, len(names)),
+	}
+	for _, name := range names {
+		if _, ok := m.index[name]; ok {
+			continue
+		}
+		m.index[name] = len(m.samples)
+		m.samples = append(m.samples, metrics.Sample{Name: name})
 	}
+	return m
+}
 
-	return r.registerMemStats()
+// read reads the current value of all the runtime/metrics of m.  Unlike
+// runtime.ReadMemStats, this does not stop the world.
+func (m *runtimeMetrics) read() {
+	metrics.Read(m.samples)
 }
 
-func (r *runtime) registerMemStats() error {
+// supported returns whether the named runtime/metric is supported by the
+// Go runtime.
+func (m *runtimeMetrics) supported(name string) bool {
+	return m.samples[m.index[name]].Value.Kind() != metrics.KindBad
+}
+
+// uint64 returns the value of the named uint64 runtime/metric, or 0 if it
+// is not supported by the Go runtime.
+func (m *runtimeMetrics) uint64(name string) uint64 {
+	v := m.samples[m.index[name]].Value
+	if v.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return v.Uint64()
+}
+
+// float64 returns the value of the named float64 runtime/metric and
+// whether it is supported by the Go runtime.
+func (m *runtimeMetrics) float64(name string) (float64, bool) {
+	v := m.samples[m.index[name]].Value
+	if v.Kind() != metrics.KindFloat64 {
+		return 0, false
+	}
+	return v.Float64(), true
+}
+
+// histogram returns the value of the named histogram runtime/metric, or
+// nil if it is not supported by the Go runtime.
+func (m *runtimeMetrics) histogram(name string) *metrics.Float64Histogram {
+	v := m.samples[m.index[name]].Value
+	if v.Kind() != metrics.KindFloat64Histogram {
+		return nil
+	}
+	return v.Float64Histogram()
+}
+
+func (r *runtime) newMemStatsCollector() (collector, error) {
 	var (
 		err error
 
@@ -174,33 +369,26 @@ func (r *runtime) registerMemStats() error {
 		heapObjects  asyncint64.UpDownCounter
 		heapReleased asyncint64.UpDownCounter
 		heapSys      asyncint64.UpDownCounter
+		heapGoal     int64Observers
 		liveObjects  asyncint64.UpDownCounter
 
-		// TODO: is ptrLookups useful? I've not seen a value
-		// other than zero.
-		ptrLookups asyncint64.Counter
+		allocBytes   int64Observers
+		allocObjects int64Observers
 
 		gcCount      asyncint64.Counter
 		pauseTotalNs asyncint64.Counter
+		gcPause      syncfloat64.Histogram
 		gcPauseNs    syncint64.Histogram
 
-		lastNumGC    uint32
-		lastMemStats time.Time
-		memStats     goruntime.MemStats
-
-		// lock prevents a race between batch observer and instrument registration.
-		lock sync.Mutex
+		lastPauses []uint64
 	)
 
-	lock.Lock()
-	defer lock.Unlock()
-
 	if heapAlloc, err = r.meter.AsyncInt64().UpDownCounter(
 		"process.runtime.go.mem.heap_alloc",
 		instrument.WithUnit(unit.Bytes),
 		instrument.WithDescription("Bytes of allocated heap objects"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if heapIdle, err = r.meter.AsyncInt64().UpDownCounter(
@@ -208,7 +396,7 @@ func (r *runtime) registerMemStats() error {
 		instrument.WithUnit(unit.Bytes),
 		instrument.WithDescription("Bytes in idle (unused) spans"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if heapInuse, err = r.meter.AsyncInt64().UpDownCounter(
@@ -216,14 +404,14 @@ func (r *runtime) registerMemStats() error {
 		instrument.WithUnit(unit.Bytes),
 		instrument.WithDescription("Bytes in in-use spans"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if heapObjects, err = r.meter.AsyncInt64().UpDownCounter(
 		"process.runtime.go.mem.heap_objects",
 		instrument.WithDescription("Number of allocated heap objects"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	// FYI see https://github.com/golang/go/issues/32284 to help
@@ -233,7 +421,7 @@ func (r *runtime) registerMemStats() error {
 		instrument.WithUnit(unit.Bytes),
 		instrument.WithDescription("Bytes of idle spans whose physical memory has been returned to the OS"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if heapSys, err = r.meter.AsyncInt64().UpDownCounter(
@@ -241,39 +429,67 @@ func (r *runtime) registerMemStats() error {
 		instrument.WithUnit(unit.Bytes),
 		instrument.WithDescription("Bytes of heap memory obtained from the OS"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
-	if ptrLookups, err = r.meter.AsyncInt64().Counter(
-		"process.runtime.go.mem.lookups",
-		instrument.WithDescription("Number of pointer lookups performed by the runtime"),
+	if heapGoal, err = r.upDownCounters(
+		"process.runtime.go.mem.heap_goal",
+		"go.memory.gc.goal",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Heap size target for the end of the GC cycle"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if liveObjects, err = r.meter.AsyncInt64().UpDownCounter(
 		"process.runtime.go.mem.live_objects",
 		instrument.WithDescription("Number of live objects is the number of cumulative Mallocs - Frees"),
 	); err != nil {
-		return err
+		return collector{}, err
+	}
+
+	if allocBytes, err = r.counters(
+		"process.runtime.go.mem.allocs",
+		"go.memory.allocated",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Cumulative bytes allocated for heap objects"),
+	); err != nil {
+		return collector{}, err
+	}
+
+	if allocObjects, err = r.counters(
+		"process.runtime.go.mem.alloc_objects",
+		"go.memory.allocations",
+		instrument.WithDescription("Cumulative count of heap objects allocated"),
+	); err != nil {
+		return collector{}, err
 	}
 
 	if gcCount, err = r.meter.AsyncInt64().Counter(
 		"process.runtime.go.gc.count",
 		instrument.WithDescription("Number of completed garbage collection cycles"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	// Note that the following could be derived as a sum of
 	// individual pauses, but we may lose individual pauses if the
-	// observation interval is too slow.
+	// observation interval is too slow.  runtime/metrics has no exact
+	// total pause time, it is summed from the pause histogram buckets.
 	if pauseTotalNs, err = r.meter.AsyncInt64().Counter(
 		"process.runtime.go.gc.pause_total_ns",
 		// TODO: nanoseconds units
-		instrument.WithDescription("Cumulative nanoseconds in GC stop-the-world pauses since the program started"),
+		instrument.WithDescription("Approximate cumulative nanoseconds in GC stop-the-world pauses since the program started, summed from the runtime GC pause histogram buckets"),
+	); err != nil {
+		return collector{}, err
+	}
+
+	if gcPause, err = r.meter.SyncFloat64().Histogram(
+		"process.runtime.go.gc.pause",
+		instrument.WithUnit("s"),
+		instrument.WithDescription("Duration of GC stop-the-world pauses"),
 	); err != nil {
-		return err
+		return collector{}, err
 	}
 
 	if gcPauseNs, err = r.meter.SyncInt64().Histogram(
@@ -281,92 +497,121 @@ func (r *runtime) registerMemStats() error {
 		// TODO: nanoseconds units
 		instrument.WithDescription("Amount of nanoseconds in GC stop-the-world pauses"),
 	); err != nil {
-		return err
-	}
-
-	err = r.meter.RegisterCallback(
-		[]instrument.Asynchronous{
-			heapAlloc,
-			heapIdle,
-			heapInuse,
-			heapObjects,
-			heapReleased,
-			heapSys,
-			liveObjects,
-
-			ptrLookups,
-
-			gcCount,
-			pauseTotalNs,
-		}, func(ctx context.Context) {
-			lock.Lock()
-			defer lock.Unlock()
-
-			now := time.Now()
-			if now.Sub(lastMemStats) >= r.config.MinimumReadMemStatsInterval {
-				goruntime.ReadMemStats(&memStats)
-				lastMemStats = now
-			}
-
-			heapAlloc.Observe(ctx, int64(memStats.HeapAlloc))
-			heapIdle.Observe(ctx, int64(memStats.HeapIdle))
-			heapInuse.Observe(ctx, int64(memStats.HeapInuse))
-			heapObjects.Observe(ctx, int64(memStats.HeapObjects))
-			heapReleased.Observe(ctx, int64(memStats.HeapReleased))
-			heapSys.Observe(ctx, int64(memStats.HeapSys))
-			liveObjects.Observe(ctx, int64(memStats.Mallocs-memStats.Frees))
-			ptrLookups.Observe(ctx, int64(memStats.Lookups))
-			gcCount.Observe(ctx, int64(memStats.NumGC))
-			pauseTotalNs.Observe(ctx, int64(memStats.PauseTotalNs))
-
-			computeGCPauses(ctx, gcPauseNs, memStats.PauseNs[:], lastNumGC, memStats.NumGC)
-
-			lastNumGC = memStats.NumGC
-		})
-	if err != nil {
-		return err
+		return collector{}, err
 	}
-	return nil
-}
 
-func computeGCPauses(
-	ctx context.Context,
-	recorder syncint64.Histogram,
-	circular []uint64,
-	lastNumGC, currentNumGC uint32,
-) {
-	delta := int(int64(currentNumGC) - int64(lastNumGC))
+	return collector{
+		instruments: append(
+			[]instrument.Asynchronous{
+				heapAlloc,
+				heapIdle,
+				heapInuse,
+				heapObjects,
+				heapReleased,
+				heapSys,
+				liveObjects,
+
+				gcCount,
+				pauseTotalNs,
+			},
+			asynchronous(heapGoal, allocBytes, allocObjects)...,
+		),
+		metrics: []string{
+			heapObjectsBytesMetric,
+			heapUnusedBytesMetric,
+			heapFreeBytesMetric,
+			heapReleasedBytesMetric,
+			heapObjectsMetric,
+			heapAllocsBytesMetric,
+			heapAllocsMetric,
+			heapGoalMetric,
+			gcCyclesMetric,
+			gcPausesMetric,
+		},
+		collect: func(ctx context.Context, rm *runtimeMetrics) {
+			objects := rm.uint64(heapObjectsBytesMetric)
+			unused := rm.uint64(heapUnusedBytesMetric)
+			free := rm.uint64(heapFreeBytesMetric)
+			released := rm.uint64(heapReleasedBytesMetric)
+
+			// These follow the definitions of the runtime.MemStats
+			// fields in terms of runtime/metrics.
+			heapAlloc.Observe(ctx, int64(objects))
+			heapIdle.Observe(ctx, int64(free+released))
+			heapInuse.Observe(ctx, int64(objects+unused))
+			heapObjects.Observe(ctx, int64(rm.uint64(heapObjectsMetric)))
+			heapReleased.Observe(ctx, int64(released))
+			heapSys.Observe(ctx, int64(objects+unused+free+released))
+			heapGoal.Observe(ctx, int64(rm.uint64(heapGoalMetric)))
+			liveObjects.Observe(ctx, int64(rm.uint64(heapObjectsMetric)))
+			allocBytes.Observe(ctx, int64(rm.uint64(heapAllocsBytesMetric)))
+			allocObjects.Observe(ctx, int64(rm.uint64(heapAllocsMetric)))
+			gcCount.Observe(ctx, int64(rm.uint64(gcCyclesMetric)))
+
+			pauses := rm.histogram(gcPausesMetric)
+			pauseTotalNs.Observe(ctx, int64(histogramSum(pauses)*float64(time.Second)))
+			lastPauses = histogramDelta(pauses, lastPauses, func(v float64) {
+				gcPause.Record(ctx, v)
+				gcPauseNs.Record(ctx, int64(v*float64(time.Second)))
+			})
+		},
+	}, nil
+}
 
-	if delta == 0 {
-		return
+// histogramDelta calls observe with each observation made in the
+// runtime/metrics histogram h since its previous bucket counts last.  Each
+// observation is the midpoint of its bucket.  The bucket counts of h are
+// returned to be passed as last for the next call.
+//
+// The runtime only provides the bucket counts of its histograms, so the
+// observations are an approximation of the actual ones within the
+// precision of the runtime buckets.
+func histogramDelta(h *metrics.Float64Histogram, last []uint64, observe func(float64)) []uint64 {
+	if h == nil {
+		return last
 	}
-
-	if delta >= len(circular) {
-		// There were > 256 collections, some may have been lost.
-		recordGCPauses(ctx, recorder, circular)
-		return
+	if len(last) != len(h.Counts) {
+		last = make([]uint64, len(h.Counts))
 	}
 
-	length := uint32(len(circular))
-
-	i := lastNumGC % length
-	j := currentNumGC % length
-
-	if j < i { // wrap around the circular buffer
-		recordGCPauses(ctx, recorder, circular[i:])
-		recordGCPauses(ctx, recorder, circular[:j])
-		return
+	for i, count := range h.Counts {
+		delta := count - last[i]
+		if delta == 0 {
+			continue
+		}
+		v := bucketValue(h.Buckets[i], h.Buckets[i+1])
+		for ; delta > 0; delta-- {
+			observe(v)
+		}
 	}
+	copy(last, h.Counts)
+	return last
+}
 
-	recordGCPauses(ctx, recorder, circular[i:j])
+// histogramSum returns the sum of the observations of the runtime/metrics
+// histogram h, approximated the same way as histogramDelta does.
+func histogramSum(h *metrics.Float64Histogram) float64 {
+	if h == nil {
+		return 0
+	}
+	var sum float64
+	for i, count := range h.Counts {
+		if count > 0 {
+			sum += float64(count) * bucketValue(h.Buckets[i], h.Buckets[i+1])
+		}
+	}
+	return sum
 }
 
-func recordGCPauses(
-	ctx context.Context,
-	recorder syncint64.Histogram,
-	pauses []uint64,
-) {
-	for _, pause := range pauses {
-		recorder.Record(ctx, int64(pause))
+// bucketValue returns the value representing the observations of the
+// runtime/metrics histogram bucket [lower, upper).
+func bucketValue(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	default:
+		return lower + (upper-lower)/2
 	}
 }
@@ -18,6 +18,9 @@ import (
 	"context"
 	"fmt"
 	goruntime "runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,14 +28,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/metrictest"
 )
 
 func TestRuntime(t *testing.T) {
-	err := runtime.Start(
-		runtime.WithMinimumReadMemStatsInterval(time.Second),
-	)
+	err := runtime.Start()
 	assert.NoError(t, err)
 	time.Sleep(time.Second)
 }
@@ -53,7 +55,7 @@ func getGCCount(exp *metrictest.Exporter) int {
 	panic("Could not locate a process.runtime.go.gc.count metric in test output")
 }
 
-func testMinimumInterval(t *testing.T, expect int, opts ...runtime.Option) {
+func testGCCount(t *testing.T, opts ...runtime.Option) {
 	goruntime.GC()
 
 	var mstats0 goruntime.MemStats
@@ -76,24 +78,337 @@ func testMinimumInterval(t *testing.T, expect int, opts ...runtime.Option) {
 
 	require.Equal(t, 1, getGCCount(exp)-baseline)
 
-	// Only counted for small min read mem state itervals.
+	// Each collection reads a new snapshot of the runtime metrics.
+	goruntime.GC()
+	goruntime.GC()
 	goruntime.GC()
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	require.Equal(t, 4, getGCCount(exp)-baseline)
+}
+
+func TestGCCount(t *testing.T) {
+	testGCCount(t)
+}
+
+func TestMinimumReadMemStatsIntervalIgnored(t *testing.T) {
+	testGCCount(t, runtime.WithMinimumReadMemStatsInterval(time.Hour)) //nolint:staticcheck // Testing deprecated option.
+}
+
+func TestHeapMetrics(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := runtime.Start(
+		runtime.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+
+	goruntime.GC()
+	require.NoError(t, exp.Collect(context.Background()))
+
+	get := func(name string) int64 {
+		r, err := exp.GetByName(name)
+		require.NoError(t, err)
+		return r.Sum.CoerceToInt64(r.NumberKind)
+	}
+
+	heapAlloc := get("process.runtime.go.mem.heap_alloc")
+	heapInuse := get("process.runtime.go.mem.heap_inuse")
+	heapSys := get("process.runtime.go.mem.heap_sys")
+	assert.Greater(t, heapAlloc, int64(0))
+	assert.GreaterOrEqual(t, heapInuse, heapAlloc)
+	assert.GreaterOrEqual(t, heapSys, heapInuse)
+	assert.Greater(t, get("process.runtime.go.mem.allocs"), int64(0))
+	assert.Greater(t, get("process.runtime.go.gc.pause_total_ns"), int64(0))
+
+	pauses, err := exp.GetByName("process.runtime.go.gc.pause_ns")
+	require.NoError(t, err)
+	assert.Greater(t, pauses.Count, uint64(0))
+}
+
+func TestGCPause(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := runtime.Start(
+		runtime.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+	pauses, err := exp.GetByName("process.runtime.go.gc.pause")
+	require.NoError(t, err)
+	before := pauses.Count
+
 	goruntime.GC()
 	goruntime.GC()
+	require.NoError(t, exp.Collect(context.Background()))
+
+	// Each GC cycle has two stop-the-world pauses.
+	pauses, err = exp.GetByName("process.runtime.go.gc.pause")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, pauses.Count-before, uint64(4))
+	assert.Greater(t, pauses.Sum.CoerceToFloat64(pauses.NumberKind), 0.0)
+	assert.Less(t, pauses.Sum.CoerceToFloat64(pauses.NumberKind), 60.0)
+}
+
+func TestSchedLatency(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	err := runtime.Start(
+		runtime.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() { done <- struct{}{} }()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	count, err := exp.GetByName("process.runtime.go.sched.latency.count")
+	require.NoError(t, err)
+	assert.Greater(t, count.Sum.CoerceToInt64(count.NumberKind), int64(0))
+
+	var quantiles int
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName != "process.runtime.go.sched.latency" {
+			continue
+		}
+		quantiles++
+		assert.GreaterOrEqual(t, r.LastValue.CoerceToFloat64(r.NumberKind), 0.0)
+	}
+	assert.Equal(t, 4, quantiles)
+}
+
+func TestCgoCalls(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(runtime.WithMeterProvider(provider)))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	calls, err := exp.GetByName("process.runtime.go.cgo.calls")
+	require.NoError(t, err)
+	assert.Equal(t, goruntime.NumCgoCall(), calls.Sum.CoerceToInt64(calls.NumberKind))
+}
+
+func TestUnregister(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	reg, err := runtime.Register(
+		runtime.WithMeterProvider(provider),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Collect(context.Background()))
+	_, err = exp.GetByName("process.runtime.go.goroutines")
+	require.NoError(t, err)
+
+	require.NoError(t, reg.Unregister())
 
 	require.NoError(t, exp.Collect(context.Background()))
+	for _, name := range []string{
+		"process.runtime.go.goroutines",
+		"process.runtime.go.gc.count",
+		"process.runtime.go.sched.latency.count",
+	} {
+		_, err = exp.GetByName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGOMAXPROCS(t *testing.T) {
+	defer goruntime.GOMAXPROCS(goruntime.GOMAXPROCS(3))
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(runtime.WithMeterProvider(provider)))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	procs, err := exp.GetByName("process.runtime.go.gomaxprocs")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), procs.Sum.CoerceToInt64(procs.NumberKind))
 
-	require.Equal(t, expect, getGCCount(exp)-baseline)
+	cpus, err := exp.GetByName("process.runtime.go.num_cpu")
+	require.NoError(t, err)
+	assert.Equal(t, int64(goruntime.NumCPU()), cpus.Sum.CoerceToInt64(cpus.NumberKind))
 }
 
-func TestDefaultMinimumInterval(t *testing.T) {
-	testMinimumInterval(t, 1)
+func TestMemoryClasses(t *testing.T) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(runtime.WithMeterProvider(provider)))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	used := make(map[string]int64)
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName != "go.memory.used" {
+			continue
+		}
+		require.Len(t, r.Attributes, 1)
+		used[r.Attributes[0].Value.AsString()] = r.Sum.CoerceToInt64(r.NumberKind)
+	}
+
+	for _, attrs := range [][]attribute.KeyValue{
+		runtime.AttributeMemoryHeap,
+		runtime.AttributeMemoryStack,
+		runtime.AttributeMemoryMSpan,
+		runtime.AttributeMemoryMCache,
+		runtime.AttributeMemoryOther,
+	} {
+		v, ok := used[attrs[0].Value.AsString()]
+		assert.True(t, ok, attrs[0].Value.AsString())
+		assert.GreaterOrEqual(t, v, int64(0))
+	}
+	assert.Greater(t, used["heap"], int64(0))
+	assert.Greater(t, used["stack"], int64(0))
 }
 
-func TestNoMinimumInterval(t *testing.T) {
-	testMinimumInterval(t, 4, runtime.WithMinimumReadMemStatsInterval(0))
+func TestSemconvVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		versions []runtime.SemconvVersion
+		present  []string
+		absent   []string
+	}{
+		{
+			name:    "default",
+			present: []string{"process.runtime.go.goroutines", "process.runtime.go.mem.allocs", "runtime.uptime", "go.memory.used"},
+			absent:  []string{"go.goroutine.count", "go.memory.allocated"},
+		},
+		{
+			name:     "go",
+			versions: []runtime.SemconvVersion{runtime.SemconvGo},
+			present:  []string{"go.goroutine.count", "go.memory.allocated", "runtime.uptime", "go.memory.used"},
+			absent:   []string{"process.runtime.go.goroutines", "process.runtime.go.mem.allocs"},
+		},
+		{
+			name:     "dual",
+			versions: []runtime.SemconvVersion{runtime.SemconvLegacy, runtime.SemconvGo},
+			present:  []string{"process.runtime.go.goroutines", "go.goroutine.count", "process.runtime.go.mem.allocs", "go.memory.allocated"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, exp := metrictest.NewTestMeterProvider()
+			require.NoError(t, runtime.Start(
+				runtime.WithMeterProvider(provider),
+				runtime.WithSemconvVersion(tc.versions...),
+			))
+
+			require.NoError(t, exp.Collect(context.Background()))
+
+			for _, name := range tc.present {
+				_, err := exp.GetByName(name)
+				assert.NoError(t, err, name)
+			}
+			for _, name := range tc.absent {
+				_, err := exp.GetByName(name)
+				assert.Error(t, err, name)
+			}
+		})
+	}
 }
 
-func TestExplicitMinimumInterval(t *testing.T) {
-	testMinimumInterval(t, 1, runtime.WithMinimumReadMemStatsInterval(time.Hour))
+// requireRuntimeMetric skips the test if the named runtime/metric is not
+// supported by the Go runtime.
+func requireRuntimeMetric(t *testing.T, name string) {
+	for _, d := range metrics.All() {
+		if d.Name == name {
+			return
+		}
+	}
+	t.Skipf("%s is not supported by %s", name, goruntime.Version())
+}
+
+// mutexWait returns the time goroutines have spent blocked on mutexes as
+// reported by the runtime.
+func mutexWait() float64 {
+	s := []metrics.Sample{{Name: "/sync/mutex/wait/total:seconds"}}
+	metrics.Read(s)
+	return s[0].Value.Float64()
+}
+
+func TestMutexWait(t *testing.T) {
+	requireRuntimeMetric(t, "/sync/mutex/wait/total:seconds")
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(runtime.WithMeterProvider(provider)))
+
+	// The runtime only accounts for the goroutines that were parked
+	// waiting for the mutex, contend until at least one has been.
+	var mu sync.Mutex
+	for start := time.Now(); time.Since(start) < 5*time.Second; {
+		var wg sync.WaitGroup
+		mu.Lock()
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mu.Lock()
+				defer mu.Unlock()
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		mu.Unlock()
+		wg.Wait()
+
+		if mutexWait() > 0 {
+			break
+		}
+	}
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	wait, err := exp.GetByName("process.runtime.go.sync.mutex.wait")
+	require.NoError(t, err)
+	assert.Greater(t, wait.Sum.CoerceToFloat64(wait.NumberKind), 0.0)
+}
+
+func TestGCTuning(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(50))
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(
+		runtime.WithMeterProvider(provider),
+		runtime.WithSemconvVersion(runtime.SemconvGo),
+	))
+
+	goruntime.GC()
+	require.NoError(t, exp.Collect(context.Background()))
+
+	var forced int64
+	for _, r := range exp.GetRecords() {
+		if r.InstrumentName == "process.runtime.go.gc.cycles" && r.Attributes[0] == runtime.AttributeGCCauseForced[0] {
+			forced = r.Sum.CoerceToInt64(r.NumberKind)
+		}
+	}
+	assert.GreaterOrEqual(t, forced, int64(1))
+
+	if gogc, err := exp.GetByName("go.config.gogc"); err == nil {
+		assert.Equal(t, int64(50), gogc.Sum.CoerceToInt64(gogc.NumberKind))
+	} else {
+		requireRuntimeMetric(t, "/gc/gogc:percent")
+		t.Error(err)
+	}
+
+	requireRuntimeMetric(t, "/cpu/classes/gc/total:cpu-seconds")
+	fraction, err := exp.GetByName("process.runtime.go.gc.cpu_fraction")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, fraction.LastValue.CoerceToFloat64(fraction.NumberKind), 0.0)
+	assert.LessOrEqual(t, fraction.LastValue.CoerceToFloat64(fraction.NumberKind), 1.0)
+}
+
+func TestSchedRunnable(t *testing.T) {
+	requireRuntimeMetric(t, "/sched/goroutines/runnable:goroutines")
+
+	provider, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, runtime.Start(runtime.WithMeterProvider(provider)))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	runnable, err := exp.GetByName("process.runtime.go.sched.goroutines.runnable")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, runnable.Sum.CoerceToInt64(runnable.NumberKind), int64(0))
 }
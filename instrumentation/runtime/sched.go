@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
+
+import (
+	"context"
+	"runtime/metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+)
+
+// Names of the runtime/metrics read to compute the scheduler metrics.
+const (
+	// schedLatenciesMetric is the runtime/metric of the time goroutines
+	// have spent in the scheduler in a runnable state before actually
+	// running.
+	schedLatenciesMetric = "/sched/latencies:seconds"
+	// schedRunnableMetric is the runtime/metric of the number of
+	// goroutines ready to run but not running.
+	schedRunnableMetric = "/sched/goroutines/runnable:goroutines"
+)
+
+// schedLatencyQuantiles are the quantiles of the scheduling latency
+// reported.
+var schedLatencyQuantiles = []float64{0.5, 0.9, 0.99, 1}
+
+// newSchedCollector returns the collector of the scheduler metrics.
+//
+// The runtime makes an observation each time a goroutine is scheduled,
+// which is too many to record one at a time with a histogram instrument.
+// The latency quantiles of the goroutines scheduled since the previous
+// collection are reported instead, along with the count of scheduled
+// goroutines.
+func (r *runtime) newSchedCollector() (collector, error) {
+	var (
+		err error
+
+		latency   asyncfloat64.Gauge
+		scheduled asyncint64.Counter
+		runnable  asyncint64.UpDownCounter
+
+		lastCounts []uint64
+		quantiles  = make([]float64, len(schedLatencyQuantiles))
+	)
+
+	if latency, err = r.meter.AsyncFloat64().Gauge(
+		"process.runtime.go.sched.latency",
+		instrument.WithUnit("s"),
+		instrument.WithDescription("Quantiles of the time goroutines have spent runnable before running since the last collection"),
+	); err != nil {
+		return collector{}, err
+	}
+
+	if scheduled, err = r.meter.AsyncInt64().Counter(
+		"process.runtime.go.sched.latency.count",
+		instrument.WithDescription("Number of goroutine scheduling latencies measured"),
+	); err != nil {
+		return collector{}, err
+	}
+
+	if runnable, err = r.meter.AsyncInt64().UpDownCounter(
+		"process.runtime.go.sched.goroutines.runnable",
+		instrument.WithDescription("Number of goroutines ready to run waiting for a processor (run queue length)"),
+	); err != nil {
+		return collector{}, err
+	}
+
+	quantileAttrs := make([]attribute.KeyValue, len(schedLatencyQuantiles))
+	for i, q := range schedLatencyQuantiles {
+		quantileAttrs[i] = attribute.Float64("quantile", q)
+	}
+
+	return collector{
+		instruments: []instrument.Asynchronous{
+			latency,
+			scheduled,
+			runnable,
+		},
+		metrics: []string{schedLatenciesMetric, schedRunnableMetric},
+		collect: func(ctx context.Context, snapshot *runtimeMetrics) {
+			// Only supported by Go 1.26 and later.
+			if snapshot.supported(schedRunnableMetric) {
+				runnable.Observe(ctx, int64(snapshot.uint64(schedRunnableMetric)))
+			}
+
+			h := snapshot.histogram(schedLatenciesMetric)
+			if h == nil {
+				// Not supported by the Go runtime.
+				return
+			}
+
+			var total uint64
+			for _, count := range h.Counts {
+				total += count
+			}
+			scheduled.Observe(ctx, int64(total))
+
+			if histogramQuantiles(h, lastCounts, schedLatencyQuantiles, quantiles) {
+				for i, q := range quantiles {
+					latency.Observe(ctx, q, quantileAttrs[i])
+				}
+			}
+			lastCounts = append(lastCounts[:0], h.Counts...)
+		},
+	}, nil
+}
+
+// histogramQuantiles computes into dest the quantiles qs of the
+// observations made in the runtime/metrics histogram h since its previous
+// bucket counts last.  Each quantile is approximated by the value
+// histogramDelta uses for its bucket.  False is returned if no observation
+// was made.
+func histogramQuantiles(h *metrics.Float64Histogram, last []uint64, qs, dest []float64) bool {
+	delta := make([]uint64, len(h.Counts))
+	var total uint64
+	for i, count := range h.Counts {
+		delta[i] = count
+		if len(last) == len(h.Counts) {
+			delta[i] -= last[i]
+		}
+		total += delta[i]
+	}
+	if total == 0 {
+		return false
+	}
+
+	for j, q := range qs {
+		rank := uint64(q * float64(total))
+		if rank == 0 {
+			rank = 1
+		}
+		var cumulative uint64
+		for i, count := range delta {
+			cumulative += count
+			if cumulative >= rank {
+				dest[j] = bucketValue(h.Buckets[i], h.Buckets[i+1])
+				break
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime // import "go.opentelemetry.io/contrib/instrumentation/runtime"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// SemconvVersion identifies a version of the semantic conventions the
+// names of the runtime metrics follow.
+type SemconvVersion uint8
+
+const (
+	// SemconvLegacy is the version of the semantic conventions naming the
+	// runtime metrics process.runtime.go.* and runtime.*.
+	SemconvLegacy SemconvVersion = 1 << iota
+	// SemconvGo is the version of the semantic conventions naming the
+	// runtime metrics go.*.
+	SemconvGo
+)
+
+// WithSemconvVersion sets the versions of the semantic conventions the
+// reported runtime metrics are named after.  When multiple versions are
+// passed, the metrics are reported with the names of each version so
+// dashboards can be migrated from one version to the other.  If this
+// option is not used, or no version is passed, SemconvLegacy is used.
+//
+// Only the metrics named by both versions are affected, the metrics named
+// by only one of them are always reported with that name.
+func WithSemconvVersion(versions ...SemconvVersion) Option {
+	var v SemconvVersion
+	for _, version := range versions {
+		v |= version
+	}
+	return semconvVersionOption(v)
+}
+
+type semconvVersionOption SemconvVersion
+
+func (o semconvVersionOption) apply(c *config) {
+	if o != 0 {
+		c.SemconvVersion = SemconvVersion(o)
+	}
+}
+
+// names returns the names of a metric named legacy by SemconvLegacy and
+// stable by SemconvGo to report.
+func (r *runtime) names(legacy, stable string) []string {
+	var names []string
+	if r.config.SemconvVersion&SemconvLegacy != 0 {
+		names = append(names, legacy)
+	}
+	if r.config.SemconvVersion&SemconvGo != 0 {
+		names = append(names, stable)
+	}
+	return names
+}
+
+// int64Observer is an asynchronous int64 instrument.
+type int64Observer interface {
+	instrument.Asynchronous
+
+	Observe(ctx context.Context, x int64, attrs ...attribute.KeyValue)
+}
+
+// int64Observers observes values with the instruments of each name of a
+// metric.
+type int64Observers []int64Observer
+
+// Observe observes x with each instrument of o.
+func (o int64Observers) Observe(ctx context.Context, x int64, attrs ...attribute.KeyValue) {
+	for _, obs := range o {
+		obs.Observe(ctx, x, attrs...)
+	}
+}
+
+// upDownCounters creates an asynchronous int64 up-down counter for each name
+// of the metric named legacy by SemconvLegacy and stable by SemconvGo.
+func (r *runtime) upDownCounters(legacy, stable string, opts ...instrument.Option) (int64Observers, error) {
+	var o int64Observers
+	for _, name := range r.names(legacy, stable) {
+		c, err := r.meter.AsyncInt64().UpDownCounter(name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, c)
+	}
+	return o, nil
+}
+
+// counters creates an asynchronous int64 counter for each name of the
+// metric named legacy by SemconvLegacy and stable by SemconvGo.
+func (r *runtime) counters(legacy, stable string, opts ...instrument.Option) (int64Observers, error) {
+	var o int64Observers
+	for _, name := range r.names(legacy, stable) {
+		c, err := r.meter.AsyncInt64().Counter(name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, c)
+	}
+	return o, nil
+}
+
+// asynchronous returns the instruments of all the observers.
+func asynchronous(observers ...int64Observers) []instrument.Asynchronous {
+	var insts []instrument.Asynchronous
+	for _, o := range observers {
+		for _, obs := range o {
+			insts = append(insts, obs)
+		}
+	}
+	return insts
+}
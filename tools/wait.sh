@@ -50,6 +50,18 @@ wait_for_gomemcache () {
   exit 1
 }
 
+wait_for_kafka () {
+  for ((i = 0; i < 5; ++i)); do
+    if nc -z localhost 9092; then
+      exit 0
+    fi
+    echo "Kafka not yet available..."
+    sleep 10
+  done
+  echo "Timeout waiting for kafka to initialize"
+  exit 1
+}
+
 if [ -z "$CMD" ]; then
   echo "CMD is undefined. exiting..."
   exit 1
@@ -64,6 +76,8 @@ elif [ "$CMD" == "mongo" ]; then
   wait_for_mongo "$IMG_NAME"
 elif [ "$CMD" == "gomemcache" ]; then
   wait_for_gomemcache
+elif [ "$CMD" == "kafka" ]; then
+  wait_for_kafka
 else
   echo "unknown CMD"
   exit 1